@@ -18,6 +18,11 @@
 // @name Authorization
 // @description Digite "Bearer " seguido do seu token JWT
 
+// @securityDefinitions.apikey AdminAuth
+// @in header
+// @name X-Admin-Token
+// @description Token administrativo definido em ADMIN_TOKEN
+
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 package main
@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"time"
+
+	"zpigo/internal/store/models"
+)
+
+type CacheWarmRequest struct {
+	APIKey      string `json:"apiKey,omitempty" example:""`       // API key usada na chave do cache (opcional, padrão: contexto sem API key)
+	Concurrency int    `json:"concurrency,omitempty" example:"8"` // Número máximo de sessões processadas em paralelo (opcional)
+}
+
+type CacheWarmResponse struct {
+	Success     bool   `json:"success" example:"true"`           // Indica se o aquecimento foi concluído
+	WarmedItems int    `json:"warmedItems" example:"10"`         // Quantidade de sessões inseridas no cache
+	Concurrency int    `json:"concurrency" example:"8"`          // Concorrência utilizada
+	Duration    string `json:"duration" example:"15ms"`          // Duração do aquecimento
+	Message     string `json:"message" example:"Cache aquecido"` // Mensagem descritiva
+	Timestamp   int64  `json:"timestamp" example:"1640995200"`   // Timestamp da operação
+}
+
+type CacheStatsResponse struct {
+	TotalItems       int            `json:"totalItems" example:"12"`        // Total de itens, incluindo expirados ainda não removidos
+	LiveItems        int            `json:"liveItems" example:"10"`         // Itens válidos (não expirados)
+	SessionInfoItems int            `json:"sessionInfoItems" example:"8"`   // Itens do tipo SessionInfo
+	NonExpiringItems int            `json:"nonExpiringItems" example:"8"`   // Itens sem expiração (NoExpiration)
+	ItemsByType      map[string]int `json:"itemsByType"`                    // Quantidade de itens por tipo armazenado
+	Timestamp        int64          `json:"timestamp" example:"1640995200"` // Timestamp da consulta
+}
+
+type SessionManagersResponse struct {
+	SessionID        string `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000"`                           // ID da sessão consultada
+	InSessionHandler bool   `json:"inSessionHandler" example:"true"`                                                    // Sessão presente no gerenciador do SessionHandler
+	InMessageHandler bool   `json:"inMessageHandler" example:"true"`                                                    // Sessão presente no gerenciador do MessageHandler
+	SharedManager    bool   `json:"sharedManager" example:"true"`                                                       // Os dois handlers usam a mesma instância de gerenciador
+	Consistent       bool   `json:"consistent" example:"true"`                                                          // Status e envio enxergam a sessão da mesma forma
+	Details          string `json:"details" example:"SessionHandler e MessageHandler compartilham o mesmo gerenciador"` // Diagnóstico descritivo
+	Timestamp        int64  `json:"timestamp" example:"1640995200"`                                                     // Timestamp da consulta
+}
+
+type QRSweepResponse struct {
+	Success   bool     `json:"success" example:"true"`         // Indica se a limpeza foi concluída
+	Swept     []string `json:"swept"`                          // IDs das sessões com QR expirado limpo
+	Count     int      `json:"count" example:"1"`              // Quantidade de sessões limpas
+	Timestamp int64    `json:"timestamp" example:"1640995200"` // Timestamp da operação
+}
+
+type CreateAPIKeyRequest struct {
+	Label     string `json:"label,omitempty" example:"integração CRM"`                           // Descrição da chave (opcional)
+	SessionID string `json:"sessionId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"` // Restringe a chave a uma sessão (opcional)
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`                  // ID da chave
+	Prefix     string     `json:"prefix" example:"zpg_1a2b3c4d"`                                      // Início da chave, para identificação
+	Label      string     `json:"label,omitempty" example:"integração CRM"`                           // Descrição da chave
+	SessionID  string     `json:"sessionId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"` // Sessão à qual a chave está restrita
+	CreatedAt  time.Time  `json:"createdAt"`                                                          // Data de criação
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`                                               // Último uso da chave
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`                                                // Data de revogação
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey string          `json:"apiKey" example:"zpg_1a2b3c4d..."` // Chave em texto puro, exibida apenas na criação
+	Key    *APIKeyResponse `json:"key"`                              // Dados da chave criada
+}
+
+type APIKeyListResponse struct {
+	Keys  []*APIKeyResponse `json:"keys"`              // Chaves cadastradas
+	Total int               `json:"total" example:"2"` // Total de chaves
+}
+
+func ToAPIKeyResponse(key *models.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Prefix:     key.Prefix,
+		Label:      key.Label,
+		SessionID:  key.SessionID,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+type DeviceResponse struct {
+	JID       string `json:"jid" example:"5511999999999:12@s.whatsapp.net"`                      // JID do device no container do whatsmeow
+	PushName  string `json:"pushName,omitempty" example:"Maria"`                                 // Nome de exibição da conta
+	Platform  string `json:"platform,omitempty" example:"android"`                               // Plataforma do aparelho principal
+	SessionID string `json:"sessionId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"` // Sessão vinculada ao device
+	Orphan    bool   `json:"orphan" example:"false"`                                             // Indica que nenhuma sessão usa o device
+}
+
+type ListDevicesResponse struct {
+	Devices   []DeviceResponse `json:"devices"`                        // Devices salvos no container
+	Total     int              `json:"total" example:"3"`              // Quantidade de devices listados
+	Orphans   int              `json:"orphans" example:"1"`            // Quantidade de devices sem sessão
+	Timestamp int64            `json:"timestamp" example:"1640995200"` // Timestamp da consulta
+}
+
+type DeleteDeviceResponse struct {
+	Success bool   `json:"success" example:"true"`                        // Indica se o device foi removido
+	JID     string `json:"jid" example:"5511999999999:12@s.whatsapp.net"` // JID do device removido
+	Message string `json:"message" example:"Device removido"`             // Detalhes da operação
+}
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"zpigo/internal/store/models"
+)
+
+// MaxBroadcastRecipients limita a quantidade de destinatários por job
+const MaxBroadcastRecipients = 5000
+
+type CreateBroadcastRequest struct {
+	Message    string                      `json:"message" validate:"required,min=1,max=4096" example:"Olá, {{nome}}!" binding:"required"` // Texto enviado, com marcadores {{nome}} opcionais
+	Recipients []BroadcastRecipientRequest `json:"recipients" validate:"required,min=1" binding:"required"`                                // Destinatários do broadcast
+}
+
+type BroadcastRecipientRequest struct {
+	Phone     string            `json:"phone" example:"5511999999999"` // Número do telefone ou JID do destinatário
+	Variables map[string]string `json:"variables,omitempty"`           // Valores dos marcadores da mensagem para este destinatário (opcional)
+}
+
+type BroadcastJobResponse struct {
+	ID         string     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`        // ID do job
+	SessionID  string     `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000"` // Sessão usada no envio
+	Status     string     `json:"status" example:"running"`                                 // pending, running, completed, cancelled ou failed
+	Total      int        `json:"total" example:"100"`                                      // Total de destinatários
+	Sent       int        `json:"sent" example:"40"`                                        // Mensagens enviadas
+	Failed     int        `json:"failed" example:"2"`                                       // Envios com falha
+	Pending    int        `json:"pending" example:"58"`                                     // Destinatários ainda não processados
+	LastError  string     `json:"lastError,omitempty" example:"timed out"`                  // Último erro registrado
+	CreatedAt  time.Time  `json:"createdAt"`                                                // Criação do job
+	StartedAt  *time.Time `json:"startedAt,omitempty"`                                      // Início do processamento
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`                                     // Término do processamento
+}
+
+func ToBroadcastJobResponse(job *models.BroadcastJob) *BroadcastJobResponse {
+	return &BroadcastJobResponse{
+		ID:         job.ID,
+		SessionID:  job.SessionID,
+		Status:     string(job.Status),
+		Total:      job.Total,
+		Sent:       job.Sent,
+		Failed:     job.Failed,
+		Pending:    job.Pending(),
+		LastError:  job.LastError,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
@@ -0,0 +1,63 @@
+package dto
+
+type SetDisappearingTimerRequest struct {
+	Phone    string `json:"phone" validate:"required" example:"5511999999999" binding:"required"` // Número de telefone ou JID do chat (contato ou grupo)
+	Duration string `json:"duration" validate:"required" example:"7d" binding:"required"`         // Duração das mensagens temporárias: off, 24h, 7d ou 90d
+}
+
+type SetDisappearingTimerResponse struct {
+	Success  bool   `json:"success" example:"true"`                               // Indica se o temporizador foi alterado
+	Chat     string `json:"chat" example:"5511999999999@s.whatsapp.net"`          // JID do chat
+	Duration string `json:"duration" example:"7d"`                                // Duração informada
+	Seconds  uint32 `json:"seconds" example:"604800"`                             // Duração em segundos; 0 desativa
+	Message  string `json:"message" example:"Mensagens temporárias configuradas"` // Detalhes da operação
+}
+
+type ChatActionRequest struct {
+	Phone                string `json:"phone" validate:"required" example:"5511999999999" binding:"required"` // Número de telefone ou JID do chat (contato ou grupo)
+	LastMessageID        string `json:"lastMessageId,omitempty" example:"3EB0C767D26A1D8B4A3F"`               // ID da última mensagem do chat (opcional)
+	LastMessageFromMe    bool   `json:"lastMessageFromMe,omitempty" example:"false"`                          // Indica se a última mensagem foi enviada pela sessão
+	LastMessageTimestamp int64  `json:"lastMessageTimestamp,omitempty" example:"1640995200"`                  // Timestamp da última mensagem; padrão é o horário atual
+}
+
+type ChatActionResponse struct {
+	Success bool   `json:"success" example:"true"`                      // Indica se o patch foi enviado
+	Chat    string `json:"chat" example:"5511999999999@s.whatsapp.net"` // JID do chat
+	Action  string `json:"action" example:"delete"`                     // Ação aplicada: delete ou clear
+	Message string `json:"message" example:"Chat apagado"`              // Detalhes da operação
+}
+
+type StarMessageRequest struct {
+	Phone       string `json:"phone" validate:"required" example:"5511999999999" binding:"required"`            // Número de telefone ou JID do chat (contato ou grupo)
+	MessageID   string `json:"messageId" validate:"required" example:"3EB0C767D26A1D8B4A3F" binding:"required"` // ID da mensagem
+	FromMe      bool   `json:"fromMe" example:"false"`                                                          // Indica se a mensagem foi enviada pela sessão
+	Participant string `json:"participant,omitempty" example:"5511888888888@s.whatsapp.net"`                    // Autor da mensagem; obrigatório em grupos quando fromMe é false
+	Starred     bool   `json:"starred" example:"true"`                                                          // true favorita a mensagem, false remove dos favoritos
+}
+
+type StarMessageResponse struct {
+	Success   bool   `json:"success" example:"true"`                      // Indica se o patch foi enviado
+	Chat      string `json:"chat" example:"5511999999999@s.whatsapp.net"` // JID do chat
+	MessageID string `json:"messageId" example:"3EB0C767D26A1D8B4A3F"`    // ID da mensagem
+	Starred   bool   `json:"starred" example:"true"`                      // Estado aplicado
+	Message   string `json:"message" example:"Mensagem favoritada"`       // Detalhes da operação
+}
+
+// ChatResponse é um chat conhecido da sessão. lastMessageAt e mutedUntil são timestamps Unix em segundos
+type ChatResponse struct {
+	JID           string `json:"jid" example:"5511999999999@s.whatsapp.net"`   // JID do chat
+	Name          string `json:"name,omitempty" example:"João Silva"`          // Nome do chat, do grupo ou do contato
+	IsGroup       bool   `json:"isGroup" example:"false"`                      // Indica se o chat é um grupo
+	LastMessageAt int64  `json:"lastMessageAt,omitempty" example:"1640995200"` // Horário da última mensagem conhecida
+	UnreadCount   int    `json:"unreadCount" example:"2"`                      // Mensagens não lidas
+	MarkedUnread  bool   `json:"markedUnread" example:"false"`                 // Chat marcado manualmente como não lido
+	Archived      bool   `json:"archived" example:"false"`                     // Chat arquivado
+	Pinned        bool   `json:"pinned" example:"true"`                        // Chat fixado
+	Muted         bool   `json:"muted" example:"false"`                        // Chat silenciado no momento
+	MutedUntil    int64  `json:"mutedUntil,omitempty" example:"1640995200"`    // Fim do silêncio; ausente quando sem prazo
+}
+
+type ChatsResponse struct {
+	Chats []*ChatResponse `json:"chats"`             // Chats ordenados: fixados primeiro, depois pela última mensagem
+	Total int             `json:"total" example:"1"` // Quantidade de chats retornados
+}
@@ -0,0 +1,209 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type ValidateContactRequest struct {
+	VCard string `json:"vcard,omitempty" example:"BEGIN:VCARD\nVERSION:3.0\nFN:João Silva\nTEL;type=CELL;waid=5511999999999:+5511999999999\nEND:VCARD"` // vCard completo (opcional quando name e phone são informados)
+	Name  string `json:"name,omitempty" example:"João Silva"`                                                                                           // Nome do contato, usado quando vcard não é informado
+	Phone string `json:"phone,omitempty" example:"5511999999999"`                                                                                       // Telefone do contato, usado quando vcard não é informado
+}
+
+type VCardPhone struct {
+	Number string `json:"number" example:"+5511999999999"`        // Número como aparece no vCard
+	WaID   string `json:"waid,omitempty" example:"5511999999999"` // Número WhatsApp associado (parâmetro waid)
+	Type   string `json:"type,omitempty" example:"CELL"`          // Tipo do telefone
+}
+
+type VCardFields struct {
+	FullName     string        `json:"fullName" example:"João Silva"`         // Nome exibido (FN)
+	Organization string        `json:"organization,omitempty" example:"ACME"` // Empresa (ORG)
+	Phones       []*VCardPhone `json:"phones"`                                // Telefones (TEL)
+}
+
+type ValidateContactResponse struct {
+	Valid       bool         `json:"valid" example:"true"` // Indica se o vCard é válido para envio
+	DisplayName string       `json:"displayName" example:"João Silva"`
+	VCard       string       `json:"vcard"`  // vCard normalizado
+	Fields      *VCardFields `json:"fields"` // Campos extraídos
+}
+
+// BuildVCard monta um vCard 3.0 mínimo para o contato, com o número marcado como WhatsApp
+func BuildVCard(name, phone string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("nome do contato é obrigatório")
+	}
+
+	digits := strings.TrimPrefix(strings.TrimSpace(phone), "+")
+	if !isDigitString(digits) || len(digits) < 8 || len(digits) > 15 {
+		return "", fmt.Errorf("telefone inválido: %s", phone)
+	}
+
+	return formatVCard(&VCardFields{
+		FullName: name,
+		Phones:   []*VCardPhone{{Number: "+" + digits, WaID: digits, Type: "CELL"}},
+	}), nil
+}
+
+// ParseVCard valida a estrutura de um vCard e extrai os campos usados pelo WhatsApp. O vCard
+// precisa ter FN e ao menos um TEL.
+func ParseVCard(raw string) (*VCardFields, error) {
+	lines := unfoldVCardLines(raw)
+	if len(lines) < 2 || !strings.EqualFold(lines[0], "BEGIN:VCARD") {
+		return nil, errors.New("vCard deve começar com BEGIN:VCARD")
+	}
+	if !strings.EqualFold(lines[len(lines)-1], "END:VCARD") {
+		return nil, errors.New("vCard deve terminar com END:VCARD")
+	}
+
+	fields := &VCardFields{Phones: []*VCardPhone{}}
+	var version string
+
+	for i, line := range lines[1 : len(lines)-1] {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("linha %d do vCard sem ':'", i+2)
+		}
+
+		params := strings.Split(name, ";")
+		switch strings.ToUpper(params[0]) {
+		case "VERSION":
+			version = value
+		case "FN":
+			fields.FullName = unescapeVCardValue(value)
+		case "ORG":
+			fields.Organization = unescapeVCardValue(strings.Split(value, ";")[0])
+		case "TEL":
+			fields.Phones = append(fields.Phones, parseVCardPhone(params[1:], value))
+		case "BEGIN", "END":
+			return nil, fmt.Errorf("linha %d do vCard: vCards aninhados não são suportados", i+2)
+		}
+	}
+
+	switch version {
+	case "2.1", "3.0", "4.0":
+	case "":
+		return nil, errors.New("vCard sem VERSION")
+	default:
+		return nil, fmt.Errorf("versão de vCard não suportada: %s", version)
+	}
+
+	if strings.TrimSpace(fields.FullName) == "" {
+		return nil, errors.New("vCard sem FN (nome do contato)")
+	}
+	if len(fields.Phones) == 0 {
+		return nil, errors.New("vCard sem TEL (telefone do contato)")
+	}
+
+	return fields, nil
+}
+
+// NormalizeVCard reescreve o vCard em 3.0 com apenas os campos extraídos
+func NormalizeVCard(fields *VCardFields) string {
+	return formatVCard(fields)
+}
+
+func parseVCardPhone(params []string, value string) *VCardPhone {
+	phone := &VCardPhone{Number: strings.TrimSpace(value)}
+
+	for _, param := range params {
+		key, val, found := strings.Cut(param, "=")
+		if !found {
+			// vCard 2.1 permite tipos sem o nome do parâmetro (TEL;CELL:...)
+			if phone.Type == "" {
+				phone.Type = strings.ToUpper(key)
+			}
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "waid":
+			phone.WaID = val
+		case "type":
+			if phone.Type == "" {
+				phone.Type = strings.ToUpper(strings.Split(val, ",")[0])
+			}
+		}
+	}
+
+	if phone.WaID == "" {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, phone.Number)
+		if len(digits) >= 8 {
+			phone.WaID = digits
+		}
+	}
+
+	return phone
+}
+
+func formatVCard(fields *VCardFields) string {
+	var b strings.Builder
+
+	name := escapeVCardValue(fields.FullName)
+	b.WriteString("BEGIN:VCARD\nVERSION:3.0\n")
+	fmt.Fprintf(&b, "N:;%s;;;\nFN:%s\n", name, name)
+	if fields.Organization != "" {
+		fmt.Fprintf(&b, "ORG:%s\n", escapeVCardValue(fields.Organization))
+	}
+	for _, phone := range fields.Phones {
+		phoneType := phone.Type
+		if phoneType == "" {
+			phoneType = "CELL"
+		}
+		if phone.WaID != "" {
+			fmt.Fprintf(&b, "TEL;type=%s;waid=%s:%s\n", phoneType, phone.WaID, phone.Number)
+		} else {
+			fmt.Fprintf(&b, "TEL;type=%s:%s\n", phoneType, phone.Number)
+		}
+	}
+	b.WriteString("END:VCARD")
+
+	return b.String()
+}
+
+// unfoldVCardLines separa as linhas do vCard, juntando continuações (linhas iniciadas por espaço ou tab)
+func unfoldVCardLines(raw string) []string {
+	raw = strings.ReplaceAll(strings.TrimSpace(raw), "\r\n", "\n")
+
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func escapeVCardValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`).Replace(value)
+}
+
+func unescapeVCardValue(value string) string {
+	return strings.NewReplacer(`\\`, `\`, `\,`, ",", `\;`, ";", `\n`, "\n", `\N`, "\n").Replace(value)
+}
+
+func isDigitString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, char := range s {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+type GroupParticipantResponse struct {
+	JID          string `json:"jid" example:"5511999999999@s.whatsapp.net"`                   // JID do participante
+	PhoneNumber  string `json:"phoneNumber,omitempty" example:"5511999999999@s.whatsapp.net"` // JID de telefone quando o participante usa LID
+	IsAdmin      bool   `json:"isAdmin" example:"false"`                                      // Indica se é administrador
+	IsSuperAdmin bool   `json:"isSuperAdmin" example:"false"`                                 // Indica se é o criador do grupo
+}
+
+type GroupSettingsResponse struct {
+	Locked               bool   `json:"locked" example:"false"`                      // Apenas admins podem editar as informações do grupo
+	Announce             bool   `json:"announce" example:"false"`                    // Apenas admins podem enviar mensagens
+	Ephemeral            bool   `json:"ephemeral" example:"false"`                   // Mensagens temporárias ativadas
+	DisappearingTimer    uint32 `json:"disappearingTimer" example:"0"`               // Duração das mensagens temporárias em segundos
+	JoinApprovalRequired bool   `json:"joinApprovalRequired" example:"false"`        // Entrada no grupo exige aprovação
+	MemberAddMode        string `json:"memberAddMode,omitempty" example:"admin_add"` // Quem pode adicionar participantes
+}
+
+type GroupInfoResponse struct {
+	JID          string                      `json:"jid" example:"120363000000000000@g.us"`                     // JID do grupo
+	Name         string                      `json:"name" example:"Meu Grupo"`                                  // Assunto do grupo
+	Topic        string                      `json:"topic,omitempty" example:"Descrição do grupo"`              // Descrição do grupo
+	OwnerJID     string                      `json:"ownerJid,omitempty" example:"5511999999999@s.whatsapp.net"` // JID do criador
+	CreatedAt    time.Time                   `json:"createdAt" example:"2023-01-01T00:00:00Z"`                  // Data de criação
+	Participants []*GroupParticipantResponse `json:"participants"`                                              // Participantes do grupo
+	Admins       []string                    `json:"admins"`                                                    // JIDs dos administradores
+	Settings     *GroupSettingsResponse      `json:"settings"`                                                  // Configurações do grupo
+}
+
+type SetGroupNameRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100" example:"Novo nome do grupo" binding:"required"` // Novo assunto do grupo
+}
+
+type SetGroupTopicRequest struct {
+	Topic string `json:"topic" validate:"max=2048" example:"Nova descrição do grupo"` // Nova descrição do grupo (vazio remove)
+}
+
+type GroupActionResponse struct {
+	Success  bool   `json:"success" example:"true"`                     // Indica se a operação foi bem-sucedida
+	Message  string `json:"message" example:"Nome do grupo atualizado"` // Mensagem de confirmação
+	GroupJID string `json:"groupJid" example:"120363000000000000@g.us"` // JID do grupo
+}
+
+type GroupInviteLinkResponse struct {
+	GroupJID   string `json:"groupJid" example:"120363000000000000@g.us"`                    // JID do grupo
+	InviteLink string `json:"inviteLink" example:"https://chat.whatsapp.com/AbCdEfGhIjKlMn"` // Link de convite
+	Reset      bool   `json:"reset" example:"false"`                                         // Indica se o link anterior foi revogado
+}
+
+type JoinGroupRequest struct {
+	Code string `json:"code" validate:"required" example:"https://chat.whatsapp.com/AbCdEfGhIjKlMn" binding:"required"` // Link de convite completo ou apenas o código
+}
+
+type JoinGroupResponse struct {
+	Success  bool   `json:"success" example:"true"`                       // Indica se a entrada foi bem-sucedida
+	Message  string `json:"message" example:"Entrada no grupo realizada"` // Mensagem de confirmação
+	GroupJID string `json:"groupJid" example:"120363000000000000@g.us"`   // JID do grupo
+}
+
+func ToGroupInfoResponse(info *types.GroupInfo) *GroupInfoResponse {
+	if info == nil {
+		return nil
+	}
+
+	participants := make([]*GroupParticipantResponse, 0, len(info.Participants))
+	admins := make([]string, 0)
+	for _, participant := range info.Participants {
+		response := &GroupParticipantResponse{
+			JID:          participant.JID.String(),
+			IsAdmin:      participant.IsAdmin,
+			IsSuperAdmin: participant.IsSuperAdmin,
+		}
+		if !participant.PhoneNumber.IsEmpty() && participant.PhoneNumber != participant.JID {
+			response.PhoneNumber = participant.PhoneNumber.String()
+		}
+		participants = append(participants, response)
+
+		if participant.IsAdmin || participant.IsSuperAdmin {
+			admins = append(admins, participant.JID.String())
+		}
+	}
+
+	response := &GroupInfoResponse{
+		JID:          info.JID.String(),
+		Name:         info.Name,
+		Topic:        info.Topic,
+		CreatedAt:    info.GroupCreated,
+		Participants: participants,
+		Admins:       admins,
+		Settings: &GroupSettingsResponse{
+			Locked:               info.IsLocked,
+			Announce:             info.IsAnnounce,
+			Ephemeral:            info.IsEphemeral,
+			DisappearingTimer:    info.DisappearingTimer,
+			JoinApprovalRequired: info.IsJoinApprovalRequired,
+			MemberAddMode:        string(info.MemberAddMode),
+		},
+	}
+
+	if !info.OwnerJID.IsEmpty() {
+		response.OwnerJID = info.OwnerJID.String()
+	}
+
+	return response
+}
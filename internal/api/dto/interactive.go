@@ -0,0 +1,342 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+type SendFlowMessageRequest struct {
+	Phone       string             `json:"phone" validate:"required,min=10,max=20" example:"5511999999999" binding:"required"`         // Número do telefone destinatário
+	Header      string             `json:"header,omitempty" example:"Cadastro"`                                                        // Título do cabeçalho (opcional)
+	Body        string             `json:"body" validate:"required,min=1,max=1024" example:"Preencha o formulário" binding:"required"` // Texto principal da mensagem
+	Footer      string             `json:"footer,omitempty" example:"Empresa LTDA"`                                                    // Texto do rodapé (opcional)
+	Flow        FlowParams         `json:"flow" binding:"required"`                                                                    // Parâmetros do fluxo
+	ID          string             `json:"id,omitempty" example:"custom-message-id"`                                                   // ID personalizado da mensagem (opcional)
+	ContextInfo *waE2E.ContextInfo `json:"contextInfo,omitempty"`                                                                      // Informações de contexto para replies e mentions (opcional)
+}
+
+type FlowParams struct {
+	FlowID         string                 `json:"flowId" example:"1234567890"`             // ID do fluxo publicado no WhatsApp Manager
+	FlowToken      string                 `json:"flowToken,omitempty" example:"token-123"` // Token repassado ao endpoint do fluxo (opcional)
+	FlowCTA        string                 `json:"flowCta" example:"Abrir formulário"`      // Texto do botão que abre o fluxo
+	FlowAction     string                 `json:"flowAction,omitempty" example:"navigate"` // Ação do fluxo: navigate ou data_exchange (padrão: navigate)
+	Screen         string                 `json:"screen,omitempty" example:"WELCOME"`      // Tela inicial, obrigatória para a ação navigate
+	Data           map[string]interface{} `json:"data,omitempty"`                          // Dados iniciais da tela (opcional)
+	Mode           string                 `json:"mode,omitempty" example:"published"`      // Modo do fluxo: published ou draft (padrão: published)
+	MessageVersion string                 `json:"messageVersion,omitempty" example:"3"`    // Versão da mensagem de fluxo (padrão: 3)
+}
+
+func (req *SendFlowMessageRequest) ValidatePhoneNumber() bool {
+	phone := strings.TrimPrefix(req.Phone, "+")
+	if phone == "" {
+		return false
+	}
+
+	for _, char := range phone {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return len(phone) >= 8 && len(phone) <= 15
+}
+
+func (f *FlowParams) Validate() error {
+	if f.FlowID == "" {
+		return errors.New("o campo 'flow.flowId' é obrigatório")
+	}
+
+	if f.FlowCTA == "" {
+		return errors.New("o campo 'flow.flowCta' é obrigatório")
+	}
+
+	if len(f.FlowCTA) > 20 {
+		return errors.New("o campo 'flow.flowCta' deve ter no máximo 20 caracteres")
+	}
+
+	switch f.GetAction() {
+	case "navigate":
+		if f.Screen == "" {
+			return errors.New("o campo 'flow.screen' é obrigatório para a ação navigate")
+		}
+	case "data_exchange":
+	default:
+		return errors.New("o campo 'flow.flowAction' deve ser navigate ou data_exchange")
+	}
+
+	if f.Mode != "" && f.Mode != "published" && f.Mode != "draft" {
+		return errors.New("o campo 'flow.mode' deve ser published ou draft")
+	}
+
+	return nil
+}
+
+func (f *FlowParams) GetAction() string {
+	if f.FlowAction == "" {
+		return "navigate"
+	}
+	return f.FlowAction
+}
+
+// ToButtonParamsJSON monta o JSON esperado pelo botão "flow" do NativeFlowMessage
+func (f *FlowParams) ToButtonParamsJSON() (string, error) {
+	params := map[string]interface{}{
+		"flow_message_version": f.MessageVersion,
+		"flow_id":              f.FlowID,
+		"flow_cta":             f.FlowCTA,
+		"flow_action":          f.GetAction(),
+		"mode":                 f.Mode,
+	}
+
+	if f.MessageVersion == "" {
+		params["flow_message_version"] = "3"
+	}
+
+	if f.Mode == "" {
+		params["mode"] = "published"
+	}
+
+	if f.FlowToken != "" {
+		params["flow_token"] = f.FlowToken
+	}
+
+	if f.GetAction() == "navigate" {
+		payload := map[string]interface{}{
+			"screen": f.Screen,
+		}
+		if len(f.Data) > 0 {
+			payload["data"] = f.Data
+		}
+		params["flow_action_payload"] = payload
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// Limites de opções aceitos pelo WhatsApp em enquetes
+const (
+	MinPollOptions = 2
+	MaxPollOptions = 12
+)
+
+type SendPollRequest struct {
+	Phone           string   `json:"phone" validate:"required" example:"5511999999999" binding:"required"`                       // Número do telefone ou JID do grupo destinatário
+	Question        string   `json:"question" validate:"required,min=1,max=255" example:"Qual o melhor dia?" binding:"required"` // Pergunta da enquete
+	Options         []string `json:"options" validate:"required,min=2,max=12" example:"Segunda,Quarta,Sexta" binding:"required"` // Opções da enquete (2 a 12)
+	SelectableCount int      `json:"selectableCount,omitempty" example:"1"`                                                      // Quantidade de opções selecionáveis (0 = sem limite)
+	ID              string   `json:"id,omitempty" example:"custom-message-id"`                                                   // ID personalizado da mensagem (opcional)
+}
+
+type SendPollResponse struct {
+	Success         bool     `json:"success" example:"true"`                        // Indica se o envio foi bem-sucedido
+	MessageID       string   `json:"messageId" example:"3EB0C431C26A1916EA9A_out"`  // ID da enquete, usado para correlacionar eventos PollUpdate
+	Timestamp       int64    `json:"timestamp" example:"1640995200"`                // Timestamp do envio
+	Details         string   `json:"details" example:"Enquete enviada com sucesso"` // Detalhes do envio
+	Phone           string   `json:"phone" example:"5511999999999"`                 // Destinatário
+	Options         []string `json:"options" example:"Segunda,Quarta,Sexta"`        // Opções enviadas, após remoção de duplicadas
+	SelectableCount int      `json:"selectableCount" example:"1"`                   // Quantidade de opções selecionáveis
+}
+
+// NormalizeOptions remove espaços nas pontas, opções vazias e duplicadas, preservando a ordem
+func (req *SendPollRequest) NormalizeOptions() []string {
+	seen := make(map[string]bool, len(req.Options))
+	options := make([]string, 0, len(req.Options))
+
+	for _, option := range req.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || seen[option] {
+			continue
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+
+	return options
+}
+
+func (req *SendPollRequest) Validate(options []string) error {
+	if strings.TrimSpace(req.Question) == "" {
+		return errors.New("o campo 'question' é obrigatório")
+	}
+
+	if len(options) < MinPollOptions || len(options) > MaxPollOptions {
+		return fmt.Errorf("a enquete deve ter entre %d e %d opções distintas, recebidas %d", MinPollOptions, MaxPollOptions, len(options))
+	}
+
+	if req.SelectableCount < 0 || req.SelectableCount > len(options) {
+		return fmt.Errorf("o campo 'selectableCount' deve estar entre 0 e %d", len(options))
+	}
+
+	return nil
+}
+
+// Limites aceitos pelo WhatsApp em mensagens de lista
+const (
+	MaxListRows          = 10
+	MaxListButtonTextLen = 20
+	MaxListRowTitleLen   = 24
+)
+
+type SendListMessageRequest struct {
+	Phone       string             `json:"phone" validate:"required" example:"5511999999999" binding:"required"`                          // Número do telefone ou JID do grupo destinatário
+	Title       string             `json:"title,omitempty" example:"Menu principal"`                                                      // Título da lista (opcional)
+	Description string             `json:"description" validate:"required,min=1,max=1024" example:"Escolha uma opção" binding:"required"` // Texto principal da mensagem
+	ButtonText  string             `json:"buttonText" validate:"required,min=1,max=20" example:"Ver opções" binding:"required"`           // Texto do botão que abre a lista
+	Footer      string             `json:"footer,omitempty" example:"Empresa LTDA"`                                                       // Texto do rodapé (opcional)
+	Sections    []ListSection      `json:"sections" validate:"required,min=1" binding:"required"`                                         // Seções da lista
+	ID          string             `json:"id,omitempty" example:"custom-message-id"`                                                      // ID personalizado da mensagem (opcional)
+	ContextInfo *waE2E.ContextInfo `json:"contextInfo,omitempty"`                                                                         // Informações de contexto para replies e mentions (opcional)
+}
+
+type ListSection struct {
+	Title string    `json:"title,omitempty" example:"Atendimento"` // Título da seção (obrigatório quando há mais de uma seção)
+	Rows  []ListRow `json:"rows"`                                  // Opções da seção
+}
+
+type ListRow struct {
+	RowID       string `json:"rowId" example:"suporte"`                             // Identificador retornado quando a opção é escolhida
+	Title       string `json:"title" example:"Suporte"`                             // Título da opção
+	Description string `json:"description,omitempty" example:"Falar com o suporte"` // Descrição da opção (opcional)
+}
+
+type SendListMessageResponse struct {
+	Success   bool   `json:"success" example:"true"`                       // Indica se o envio foi bem-sucedido
+	MessageID string `json:"messageId" example:"3EB0C431C26A1916EA9A_out"` // ID da mensagem enviada
+	Timestamp int64  `json:"timestamp" example:"1640995200"`               // Timestamp do envio
+	Details   string `json:"details" example:"Lista enviada com sucesso"`  // Detalhes do envio
+	Phone     string `json:"phone" example:"5511999999999"`                // Destinatário
+	Rows      int    `json:"rows" example:"3"`                             // Quantidade de opções enviadas
+}
+
+func (req *SendListMessageRequest) Validate() error {
+	if strings.TrimSpace(req.Description) == "" {
+		return errors.New("o campo 'description' é obrigatório")
+	}
+
+	if strings.TrimSpace(req.ButtonText) == "" {
+		return errors.New("o campo 'buttonText' é obrigatório")
+	}
+
+	if len([]rune(req.ButtonText)) > MaxListButtonTextLen {
+		return fmt.Errorf("o campo 'buttonText' deve ter no máximo %d caracteres", MaxListButtonTextLen)
+	}
+
+	if len(req.Sections) == 0 {
+		return errors.New("a lista deve ter pelo menos uma seção")
+	}
+
+	rowIDs := make(map[string]bool)
+	for i, section := range req.Sections {
+		if len(req.Sections) > 1 && strings.TrimSpace(section.Title) == "" {
+			return fmt.Errorf("a seção %d precisa de título quando a lista tem mais de uma seção", i+1)
+		}
+
+		if len(section.Rows) == 0 {
+			return fmt.Errorf("a seção %d deve ter pelo menos uma opção", i+1)
+		}
+
+		for _, row := range section.Rows {
+			if strings.TrimSpace(row.RowID) == "" {
+				return fmt.Errorf("todas as opções da seção %d precisam de 'rowId'", i+1)
+			}
+			if rowIDs[row.RowID] {
+				return fmt.Errorf("rowId duplicado: %s", row.RowID)
+			}
+			rowIDs[row.RowID] = true
+
+			if strings.TrimSpace(row.Title) == "" {
+				return fmt.Errorf("a opção %s precisa de título", row.RowID)
+			}
+			if len([]rune(row.Title)) > MaxListRowTitleLen {
+				return fmt.Errorf("o título da opção %s deve ter no máximo %d caracteres", row.RowID, MaxListRowTitleLen)
+			}
+		}
+	}
+
+	if len(rowIDs) > MaxListRows {
+		return fmt.Errorf("a lista deve ter no máximo %d opções, recebidas %d", MaxListRows, len(rowIDs))
+	}
+
+	return nil
+}
+
+// RowCount retorna o total de opções somando todas as seções
+func (req *SendListMessageRequest) RowCount() int {
+	total := 0
+	for _, section := range req.Sections {
+		total += len(section.Rows)
+	}
+	return total
+}
+
+// Limites aceitos pelo WhatsApp em mensagens de botões de resposta rápida
+const (
+	MaxQuickReplyButtons       = 3
+	MaxQuickReplyButtonTextLen = 20
+)
+
+type SendButtonsMessageRequest struct {
+	Phone       string             `json:"phone" validate:"required" example:"5511999999999" binding:"required"`                    // Número do telefone ou JID do grupo destinatário
+	Title       string             `json:"title,omitempty" example:"Confirmação"`                                                   // Texto do cabeçalho (opcional)
+	Text        string             `json:"text" validate:"required,min=1,max=1024" example:"Confirma o pedido?" binding:"required"` // Texto principal da mensagem
+	Footer      string             `json:"footer,omitempty" example:"Empresa LTDA"`                                                 // Texto do rodapé (opcional)
+	Buttons     []QuickReplyButton `json:"buttons" validate:"required,min=1,max=3" binding:"required"`                              // Botões de resposta rápida (1 a 3)
+	ID          string             `json:"id,omitempty" example:"custom-message-id"`                                                // ID personalizado da mensagem (opcional)
+	ContextInfo *waE2E.ContextInfo `json:"contextInfo,omitempty"`                                                                   // Informações de contexto para replies e mentions (opcional)
+}
+
+type QuickReplyButton struct {
+	ID   string `json:"id" example:"confirmar"` // Identificador retornado quando o botão é tocado
+	Text string `json:"text" example:"Sim"`     // Texto exibido no botão
+}
+
+type SendButtonsMessageResponse struct {
+	Success   bool   `json:"success" example:"true"`                        // Indica se o envio foi bem-sucedido
+	MessageID string `json:"messageId" example:"3EB0C431C26A1916EA9A_out"`  // ID da mensagem enviada, citado nas respostas aos botões
+	Timestamp int64  `json:"timestamp" example:"1640995200"`                // Timestamp do envio
+	Details   string `json:"details" example:"Botões enviados com sucesso"` // Detalhes do envio
+	Phone     string `json:"phone" example:"5511999999999"`                 // Destinatário
+	Buttons   int    `json:"buttons" example:"2"`                           // Quantidade de botões enviados
+}
+
+func (req *SendButtonsMessageRequest) Validate() error {
+	if strings.TrimSpace(req.Text) == "" {
+		return errors.New("o campo 'text' é obrigatório")
+	}
+
+	if len(req.Buttons) == 0 {
+		return errors.New("a mensagem deve ter pelo menos um botão")
+	}
+	if len(req.Buttons) > MaxQuickReplyButtons {
+		return fmt.Errorf("a mensagem deve ter no máximo %d botões, recebidos %d", MaxQuickReplyButtons, len(req.Buttons))
+	}
+
+	buttonIDs := make(map[string]bool, len(req.Buttons))
+	for i, button := range req.Buttons {
+		if strings.TrimSpace(button.ID) == "" {
+			return fmt.Errorf("o botão %d precisa de 'id'", i+1)
+		}
+		if buttonIDs[button.ID] {
+			return fmt.Errorf("id de botão duplicado: %s", button.ID)
+		}
+		buttonIDs[button.ID] = true
+
+		if strings.TrimSpace(button.Text) == "" {
+			return fmt.Errorf("o botão %s precisa de texto", button.ID)
+		}
+		if len([]rune(button.Text)) > MaxQuickReplyButtonTextLen {
+			return fmt.Errorf("o texto do botão %s deve ter no máximo %d caracteres", button.ID, MaxQuickReplyButtonTextLen)
+		}
+	}
+
+	return nil
+}
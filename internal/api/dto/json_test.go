@@ -0,0 +1,231 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var camelCaseKey = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+// responseDTOs lista as respostas da API; todas devem serializar com chaves em camelCase
+var responseDTOs = []interface{}{
+	&CacheWarmResponse{},
+	&CacheStatsResponse{},
+	&SessionManagersResponse{},
+	&QRSweepResponse{},
+	&APIKeyResponse{},
+	&CreateAPIKeyResponse{},
+	&APIKeyListResponse{},
+	&DeviceResponse{},
+	&ListDevicesResponse{},
+	&DeleteDeviceResponse{},
+	&BroadcastJobResponse{},
+	&SetDisappearingTimerResponse{},
+	&ChatActionResponse{},
+	&StarMessageResponse{},
+	&ChatResponse{},
+	&ChatsResponse{},
+	&ValidateContactResponse{},
+	&GroupParticipantResponse{},
+	&GroupSettingsResponse{},
+	&GroupInfoResponse{},
+	&GroupActionResponse{},
+	&GroupInviteLinkResponse{},
+	&JoinGroupResponse{},
+	&SendPollResponse{},
+	&SendListMessageResponse{},
+	&SendButtonsMessageResponse{},
+	&SendBulkTextResponse{},
+	&SendTextMessageResponse{},
+	&MessageErrorResponse{},
+	&SendMediaResponse{},
+	&SendStickerResponse{},
+	&DownloadMediaResponse{},
+	&ResolveJIDResponse{},
+	&MessageEditResponse{},
+	&MessageEditHistoryResponse{},
+	&StoredMessageResponse{},
+	&StoredMessagesResponse{},
+	&MessageReaderResponse{},
+	&MessageReadersResponse{},
+	&MessageStatusResponse{},
+	&WebhookMetricsResponse{},
+	&SessionMetricsResponse{},
+	&MetricsResponse{},
+	&NewsletterResponse{},
+	&NewsletterActionResponse{},
+	&CreateSessionResponse{},
+	&SessionResponse{},
+	&SessionListResponse{},
+	&SessionInfoResponse{},
+	&ConnectSessionResponse{},
+	&LogoutSessionResponse{},
+	&LogoutAllResponse{},
+	&QRCodeResponse{},
+	&PairPhoneResponse{},
+	&SetPlatformResponse{},
+	&SetMessageStorageResponse{},
+	&SetProxyResponse{},
+	&DeleteSessionResponse{},
+	&SessionCleanupResponse{},
+	&SessionStatusResponse{},
+	&DeviceInfoResponse{},
+	&SessionUptimeResponse{},
+	&SessionNotificationsResponse{},
+	&OfflineSyncResponse{},
+	&DashboardWebhookResponse{},
+	&DashboardMessagesResponse{},
+	&DashboardErrorResponse{},
+	&SessionDashboardResponse{},
+	&ErrorResponse{},
+	&APIResponse{},
+	&APIErrorResponse{},
+	&SessionConnectData{},
+	&SessionDisconnectData{},
+	&QRCodeData{},
+	&BlockedByResponse{},
+	&UserAboutResponse{},
+	&UserAboutListResponse{},
+	&SubscribePresenceResponse{},
+	&ContactResponse{},
+	&ContactsResponse{},
+	&TriggerWebhookResponse{},
+	&WebhookResponse{},
+	&DeleteWebhookResponse{},
+	&WebhookDeliveryResponse{},
+	&WebhookDeliveriesResponse{},
+	&ActiveEventsResponse{},
+	&GlobalWebhookResponse{},
+}
+
+// fillValue preenche recursivamente v com valores não nulos, para que campos omitempty também apareçam
+func fillValue(v reflect.Value, depth int) {
+	if depth > 6 {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		fillValue(v.Elem(), depth+1)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(time.Unix(1700000000, 0).UTC()))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillValue(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Slice:
+		if v.Type() == reflect.TypeOf(json.RawMessage{}) {
+			v.Set(reflect.ValueOf(json.RawMessage(`{}`)))
+			return
+		}
+		slice := reflect.MakeSlice(v.Type(), 1, 1)
+		fillValue(slice.Index(0), depth+1)
+		v.Set(slice)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		key := reflect.New(v.Type().Key()).Elem()
+		fillValue(key, depth+1)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fillValue(elem, depth+1)
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+	case reflect.String:
+		v.SetString("valor")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	}
+}
+
+// collectKeys retorna as chaves de todos os objetos JSON aninhados em value
+func collectKeys(value interface{}, keys map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			keys[key] = true
+			collectKeys(nested, keys)
+		}
+	case []interface{}:
+		for _, nested := range v {
+			collectKeys(nested, keys)
+		}
+	}
+}
+
+func marshalKeys(t *testing.T, dto interface{}) map[string]bool {
+	t.Helper()
+
+	fillValue(reflect.ValueOf(dto), 0)
+	data, err := json.Marshal(dto)
+	if err != nil {
+		t.Fatalf("erro ao serializar %T: %v", dto, err)
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("erro ao decodificar %T: %v", dto, err)
+	}
+
+	keys := make(map[string]bool)
+	collectKeys(decoded, keys)
+	return keys
+}
+
+func TestResponseDTOsUseCamelCaseKeys(t *testing.T) {
+	for _, dto := range responseDTOs {
+		name := reflect.TypeOf(dto).Elem().Name()
+		t.Run(name, func(t *testing.T) {
+			for key := range marshalKeys(t, dto) {
+				if key == "valor" {
+					// chave preenchida em campos do tipo map, não vem de tag json
+					continue
+				}
+				if !camelCaseKey.MatchString(key) {
+					t.Errorf("chave %q fora do padrão camelCase", key)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseDTOsExpectedKeys(t *testing.T) {
+	tests := []struct {
+		dto  interface{}
+		keys []string
+	}{
+		{&SendTextMessageResponse{}, []string{"success", "messageId", "timestamp", "details", "phone"}},
+		{&MessageErrorResponse{}, []string{"error", "message", "code", "details", "errors", "timestamp"}},
+		{&SessionConnectData{}, []string{"details", "events"}},
+		{&SessionDisconnectData{}, []string{"details"}},
+		{&QRCodeData{}, []string{"qrCode", "expiresIn"}},
+		{&QRCodeResponse{}, []string{"sessionId", "qrCode", "expiresIn", "qrExpiresAt"}},
+		{&PairPhoneResponse{}, []string{"session", "code", "formattedCode", "expiresIn", "expiresAt", "message", "success"}},
+	}
+
+	for _, tt := range tests {
+		name := reflect.TypeOf(tt.dto).Elem().Name()
+		t.Run(name, func(t *testing.T) {
+			keys := marshalKeys(t, tt.dto)
+			for _, key := range tt.keys {
+				if !keys[key] {
+					t.Errorf("chave %q ausente", key)
+				}
+			}
+		})
+	}
+}
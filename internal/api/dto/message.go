@@ -2,42 +2,178 @@ package dto
 
 import (
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"mime"
+	"net/url"
+	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
 	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type SendTextMessageRequest struct {
-	Phone       string             `json:"phone" validate:"required,min=10,max=20" example:"5511999999999" binding:"required"`           // Número do telefone destinatário
-	Message     string             `json:"message" validate:"required,min=1,max=4096" example:"Olá, como você está?" binding:"required"` // Conteúdo da mensagem
-	ID          string             `json:"id,omitempty" example:"custom-message-id"`                                                     // ID personalizado da mensagem (opcional)
-	ContextInfo *waE2E.ContextInfo `json:"contextInfo,omitempty"`                                                                        // Informações de contexto para replies e mentions (opcional)
+	Phone            string             `json:"phone" validate:"required,min=10,max=20" example:"5511999999999" binding:"required"`           // Número do telefone destinatário
+	Message          string             `json:"message" validate:"required,min=1,max=4096" example:"Olá, como você está?" binding:"required"` // Conteúdo da mensagem
+	ID               string             `json:"id,omitempty" example:"custom-message-id"`                                                     // ID personalizado da mensagem (opcional); também usado como chave de idempotência
+	ContextInfo      *waE2E.ContextInfo `json:"contextInfo,omitempty"`                                                                        // Informações de contexto para replies e mentions (opcional)
+	RequestReceipts  bool               `json:"requestReceipts,omitempty" example:"false"`                                                    // Registra a mensagem para recibos detalhados por participante antes do envio (opcional)
+	ReplyTo          *ReplyTo           `json:"replyTo,omitempty"`                                                                            // Mensagem citada na resposta (opcional)
+	Variables        map[string]string  `json:"variables,omitempty"`                                                                          // Valores substituídos nos marcadores {{nome}} da mensagem (opcional)
+	LinkPreview      bool               `json:"linkPreview,omitempty" example:"false"`                                                        // Gera a prévia da primeira URL da mensagem (opcional)
+	ResolveRecipient bool               `json:"resolveRecipient,omitempty" example:"false"`                                                   // Consulta o destinatário após o envio e retorna o nome verificado de contas comerciais (opcional)
+	Expiration       string             `json:"expiration,omitempty" example:"7d"`                                                            // Envia como mensagem temporária (24h, 7d ou 90d); use a duração configurada no chat (opcional)
+}
+
+// RecipientInfo descreve o destinatário consultado após o envio quando resolveRecipient é informado
+type RecipientInfo struct {
+	JID          string `json:"jid" example:"5511999999999@s.whatsapp.net"`    // JID do destinatário
+	IsBusiness   bool   `json:"isBusiness" example:"true"`                     // Indica se o destinatário é uma conta comercial com nome verificado
+	VerifiedName string `json:"verifiedName,omitempty" example:"Loja Exemplo"` // Nome verificado da conta comercial
+}
+
+// MaxBulkTextRecipients limita a quantidade de destinatários por requisição de envio em lote
+const MaxBulkTextRecipients = 100
+
+type SendBulkTextRequest struct {
+	Message string   `json:"message" validate:"required,min=1,max=4096" example:"Olá, como você está?" binding:"required"`      // Texto enviado a todos os destinatários
+	Phones  []string `json:"phones" validate:"required,min=1,max=100" example:"5511999999999,5511888888888" binding:"required"` // Números ou JIDs dos destinatários (até 100)
+}
+
+type BulkTextResult struct {
+	Phone     string `json:"phone" example:"5511999999999"`                          // Destinatário informado
+	Success   bool   `json:"success" example:"true"`                                 // Indica se o envio foi bem-sucedido
+	MessageID string `json:"messageId,omitempty" example:"3EB0C431C26A1916EA9A_out"` // ID da mensagem enviada
+	Timestamp int64  `json:"timestamp,omitempty" example:"1640995200"`               // Timestamp do envio
+	Error     string `json:"error,omitempty" example:"limite de envio atingido"`     // Motivo da falha
+}
+
+type SendBulkTextResponse struct {
+	Total     int              `json:"total" example:"2"`     // Total de destinatários processados
+	Succeeded int              `json:"succeeded" example:"1"` // Envios bem-sucedidos
+	Failed    int              `json:"failed" example:"1"`    // Envios com falha
+	Results   []BulkTextResult `json:"results"`               // Resultado por destinatário, na ordem recebida
+}
+
+// NormalizePhones remove espaços, destinatários vazios e repetidos, preservando a ordem
+func (req *SendBulkTextRequest) NormalizePhones() []string {
+	seen := make(map[string]bool, len(req.Phones))
+	phones := make([]string, 0, len(req.Phones))
+
+	for _, phone := range req.Phones {
+		phone = strings.TrimSpace(phone)
+		if phone == "" || seen[phone] {
+			continue
+		}
+		seen[phone] = true
+		phones = append(phones, phone)
+	}
+
+	return phones
+}
+
+// ReplyTo identifica a mensagem citada em uma resposta. Quando remoteJid aponta para outra
+// conversa, a citação referencia a mensagem original naquela conversa.
+type ReplyTo struct {
+	MessageID   string `json:"messageId" example:"3EB0C431C26A1916EA9A"`                     // ID da mensagem citada
+	Participant string `json:"participant,omitempty" example:"5511999999999@s.whatsapp.net"` // Autor da mensagem citada (obrigatório em grupos e citações de outra conversa)
+	RemoteJID   string `json:"remoteJid,omitempty" example:"120363000000000000@g.us"`        // Conversa de origem da mensagem citada (padrão: conversa do destinatário)
+	Text        string `json:"text,omitempty" example:"Mensagem original"`                   // Texto exibido na citação (opcional)
 }
 
 type SendTextMessageResponse struct {
-	Success   bool   `json:"success" example:"true"`                         // Indica se o envio foi bem-sucedido
-	MessageID string `json:"messageId" example:"3EB0C431C26A1916EA9A_out"`   // ID da mensagem enviada
-	Timestamp int64  `json:"timestamp" example:"1640995200"`                 // Timestamp do envio
-	Details   string `json:"details" example:"Mensagem enviada com sucesso"` // Detalhes do envio
-	Phone     string `json:"phone" example:"5511999999999"`                  // Número do telefone destinatário
+	Success           bool           `json:"success" example:"true"`                         // Indica se o envio foi bem-sucedido
+	MessageID         string         `json:"messageId" example:"3EB0C431C26A1916EA9A_out"`   // ID da mensagem enviada
+	Timestamp         int64          `json:"timestamp" example:"1640995200"`                 // Timestamp do envio
+	Details           string         `json:"details" example:"Mensagem enviada com sucesso"` // Detalhes do envio
+	Phone             string         `json:"phone" example:"5511999999999"`                  // Número do telefone destinatário
+	ReceiptsRequested bool           `json:"receiptsRequested,omitempty" example:"false"`    // Indica se a mensagem foi enviada com recibos detalhados
+	RenderedText      string         `json:"renderedText,omitempty" example:"Olá, Maria!"`   // Texto enviado após a substituição das variáveis
+	LinkPreview       bool           `json:"linkPreview,omitempty" example:"true"`           // Indica se a prévia do link foi anexada à mensagem
+	Recipient         *RecipientInfo `json:"recipient,omitempty"`                            // Destinatário consultado, quando resolveRecipient é informado
 }
 
 type MessageErrorResponse struct {
-	Error     bool   `json:"error" example:"true"`                                                               // Indica que houve erro
-	Message   string `json:"message" example:"Sessão não conectada"`                                             // Mensagem de erro
-	Code      int    `json:"code" example:"400"`                                                                 // Código HTTP do erro
-	Details   string `json:"details,omitempty" example:"A sessão precisa estar conectada para enviar mensagens"` // Detalhes adicionais do erro
-	Timestamp int64  `json:"timestamp" example:"1640995200"`                                                     // Timestamp do erro
+	Error     bool              `json:"error" example:"true"`                                                               // Indica que houve erro
+	Message   string            `json:"message" example:"Sessão não conectada"`                                             // Mensagem de erro
+	Code      int               `json:"code" example:"400"`                                                                 // Código HTTP do erro
+	Details   string            `json:"details,omitempty" example:"A sessão precisa estar conectada para enviar mensagens"` // Detalhes adicionais do erro
+	Errors    []ValidationError `json:"errors,omitempty"`                                                                   // Erros de validação por campo, quando o corpo da requisição é inválido
+	Timestamp int64             `json:"timestamp" example:"1640995200"`                                                     // Timestamp do erro
 }
 
-type MessageStatusResponse struct {
-	MessageID string `json:"messageId" example:"3EB0C431C26A1916EA9A_out"` // ID da mensagem
-	Status    string `json:"status" example:"sent"`                        // Status da mensagem (sent, delivered, read)
-	Timestamp int64  `json:"timestamp" example:"1640995200"`               // Timestamp da última atualização
-	Phone     string `json:"phone" example:"5511999999999"`                // Número do telefone destinatário
+// MaxTextMessageLength é o tamanho máximo do texto enviado, já com as variáveis substituídas
+const MaxTextMessageLength = 4096
+
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// RenderMessage substitui os marcadores {{nome}} da mensagem pelos valores de Variables
+func (req *SendTextMessageRequest) RenderMessage() (string, error) {
+	return RenderTemplate(req.Message, req.Variables)
+}
+
+// RenderTemplate substitui os marcadores {{nome}} do texto pelos valores informados.
+// A substituição é puramente textual: valores não são reinterpretados como marcadores e
+// todas as variáveis referenciadas precisam ter sido informadas. Sem variáveis o texto é
+// retornado sem alterações.
+func RenderTemplate(message string, variables map[string]string) (string, error) {
+	if len(variables) == 0 {
+		return message, nil
+	}
+
+	var missing []string
+	for _, match := range templateVariablePattern.FindAllStringSubmatch(message, -1) {
+		name := match[1]
+		if _, ok := variables[name]; !ok && !containsString(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("variáveis não informadas: %s", strings.Join(missing, ", "))
+	}
+
+	rendered := templateVariablePattern.ReplaceAllStringFunc(message, func(placeholder string) string {
+		name := templateVariablePattern.FindStringSubmatch(placeholder)[1]
+		return variables[name]
+	})
+
+	if strings.TrimSpace(rendered) == "" {
+		return "", errors.New("a mensagem ficou vazia após a substituição das variáveis")
+	}
+	if len(rendered) > MaxTextMessageLength {
+		return "", fmt.Errorf("a mensagem excede %d caracteres após a substituição das variáveis", MaxTextMessageLength)
+	}
+
+	return rendered, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNewsletterJID indica se o destinatário é o JID de um canal (ex: 120363000000000000@newsletter)
+func IsNewsletterJID(value string) bool {
+	user, found := strings.CutSuffix(value, "@"+types.NewsletterServer)
+	if !found || user == "" {
+		return false
+	}
+
+	for _, char := range user {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (req *SendTextMessageRequest) ValidatePhoneNumber() bool {
@@ -46,6 +182,10 @@ func (req *SendTextMessageRequest) ValidatePhoneNumber() bool {
 		return false
 	}
 
+	if IsNewsletterJID(phone) {
+		return true
+	}
+
 	phone = strings.TrimPrefix(phone, "+")
 
 	for _, char := range phone {
@@ -62,24 +202,80 @@ func (req *SendTextMessageRequest) ValidatePhoneNumber() bool {
 }
 
 type SendMediaRequest struct {
-	Phone       string             `json:"phone" validate:"required,min=10,max=20" example:"5511999999999" binding:"required"` // Número do telefone destinatário
-	MediaType   string             `json:"mediaType" validate:"required" example:"image" binding:"required"`                   // Tipo de mídia: image, audio, video, document
-	MediaData   string             `json:"mediaData" validate:"required" example:"base64_encoded_data" binding:"required"`     // Dados da mídia em base64
-	FileName    string             `json:"fileName,omitempty" example:"documento.pdf"`                                         // Nome do arquivo (opcional)
-	Caption     string             `json:"caption,omitempty" example:"Legenda da mídia"`                                       // Legenda da mídia (opcional)
-	MimeType    string             `json:"mimeType,omitempty" example:"image/jpeg"`                                            // Tipo MIME (opcional, será detectado automaticamente)
-	ID          string             `json:"id,omitempty" example:"custom-message-id"`                                           // ID personalizado da mensagem (opcional)
-	ContextInfo *waE2E.ContextInfo `json:"contextInfo,omitempty"`                                                              // Informações de contexto para replies e mentions (opcional)
+	Phone            string             `json:"phone" validate:"required,min=10,max=20" example:"5511999999999" binding:"required"` // Número do telefone destinatário
+	MediaType        string             `json:"mediaType" validate:"required" example:"image" binding:"required"`                   // Tipo de mídia: image, audio, video, document
+	MediaData        string             `json:"mediaData,omitempty" example:"base64_encoded_data"`                                  // Dados da mídia em base64 (obrigatório quando mediaUrl não é informado)
+	MediaURL         string             `json:"mediaUrl,omitempty" example:"https://example.com/imagem.jpg"`                        // URL para baixar a mídia (usada quando mediaData está vazio)
+	FileName         string             `json:"fileName,omitempty" example:"documento.pdf"`                                         // Nome do arquivo (opcional)
+	Caption          string             `json:"caption,omitempty" example:"Legenda da mídia"`                                       // Legenda da mídia (opcional)
+	MimeType         string             `json:"mimeType,omitempty" example:"image/jpeg"`                                            // Tipo MIME (opcional, será detectado automaticamente)
+	ID               string             `json:"id,omitempty" example:"custom-message-id"`                                           // ID personalizado da mensagem (opcional)
+	ContextInfo      *waE2E.ContextInfo `json:"contextInfo,omitempty"`                                                              // Informações de contexto para replies e mentions (opcional)
+	RequestReceipts  bool               `json:"requestReceipts,omitempty" example:"false"`                                          // Registra a mensagem para recibos detalhados por participante antes do envio (opcional)
+	PTT              bool               `json:"ptt,omitempty" example:"false"`                                                      // Envia o áudio como mensagem de voz (push-to-talk) (opcional)
+	Seconds          uint32             `json:"seconds,omitempty" example:"12"`                                                     // Duração do áudio em segundos (opcional)
+	Waveform         []byte             `json:"waveform,omitempty" swaggertype:"string" format:"base64"`                            // Forma de onda da mensagem de voz em base64, até 64 amostras (opcional)
+	ViewOnce         bool               `json:"viewOnce,omitempty" example:"false"`                                                 // Envia a mensagem de voz como visualização única; exige ptt (opcional)
+	ReplyTo          *ReplyTo           `json:"replyTo,omitempty"`                                                                  // Mensagem citada na resposta (opcional)
+	ResolveRecipient bool               `json:"resolveRecipient,omitempty" example:"false"`                                         // Consulta o destinatário após o envio e retorna o nome verificado de contas comerciais (opcional)
 }
 
+// PTTMimeType é o tipo MIME exigido pelo WhatsApp para mensagens de voz
+const PTTMimeType = "audio/ogg; codecs=opus"
+
+// MaxWaveformSamples é o número máximo de amostras aceitas na forma de onda de mensagens de voz
+const MaxWaveformSamples = 64
+
 type SendMediaResponse struct {
-	Success   bool   `json:"success" example:"true"`                       // Indica se o envio foi bem-sucedido
-	MessageID string `json:"messageId" example:"3EB0C431C26A1916EA9A_out"` // ID da mensagem enviada
-	Timestamp int64  `json:"timestamp" example:"1640995200"`               // Timestamp do envio
-	Details   string `json:"details" example:"Mídia enviada com sucesso"`  // Detalhes da operação
-	Phone     string `json:"phone" example:"5511999999999"`                // Número do telefone destinatário
-	MediaType string `json:"mediaType" example:"image"`                    // Tipo de mídia enviada
-	FileName  string `json:"fileName,omitempty" example:"imagem.jpg"`      // Nome do arquivo enviado
+	Success           bool           `json:"success" example:"true"`                       // Indica se o envio foi bem-sucedido
+	MessageID         string         `json:"messageId" example:"3EB0C431C26A1916EA9A_out"` // ID da mensagem enviada
+	Timestamp         int64          `json:"timestamp" example:"1640995200"`               // Timestamp do envio
+	Details           string         `json:"details" example:"Mídia enviada com sucesso"`  // Detalhes da operação
+	Phone             string         `json:"phone" example:"5511999999999"`                // Número do telefone destinatário
+	MediaType         string         `json:"mediaType" example:"image"`                    // Tipo de mídia enviada
+	FileName          string         `json:"fileName,omitempty" example:"imagem.jpg"`      // Nome do arquivo enviado
+	ReceiptsRequested bool           `json:"receiptsRequested,omitempty" example:"false"`  // Indica se a mensagem foi enviada com recibos detalhados
+	ViewOnce          bool           `json:"viewOnce,omitempty" example:"false"`           // Indica se a mensagem foi enviada como visualização única
+	Recipient         *RecipientInfo `json:"recipient,omitempty"`                          // Destinatário consultado, quando resolveRecipient é informado
+}
+
+// StickerMimeType é o único formato aceito pelo WhatsApp para figurinhas
+const StickerMimeType = "image/webp"
+
+type SendStickerRequest struct {
+	Phone       string             `json:"phone" validate:"required" example:"5511999999999" binding:"required"` // Número do telefone ou JID do grupo destinatário
+	StickerData string             `json:"stickerData,omitempty" example:"base64_encoded_webp"`                  // Figurinha WebP em base64 (obrigatório quando stickerUrl não é informado)
+	StickerURL  string             `json:"stickerUrl,omitempty" example:"https://example.com/figurinha.webp"`    // URL para baixar a figurinha WebP (usada quando stickerData está vazio)
+	ID          string             `json:"id,omitempty" example:"custom-message-id"`                             // ID personalizado da mensagem (opcional)
+	ContextInfo *waE2E.ContextInfo `json:"contextInfo,omitempty"`                                                // Informações de contexto para replies e mentions (opcional)
+	ReplyTo     *ReplyTo           `json:"replyTo,omitempty"`                                                    // Mensagem citada na resposta (opcional)
+}
+
+type SendStickerResponse struct {
+	Success   bool   `json:"success" example:"true"`                          // Indica se o envio foi bem-sucedido
+	MessageID string `json:"messageId" example:"3EB0C431C26A1916EA9A_out"`    // ID da mensagem enviada
+	Timestamp int64  `json:"timestamp" example:"1640995200"`                  // Timestamp do envio
+	Details   string `json:"details" example:"Figurinha enviada com sucesso"` // Detalhes da operação
+	Phone     string `json:"phone" example:"5511999999999"`                   // Destinatário
+	Width     uint32 `json:"width" example:"512"`                             // Largura da figurinha em pixels
+	Height    uint32 `json:"height" example:"512"`                            // Altura da figurinha em pixels
+	Animated  bool   `json:"animated" example:"false"`                        // Indica se a figurinha é animada
+}
+
+func (req *SendStickerRequest) Validate() error {
+	if strings.TrimSpace(req.Phone) == "" {
+		return errors.New("o campo 'phone' é obrigatório")
+	}
+
+	if req.StickerData == "" && req.StickerURL == "" {
+		return errors.New("o campo 'stickerData' ou 'stickerUrl' deve ser fornecido")
+	}
+
+	if req.StickerData == "" && !IsHTTPURL(req.StickerURL) {
+		return errors.New("a URL da figurinha deve usar o esquema http ou https")
+	}
+
+	return nil
 }
 
 func (req *SendMediaRequest) ValidateMediaType() bool {
@@ -101,7 +297,70 @@ func (req *SendMediaRequest) ValidateMediaData() bool {
 	return err == nil
 }
 
+func (req *SendMediaRequest) ValidateMediaURL() bool {
+	return IsHTTPURL(req.MediaURL)
+}
+
+// IsHTTPURL verifica se o valor é uma URL absoluta http ou https
+func IsHTTPURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// MatchesContentType verifica se o content-type retornado pela URL corresponde ao mediaType declarado
+func (req *SendMediaRequest) MatchesContentType(contentType string) bool {
+	return MediaMatchesContentType(req.MediaType, contentType)
+}
+
+// MediaMatchesContentType verifica se o content-type corresponde ao tipo de mídia
+// (image, audio, video, document ou sticker)
+func MediaMatchesContentType(mediaKind, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(mediaKind) {
+	case "sticker":
+		return mediaType == StickerMimeType
+	case "image":
+		return strings.HasPrefix(mediaType, "image/")
+	case "audio":
+		return strings.HasPrefix(mediaType, "audio/")
+	case "video":
+		return strings.HasPrefix(mediaType, "video/")
+	case "document":
+		return true
+	default:
+		return false
+	}
+}
+
+func (req *SendMediaRequest) ValidateVoiceNote() error {
+	if req.PTT && strings.ToLower(req.MediaType) != "audio" {
+		return errors.New("o campo 'ptt' só pode ser usado com mediaType audio")
+	}
+
+	if req.ViewOnce && !req.PTT {
+		return errors.New("o campo 'viewOnce' só é suportado em mensagens de voz (audio com ptt)")
+	}
+
+	if len(req.Waveform) > MaxWaveformSamples {
+		return fmt.Errorf("o campo 'waveform' deve ter no máximo %d amostras", MaxWaveformSamples)
+	}
+
+	return nil
+}
+
 func (req *SendMediaRequest) GetMimeType() string {
+	if req.PTT {
+		return PTTMimeType
+	}
+
 	if req.MimeType != "" {
 		return req.MimeType
 	}
@@ -133,6 +392,14 @@ func (req *SendMediaRequest) GetFileName() string {
 		return req.FileName
 	}
 
+	if req.MediaURL != "" {
+		if parsed, err := url.Parse(req.MediaURL); err == nil {
+			if base := path.Base(parsed.Path); filepath.Ext(base) != "" {
+				return base
+			}
+		}
+	}
+
 	switch strings.ToLower(req.MediaType) {
 	case "image":
 		return "image.jpg"
@@ -153,6 +420,10 @@ func (req *SendMediaRequest) ValidatePhoneNumber() bool {
 		return false
 	}
 
+	if IsNewsletterJID(phone) {
+		return true
+	}
+
 	phone = strings.TrimPrefix(phone, "+")
 
 	for _, char := range phone {
@@ -168,6 +439,131 @@ func (req *SendMediaRequest) ValidatePhoneNumber() bool {
 	return true
 }
 
+type DownloadMediaRequest struct {
+	URL           string `json:"url,omitempty" example:"https://mmg.whatsapp.net/v/t62.7118-24/..."`    // URL da mídia (opcional quando directPath é informado)
+	DirectPath    string `json:"directPath" example:"/v/t62.7118-24/..."`                               // Caminho direto da mídia
+	MediaKey      []byte `json:"mediaKey" swaggertype:"string" format:"base64" binding:"required"`      // Chave da mídia em base64
+	FileEncSHA256 []byte `json:"fileEncSHA256" swaggertype:"string" format:"base64" binding:"required"` // SHA256 do arquivo criptografado em base64
+	FileSHA256    []byte `json:"fileSHA256" swaggertype:"string" format:"base64" binding:"required"`    // SHA256 do arquivo em base64
+	FileLength    uint64 `json:"fileLength" example:"12345"`                                            // Tamanho do arquivo em bytes
+	MediaType     string `json:"mediaType" validate:"required" example:"image" binding:"required"`      // Tipo de mídia: image, audio, video, document, sticker
+	MimeType      string `json:"mimeType,omitempty" example:"image/jpeg"`                               // Tipo MIME recebido no evento (opcional)
+}
+
+type DownloadMediaResponse struct {
+	Success   bool   `json:"success" example:"true"`             // Indica se o download foi bem-sucedido
+	MediaType string `json:"mediaType" example:"image"`          // Tipo de mídia baixada
+	MimeType  string `json:"mimeType" example:"image/jpeg"`      // Tipo MIME detectado
+	Size      int    `json:"size" example:"12345"`               // Tamanho em bytes
+	Data      string `json:"data" example:"base64_encoded_data"` // Conteúdo da mídia em base64
+}
+
+func (req *DownloadMediaRequest) ValidateMediaType() bool {
+	supportedTypes := map[string]bool{
+		"image":    true,
+		"audio":    true,
+		"video":    true,
+		"document": true,
+		"sticker":  true,
+	}
+	return supportedTypes[strings.ToLower(req.MediaType)]
+}
+
+type EditMessageRequest struct {
+	Phone   string `json:"phone" validate:"required,min=10,max=20" example:"5511999999999" binding:"required"`      // Número do telefone (ou JID do grupo) da conversa
+	Message string `json:"message" validate:"required,min=1,max=4096" example:"Texto corrigido" binding:"required"` // Novo conteúdo da mensagem
+}
+
+// ForwardMessageRequest identifica a mensagem de origem e o destino do encaminhamento. O conteúdo
+// original é lido de message; sem ele, apenas textos com histórico de edição podem ser reconstruídos.
+type ForwardMessageRequest struct {
+	Phone     string         `json:"phone" validate:"required,min=10,max=20" example:"5511999999999" binding:"required"` // Número do telefone (ou JID do grupo) de destino
+	Chat      string         `json:"chat" example:"5511888888888@s.whatsapp.net" binding:"required"`                     // Conversa de origem da mensagem
+	MessageID string         `json:"messageId" example:"3EB0C431C26A1916EA9A" binding:"required"`                        // ID da mensagem original
+	FromMe    bool           `json:"fromMe" example:"false"`                                                             // Indica se a mensagem original foi enviada por esta sessão
+	Message   *waE2E.Message `json:"message,omitempty"`                                                                  // Payload bruto da mensagem original, como recebido no webhook (opcional)
+	ID        string         `json:"id,omitempty" example:"custom-message-id"`                                           // ID personalizado da mensagem encaminhada (opcional)
+}
+
+type ResolveJIDResponse struct {
+	Phone           string   `json:"phone" example:"+55 11999999999"`                                // Valor informado no parâmetro phone
+	NormalizedPhone string   `json:"normalizedPhone" example:"5511999999999"`                        // Valor após a normalização aplicada nos envios
+	JID             string   `json:"jid" example:"5511999999999@s.whatsapp.net"`                     // JID que os envios usariam como destino
+	Server          string   `json:"server" example:"s.whatsapp.net"`                                // Servidor do JID resolvido
+	Checked         bool     `json:"checked" example:"true"`                                         // Indica se o número foi consultado no WhatsApp
+	Registered      *bool    `json:"registered,omitempty" example:"true"`                            // Número está registrado no WhatsApp (somente com check=true)
+	RegisteredJID   string   `json:"registeredJid,omitempty" example:"5511999999999@s.whatsapp.net"` // JID canônico retornado pelo WhatsApp (somente com check=true)
+	Warnings        []string `json:"warnings"`                                                       // Possíveis problemas de formatação do número
+}
+
+type MessageEditResponse struct {
+	Content   string `json:"content" example:"Texto corrigido"`                          // Conteúdo da versão
+	EditedAt  int64  `json:"editedAt" example:"1640995200"`                              // Timestamp da edição
+	IsFromMe  bool   `json:"isFromMe" example:"true"`                                    // Indica se a edição foi feita por esta conta
+	SenderJID string `json:"senderJid,omitempty" example:"5511999999999@s.whatsapp.net"` // JID de quem editou
+	Source    string `json:"source" example:"api"`                                       // Origem do registro: api ou event
+}
+
+type MessageEditHistoryResponse struct {
+	MessageID string                `json:"messageId" example:"3EB0C431C26A1916EA9A"`                 // ID da mensagem original
+	ChatJID   string                `json:"chatJid,omitempty" example:"5511999999999@s.whatsapp.net"` // JID da conversa
+	Edits     []MessageEditResponse `json:"edits"`                                                    // Versões em ordem cronológica
+	Total     int                   `json:"total" example:"2"`                                        // Quantidade de edições
+}
+
+// StoredMessageResponse é uma mensagem armazenada; sentAt é um timestamp Unix em segundos
+type StoredMessageResponse struct {
+	MessageID string `json:"messageId" example:"3EB0C431C26A1916EA9A"`                   // ID da mensagem
+	ChatJID   string `json:"chatJid" example:"5511999999999@s.whatsapp.net"`             // JID da conversa
+	SenderJID string `json:"senderJid,omitempty" example:"5511999999999@s.whatsapp.net"` // JID do autor
+	IsFromMe  bool   `json:"isFromMe" example:"false"`                                   // Indica se a mensagem foi enviada por esta conta
+	IsGroup   bool   `json:"isGroup" example:"false"`                                    // Indica se a conversa é um grupo
+	Type      string `json:"type" example:"text"`                                        // Tipo da mensagem (text, image, video...)
+	Text      string `json:"text,omitempty" example:"Olá!"`                              // Texto ou legenda da mensagem
+	SentAt    int64  `json:"sentAt" example:"1640995200"`                                // Horário da mensagem
+}
+
+type StoredMessagesResponse struct {
+	Messages     []StoredMessageResponse `json:"messages"`                                              // Mensagens da mais recente para a mais antiga
+	Total        int                     `json:"total" example:"50"`                                    // Quantidade de mensagens retornadas
+	NextBefore   int64                   `json:"nextBefore,omitempty" example:"1640990000"`             // Valor de before para a próxima página; ausente na última
+	NextBeforeID string                  `json:"nextBeforeId,omitempty" example:"3EB0C431C26A1916EA9A"` // Valor de beforeId para a próxima página
+}
+
+type MessageReaderResponse struct {
+	ReaderJID string `json:"readerJid" example:"5511999999999@s.whatsapp.net"` // JID do participante
+	ReadAt    int64  `json:"readAt,omitempty" example:"1640995200"`            // Timestamp da leitura
+	PlayedAt  int64  `json:"playedAt,omitempty" example:"1640995260"`          // Timestamp da reprodução (áudio e vídeo)
+}
+
+type MessageReadersResponse struct {
+	MessageID string                  `json:"messageId" example:"3EB0C431C26A1916EA9A"`            // ID da mensagem
+	ChatJID   string                  `json:"chatJid,omitempty" example:"120363025246125888@g.us"` // JID do grupo
+	Readers   []MessageReaderResponse `json:"readers"`                                             // Participantes na ordem em que leram
+	Total     int                     `json:"total" example:"3"`                                   // Quantidade de participantes
+}
+
+type MessageStatusResponse struct {
+	MessageID   string `json:"messageId" example:"3EB0C431C26A1916EA9A"`       // ID da mensagem
+	ChatJID     string `json:"chatJid" example:"5511999999999@s.whatsapp.net"` // Conversa da mensagem
+	Status      string `json:"status" example:"read"`                          // Último estado conhecido: sent, delivered, read ou played
+	SentAt      int64  `json:"sentAt,omitempty" example:"1640995190"`          // Timestamp do ack do servidor (apenas com requestReceipts)
+	DeliveredAt int64  `json:"deliveredAt,omitempty" example:"1640995200"`     // Timestamp da entrega
+	ReadAt      int64  `json:"readAt,omitempty" example:"1640995260"`          // Timestamp da leitura
+	PlayedAt    int64  `json:"playedAt,omitempty" example:"1640995300"`        // Timestamp da reprodução (mídias de visualização única e áudios)
+	UpdatedAt   int64  `json:"updatedAt" example:"1640995300"`                 // Quando o último recibo foi processado
+
+	Participants []MessageParticipantStatus `json:"participants,omitempty"` // Recibos de cada destinatário (apenas com requestReceipts)
+}
+
+// MessageParticipantStatus traz os recibos de um destinatário da mensagem
+type MessageParticipantStatus struct {
+	JID         string `json:"jid" example:"5511999999999@s.whatsapp.net"` // Destinatário
+	DeliveredAt int64  `json:"deliveredAt,omitempty" example:"1640995200"` // Timestamp da entrega
+	ReadAt      int64  `json:"readAt,omitempty" example:"1640995260"`      // Timestamp da leitura
+	PlayedAt    int64  `json:"playedAt,omitempty" example:"1640995300"`    // Timestamp da reprodução
+}
+
 func ToMessageErrorResponse(code int, message string, details string) *MessageErrorResponse {
 	return &MessageErrorResponse{
 		Error:     true,
@@ -178,6 +574,13 @@ func ToMessageErrorResponse(code int, message string, details string) *MessageEr
 	}
 }
 
+// ToMessageValidationErrorResponse monta a resposta 400 com os erros de validação por campo
+func ToMessageValidationErrorResponse(details string, fieldErrors []ValidationError) *MessageErrorResponse {
+	response := ToMessageErrorResponse(400, "Dados inválidos", details)
+	response.Errors = fieldErrors
+	return response
+}
+
 func ToMessageSuccessResponse(messageID, phone string) *SendTextMessageResponse {
 	return &SendTextMessageResponse{
 		Success:   true,
@@ -0,0 +1,23 @@
+package dto
+
+type WebhookMetricsResponse struct {
+	QueueSize      int   `json:"queueSize" example:"0"`          // Entregas aguardando na fila
+	TotalSent      int64 `json:"totalSent" example:"120"`        // Entregas enfileiradas
+	TotalSuccess   int64 `json:"totalSuccess" example:"118"`     // Entregas concluídas com sucesso
+	TotalFailed    int64 `json:"totalFailed" example:"2"`        // Entregas que falharam definitivamente
+	TotalRetries   int64 `json:"totalRetries" example:"5"`       // Novas tentativas agendadas
+	TotalDropped   int64 `json:"totalDropped" example:"0"`       // Entregas descartadas por falta de vaga na fila
+	AverageLatency int64 `json:"averageLatencyMs" example:"150"` // Latência média em milissegundos
+}
+
+type SessionMetricsResponse struct {
+	Loaded    int            `json:"loaded" example:"3"`    // Clientes carregados no gerenciador
+	Connected int            `json:"connected" example:"2"` // Clientes com socket conectado
+	LoggedIn  int            `json:"loggedIn" example:"2"`  // Clientes autenticados
+	ByStatus  map[string]int `json:"byStatus"`              // Sessões cadastradas por status
+}
+
+type MetricsResponse struct {
+	Webhooks *WebhookMetricsResponse `json:"webhooks"`
+	Sessions *SessionMetricsResponse `json:"sessions"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+type NewsletterFollowRequest struct {
+	JID        string `json:"jid,omitempty" example:"120363000000000000@newsletter"`                    // JID do canal (obrigatório quando inviteCode não é informado)
+	InviteCode string `json:"inviteCode,omitempty" example:"https://whatsapp.com/channel/0029VaAbCdEf"` // Link do canal completo ou apenas o código
+}
+
+type NewsletterResponse struct {
+	JID             string     `json:"jid" example:"120363000000000000@newsletter"`        // JID do canal
+	Name            string     `json:"name" example:"Meu Canal"`                           // Nome do canal
+	Description     string     `json:"description,omitempty" example:"Novidades da loja"`  // Descrição do canal
+	InviteCode      string     `json:"inviteCode,omitempty" example:"0029VaAbCdEf"`        // Código do link do canal
+	SubscriberCount int        `json:"subscriberCount" example:"1500"`                     // Quantidade de seguidores
+	Verified        bool       `json:"verified" example:"false"`                           // Canal verificado pelo WhatsApp
+	State           string     `json:"state,omitempty" example:"active"`                   // Estado do canal
+	Role            string     `json:"role,omitempty" example:"subscriber"`                // Papel da sessão no canal: owner, admin, subscriber ou guest
+	Muted           bool       `json:"muted" example:"false"`                              // Indica se a sessão silenciou o canal
+	CreatedAt       *time.Time `json:"createdAt,omitempty" example:"2024-01-01T00:00:00Z"` // Data de criação
+}
+
+type NewsletterActionResponse struct {
+	Success    bool                `json:"success" example:"true"`                      // Indica se a operação foi bem-sucedida
+	Message    string              `json:"message" example:"Canal seguido"`             // Mensagem de confirmação
+	JID        string              `json:"jid" example:"120363000000000000@newsletter"` // JID do canal
+	Newsletter *NewsletterResponse `json:"newsletter,omitempty"`                        // Metadados do canal, quando disponíveis
+}
+
+func ToNewsletterResponse(meta *types.NewsletterMetadata) *NewsletterResponse {
+	if meta == nil {
+		return nil
+	}
+
+	response := &NewsletterResponse{
+		JID:             meta.ID.String(),
+		Name:            meta.ThreadMeta.Name.Text,
+		Description:     meta.ThreadMeta.Description.Text,
+		InviteCode:      meta.ThreadMeta.InviteCode,
+		SubscriberCount: meta.ThreadMeta.SubscriberCount,
+		Verified:        meta.ThreadMeta.VerificationState == types.NewsletterVerificationStateVerified,
+		State:           string(meta.State.Type),
+	}
+
+	if createdAt := meta.ThreadMeta.CreationTime.Time; !createdAt.IsZero() {
+		response.CreatedAt = &createdAt
+	}
+
+	if meta.ViewerMeta != nil {
+		response.Role = string(meta.ViewerMeta.Role)
+		response.Muted = meta.ViewerMeta.Mute == types.NewsletterMuteOn
+	}
+
+	return response
+}
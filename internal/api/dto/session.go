@@ -1,6 +1,9 @@
 package dto
 
 import (
+	"errors"
+	"regexp"
+	"strings"
 	"time"
 
 	"zpigo/internal/store/models"
@@ -10,25 +13,32 @@ type CreateSessionRequest struct {
 	Name string `json:"name" validate:"required,min=1,max=255" example:"Minha Sessão WhatsApp" binding:"required"` // Nome da sessão
 }
 
+type RenameSessionRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255" example:"Atendimento" binding:"required"` // Novo nome da sessão
+}
+
 type CreateSessionResponse struct {
 	Session *SessionResponse `json:"session"`                                     // Dados da sessão criada
 	Message string           `json:"message" example:"Sessão criada com sucesso"` // Mensagem de confirmação
 }
 
 type SessionResponse struct {
-	ID          string               `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`           // ID único da sessão
-	Name        string               `json:"name" example:"Minha Sessão WhatsApp"`                        // Nome da sessão
-	Phone       string               `json:"phone,omitempty" example:"5511999999999"`                     // Número do telefone conectado
-	Status      models.SessionStatus `json:"status" example:"disconnected"`                               // Status da sessão
-	QRCode      string               `json:"qrCode,omitempty" example:"data:image/png;base64,iVBORw0..."` // QR Code em base64
-	ProxyHost   string               `json:"proxyHost,omitempty" example:"proxy.example.com"`             // Host do proxy
-	ProxyPort   int                  `json:"proxyPort,omitempty" example:"8080"`                          // Porta do proxy
-	ProxyType   models.ProxyType     `json:"proxyType,omitempty" example:"http"`                          // Tipo do proxy
-	ProxyUser   string               `json:"proxyUser,omitempty" example:"usuario"`                       // Usuário do proxy
-	ProxyPass   string               `json:"proxyPass,omitempty" example:"senha"`                         // Senha do proxy
-	CreatedAt   time.Time            `json:"createdAt" example:"2023-01-01T00:00:00Z"`                    // Data de criação
-	UpdatedAt   time.Time            `json:"updatedAt" example:"2023-01-01T00:00:00Z"`                    // Data de atualização
-	ConnectedAt *time.Time           `json:"connectedAt,omitempty" example:"2023-01-01T00:00:00Z"`        // Data de conexão
+	ID            string               `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`           // ID único da sessão
+	Name          string               `json:"name" example:"Minha Sessão WhatsApp"`                        // Nome da sessão
+	Phone         string               `json:"phone,omitempty" example:"5511999999999"`                     // Número do telefone conectado
+	Status        models.SessionStatus `json:"status" example:"disconnected"`                               // Status da sessão
+	QRCode        string               `json:"qrCode,omitempty" example:"data:image/png;base64,iVBORw0..."` // QR Code em base64 (omitido quando expirado)
+	QRExpiresAt   *time.Time           `json:"qrExpiresAt,omitempty" example:"2023-01-01T00:01:00Z"`        // Validade do QR Code
+	ProxyHost     string               `json:"proxyHost,omitempty" example:"proxy.example.com"`             // Host do proxy
+	ProxyPort     int                  `json:"proxyPort,omitempty" example:"8080"`                          // Porta do proxy
+	ProxyType     models.ProxyType     `json:"proxyType,omitempty" example:"http"`                          // Tipo do proxy
+	ProxyAuth     bool                 `json:"proxyAuth,omitempty" example:"true"`                          // Indica se o proxy usa usuário e senha, que nunca são retornados
+	Platform      string               `json:"platform,omitempty" example:"DESKTOP"`                        // Plataforma informada no emparelhamento
+	OSName        string               `json:"osName,omitempty" example:"Mac OS"`                           // Nome do SO exibido no aparelho vinculado
+	StoreMessages bool                 `json:"storeMessages" example:"false"`                               // Indica se a sessão armazena as mensagens recebidas
+	CreatedAt     time.Time            `json:"createdAt" example:"2023-01-01T00:00:00Z"`                    // Data de criação
+	UpdatedAt     time.Time            `json:"updatedAt" example:"2023-01-01T00:00:00Z"`                    // Data de atualização
+	ConnectedAt   *time.Time           `json:"connectedAt,omitempty" example:"2023-01-01T00:00:00Z"`        // Data de conexão
 }
 
 type SessionListResponse struct {
@@ -57,21 +67,83 @@ type LogoutSessionResponse struct {
 	Message string           `json:"message"`
 }
 
+type LogoutAllResult struct {
+	SessionID string `json:"sessionId" example:"session-1"`                  // ID da sessão
+	Success   bool   `json:"success" example:"true"`                         // Indica se o logout foi realizado
+	Error     string `json:"error,omitempty" example:"erro ao fazer logout"` // Motivo da falha
+}
+
+type LogoutAllResponse struct {
+	Total     int               `json:"total" example:"2"`     // Sessões da API key processadas
+	Succeeded int               `json:"succeeded" example:"1"` // Logouts realizados
+	Failed    int               `json:"failed" example:"1"`    // Logouts com falha
+	Results   []LogoutAllResult `json:"results"`               // Resultado por sessão, ordenado pelo ID
+}
+
 type QRCodeResponse struct {
-	SessionID string `json:"sessionId"`
-	QRCode    string `json:"qrCode"`
-	ExpiresIn int    `json:"expiresIn"`
+	SessionID   string    `json:"sessionId"`
+	QRCode      string    `json:"qrCode"`
+	ExpiresIn   int       `json:"expiresIn"`   // Segundos até o código expirar
+	QRExpiresAt time.Time `json:"qrExpiresAt"` // Momento em que o código expira
 }
 
 type PairPhoneRequest struct {
-	PhoneNumber string `json:"phoneNumber" validate:"required,min=10,max=20"`
-	Code        string `json:"code" validate:"required,len=6"`
+	PhoneNumber       string `json:"phoneNumber" validate:"required,min=10,max=20" example:"5511999999999" binding:"required"` // Número em formato internacional, sem zero à esquerda
+	ClientType        string `json:"clientType,omitempty" example:"chrome"`                                                    // Tipo do cliente: chrome, edge, firefox, ie, opera, safari, electron, uwp ou other (padrão: chrome)
+	ClientDisplayName string `json:"clientDisplayName,omitempty" example:"Chrome (Linux)"`                                     // Nome exibido no celular, no formato "Navegador (SO)" (padrão: Chrome (Linux))
+}
+
+// clientDisplayNamePattern segue o formato "Navegador (SO)" exigido pelo WhatsApp
+var clientDisplayNamePattern = regexp.MustCompile(`^[^()]+ \([^()]+\)$`)
+
+// ValidatePhoneNumber verifica se o número está em formato internacional, aceitando o prefixo "+"
+func (req *PairPhoneRequest) ValidatePhoneNumber() error {
+	phone := strings.TrimPrefix(strings.TrimSpace(req.PhoneNumber), "+")
+
+	for _, char := range phone {
+		if char < '0' || char > '9' {
+			return errors.New("o número deve conter apenas dígitos, opcionalmente precedidos de '+'")
+		}
+	}
+
+	if len(phone) < 10 || len(phone) > 15 {
+		return errors.New("o número deve ter entre 10 e 15 dígitos")
+	}
+
+	if strings.HasPrefix(phone, "0") {
+		return errors.New("o número deve estar em formato internacional, iniciando pelo código do país")
+	}
+
+	return nil
+}
+
+func (req *PairPhoneRequest) ValidateClientDisplayName() error {
+	if req.ClientDisplayName == "" {
+		return nil
+	}
+
+	if !clientDisplayNamePattern.MatchString(req.ClientDisplayName) {
+		return errors.New("o campo 'clientDisplayName' deve seguir o formato 'Navegador (SO)', por exemplo 'Chrome (Linux)'")
+	}
+
+	return nil
+}
+
+// GetPhoneNumber retorna o número apenas com dígitos
+func (req *PairPhoneRequest) GetPhoneNumber() string {
+	return strings.TrimPrefix(strings.TrimSpace(req.PhoneNumber), "+")
 }
 
+// PairPhoneResponse traz o código exibido pela API para ser digitado no celular, em
+// Aparelhos conectados > Conectar com número de telefone
 type PairPhoneResponse struct {
-	Session *SessionResponse `json:"session"`
-	Message string           `json:"message"`
-	Success bool             `json:"success"`
+	Session       *SessionResponse `json:"session"`
+	Code          string           `json:"code" example:"ABCDEFGH"`                  // Código de vinculação sem formatação
+	FormattedCode string           `json:"formattedCode" example:"ABCD-EFGH"`        // Código agrupado como o WhatsApp exibe
+	ExpiresIn     int              `json:"expiresIn" example:"160"`                  // Segundos, no máximo, até o código expirar
+	ExpiresAt     time.Time        `json:"expiresAt" example:"2023-01-01T00:02:40Z"` // Momento máximo em que o código expira
+	Message       string           `json:"message"`
+	Success       bool             `json:"success"`
 }
 
 type SetProxyRequest struct {
@@ -80,6 +152,27 @@ type SetProxyRequest struct {
 	Type     models.ProxyType `json:"type" validate:"required"`
 	Username string           `json:"username,omitempty"`
 	Password string           `json:"password,omitempty"`
+	Validate bool             `json:"validate,omitempty"` // Verifica se o proxy está acessível antes de salvar, validando também as credenciais
+}
+
+type SetPlatformRequest struct {
+	Platform string `json:"platform" example:"DESKTOP"`        // Plataforma do DeviceProps do WhatsApp (ex: CHROME, FIREFOX, SAFARI, EDGE, DESKTOP, IPAD); vazio restaura o padrão
+	OSName   string `json:"osName,omitempty" example:"Mac OS"` // Nome do SO exibido no aparelho vinculado (opcional, até 100 caracteres)
+}
+
+type SetPlatformResponse struct {
+	Session *SessionResponse `json:"session"` // Sessão atualizada
+	Message string           `json:"message"` // Mensagem descritiva
+}
+
+type SetMessageStorageRequest struct {
+	Enabled *bool `json:"enabled" binding:"required" example:"true"` // Habilita ou desabilita o armazenamento das mensagens da sessão
+	Purge   bool  `json:"purge,omitempty" example:"false"`           // Apaga as mensagens já armazenadas da sessão
+}
+
+type SetMessageStorageResponse struct {
+	Session *SessionResponse `json:"session"` // Sessão atualizada
+	Message string           `json:"message"` // Mensagem descritiva
 }
 
 type SetProxyResponse struct {
@@ -88,8 +181,21 @@ type SetProxyResponse struct {
 }
 
 type DeleteSessionResponse struct {
-	Message string `json:"message"`
-	Success bool   `json:"success"`
+	Message string                  `json:"message"`
+	Success bool                    `json:"success"`
+	Cleanup *SessionCleanupResponse `json:"cleanup,omitempty"`
+}
+
+// SessionCleanupResponse informa o que foi removido junto com a sessão
+type SessionCleanupResponse struct {
+	BroadcastsStopped    int  `json:"broadcastsStopped" example:"0"`       // Broadcasts em execução interrompidos
+	LoggedOut            bool `json:"loggedOut" example:"true"`            // Logout feito no WhatsApp
+	DeviceDeleted        bool `json:"deviceDeleted" example:"true"`        // Device removido do container do whatsmeow
+	ManagerEntryRemoved  bool `json:"managerEntryRemoved" example:"true"`  // Cliente removido do gerenciador de sessões
+	WebhookConfigRemoved bool `json:"webhookConfigRemoved" example:"true"` // Configuração de webhook removida da memória
+	WebhooksDeleted      bool `json:"webhooksDeleted" example:"true"`      // Webhooks removidos do banco
+	MessageDataDeleted   bool `json:"messageDataDeleted" example:"true"`   // Histórico de edições, recibos, chats e mensagens removido
+	CacheEntriesEvicted  int  `json:"cacheEntriesEvicted" example:"1"`     // Entradas de cache removidas
 }
 
 type SessionStatusResponse struct {
@@ -98,10 +204,104 @@ type SessionStatusResponse struct {
 	LoggedIn  bool                 `json:"loggedIn"`
 	Status    models.SessionStatus `json:"status"`
 	Phone     string               `json:"phone,omitempty"`
+	DeviceJID string               `json:"deviceJid,omitempty"` // JID do dispositivo pareado
+	PushName  string               `json:"pushName,omitempty"`  // Nome de exibição da conta; omitido quando o cliente não está no gerenciador
 	HasProxy  bool                 `json:"hasProxy"`
 	Timestamp int64                `json:"timestamp"`
 }
 
+// Origem das informações do dispositivo
+const (
+	DeviceSourceLive   = "live"
+	DeviceSourceStored = "stored"
+)
+
+type DeviceInfoResponse struct {
+	SessionID    string `json:"sessionId" example:"session-1"`
+	Source       string `json:"source" example:"live"`                          // live quando lido do cliente ativo; stored quando vem apenas do banco
+	JID          string `json:"jid" example:"5511999999999:12@s.whatsapp.net"`  // JID do dispositivo pareado
+	LID          string `json:"lid,omitempty" example:"123456789012345:12@lid"` // LID do dispositivo, quando conhecido
+	Phone        string `json:"phone,omitempty" example:"5511999999999"`        // Número da conta
+	PushName     string `json:"pushName,omitempty" example:"Maria"`             // Nome de exibição da conta
+	Platform     string `json:"platform,omitempty" example:"android"`           // Plataforma do celular principal informada pelo WhatsApp
+	BusinessName string `json:"businessName,omitempty" example:"Loja Exemplo"`  // Nome comercial, em contas WhatsApp Business
+	PairedAt     *int64 `json:"pairedAt,omitempty" example:"1640995200"`        // Timestamp do emparelhamento do dispositivo
+}
+
+type SessionUptimeResponse struct {
+	SessionID          string     `json:"sessionId"`
+	Connected          bool       `json:"connected"`
+	ConnectedAt        *time.Time `json:"connectedAt,omitempty"`
+	LastDisconnectedAt *time.Time `json:"lastDisconnectedAt,omitempty"`
+	UptimeSeconds      int64      `json:"uptimeSeconds"`
+	Uptime             string     `json:"uptime"`
+	Reconnects         int        `json:"reconnects"`
+}
+
+type SessionNotificationsResponse struct {
+	SessionID                     string               `json:"sessionId"`
+	Connected                     bool                 `json:"connected"`
+	Passive                       bool                 `json:"passive"`                       // Dispositivo marcado como passivo no servidor
+	ReceivingOfflineNotifications bool                 `json:"receivingOfflineNotifications"` // Conectado e ativo, recebendo os eventos pendentes
+	OfflineSync                   *OfflineSyncResponse `json:"offlineSync,omitempty"`         // Última sincronização offline desde a conexão
+}
+
+type OfflineSyncResponse struct {
+	InProgress     bool       `json:"inProgress"`
+	Total          int        `json:"total"`
+	AppDataChanges int        `json:"appDataChanges"`
+	Messages       int        `json:"messages"`
+	Notifications  int        `json:"notifications"`
+	Receipts       int        `json:"receipts"`
+	Delivered      int        `json:"delivered"`
+	PreviewAt      *time.Time `json:"previewAt,omitempty"`
+	CompletedAt    *time.Time `json:"completedAt,omitempty"`
+}
+
+type SetNotificationsRequest struct {
+	Passive *bool `json:"passive" binding:"required" example:"false"` // true deixa de receber eventos em tempo real e pendentes
+}
+
+type DashboardWebhookResponse struct {
+	Configured   bool     `json:"configured"`
+	Enabled      bool     `json:"enabled"`
+	URL          string   `json:"url,omitempty"`
+	Events       []string `json:"events"`
+	TotalSent    int64    `json:"totalSent"`
+	TotalSuccess int64    `json:"totalSuccess"`
+	TotalFailed  int64    `json:"totalFailed"`
+	TotalRetries int64    `json:"totalRetries"`
+}
+
+type DashboardMessagesResponse struct {
+	Sent     int64 `json:"sent"`
+	Received int64 `json:"received"`
+}
+
+type DashboardErrorResponse struct {
+	Message    string     `json:"message"`
+	OccurredAt *time.Time `json:"occurredAt,omitempty"`
+}
+
+type SessionDashboardResponse struct {
+	SessionID     string                     `json:"sessionId"`
+	Name          string                     `json:"name"`
+	Status        models.SessionStatus       `json:"status"`
+	Phone         string                     `json:"phone,omitempty"`
+	Active        bool                       `json:"active"`
+	Connected     bool                       `json:"connected"`
+	LoggedIn      bool                       `json:"loggedIn"`
+	ConnectedAt   *time.Time                 `json:"connectedAt,omitempty"`
+	UptimeSeconds int64                      `json:"uptimeSeconds"`
+	Uptime        string                     `json:"uptime"`
+	Reconnects    int                        `json:"reconnects"`
+	Subscriptions []string                   `json:"subscriptions"`
+	Messages      *DashboardMessagesResponse `json:"messages"`
+	RecentError   *DashboardErrorResponse    `json:"recentError,omitempty"`
+	Webhook       *DashboardWebhookResponse  `json:"webhook"`
+	GeneratedAt   time.Time                  `json:"generatedAt"`
+}
+
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Code    int    `json:"code"`
@@ -134,7 +334,7 @@ type SessionConnectData struct {
 }
 
 type SessionDisconnectData struct {
-	Details string `json:"Details"`
+	Details string `json:"details"`
 }
 
 type QRCodeData struct {
@@ -147,21 +347,28 @@ func ToSessionResponse(session *models.Session) *SessionResponse {
 		return nil
 	}
 
-	return &SessionResponse{
-		ID:          session.ID,
-		Name:        session.Name,
-		Phone:       session.Phone,
-		Status:      session.Status,
-		QRCode:      session.QRCode,
-		ProxyHost:   session.ProxyHost,
-		ProxyPort:   session.ProxyPort,
-		ProxyType:   session.ProxyType,
-		ProxyUser:   session.ProxyUser,
-		ProxyPass:   session.ProxyPass,
-		CreatedAt:   session.CreatedAt,
-		UpdatedAt:   session.UpdatedAt,
-		ConnectedAt: session.ConnectedAt,
+	response := &SessionResponse{
+		ID:            session.ID,
+		Name:          session.Name,
+		Phone:         session.Phone,
+		Status:        session.Status,
+		ProxyHost:     session.ProxyHost,
+		ProxyPort:     session.ProxyPort,
+		ProxyType:     session.ProxyType,
+		ProxyAuth:     session.ProxyUser != "" || session.ProxyPass != "",
+		Platform:      session.Platform,
+		OSName:        session.OSName,
+		StoreMessages: session.StoreMessages,
+		CreatedAt:     session.CreatedAt,
+		UpdatedAt:     session.UpdatedAt,
+		ConnectedAt:   session.ConnectedAt,
 	}
+	if session.QRCode != "" && !session.QRCodeExpired(time.Now()) {
+		response.QRCode = session.QRCode
+		response.QRExpiresAt = session.QRCodeExpiresAt
+	}
+
+	return response
 }
 
 func ToSessionResponseList(sessions []*models.Session) []*SessionResponse {
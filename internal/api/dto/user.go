@@ -0,0 +1,67 @@
+package dto
+
+// Confiança da inferência de bloqueio. Nunca é "alta": o WhatsApp não expõe
+// se um contato bloqueou a sessão, apenas sinais indiretos
+const (
+	BlockConfidenceNone   = "none"
+	BlockConfidenceLow    = "low"
+	BlockConfidenceMedium = "medium"
+)
+
+// Sinais usados na inferência de bloqueio
+const (
+	BlockSignalProfilePictureHidden = "profile_picture_hidden"
+	BlockSignalStatusHidden         = "status_hidden"
+	BlockSignalNoDevices            = "no_devices"
+)
+
+type BlockedByResponse struct {
+	Phone            string   `json:"phone" example:"5511999999999"`                        // Número consultado
+	JID              string   `json:"jid,omitempty" example:"5511999999999@s.whatsapp.net"` // JID canônico do contato
+	Registered       bool     `json:"registered" example:"true"`                            // Número está registrado no WhatsApp
+	LikelyBlocked    bool     `json:"likelyBlocked" example:"false"`                        // Contato aparenta ter bloqueado a sessão
+	Confidence       string   `json:"confidence" example:"low"`                             // none, low ou medium
+	Signals          []string `json:"signals"`                                              // Sinais observados que sugerem bloqueio
+	BlockedBySession bool     `json:"blockedBySession" example:"false"`                     // A própria sessão bloqueou o contato
+	Caveat           string   `json:"caveat"`                                               // Limitações da inferência
+}
+
+// MaxUserAboutBatch limita a quantidade de JIDs consultados por requisição
+const MaxUserAboutBatch = 100
+
+type UserAboutRequest struct {
+	JIDs []string `json:"jids" validate:"required,min=1" binding:"required"` // Números de telefone ou JIDs dos usuários
+}
+
+type UserAboutResponse struct {
+	JID        string `json:"jid" example:"5511999999999@s.whatsapp.net"` // JID consultado
+	Registered bool   `json:"registered" example:"true"`                  // Indica se o WhatsApp retornou informações do usuário
+	Status     string `json:"status" example:"Disponível"`                // Texto do recado; vazio quando não definido ou oculto pela privacidade
+	SetAt      *int64 `json:"setAt,omitempty" example:"1640995200"`       // Timestamp da alteração, quando recebida por esta sessão no evento UserAbout
+}
+
+type UserAboutListResponse struct {
+	Users []*UserAboutResponse `json:"users"`             // Recados na ordem da requisição
+	Total int                  `json:"total" example:"2"` // Quantidade de usuários
+}
+
+type SubscribePresenceResponse struct {
+	Success bool   `json:"success" example:"true"`                          // Indica se a inscrição foi enviada
+	JID     string `json:"jid" example:"5511999999999@s.whatsapp.net"`      // JID inscrito
+	Message string `json:"message" example:"Inscrição de presença enviada"` // Detalhes da operação
+}
+
+type ContactResponse struct {
+	JID          string `json:"jid" example:"5511999999999@s.whatsapp.net"`     // JID do contato
+	Phone        string `json:"phone,omitempty" example:"5511999999999"`        // Número do contato, quando o JID é de telefone
+	PushName     string `json:"pushName,omitempty" example:"Maria"`             // Nome definido pelo próprio contato
+	BusinessName string `json:"businessName,omitempty" example:"Loja da Maria"` // Nome comercial verificado
+	FirstName    string `json:"firstName,omitempty" example:"Maria"`            // Primeiro nome na agenda do aparelho
+	FullName     string `json:"fullName,omitempty" example:"Maria Souza"`       // Nome completo na agenda do aparelho
+}
+
+type ContactsResponse struct {
+	Contacts []*ContactResponse `json:"contacts"`          // Contatos sincronizados, ordenados por nome
+	Total    int                `json:"total" example:"1"` // Quantidade de contatos retornados
+	Search   string             `json:"search,omitempty"`  // Filtro aplicado
+}
@@ -0,0 +1,164 @@
+package dto
+
+import (
+	"zpigo/internal/store/models"
+	"zpigo/internal/webhook"
+)
+
+type SetWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,url" example:"https://example.com/webhook" binding:"required"` // URL que receberá os eventos
+	Events []string `json:"events" example:"Message,Receipt"`                                                     // Eventos inscritos ou grupos (messages, calls, groups, connection...); padrão: All
+	Secret string   `json:"secret,omitempty" example:"minha-chave-secreta"`                                       // Chave para assinatura HMAC dos payloads (opcional)
+
+	FromMe  *bool `json:"fromMe,omitempty" example:"false"` // Entrega só mensagens enviadas (true) ou recebidas (false) pela sessão (opcional)
+	IsGroup *bool `json:"isGroup,omitempty" example:"true"` // Entrega só mensagens de grupos (true) ou de chats individuais (false) (opcional)
+}
+
+type UpdateWebhookRetryRequest struct {
+	MaxRetries    *int    `json:"maxRetries,omitempty" example:"5"`        // Número máximo de tentativas (1-10)
+	RetryDelay    *int    `json:"retryDelay,omitempty" example:"10"`       // Intervalo base entre tentativas em segundos (1-300)
+	MaxRetryDelay *int    `json:"maxRetryDelay,omitempty" example:"300"`   // Teto do intervalo entre tentativas em segundos (retryDelay-300)
+	Backoff       *string `json:"backoff,omitempty" example:"exponential"` // Estratégia de backoff: fixed, linear ou exponential
+	Deadline      *int    `json:"deadline,omitempty" example:"3600"`       // Prazo de cada entrega em segundos, contado do enfileiramento (60-86400)
+}
+
+type TriggerWebhookRequest struct {
+	Event string                 `json:"event" validate:"required" example:"GroupInfo" binding:"required"` // Tipo do evento sintético
+	Data  map[string]interface{} `json:"data" example:"jid:120363025246125888@g.us"`                       // Conteúdo arbitrário enviado no campo event do payload
+}
+
+type TriggerWebhookResponse struct {
+	Success   bool   `json:"success" example:"true"`                                   // Indica se o evento foi enfileirado
+	Message   string `json:"message" example:"Evento sintético enfileirado"`           // Mensagem descritiva
+	SessionID string `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000"` // ID da sessão
+	Event     string `json:"event" example:"GroupInfo"`                                // Tipo do evento enfileirado
+}
+
+type WebhookResponse struct {
+	SessionID     string   `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000"` // ID da sessão
+	URL           string   `json:"url" example:"https://example.com/webhook"`                // URL configurada
+	Events        []string `json:"events" example:"Message,Receipt"`                         // Eventos inscritos
+	HasSecret     bool     `json:"hasSecret" example:"true"`                                 // Indica se os payloads são assinados
+	MaxRetries    int      `json:"maxRetries" example:"3"`                                   // Número máximo de tentativas
+	RetryDelay    int      `json:"retryDelay" example:"5"`                                   // Intervalo base entre tentativas em segundos
+	Backoff       string   `json:"backoff" example:"linear"`                                 // Estratégia de backoff
+	MaxRetryDelay int      `json:"maxRetryDelay" example:"300"`                              // Teto do intervalo entre tentativas em segundos
+	Deadline      int      `json:"deadline" example:"3600"`                                  // Prazo de cada entrega em segundos
+	FromMe        *bool    `json:"fromMe,omitempty" example:"false"`                         // Filtro por origem da mensagem, se configurado
+	IsGroup       *bool    `json:"isGroup,omitempty" example:"true"`                         // Filtro por tipo de chat, se configurado
+	Enabled       bool     `json:"enabled" example:"true"`                                   // Indica se o webhook está ativo no gerenciador
+	UpdatedAt     int64    `json:"updatedAt" example:"1640995200"`                           // Timestamp da última alteração
+}
+
+type DeleteWebhookResponse struct {
+	Message string `json:"message" example:"Webhook removido com sucesso"` // Mensagem descritiva
+	Success bool   `json:"success" example:"true"`                         // Indica se a remoção foi bem-sucedida
+}
+
+func ToWebhookResponse(model *models.Webhook, enabled bool) *WebhookResponse {
+	return &WebhookResponse{
+		SessionID:     model.SessionID,
+		URL:           model.URL,
+		Events:        webhook.EventTypeStrings(model.GetEvents()),
+		HasSecret:     model.Secret != "",
+		MaxRetries:    model.MaxRetries,
+		RetryDelay:    model.RetryDelay,
+		Backoff:       model.Backoff,
+		MaxRetryDelay: model.MaxRetryDelay,
+		Deadline:      model.DeliveryDeadline,
+		FromMe:        model.FilterFromMe,
+		IsGroup:       model.FilterIsGroup,
+		Enabled:       enabled,
+		UpdatedAt:     model.UpdatedAt.Unix(),
+	}
+}
+
+type WebhookDeliveryResponse struct {
+	ID          string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000-1640995200000000000"` // ID da entrega
+	Event       string `json:"event" example:"Message"`                                               // Tipo do evento entregue
+	URL         string `json:"url" example:"https://example.com/webhook"`                             // URL de destino
+	Status      string `json:"status" example:"pending"`                                              // pending, success, failed ou expired
+	Attempts    int    `json:"attempts" example:"2"`                                                  // Tentativas realizadas
+	MaxRetries  int    `json:"maxRetries" example:"5"`                                                // Máximo de tentativas
+	LastAttempt int64  `json:"lastAttempt,omitempty" example:"1640995200"`                            // Timestamp da última tentativa
+	NextRetry   int64  `json:"nextRetry,omitempty" example:"1640995230"`                              // Timestamp da próxima tentativa agendada
+	Deadline    int64  `json:"deadline,omitempty" example:"1640998800"`                               // Timestamp após o qual a entrega expira
+	Error       string `json:"error,omitempty" example:"Status code inválido: 503"`                   // Último erro
+	DurationMs  int64  `json:"durationMs" example:"120"`                                              // Duração da última tentativa em milissegundos
+}
+
+type WebhookDeliveriesResponse struct {
+	SessionID  string                     `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000"` // ID da sessão
+	Deliveries []*WebhookDeliveryResponse `json:"deliveries"`                                               // Entregas recentes, da mais nova para a mais antiga
+	Total      int                        `json:"total" example:"1"`                                        // Quantidade de entregas retornadas
+}
+
+func ToWebhookDeliveryResponse(delivery *webhook.Delivery) *WebhookDeliveryResponse {
+	resp := &WebhookDeliveryResponse{
+		ID:         delivery.ID,
+		URL:        delivery.URL,
+		Status:     delivery.Status,
+		Attempts:   delivery.Attempts,
+		MaxRetries: delivery.MaxRetries,
+		Error:      delivery.Error,
+		DurationMs: delivery.Duration.Milliseconds(),
+	}
+	if payload, ok := delivery.Payload.(*webhook.Payload); ok {
+		resp.Event = payload.Type
+	}
+	if !delivery.LastAttempt.IsZero() {
+		resp.LastAttempt = delivery.LastAttempt.Unix()
+	}
+	if delivery.Status == string(webhook.StatusPending) && !delivery.NextRetry.IsZero() {
+		resp.NextRetry = delivery.NextRetry.Unix()
+	}
+	if !delivery.Deadline.IsZero() {
+		resp.Deadline = delivery.Deadline.Unix()
+	}
+	return resp
+}
+
+type ActiveEventsResponse struct {
+	SessionID     string                 `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000"` // ID da sessão
+	Subscriptions []string               `json:"subscriptions" example:"Message,Receipt"`                  // Eventos inscritos na sessão
+	Active        []string               `json:"active" example:"Message,Receipt"`                         // Eventos inscritos que são efetivamente emitidos
+	Unhandled     []UnhandledEventStatus `json:"unhandled"`                                                // Eventos inscritos que nunca serão entregues
+	Handled       []string               `json:"handled" example:"Connected,Message,Receipt"`              // Todos os eventos emitidos pelo ZPigo
+}
+
+type UnhandledEventStatus struct {
+	Event   string `json:"event" example:"CallOffer"`                                            // Tipo do evento inscrito
+	Warning string `json:"warning" example:"Evento declarado, mas ainda não emitido pelo ZPigo"` // Motivo pelo qual o evento não é entregue
+}
+
+type GlobalWebhookResponse struct {
+	Configured bool     `json:"configured" example:"true"`                           // Indica se há webhook global configurado
+	URL        string   `json:"url,omitempty" example:"https://example.com/webhook"` // URL configurada
+	Events     []string `json:"events,omitempty" example:"Message,Receipt"`          // Eventos inscritos
+	HasSecret  bool     `json:"hasSecret" example:"true"`                            // Indica se os payloads são assinados
+	FromMe     *bool    `json:"fromMe,omitempty" example:"false"`                    // Filtro por origem da mensagem, se configurado
+	IsGroup    *bool    `json:"isGroup,omitempty" example:"true"`                    // Filtro por tipo de chat, se configurado
+	Enabled    bool     `json:"enabled" example:"true"`                              // Indica se o webhook global está ativo
+}
+
+// ToGlobalWebhookResponse converte a configuração do webhook global; config nil indica que não há
+// webhook global
+func ToGlobalWebhookResponse(config *webhook.Config) *GlobalWebhookResponse {
+	if config == nil {
+		return &GlobalWebhookResponse{}
+	}
+
+	response := &GlobalWebhookResponse{
+		Configured: true,
+		URL:        config.URL,
+		Events:     webhook.EventTypeStrings(config.Events),
+		HasSecret:  config.Secret != "",
+		Enabled:    config.Enabled,
+	}
+	if config.Filter != nil {
+		response.FromMe = config.Filter.FromMe
+		response.IsGroup = config.Filter.IsGroup
+	}
+
+	return response
+}
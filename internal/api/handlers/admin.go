@@ -0,0 +1,498 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mau.fi/whatsmeow/types"
+
+	"zpigo/internal/api/dto"
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
+	"zpigo/internal/webhook"
+)
+
+type AdminHandler struct {
+	*BaseHandler
+	sessionRepo    store.SessionRepositoryInterface
+	sessionManager *meow.SessionManager
+	sessionHandler *SessionHandler
+	messageHandler *MessageHandler
+	authManager    *meow.AuthManager
+}
+
+func NewAdminHandler(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager, sessionHandler *SessionHandler, messageHandler *MessageHandler, authManager *meow.AuthManager) *AdminHandler {
+	return &AdminHandler{
+		BaseHandler:    NewBaseHandler("AdminHandler"),
+		sessionRepo:    sessionRepo,
+		sessionManager: sessionManager,
+		sessionHandler: sessionHandler,
+		messageHandler: messageHandler,
+		authManager:    authManager,
+	}
+}
+
+// @Summary      Diagnóstico de gerenciadores da sessão
+// @Description  Informa se a sessão está presente no gerenciador usado pelo SessionHandler, pelo MessageHandler ou em ambos.
+// @Description  Quando os handlers usam gerenciadores distintos, operações de status e envio podem divergir.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.SessionManagersResponse
+// @Failure      401        {object}  map[string]interface{}
+// @Router       /admin/sessions/{sessionID}/managers [get]
+// @Security     AdminAuth
+func (h *AdminHandler) GetSessionManagers(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	sessionManager := h.sessionHandler.GetSessionManager()
+	messageManager := h.messageHandler.GetSessionManager()
+
+	_, inSessionHandler := sessionManager.GetSession(sessionID)
+	_, inMessageHandler := messageManager.GetSession(sessionID)
+	shared := sessionManager == messageManager
+
+	response := &dto.SessionManagersResponse{
+		SessionID:        sessionID,
+		InSessionHandler: inSessionHandler,
+		InMessageHandler: inMessageHandler,
+		SharedManager:    shared,
+		Consistent:       shared || inSessionHandler == inMessageHandler,
+		Timestamp:        time.Now().Unix(),
+	}
+
+	switch {
+	case shared:
+		response.Details = "SessionHandler e MessageHandler compartilham o mesmo gerenciador"
+	case inSessionHandler && inMessageHandler:
+		response.Details = "Sessão carregada em dois gerenciadores distintos; os clientes WhatsApp são independentes"
+	case inSessionHandler:
+		response.Details = "Sessão presente apenas no gerenciador do SessionHandler; envios de mensagem falharão"
+	case inMessageHandler:
+		response.Details = "Sessão presente apenas no gerenciador do MessageHandler; consultas de status falharão"
+	default:
+		response.Details = "Sessão não carregada em nenhum gerenciador"
+	}
+
+	if !response.Consistent {
+		h.logger.Warn("Gerenciadores de sessão divergentes", "sessionID", sessionID, "inSessionHandler", inSessionHandler, "inMessageHandler", inMessageHandler)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Estatísticas do cache
+// @Description  Retorna a quantidade de itens no cache em memória, detalhada por tipo e por política de expiração
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.CacheStatsResponse
+// @Failure      401  {object}  map[string]interface{}
+// @Router       /admin/cache/stats [get]
+// @Security     AdminAuth
+func (h *AdminHandler) GetCacheStats(c *gin.Context) {
+	stats := h.sessionManager.GetCacheManager().GetDetailedStats()
+
+	h.logger.Debug("Estatísticas do cache consultadas", "totalItems", stats.TotalItems, "sessionInfoItems", stats.SessionInfoItems)
+
+	c.JSON(http.StatusOK, &dto.CacheStatsResponse{
+		TotalItems:       stats.TotalItems,
+		LiveItems:        stats.LiveItems,
+		SessionInfoItems: stats.SessionInfoItems,
+		NonExpiringItems: stats.NonExpiringItems,
+		ItemsByType:      stats.ItemsByType,
+		Timestamp:        time.Now().Unix(),
+	})
+}
+
+// @Summary      Aquecer cache de sessões
+// @Description  Carrega todas as sessões do banco no cache em memória, reduzindo a latência da primeira requisição autenticada após um restart
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.CacheWarmRequest  false  "Opções de aquecimento"
+// @Success      200      {object}  dto.CacheWarmResponse
+// @Failure      400      {object}  map[string]interface{}
+// @Failure      401      {object}  map[string]interface{}
+// @Failure      500      {object}  map[string]interface{}
+// @Router       /admin/cache/warm [post]
+// @Security     AdminAuth
+func (h *AdminHandler) WarmCache(c *gin.Context) {
+	var req dto.CacheWarmRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Erro ao decodificar request", "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = meow.DefaultCacheWarmConcurrency
+	}
+
+	start := time.Now()
+	warmed, err := h.sessionManager.WarmCache(c.Request.Context(), req.APIKey, concurrency)
+	if err != nil {
+		h.logger.Error("Erro ao aquecer cache", "warmed", warmed, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao aquecer cache",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.CacheWarmResponse{
+		Success:     true,
+		WarmedItems: warmed,
+		Concurrency: concurrency,
+		Duration:    time.Since(start).Round(time.Millisecond).String(),
+		Message:     "Cache aquecido",
+		Timestamp:   time.Now().Unix(),
+	})
+}
+
+// @Summary      Limpar QR codes expirados
+// @Description  Volta para disconnected as sessões presas em connecting cujo QR code expirou, removendo o QR armazenado.
+// @Description  A mesma limpeza roda periodicamente conforme SESSION_QR_SWEEP_INTERVAL
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.QRSweepResponse
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/sessions/sweep-qr [post]
+// @Security     AdminAuth
+func (h *AdminHandler) SweepStaleQRCodes(c *gin.Context) {
+	swept, err := h.sessionManager.SweepStaleQRCodes(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Erro ao limpar QR codes expirados", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao limpar QR codes expirados",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.QRSweepResponse{
+		Success:   true,
+		Swept:     swept,
+		Count:     len(swept),
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// @Summary      Listar devices do whatsmeow
+// @Description  Lista os devices salvos no container do whatsmeow e a sessão vinculada a cada um. Devices órfãos
+// @Description  (sem sessão correspondente) sobram quando a sessão é removida sem logout e podem ser apagados.
+// @Tags         admin
+// @Produce      json
+// @Param        orphaned  query     bool  false  "Lista apenas os devices órfãos"
+// @Success      200       {object}  dto.ListDevicesResponse
+// @Failure      401       {object}  map[string]interface{}
+// @Failure      500       {object}  map[string]interface{}
+// @Router       /admin/devices [get]
+// @Security     AdminAuth
+func (h *AdminHandler) ListDevices(c *gin.Context) {
+	devices, err := h.sessionManager.ListDevices(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Erro ao listar devices", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao listar devices",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	onlyOrphans := c.Query("orphaned") == "true"
+
+	response := &dto.ListDevicesResponse{
+		Devices:   make([]dto.DeviceResponse, 0, len(devices)),
+		Timestamp: time.Now().Unix(),
+	}
+	for _, device := range devices {
+		if device.Orphan {
+			response.Orphans++
+		} else if onlyOrphans {
+			continue
+		}
+		response.Devices = append(response.Devices, dto.DeviceResponse{
+			JID:       device.JID,
+			PushName:  device.PushName,
+			Platform:  device.Platform,
+			SessionID: device.SessionID,
+			Orphan:    device.Orphan,
+		})
+	}
+	response.Total = len(response.Devices)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Remover device do whatsmeow
+// @Description  Remove um device órfão do container do whatsmeow. Devices vinculados a uma sessão são recusados;
+// @Description  nesse caso remova a sessão pelos endpoints de sessão.
+// @Tags         admin
+// @Produce      json
+// @Param        jid  path      string  true  "JID do device"
+// @Success      200  {object}  dto.DeleteDeviceResponse
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      404  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/devices/{jid} [delete]
+// @Security     AdminAuth
+func (h *AdminHandler) DeleteDevice(c *gin.Context) {
+	jid, err := types.ParseJID(c.Param("jid"))
+	if err != nil || jid.User == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "JID inválido",
+			"details": "informe o JID completo do device, como listado em GET /admin/devices",
+		})
+		return
+	}
+
+	if err := h.sessionManager.DeleteDevice(c.Request.Context(), jid); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, meow.ErrDeviceNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, meow.ErrDeviceInUse):
+			status = http.StatusConflict
+		default:
+			h.logger.Error("Erro ao remover device", "deviceJid", jid, "error", err)
+		}
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": "Erro ao remover device",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.DeleteDeviceResponse{
+		Success: true,
+		JID:     jid.String(),
+		Message: "Device removido",
+	})
+}
+
+// @Summary      Consultar webhook global
+// @Description  Retorna o webhook global, que recebe os eventos de todas as sessões além dos webhooks de cada sessão
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  dto.GlobalWebhookResponse
+// @Failure      401  {object}  map[string]interface{}
+// @Router       /admin/webhook/global [get]
+// @Security     AdminAuth
+func (h *AdminHandler) GetGlobalWebhook(c *gin.Context) {
+	config, _ := h.sessionManager.GetWebhookManager().GetGlobalConfig()
+	c.JSON(http.StatusOK, dto.ToGlobalWebhookResponse(config))
+}
+
+// @Summary      Configurar webhook global
+// @Description  Define o webhook que recebe os eventos de todas as sessões, inclusive as criadas depois. O webhook global
+// @Description  não substitui o webhook da sessão: um evento aceito pelos dois é entregue aos dois, e o payload traz o
+// @Description  sessionId de origem. A configuração vale até o próximo restart, quando WEBHOOK_GLOBAL_URL é aplicado de novo.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.SetWebhookRequest  true  "Configuração do webhook global"
+// @Success      200      {object}  dto.GlobalWebhookResponse
+// @Failure      400      {object}  map[string]interface{}
+// @Failure      401      {object}  map[string]interface{}
+// @Router       /admin/webhook/global [put]
+// @Security     AdminAuth
+func (h *AdminHandler) SetGlobalWebhook(c *gin.Context) {
+	var req dto.SetWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	if !meow.ValidateWebhookURL(req.URL) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "URL de webhook inválida",
+			"details": "A URL deve começar com http:// ou https://",
+		})
+		return
+	}
+
+	events, err := webhook.ParseEventTypes(req.Events)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Eventos inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(events) == 0 {
+		events = []webhook.EventType{webhook.EventAll}
+	}
+
+	config := &webhook.Config{
+		URL:     req.URL,
+		Events:  events,
+		Secret:  req.Secret,
+		Filter:  webhook.NewFilter(req.FromMe, req.IsGroup),
+		Enabled: true,
+	}
+	if err := h.sessionManager.GetWebhookManager().SetGlobalConfig(config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "URL de webhook inválida",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToGlobalWebhookResponse(config))
+}
+
+// @Summary      Remover webhook global
+// @Description  Remove o webhook global até o próximo restart; os webhooks das sessões continuam ativos
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  dto.DeleteWebhookResponse
+// @Failure      401  {object}  map[string]interface{}
+// @Router       /admin/webhook/global [delete]
+// @Security     AdminAuth
+func (h *AdminHandler) DeleteGlobalWebhook(c *gin.Context) {
+	h.sessionManager.GetWebhookManager().ClearGlobalConfig()
+
+	c.JSON(http.StatusOK, &dto.DeleteWebhookResponse{
+		Message: "Webhook global removido",
+		Success: true,
+	})
+}
+
+// @Summary      Criar chave de API
+// @Description  Gera uma nova chave de API, opcionalmente restrita a uma sessão. A chave é exibida apenas nesta resposta
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.CreateAPIKeyRequest  true  "Dados da chave"
+// @Success      201      {object}  dto.CreateAPIKeyResponse
+// @Failure      400      {object}  map[string]interface{}
+// @Failure      401      {object}  map[string]interface{}
+// @Failure      404      {object}  map[string]interface{}
+// @Failure      500      {object}  map[string]interface{}
+// @Router       /admin/api-keys [post]
+// @Security     AdminAuth
+func (h *AdminHandler) CreateAPIKey(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.logger.Error("Erro ao decodificar request", "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	if len(req.Label) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Label deve ter no máximo 255 caracteres",
+		})
+		return
+	}
+
+	if req.SessionID != "" {
+		if _, err := h.sessionRepo.GetByID(c.Request.Context(), req.SessionID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": "Sessão não encontrada",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	apiKey, key, err := h.authManager.CreateAPIKey(c.Request.Context(), req.Label, req.SessionID)
+	if err != nil {
+		h.logger.Error("Erro ao criar chave de API", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao criar chave de API",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, &dto.CreateAPIKeyResponse{
+		APIKey: apiKey,
+		Key:    dto.ToAPIKeyResponse(key),
+	})
+}
+
+// @Summary      Listar chaves de API
+// @Description  Lista as chaves de API cadastradas, sem o valor da chave
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.APIKeyListResponse
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /admin/api-keys [get]
+// @Security     AdminAuth
+func (h *AdminHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.authManager.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Erro ao listar chaves de API", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao listar chaves de API",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response := &dto.APIKeyListResponse{
+		Keys:  make([]*dto.APIKeyResponse, 0, len(keys)),
+		Total: len(keys),
+	}
+	for _, key := range keys {
+		response.Keys = append(response.Keys, dto.ToAPIKeyResponse(key))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Revogar chave de API
+// @Description  Revoga uma chave de API. Requisições com a chave passam a ser recusadas imediatamente
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        keyID  path      string  true  "ID da chave"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      401    {object}  map[string]interface{}
+// @Failure      404    {object}  map[string]interface{}
+// @Router       /admin/api-keys/{keyID} [delete]
+// @Security     AdminAuth
+func (h *AdminHandler) RevokeAPIKey(c *gin.Context) {
+	keyID := c.Param("keyID")
+
+	if err := h.authManager.RevokeAPIKey(c.Request.Context(), keyID); err != nil {
+		h.logger.Warn("Erro ao revogar chave de API", "keyID", keyID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Chave de API não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Chave de API revogada",
+		"keyId":   keyID,
+	})
+}
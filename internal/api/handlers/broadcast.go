@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"zpigo/internal/api/dto"
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
+	"zpigo/internal/store/models"
+)
+
+type BroadcastHandler struct {
+	*BaseHandler
+	sessionRepo    store.SessionRepositoryInterface
+	sessionManager *meow.SessionManager
+	messageHandler *MessageHandler
+}
+
+func NewBroadcastHandler(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager, messageHandler *MessageHandler) *BroadcastHandler {
+	return &BroadcastHandler{
+		BaseHandler:    NewBaseHandler("BroadcastHandler"),
+		sessionRepo:    sessionRepo,
+		sessionManager: sessionManager,
+		messageHandler: messageHandler,
+	}
+}
+
+// @Summary      Criar broadcast
+// @Description  Cria um job persistido que envia a mensagem a cada destinatário em segundo plano, respeitando o limite
+// @Description  de envio da sessão. Marcadores {{nome}} são substituídos pelas variables de cada destinatário e validados
+// @Description  na criação. O job sobrevive a reinícios da aplicação e pode ser acompanhado em
+// @Description  GET /sessions/{sessionID}/broadcast/{jobID}.
+// @Tags         broadcast
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                      true  "ID da sessão"
+// @Param        request    body      dto.CreateBroadcastRequest  true  "Mensagem e destinatários"
+// @Success      202        {object}  dto.BroadcastJobResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/broadcast [post]
+// @Security     ApiKeyAuth
+func (h *BroadcastHandler) CreateBroadcast(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.CreateBroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	if len(req.Recipients) > dto.MaxBroadcastRecipients {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Destinatários demais",
+			"details": fmt.Sprintf("O broadcast aceita no máximo %d destinatários", dto.MaxBroadcastRecipients),
+		})
+		return
+	}
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		h.logger.Error("Sessão não encontrada", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	recipients := make([]*models.BroadcastRecipient, 0, len(req.Recipients))
+	for i, r := range req.Recipients {
+		if r.Phone == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Destinatário inválido",
+				"details": fmt.Sprintf("O destinatário %d não informou 'phone'", i+1),
+			})
+			return
+		}
+
+		jid, err := h.messageHandler.parseJID(r.Phone)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Destinatário inválido",
+				"details": fmt.Sprintf("%s: %v", r.Phone, err),
+			})
+			return
+		}
+
+		text, err := dto.RenderTemplate(req.Message, r.Variables)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Variáveis da mensagem inválidas",
+				"details": fmt.Sprintf("%s: %v", r.Phone, err),
+			})
+			return
+		}
+
+		recipients = append(recipients, &models.BroadcastRecipient{
+			Phone: r.Phone,
+			JID:   jid.String(),
+			Text:  text,
+		})
+	}
+
+	job := &models.BroadcastJob{
+		SessionID: sessionID,
+		Message:   req.Message,
+	}
+
+	if err := h.sessionManager.GetBroadcastRepository().Create(c.Request.Context(), job, recipients); err != nil {
+		h.logger.Error("Erro ao criar broadcast", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao criar broadcast",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.sessionManager.StartBroadcast(job)
+
+	h.logger.Info("Broadcast criado", "sessionID", sessionID, "jobID", job.ID, "total", job.Total)
+
+	c.JSON(http.StatusAccepted, dto.ToBroadcastJobResponse(job))
+}
+
+// @Summary      Consultar broadcast
+// @Description  Retorna o status e o progresso do broadcast (enviados, com falha e pendentes)
+// @Tags         broadcast
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        jobID      path      string  true  "ID do broadcast"
+// @Success      200        {object}  dto.BroadcastJobResponse
+// @Failure      401        {object}  map[string]interface{}
+// @Failure      403        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/broadcast/{jobID} [get]
+// @Security     ApiKeyAuth
+func (h *BroadcastHandler) GetBroadcast(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	jobID := c.Param("jobID")
+
+	job, err := h.sessionManager.GetBroadcast(c.Request.Context(), sessionID, jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Broadcast não encontrado",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToBroadcastJobResponse(job))
+}
+
+// @Summary      Cancelar broadcast
+// @Description  Interrompe o broadcast; destinatários ainda não processados não recebem a mensagem
+// @Tags         broadcast
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        jobID      path      string  true  "ID do broadcast"
+// @Success      200        {object}  dto.BroadcastJobResponse
+// @Failure      401        {object}  map[string]interface{}
+// @Failure      403        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      409        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/broadcast/{jobID} [delete]
+// @Security     ApiKeyAuth
+func (h *BroadcastHandler) CancelBroadcast(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	jobID := c.Param("jobID")
+
+	job, err := h.sessionManager.CancelBroadcast(c.Request.Context(), sessionID, jobID)
+	if errors.Is(err, meow.ErrBroadcastFinished) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Broadcast já finalizado",
+			"details": fmt.Sprintf("O broadcast terminou com status %s", job.Status),
+		})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Erro ao cancelar broadcast", "sessionID", sessionID, "jobID", jobID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Broadcast não encontrado",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToBroadcastJobResponse(job))
+}
@@ -0,0 +1,417 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+
+	"zpigo/internal/api/dto"
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
+	"zpigo/internal/store/models"
+)
+
+type ChatHandler struct {
+	*BaseHandler
+	sessionRepo    store.SessionRepositoryInterface
+	sessionManager *meow.SessionManager
+}
+
+func NewChatHandler(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager) *ChatHandler {
+	return &ChatHandler{
+		BaseHandler:    NewBaseHandler("ChatHandler"),
+		sessionRepo:    sessionRepo,
+		sessionManager: sessionManager,
+	}
+}
+
+// @Summary      Configurar mensagens temporárias
+// @Description  Ativa ou desativa as mensagens temporárias de um chat individual ou grupo.
+// @Description  Valores aceitos para duration: off, 24h, 7d e 90d, os únicos permitidos pelo WhatsApp.
+// @Tags         chats
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                           true  "ID da sessão"
+// @Param        request    body      dto.SetDisappearingTimerRequest  true  "Chat e duração"
+// @Success      200        {object}  dto.SetDisappearingTimerResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/chat/ephemeral [post]
+// @Security     ApiKeyAuth
+func (h *ChatHandler) SetDisappearingTimer(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.SetDisappearingTimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	timer, ok := whatsmeow.ParseDisappearingTimerString(req.Duration)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Duração inválida",
+			"details": "Valores aceitos: off, 24h, 7d, 90d",
+		})
+		return
+	}
+
+	chat, err := parseChatJID(req.Phone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Chat inválido",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	if err := client.SetDisappearingTimer(chat, timer); err != nil {
+		h.logger.Error("Erro ao configurar mensagens temporárias", "sessionID", sessionID, "chat", chat, "duration", req.Duration, "error", err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, whatsmeow.ErrInvalidDisappearingTimer) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": "Erro ao configurar mensagens temporárias",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Mensagens temporárias configuradas", "sessionID", sessionID, "chat", chat, "duration", timer)
+
+	c.JSON(http.StatusOK, &dto.SetDisappearingTimerResponse{
+		Success:  true,
+		Chat:     chat.String(),
+		Duration: req.Duration,
+		Seconds:  uint32(timer.Seconds()),
+		Message:  "Mensagens temporárias configuradas",
+	})
+}
+
+// @Summary      Apagar chat
+// @Description  Apaga o chat e suas mídias em todos os dispositivos da conta, enviando o patch de app state
+// @Description  deleteChat. A última mensagem do chat é opcional e delimita as mensagens apagadas.
+// @Tags         chats
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                 true  "ID da sessão"
+// @Param        request    body      dto.ChatActionRequest  true  "Chat e última mensagem"
+// @Success      200        {object}  dto.ChatActionResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/chat/delete [post]
+// @Security     ApiKeyAuth
+func (h *ChatHandler) DeleteChat(c *gin.Context) {
+	h.applyChatAction(c, "delete", meow.BuildDeleteChat)
+}
+
+// @Summary      Limpar chat
+// @Description  Apaga as mensagens do chat em todos os dispositivos da conta, mantendo o chat e as mensagens
+// @Description  favoritadas, enviando o patch de app state clearChat.
+// @Tags         chats
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                 true  "ID da sessão"
+// @Param        request    body      dto.ChatActionRequest  true  "Chat e última mensagem"
+// @Success      200        {object}  dto.ChatActionResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/chat/clear [post]
+// @Security     ApiKeyAuth
+func (h *ChatHandler) ClearChat(c *gin.Context) {
+	h.applyChatAction(c, "clear", meow.BuildClearChat)
+}
+
+// @Summary      Favoritar mensagem
+// @Description  Favorita ou remove dos favoritos uma mensagem em todos os dispositivos da conta, enviando o patch
+// @Description  de app state star. Em grupos, mensagens de outros participantes exigem o participant.
+// @Tags         chats
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                  true  "ID da sessão"
+// @Param        request    body      dto.StarMessageRequest  true  "Mensagem e estado"
+// @Success      200        {object}  dto.StarMessageResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/message/star [post]
+// @Security     ApiKeyAuth
+func (h *ChatHandler) StarMessage(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.StarMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	chat, err := parseChatJID(req.Phone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Chat inválido",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Fora de grupos e em mensagens próprias o autor é omitido do índice; BuildStar faz isso quando o
+	// autor e o chat são o mesmo usuário
+	sender := chat
+	if chat.Server == types.GroupServer && !req.FromMe {
+		if req.Participant == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Participante obrigatório",
+				"details": "Em grupos, informe o autor da mensagem em participant",
+			})
+			return
+		}
+		sender, err = parseChatJID(req.Participant)
+		if err != nil || sender.Server == types.GroupServer {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Participante inválido",
+				"details": "esperado o número ou JID de um contato",
+			})
+			return
+		}
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	patch := appstate.BuildStar(chat, sender, req.MessageID, req.FromMe, req.Starred)
+	if err := client.SendAppState(c.Request.Context(), patch); err != nil {
+		h.logger.Error("Erro ao enviar patch de favorito", "sessionID", sessionID, "chat", chat, "messageID", req.MessageID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao favoritar mensagem",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	message := "Mensagem favoritada"
+	if !req.Starred {
+		message = "Mensagem removida dos favoritos"
+	}
+
+	h.logger.Info(message, "sessionID", sessionID, "chat", chat, "messageID", req.MessageID)
+
+	c.JSON(http.StatusOK, &dto.StarMessageResponse{
+		Success:   true,
+		Chat:      chat.String(),
+		MessageID: req.MessageID,
+		Starred:   req.Starred,
+		Message:   message,
+	})
+}
+
+// @Summary      Listar chats
+// @Description  Lista os chats conhecidos da sessão, montados a partir da sincronização de histórico, do app state
+// @Description  (arquivar, fixar, silenciar e marcar como lido) e das mensagens recebidas desde então. Fixados vêm
+// @Description  primeiro, seguidos pela última mensagem. unreadCount é aproximado: é o valor do histórico somado
+// @Description  às mensagens recebidas, zerado ao ler o chat ou ao responder.
+// @Tags         chats
+// @Produce      json
+// @Param        sessionID  path      string  true   "ID da sessão"
+// @Param        archived   query     bool    false  "Filtra por chats arquivados (true) ou não arquivados (false)"
+// @Success      200        {object}  dto.ChatsResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/chats [get]
+// @Security     ApiKeyAuth
+func (h *ChatHandler) ListChats(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var archivedFilter *bool
+	if value := c.Query("archived"); value != "" {
+		archived, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Filtro inválido",
+				"details": "archived deve ser true ou false",
+			})
+			return
+		}
+		archivedFilter = &archived
+	}
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	chats, err := h.sessionManager.GetChatRepository().ListBySessionID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Erro ao listar chats", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao listar chats",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Chats criados por mensagens não têm nome; para contatos usamos o nome salvo no store local
+	var contacts map[types.JID]types.ContactInfo
+	if client, exists := h.sessionManager.GetSession(sessionID); exists && client.Store.ID != nil {
+		contacts, _ = client.Store.Contacts.GetAllContacts(c.Request.Context())
+	}
+
+	now := time.Now()
+	response := &dto.ChatsResponse{Chats: make([]*dto.ChatResponse, 0, len(chats))}
+	for _, chat := range chats {
+		if archivedFilter != nil && chat.Archived != *archivedFilter {
+			continue
+		}
+
+		jid, _ := types.ParseJID(chat.JID)
+		item := &dto.ChatResponse{
+			JID:          chat.JID,
+			Name:         chat.Name,
+			IsGroup:      jid.Server == types.GroupServer,
+			UnreadCount:  chat.UnreadCount,
+			MarkedUnread: chat.MarkedUnread,
+			Archived:     chat.Archived,
+			Pinned:       chat.Pinned,
+			Muted:        chat.IsMuted(now),
+		}
+		if item.Name == "" {
+			if info, found := contacts[jid]; found {
+				for _, name := range []string{info.FullName, info.BusinessName, info.PushName} {
+					if name != "" {
+						item.Name = name
+						break
+					}
+				}
+			}
+		}
+		if chat.LastMessageAt != nil {
+			item.LastMessageAt = chat.LastMessageAt.Unix()
+		}
+		if item.Muted && chat.MutedUntil != models.ChatMutedForever {
+			item.MutedUntil = chat.MutedUntil
+		}
+		response.Chats = append(response.Chats, item)
+	}
+	response.Total = len(response.Chats)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// applyChatAction valida o chat, monta o patch com buildPatch e o envia ao WhatsApp
+func (h *ChatHandler) applyChatAction(c *gin.Context, action string, buildPatch func(types.JID, time.Time, *waCommon.MessageKey) appstate.PatchInfo) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.ChatActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	chat, err := parseChatJID(req.Phone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Chat inválido",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	var lastMessageTimestamp time.Time
+	if req.LastMessageTimestamp > 0 {
+		lastMessageTimestamp = time.Unix(req.LastMessageTimestamp, 0)
+	}
+
+	var lastMessageKey *waCommon.MessageKey
+	if req.LastMessageID != "" {
+		lastMessageKey = &waCommon.MessageKey{
+			RemoteJID: proto.String(chat.String()),
+			FromMe:    proto.Bool(req.LastMessageFromMe),
+			ID:        proto.String(req.LastMessageID),
+		}
+	}
+
+	patch := buildPatch(chat, lastMessageTimestamp, lastMessageKey)
+	if err := client.SendAppState(c.Request.Context(), patch); err != nil {
+		h.logger.Error("Erro ao enviar patch de app state do chat", "sessionID", sessionID, "chat", chat, "action", action, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao atualizar chat",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	message := "Chat apagado"
+	if action == "clear" {
+		message = "Chat limpo"
+	}
+
+	h.logger.Info(message, "sessionID", sessionID, "chat", chat)
+
+	c.JSON(http.StatusOK, &dto.ChatActionResponse{
+		Success: true,
+		Chat:    chat.String(),
+		Action:  action,
+		Message: message,
+	})
+}
+
+// parseChatJID aceita um número de telefone (com ou sem +) ou o JID de um contato ou grupo
+func parseChatJID(value string) (types.JID, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "+")
+	if isDigits(value) {
+		return types.NewJID(value, types.DefaultUserServer), nil
+	}
+
+	jid, err := types.ParseJID(value)
+	if err != nil {
+		return types.JID{}, err
+	}
+
+	switch jid.Server {
+	case types.DefaultUserServer, types.HiddenUserServer, types.GroupServer:
+		return jid.ToNonAD(), nil
+	default:
+		return types.JID{}, fmt.Errorf("esperado o JID de um contato ou grupo, recebido %s", value)
+	}
+}
@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mau.fi/whatsmeow/types"
+
+	"zpigo/internal/api/dto"
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
+)
+
+type GroupHandler struct {
+	*BaseHandler
+	sessionRepo    store.SessionRepositoryInterface
+	sessionManager *meow.SessionManager
+}
+
+func NewGroupHandler(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager) *GroupHandler {
+	return &GroupHandler{
+		BaseHandler:    NewBaseHandler("GroupHandler"),
+		sessionRepo:    sessionRepo,
+		sessionManager: sessionManager,
+	}
+}
+
+// @Summary      Obter informações do grupo
+// @Description  Retorna assunto, descrição, participantes, administradores e configurações de um grupo
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        groupJID   path      string  true  "JID do grupo"
+// @Success      200        {object}  dto.GroupInfoResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/group/{groupJID} [get]
+// @Security     ApiKeyAuth
+func (h *GroupHandler) GetGroupInfo(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	groupJID, ok := h.parseGroupJID(c)
+	if !ok {
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	h.logger.Debug("Buscando informações do grupo", "sessionID", sessionID, "groupJID", groupJID)
+
+	info, err := client.GetGroupInfo(groupJID)
+	if err != nil {
+		h.logger.Error("Erro ao buscar informações do grupo", "sessionID", sessionID, "groupJID", groupJID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao buscar informações do grupo",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToGroupInfoResponse(info))
+}
+
+// @Summary      Alterar nome do grupo
+// @Description  Altera o assunto (nome) de um grupo
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                    true  "ID da sessão"
+// @Param        groupJID   path      string                    true  "JID do grupo"
+// @Param        request    body      dto.SetGroupNameRequest   true  "Novo nome"
+// @Success      200        {object}  dto.GroupActionResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/group/{groupJID}/name [post]
+// @Security     ApiKeyAuth
+func (h *GroupHandler) SetGroupName(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	groupJID, ok := h.parseGroupJID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SetGroupNameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	if req.Name == "" || len(req.Name) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Nome do grupo deve ter entre 1 e 100 caracteres",
+		})
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	if err := client.SetGroupName(groupJID, req.Name); err != nil {
+		h.logger.Error("Erro ao alterar nome do grupo", "sessionID", sessionID, "groupJID", groupJID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao alterar nome do grupo",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Nome do grupo alterado", "sessionID", sessionID, "groupJID", groupJID)
+
+	c.JSON(http.StatusOK, &dto.GroupActionResponse{
+		Success:  true,
+		Message:  "Nome do grupo atualizado",
+		GroupJID: groupJID.String(),
+	})
+}
+
+// @Summary      Alterar descrição do grupo
+// @Description  Altera a descrição (topic) de um grupo. Uma descrição vazia remove a atual
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                     true  "ID da sessão"
+// @Param        groupJID   path      string                     true  "JID do grupo"
+// @Param        request    body      dto.SetGroupTopicRequest   true  "Nova descrição"
+// @Success      200        {object}  dto.GroupActionResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/group/{groupJID}/topic [post]
+// @Security     ApiKeyAuth
+func (h *GroupHandler) SetGroupTopic(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	groupJID, ok := h.parseGroupJID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SetGroupTopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	if len(req.Topic) > 2048 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Descrição do grupo deve ter no máximo 2048 caracteres",
+		})
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	if err := client.SetGroupTopic(groupJID, "", "", req.Topic); err != nil {
+		h.logger.Error("Erro ao alterar descrição do grupo", "sessionID", sessionID, "groupJID", groupJID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao alterar descrição do grupo",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Descrição do grupo alterada", "sessionID", sessionID, "groupJID", groupJID)
+
+	c.JSON(http.StatusOK, &dto.GroupActionResponse{
+		Success:  true,
+		Message:  "Descrição do grupo atualizada",
+		GroupJID: groupJID.String(),
+	})
+}
+
+// @Summary      Obter link de convite do grupo
+// @Description  Retorna o link de convite do grupo. Com reset=true o link atual é revogado e um novo é gerado
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true   "ID da sessão"
+// @Param        groupJID   path      string  true   "JID do grupo"
+// @Param        reset      query     bool    false  "Revogar o link atual e gerar um novo"
+// @Success      200        {object}  dto.GroupInviteLinkResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/group/{groupJID}/invite [get]
+// @Security     ApiKeyAuth
+func (h *GroupHandler) GetGroupInviteLink(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	groupJID, ok := h.parseGroupJID(c)
+	if !ok {
+		return
+	}
+
+	reset := false
+	if rawReset := c.Query("reset"); rawReset != "" {
+		parsed, err := strconv.ParseBool(rawReset)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Parâmetro reset inválido",
+				"details": err.Error(),
+			})
+			return
+		}
+		reset = parsed
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	link, err := client.GetGroupInviteLink(groupJID, reset)
+	if err != nil {
+		h.logger.Error("Erro ao obter link de convite", "sessionID", sessionID, "groupJID", groupJID, "reset", reset, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao obter link de convite",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Link de convite obtido", "sessionID", sessionID, "groupJID", groupJID, "reset", reset)
+
+	c.JSON(http.StatusOK, &dto.GroupInviteLinkResponse{
+		GroupJID:   groupJID.String(),
+		InviteLink: link,
+		Reset:      reset,
+	})
+}
+
+// @Summary      Entrar em grupo por link
+// @Description  Entra em um grupo usando um link de convite completo ou apenas o código
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                true  "ID da sessão"
+// @Param        request    body      dto.JoinGroupRequest  true  "Link ou código de convite"
+// @Success      200        {object}  dto.JoinGroupResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/group/join [post]
+// @Security     ApiKeyAuth
+func (h *GroupHandler) JoinGroupWithLink(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.JoinGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	code := parseInviteCode(req.Code)
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Código de convite é obrigatório",
+		})
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	groupJID, err := client.JoinGroupWithLink(code)
+	if err != nil {
+		h.logger.Error("Erro ao entrar no grupo", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao entrar no grupo",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Entrada no grupo realizada", "sessionID", sessionID, "groupJID", groupJID)
+
+	c.JSON(http.StatusOK, &dto.JoinGroupResponse{
+		Success:  true,
+		Message:  "Entrada no grupo realizada",
+		GroupJID: groupJID.String(),
+	})
+}
+
+// parseInviteCode aceita o link de convite completo ou apenas o código
+func parseInviteCode(raw string) string {
+	code := strings.TrimSpace(raw)
+	code = strings.TrimPrefix(code, "https://")
+	code = strings.TrimPrefix(code, "http://")
+	code = strings.TrimPrefix(code, "chat.whatsapp.com/")
+	code = strings.TrimPrefix(code, "invite/")
+	return strings.Trim(code, "/")
+}
+
+func (h *GroupHandler) parseGroupJID(c *gin.Context) (types.JID, bool) {
+	rawJID := c.Param("groupJID")
+	if rawJID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "JID do grupo é obrigatório",
+		})
+		return types.JID{}, false
+	}
+
+	groupJID, err := types.ParseJID(rawJID)
+	if err == nil && groupJID.Server != types.GroupServer {
+		err = fmt.Errorf("servidor '%s' não é um servidor de grupos", groupJID.Server)
+	}
+	if err != nil {
+		h.logger.Error("JID de grupo inválido", "groupJID", rawJID, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "JID do grupo inválido",
+			"details": err.Error(),
+		})
+		return types.JID{}, false
+	}
+
+	return groupJID, true
+}
@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mau.fi/whatsmeow"
 
 	"zpigo/internal/logger"
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
 )
 
 type BaseHandler struct {
@@ -59,20 +63,79 @@ func (h *BaseHandler) WriteSuccessResponse(w http.ResponseWriter, message string
 	h.WriteJSONResponse(w, http.StatusOK, response)
 }
 
+// getLoggedInClient retorna o cliente WhatsApp da sessão, respondendo com erro
+// quando a sessão não está ativa no manager ou não está autenticada
+func getLoggedInClient(c *gin.Context, sessionManager *meow.SessionManager, sessionID string) (*whatsmeow.Client, bool) {
+	client, exists := sessionManager.GetSession(sessionID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Cliente WhatsApp não encontrado",
+			"details": "Sessão não está ativa no gerenciador",
+		})
+		return nil, false
+	}
+
+	if !client.IsConnected() || !client.IsLoggedIn() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Cliente WhatsApp não conectado",
+			"details": "A sessão precisa estar conectada e autenticada",
+		})
+		return nil, false
+	}
+
+	return client, true
+}
+
+// healthCheckTimeout limita quanto tempo cada dependência tem para responder ao health check
+const healthCheckTimeout = 3 * time.Second
+
 // @Summary      Verificar saúde da API
-// @Description  Endpoint para verificar se a API está funcionando
+// @Description  Verifica se a API está funcionando e se o banco de dados e o store do WhatsApp respondem.
+// @Description  Retorna 503 com o status de cada dependência quando alguma delas falha
 // @Tags         health
 // @Accept       json
 // @Produce      json
 // @Success      200  {object}  map[string]interface{}
+// @Failure      503  {object}  map[string]interface{}
 // @Router       /health [get]
-func HealthCheck(c *gin.Context) {
+func HealthCheck(c *gin.Context, st *store.Store) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+	defer cancel()
+
+	checks := map[string]gin.H{
+		"database": dependencyStatus(st.PingDatabase(ctx)),
+		"store":    dependencyStatus(st.PingContainer(ctx)),
+	}
+
+	status := "ok"
+	statusCode := http.StatusOK
+	for _, check := range checks {
+		if check["status"] != "ok" {
+			status = "unhealthy"
+			statusCode = http.StatusServiceUnavailable
+		}
+	}
+
 	response := map[string]interface{}{
-		"status":    "ok",
+		"status":    status,
 		"service":   "zpigo-api",
 		"timestamp": time.Now().Unix(),
 		"version":   "1.0.0",
+		"checks":    checks,
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(statusCode, response)
+}
+
+// dependencyStatus converte o resultado da verificação de uma dependência no formato do health check
+func dependencyStatus(err error) gin.H {
+	if err != nil {
+		return gin.H{
+			"status": "error",
+			"error":  err.Error(),
+		}
+	}
+	return gin.H{"status": "ok"}
 }
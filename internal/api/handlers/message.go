@@ -4,9 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"mime"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mau.fi/whatsmeow"
@@ -16,8 +24,10 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"zpigo/internal/api/dto"
+	"zpigo/internal/config"
 	"zpigo/internal/meow"
 	"zpigo/internal/store"
+	"zpigo/internal/store/models"
 )
 
 type MessageHandler struct {
@@ -25,8 +35,18 @@ type MessageHandler struct {
 	sessionRepo    store.SessionRepositoryInterface
 	sessionManager *meow.SessionManager
 	authManager    *meow.AuthManager
+	mediaConfig    config.MediaConfig
+	sendTimeout    time.Duration
 }
 
+var (
+	errMediaTooLarge     = errors.New("mídia excede o tamanho máximo permitido")
+	errMediaTypeMismatch = errors.New("content-type da URL não corresponde ao mediaType")
+)
+
+// defaultSendTimeout limita quanto tempo um SendMessage pode esperar pela confirmação do WhatsApp
+const defaultSendTimeout = 30 * time.Second
+
 func NewMessageHandler(sessionRepo store.SessionRepositoryInterface, container *sqlstore.Container, db *sql.DB) *MessageHandler {
 	sessionManager := meow.NewSessionManager(container, db, sessionRepo)
 
@@ -35,20 +55,91 @@ func NewMessageHandler(sessionRepo store.SessionRepositoryInterface, container *
 		sessionRepo:    sessionRepo,
 		sessionManager: sessionManager,
 		authManager:    meow.NewAuthManager(db, sessionRepo),
+		mediaConfig: config.MediaConfig{
+			MaxURLDownloadSize: 64 * 1024 * 1024,
+			URLDownloadTimeout: 60,
+			MaxImageSize:       16 * 1024 * 1024,
+			MaxAudioSize:       16 * 1024 * 1024,
+			MaxVideoSize:       16 * 1024 * 1024,
+			MaxDocumentSize:    100 * 1024 * 1024,
+			TransferTimeout:    240,
+		},
+		sendTimeout: defaultSendTimeout,
+	}
+}
+
+// SetSendTimeout define o tempo máximo de cada envio de mensagem; valores <= 0 mantêm apenas o
+// prazo da própria requisição
+func (h *MessageHandler) SetSendTimeout(timeout time.Duration) {
+	h.sendTimeout = timeout
+}
+
+// sendContext deriva do contexto da requisição o contexto usado em SendMessage, limitado por sendTimeout
+func (h *MessageHandler) sendContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.sendTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, h.sendTimeout)
+}
+
+// transferContext deriva do contexto da requisição o contexto usado em uploads e downloads de mídia
+func (h *MessageHandler) transferContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.mediaConfig.TransferTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(h.mediaConfig.TransferTimeout)*time.Second)
+}
+
+// uploadContext deriva do contexto da requisição o contexto usado em uploads de mídia, limitado por
+// UploadTimeout ou, se não configurado, por TransferTimeout
+func (h *MessageHandler) uploadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.mediaConfig.UploadTimeout <= 0 {
+		return h.transferContext(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(h.mediaConfig.UploadTimeout)*time.Second)
+}
+
+// sendErrorStatus retorna 504 quando a operação com o WhatsApp excedeu o prazo e 500 nos demais casos
+func sendErrorStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
 	}
+	return http.StatusInternalServerError
+}
+
+// GetSessionManager retorna o gerenciador de sessões usado pelo handler
+func (h *MessageHandler) GetSessionManager() *meow.SessionManager {
+	return h.sessionManager
 }
 
-func NewMessageHandlerWithManager(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager) *MessageHandler {
+func NewMessageHandlerWithManager(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager, mediaConfig config.MediaConfig) *MessageHandler {
 	return &MessageHandler{
 		BaseHandler:    NewBaseHandler("MessageHandler"),
 		sessionRepo:    sessionRepo,
 		sessionManager: sessionManager,
 		authManager:    meow.NewAuthManager(sessionManager.GetDB(), sessionRepo),
+		mediaConfig:    mediaConfig,
+		sendTimeout:    defaultSendTimeout,
 	}
 }
 
 // @Summary      Enviar mensagem de texto via WhatsApp
-// @Description  Envia uma mensagem de texto para um número específico através da sessão WhatsApp
+// @Description  Envia uma mensagem de texto para um número específico através da sessão WhatsApp.
+// @Description  Com requestReceipts=true a mensagem é registrada para recibos detalhados antes do envio: o status em
+// @Description  /message/{messageID}/status passa a trazer sentAt (ack do servidor) e os recibos de cada participante,
+// @Description  inclusive os que o WhatsApp entrega agrupados em grupos, e os eventos Receipt saem no webhook com
+// @Description  detailed=true, chat e isGroup por até 24 horas. Em grupos isso gera até 2×N eventos Receipt (entrega e
+// @Description  leitura) para N participantes. Suportado para contatos (@s.whatsapp.net, @lid) e grupos; outros retornam 400.
+// @Description  Com variables os marcadores {{nome}} da mensagem são substituídos antes do envio e o texto final
+// @Description  é retornado em renderedText; marcadores sem valor correspondente retornam 400.
+// @Description  Para enviar a um canal, informe o JID do canal (ex: 120363000000000000@newsletter) em phone; a sessão
+// @Description  precisa ser dona ou administradora do canal, caso contrário a resposta é 403.
+// @Description  Com expiration (24h, 7d ou 90d) a mensagem é enviada como temporária; o valor deve coincidir com o
+// @Description  temporizador configurado no chat, pois os aplicativos do WhatsApp ignoram durações diferentes.
+// @Description  Com resolveRecipient=true o destinatário é consultado após o envio e a resposta inclui, em recipient,
+// @Description  se é uma conta comercial e o nome verificado; falhas na consulta apenas omitem o campo.
+// @Description  Com id informado, reenvios com o mesmo id dentro de 10 minutos devolvem o resultado do primeiro envio
+// @Description  (cabeçalho Idempotent-Replayed: true) em vez de enviar a mensagem de novo; 409 se o primeiro ainda estiver em andamento.
 // @Tags         messages
 // @Accept       json
 // @Produce      json
@@ -56,8 +147,12 @@ func NewMessageHandlerWithManager(sessionRepo store.SessionRepositoryInterface,
 // @Param        request    body      dto.SendTextMessageRequest    true  "Dados da mensagem"
 // @Success      200        {object}  dto.SendTextMessageResponse
 // @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      403        {object}  dto.MessageErrorResponse
 // @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      409        {object}  dto.MessageErrorResponse
+// @Failure      429        {object}  dto.MessageErrorResponse
 // @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
 // @Router       /sessions/{sessionID}/message/send/text [post]
 // @Security     ApiKeyAuth
 func (h *MessageHandler) SendTextMessage(c *gin.Context) {
@@ -77,11 +172,7 @@ func (h *MessageHandler) SendTextMessage(c *gin.Context) {
 	var req dto.SendTextMessageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
-		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
-			http.StatusBadRequest,
-			"Dados inválidos",
-			err.Error(),
-		))
+		respondMessageBindingError(c, err)
 		return
 	}
 
@@ -115,6 +206,32 @@ func (h *MessageHandler) SendTextMessage(c *gin.Context) {
 		return
 	}
 
+	var expiration time.Duration
+	if req.Expiration != "" {
+		parsed, ok := whatsmeow.ParseDisappearingTimerString(req.Expiration)
+		if !ok {
+			h.logger.Error("Expiração inválida", "sessionID", sessionID, "expiration", req.Expiration)
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Expiração inválida",
+				"Valores aceitos: 24h, 7d, 90d",
+			))
+			return
+		}
+		expiration = parsed
+	}
+
+	text, err := req.RenderMessage()
+	if err != nil {
+		h.logger.Error("Erro ao aplicar variáveis da mensagem", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Variáveis da mensagem inválidas",
+			err.Error(),
+		))
+		return
+	}
+
 	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
 	if err != nil {
 		h.logger.Error("Sessão não encontrada", "sessionID", sessionID, "error", err)
@@ -183,6 +300,57 @@ func (h *MessageHandler) SendTextMessage(c *gin.Context) {
 		return
 	}
 
+	if recipient.Server == types.NewsletterServer && !h.checkNewsletterAdmin(c, client, sessionID, recipient) {
+		return
+	}
+
+	if req.RequestReceipts {
+		if err := h.validateReceiptRecipient(recipient); err != nil {
+			h.logger.Error("Recibos detalhados não suportados", "sessionID", sessionID, "phone", req.Phone, "error", err)
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Recibos detalhados não suportados",
+				err.Error(),
+			))
+			return
+		}
+	}
+
+	if req.ReplyTo != nil {
+		contextInfo, err := h.buildReplyContext(req.ReplyTo, req.ContextInfo, recipient)
+		if err != nil {
+			h.logger.Error("Resposta inválida", "sessionID", sessionID, "error", err)
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Resposta inválida",
+				err.Error(),
+			))
+			return
+		}
+		req.ContextInfo = contextInfo
+	}
+
+	// Com ID informado pelo cliente, um reenvio dentro da janela de idempotência devolve o resultado
+	// anterior em vez de enviar a mensagem duas vezes
+	var idempotent *meow.IdempotentSend
+	if req.ID != "" {
+		var reserved bool
+		idempotent, reserved = h.sessionManager.GetCacheManager().ReserveSend(sessionID, req.ID)
+		if !reserved {
+			h.replayIdempotentSend(c, sessionID, req.ID, idempotent)
+			return
+		}
+		defer func() {
+			if _, completed := idempotent.Result(); !completed {
+				h.sessionManager.GetCacheManager().ReleaseSend(sessionID, req.ID)
+			}
+		}()
+	}
+
+	if !h.allowSend(c, sessionID) {
+		return
+	}
+
 	messageID := req.ID
 	if messageID == "" {
 		messageID = client.GenerateMessageID()
@@ -190,22 +358,38 @@ func (h *MessageHandler) SendTextMessage(c *gin.Context) {
 
 	msg := &waE2E.Message{
 		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
-			Text: proto.String(req.Message),
+			Text: proto.String(text),
 		},
 	}
 
+	if expiration > 0 {
+		if req.ContextInfo == nil {
+			req.ContextInfo = &waE2E.ContextInfo{}
+		}
+		req.ContextInfo.Expiration = proto.Uint32(uint32(expiration.Seconds()))
+	}
+
 	if req.ContextInfo != nil {
 		msg.ExtendedTextMessage.ContextInfo = req.ContextInfo
 		h.logger.Info("ContextInfo adicionado à mensagem", "sessionID", sessionID, "messageID", messageID)
 	}
 
+	previewAttached := false
+	if req.LinkPreview {
+		previewAttached = h.applyLinkPreview(c.Request.Context(), session, msg.ExtendedTextMessage)
+	}
+
 	h.logger.Info("Enviando mensagem", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID)
 
-	resp, err := client.SendMessage(context.Background(), recipient, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	sendCtx, cancel := h.sendContext(c.Request.Context())
+	defer cancel()
+	resp, err := h.sendMessage(sendCtx, sessionID, client, recipient, msg, whatsmeow.SendRequestExtra{ID: messageID}, req.RequestReceipts)
+	h.recordSend(sessionID, err)
 	if err != nil {
 		h.logger.Error("Erro ao enviar mensagem", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "error", err)
-		c.JSON(http.StatusInternalServerError, dto.ToMessageErrorResponse(
-			http.StatusInternalServerError,
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
 			"Erro ao enviar mensagem",
 			err.Error(),
 		))
@@ -216,12 +400,257 @@ func (h *MessageHandler) SendTextMessage(c *gin.Context) {
 
 	response := dto.ToMessageSuccessResponse(messageID, req.Phone)
 	response.Timestamp = resp.Timestamp.Unix()
+	response.ReceiptsRequested = req.RequestReceipts
+	if len(req.Variables) > 0 {
+		response.RenderedText = text
+	}
+	response.LinkPreview = previewAttached
+	if req.ResolveRecipient {
+		response.Recipient = h.resolveRecipient(client, sessionID, recipient)
+	}
+	if idempotent != nil {
+		idempotent.Complete(response)
+	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// replayIdempotentSend responde a um envio repetido: devolve a resposta do envio original quando ele
+// já foi concluído, ou 409 enquanto ele ainda está em andamento
+func (h *MessageHandler) replayIdempotentSend(c *gin.Context, sessionID, clientID string, entry *meow.IdempotentSend) {
+	result, completed := entry.Result()
+	if !completed {
+		h.logger.Warn("Envio com o mesmo ID ainda em andamento", "sessionID", sessionID, "messageID", clientID)
+		c.JSON(http.StatusConflict, dto.ToMessageErrorResponse(
+			http.StatusConflict,
+			"Envio em andamento",
+			"Já existe um envio em andamento com este ID",
+		))
+		return
+	}
+
+	h.logger.Info("Envio repetido ignorado, devolvendo resultado anterior", "sessionID", sessionID, "messageID", clientID)
+	c.Header("Idempotent-Replayed", "true")
+	c.JSON(http.StatusOK, result)
+}
+
+// resolveRecipient consulta o destinatário no WhatsApp e informa se é uma conta comercial com nome
+// verificado. A consulta é best-effort: falhas apenas omitem a informação, pois a mensagem já foi enviada.
+func (h *MessageHandler) resolveRecipient(client *whatsmeow.Client, sessionID string, recipient types.JID) *dto.RecipientInfo {
+	if recipient.Server != types.DefaultUserServer {
+		return nil
+	}
+
+	jid := recipient.ToNonAD()
+	infos, err := client.GetUserInfo([]types.JID{jid})
+	if err != nil {
+		h.logger.Warn("Erro ao consultar destinatário", "sessionID", sessionID, "jid", jid, "error", err)
+		return nil
+	}
+
+	info := &dto.RecipientInfo{JID: jid.String()}
+	if userInfo, ok := infos[jid]; ok && userInfo.VerifiedName != nil {
+		info.IsBusiness = true
+		info.VerifiedName = userInfo.VerifiedName.Details.GetVerifiedName()
+	}
+
+	return info
+}
+
+// bulkTextWorkers é a quantidade de envios simultâneos de SendBulkText
+const bulkTextWorkers = 5
+
+// @Summary      Enviar texto em lote
+// @Description  Envia o mesmo texto para até 100 destinatários, com até 5 envios simultâneos.
+// @Description  Cada envio consome o limite de envio da sessão; destinatários além do limite falham sem bloquear os demais.
+// @Description  Retorna 200 quando todos os envios têm sucesso e 207 com o resultado por destinatário quando algum falha.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                   true  "ID da sessão"
+// @Param        request    body      dto.SendBulkTextRequest  true  "Texto e destinatários"
+// @Success      200        {object}  dto.SendBulkTextResponse
+// @Success      207        {object}  dto.SendBulkTextResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/send/bulk [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) SendBulkText(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		h.logger.Error("ID da sessão não fornecido")
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão é obrigatório",
+			"O parâmetro sessionID deve ser fornecido na URL",
+		))
+		return
+	}
+
+	var req dto.SendBulkTextRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
+	}
+
+	if strings.TrimSpace(req.Message) == "" {
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Mensagem é obrigatória",
+			"O campo 'message' deve ser fornecido",
+		))
+		return
+	}
+
+	phones := req.NormalizePhones()
+	if len(phones) == 0 {
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Destinatários são obrigatórios",
+			"O campo 'phones' deve conter ao menos um número",
+		))
+		return
+	}
+	if len(phones) > dto.MaxBulkTextRecipients {
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Destinatários demais",
+			fmt.Sprintf("O envio em lote aceita no máximo %d destinatários", dto.MaxBulkTextRecipients),
+		))
+		return
+	}
+
+	client, ok := h.getSendClient(c, sessionID)
+	if !ok {
+		return
+	}
+
+	h.logger.Info("Iniciando envio em lote", "sessionID", sessionID, "recipients", len(phones))
+
+	results := make([]dto.BulkTextResult, len(phones))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range min(bulkTextWorkers, len(phones)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = h.sendBulkTextTo(c.Request.Context(), client, sessionID, phones[i], req.Message)
+			}
+		}()
+	}
+
+	for i := range phones {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	response := &dto.SendBulkTextResponse{
+		Total:   len(results),
+		Results: results,
+	}
+	for _, result := range results {
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	h.logger.Info("Envio em lote concluído", "sessionID", sessionID, "succeeded", response.Succeeded, "failed", response.Failed)
+
+	status := http.StatusOK
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, response)
+}
+
+// sendBulkTextTo envia o texto a um destinatário do lote, consumindo o limite de envio da sessão
+func (h *MessageHandler) sendBulkTextTo(ctx context.Context, client *whatsmeow.Client, sessionID, phone, text string) dto.BulkTextResult {
+	result := dto.BulkTextResult{Phone: phone}
+
+	recipient, err := h.parseJID(phone)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if allowed, retryAfter := h.sessionManager.AllowSend(sessionID); !allowed {
+		result.Error = fmt.Sprintf("limite de envio atingido, tente novamente em %d segundos", int(math.Ceil(retryAfter.Seconds())))
+		return result
+	}
+
+	msg := &waE2E.Message{
+		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text: proto.String(text),
+		},
+	}
+
+	messageID := client.GenerateMessageID()
+	sendCtx, cancel := h.sendContext(ctx)
+	defer cancel()
+
+	resp, err := client.SendMessage(sendCtx, recipient, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	h.recordSend(sessionID, err)
+	if err != nil {
+		h.logger.Warn("Falha ao enviar mensagem do lote", "sessionID", sessionID, "phone", phone, "error", err)
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Success = true
+	result.MessageID = messageID
+	result.Timestamp = resp.Timestamp.Unix()
+	return result
+}
+
+// applyLinkPreview preenche a prévia da primeira URL do texto usando o proxy da sessão. Falhas
+// apenas são registradas, para que a mensagem seja enviada sem prévia.
+func (h *MessageHandler) applyLinkPreview(ctx context.Context, session *models.Session, textMsg *waE2E.ExtendedTextMessage) bool {
+	matched := meow.FindFirstURL(textMsg.GetText())
+	if matched == "" {
+		return false
+	}
+
+	proxyURL := ""
+	if session.HasProxy() {
+		proxyURL = session.GetProxyURL()
+	}
+
+	preview, err := meow.FetchLinkPreview(ctx, matched, proxyURL)
+	if err != nil {
+		h.logger.Warn("Não foi possível gerar a prévia do link", "sessionID", session.ID, "url", matched, "error", err)
+		return false
+	}
+
+	textMsg.MatchedText = proto.String(preview.MatchedText)
+	textMsg.Title = proto.String(preview.Title)
+	if preview.Description != "" {
+		textMsg.Description = proto.String(preview.Description)
+	}
+	if len(preview.Thumbnail) > 0 {
+		textMsg.JPEGThumbnail = preview.Thumbnail
+	}
+	textMsg.PreviewType = waE2E.ExtendedTextMessage_NONE.Enum()
+
+	return true
+}
+
 // @Summary      Enviar mídia via WhatsApp
-// @Description  Envia mídia (imagem, áudio, vídeo, documento) para um número específico através da sessão WhatsApp
+// @Description  Envia mídia (imagem, áudio, vídeo, documento) para um número específico através da sessão WhatsApp.
+// @Description  Áudios com ptt=true são enviados como mensagem de voz (audio/ogg; codecs=opus), com duração e forma de onda opcionais.
+// @Description  A mídia pode ser enviada em base64 (mediaData) ou por URL (mediaUrl), baixada pelo proxy da sessão até o tamanho máximo configurado.
+// @Description  mediaUrl precisa apontar para um endereço público: hosts que resolvem para loopback, redes privadas ou link-local retornam 400.
+// @Description  Cada tipo tem um tamanho máximo configurável (padrão 16MB para imagem, áudio e vídeo e 100MB para documento); acima dele a resposta é 413.
+// @Description  Imagens recebem largura, altura e miniatura; vídeos MP4 recebem largura, altura e duração, mas são enviados sem miniatura.
+// @Description  requestReceipts=true registra a mensagem para recibos detalhados antes do envio, como em /message/text.
+// @Description  Com resolveRecipient=true a resposta inclui o nome verificado do destinatário quando for uma conta comercial.
+// @Description  Canais (JID @newsletter em phone) recebem a mídia sem criptografia e exigem que a sessão seja administradora (403 caso contrário).
 // @Tags         messages
 // @Accept       json
 // @Produce      json
@@ -229,8 +658,13 @@ func (h *MessageHandler) SendTextMessage(c *gin.Context) {
 // @Param        request    body      dto.SendMediaRequest       true  "Dados da mídia"
 // @Success      200        {object}  dto.SendMediaResponse
 // @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      403        {object}  dto.MessageErrorResponse
 // @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      413        {object}  dto.MessageErrorResponse
+// @Failure      415        {object}  dto.MessageErrorResponse
+// @Failure      429        {object}  dto.MessageErrorResponse
 // @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
 // @Router       /sessions/{sessionID}/message/send/media [post]
 // @Security     ApiKeyAuth
 func (h *MessageHandler) SendMedia(c *gin.Context) {
@@ -250,11 +684,7 @@ func (h *MessageHandler) SendMedia(c *gin.Context) {
 	var req dto.SendMediaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
-		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
-			http.StatusBadRequest,
-			"Dados inválidos",
-			err.Error(),
-		))
+		respondMessageBindingError(c, err)
 		return
 	}
 
@@ -278,12 +708,12 @@ func (h *MessageHandler) SendMedia(c *gin.Context) {
 		return
 	}
 
-	if req.MediaData == "" {
+	if req.MediaData == "" && req.MediaURL == "" {
 		h.logger.Error("Dados da mídia não fornecidos", "sessionID", sessionID)
 		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
 			http.StatusBadRequest,
 			"Dados da mídia são obrigatórios",
-			"O campo 'mediaData' deve ser fornecido",
+			"O campo 'mediaData' ou 'mediaUrl' deve ser fornecido",
 		))
 		return
 	}
@@ -308,7 +738,27 @@ func (h *MessageHandler) SendMedia(c *gin.Context) {
 		return
 	}
 
-	if !req.ValidateMediaData() {
+	if err := req.ValidateVoiceNote(); err != nil {
+		h.logger.Error("Parâmetros de mensagem de voz inválidos", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Parâmetros de mensagem de voz inválidos",
+			err.Error(),
+		))
+		return
+	}
+
+	if req.MediaData == "" && !req.ValidateMediaURL() {
+		h.logger.Error("URL da mídia inválida", "sessionID", sessionID, "mediaUrl", req.MediaURL)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"URL da mídia inválida",
+			"A URL deve usar o esquema http ou https",
+		))
+		return
+	}
+
+	if req.MediaData != "" && !req.ValidateMediaData() {
 		h.logger.Error("Dados da mídia inválidos", "sessionID", sessionID)
 		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
 			http.StatusBadRequest,
@@ -381,17 +831,76 @@ func (h *MessageHandler) SendMedia(c *gin.Context) {
 		return
 	}
 
-	mediaBytes, err := base64.StdEncoding.DecodeString(req.MediaData)
-	if err != nil {
-		h.logger.Error("Erro ao decodificar dados da mídia", "sessionID", sessionID, "error", err)
-		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
-			http.StatusBadRequest,
-			"Erro ao decodificar mídia",
-			err.Error(),
-		))
+	if recipient.Server == types.NewsletterServer && !h.checkNewsletterAdmin(c, client, sessionID, recipient) {
+		return
+	}
+
+	if req.RequestReceipts {
+		if err := h.validateReceiptRecipient(recipient); err != nil {
+			h.logger.Error("Recibos detalhados não suportados", "sessionID", sessionID, "phone", req.Phone, "error", err)
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Recibos detalhados não suportados",
+				err.Error(),
+			))
+			return
+		}
+	}
+
+	if req.ReplyTo != nil {
+		contextInfo, err := h.buildReplyContext(req.ReplyTo, req.ContextInfo, recipient)
+		if err != nil {
+			h.logger.Error("Resposta inválida", "sessionID", sessionID, "error", err)
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Resposta inválida",
+				err.Error(),
+			))
+			return
+		}
+		req.ContextInfo = contextInfo
+	}
+
+	if !h.allowSend(c, sessionID) {
 		return
 	}
 
+	var mediaBytes []byte
+	var fetchedMimeType string
+	if req.MediaData != "" {
+		mediaBytes, err = base64.StdEncoding.DecodeString(req.MediaData)
+		if err != nil {
+			h.logger.Error("Erro ao decodificar dados da mídia", "sessionID", sessionID, "error", err)
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Erro ao decodificar mídia",
+				err.Error(),
+			))
+			return
+		}
+		if !h.checkMediaSize(c, sessionID, req.MediaType, len(mediaBytes)) {
+			return
+		}
+	} else {
+		mediaBytes, fetchedMimeType, err = h.fetchMediaFromURL(c.Request.Context(), session, req.MediaURL, req.MediaType)
+		if err != nil {
+			h.logger.Error("Erro ao baixar mídia da URL", "sessionID", sessionID, "mediaUrl", req.MediaURL, "error", err)
+			status := http.StatusBadRequest
+			switch {
+			case errors.Is(err, errMediaTooLarge):
+				status = http.StatusRequestEntityTooLarge
+			case errors.Is(err, errMediaTypeMismatch):
+				status = http.StatusUnsupportedMediaType
+			}
+			c.JSON(status, dto.ToMessageErrorResponse(
+				status,
+				"Erro ao baixar mídia da URL",
+				err.Error(),
+			))
+			return
+		}
+	}
+
 	messageID := req.ID
 	if messageID == "" {
 		messageID = client.GenerateMessageID()
@@ -399,6 +908,9 @@ func (h *MessageHandler) SendMedia(c *gin.Context) {
 
 	fileName := req.GetFileName()
 	mimeType := req.GetMimeType()
+	if req.MimeType == "" && fetchedMimeType != "" && !req.PTT {
+		mimeType = fetchedMimeType
+	}
 
 	h.logger.Info("Preparando upload de mídia",
 		"sessionID", sessionID,
@@ -427,18 +939,22 @@ func (h *MessageHandler) SendMedia(c *gin.Context) {
 		return
 	}
 
-	uploadResp, err := client.Upload(context.Background(), mediaBytes, mediaType)
+	// Mídias de canais não são criptografadas e são enviadas com o handle retornado pelo upload
+	uploadCtx, cancelUpload := h.uploadContext(c.Request.Context())
+	uploadResp, err := meow.UploadMedia(uploadCtx, client, mediaBytes, mediaType, recipient.Server == types.NewsletterServer, h.logger.With("sessionID", sessionID).With("messageID", messageID))
+	cancelUpload()
 	if err != nil {
 		h.logger.Error("Erro ao fazer upload da mídia", "sessionID", sessionID, "error", err)
-		c.JSON(http.StatusInternalServerError, dto.ToMessageErrorResponse(
-			http.StatusInternalServerError,
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
 			"Erro ao fazer upload da mídia",
 			err.Error(),
 		))
 		return
 	}
 
-	msg, err := h.createMediaMessage(req.MediaType, uploadResp, fileName, mimeType, req.Caption, req.ContextInfo)
+	msg, err := h.createMediaMessage(&req, uploadResp, mediaBytes, fileName, mimeType)
 	if err != nil {
 		h.logger.Error("Erro ao criar mensagem de mídia", "sessionID", sessionID, "error", err)
 		c.JSON(http.StatusInternalServerError, dto.ToMessageErrorResponse(
@@ -451,11 +967,15 @@ func (h *MessageHandler) SendMedia(c *gin.Context) {
 
 	h.logger.Info("Enviando mídia", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "mediaType", req.MediaType)
 
-	resp, err := client.SendMessage(context.Background(), recipient, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	sendCtx, cancel := h.sendContext(c.Request.Context())
+	defer cancel()
+	resp, err := h.sendMessage(sendCtx, sessionID, client, recipient, msg, whatsmeow.SendRequestExtra{ID: messageID, MediaHandle: uploadResp.Handle}, req.RequestReceipts)
+	h.recordSend(sessionID, err)
 	if err != nil {
 		h.logger.Error("Erro ao enviar mídia", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "error", err)
-		c.JSON(http.StatusInternalServerError, dto.ToMessageErrorResponse(
-			http.StatusInternalServerError,
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
 			"Erro ao enviar mídia",
 			err.Error(),
 		))
@@ -466,34 +986,1870 @@ func (h *MessageHandler) SendMedia(c *gin.Context) {
 
 	response := dto.ToMediaSuccessResponse(messageID, req.Phone, req.MediaType, fileName)
 	response.Timestamp = resp.Timestamp.Unix()
+	response.ReceiptsRequested = req.RequestReceipts
+	response.ViewOnce = req.ViewOnce
+	if req.ResolveRecipient {
+		response.Recipient = h.resolveRecipient(client, sessionID, recipient)
+	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-func (h *MessageHandler) parseJID(phone string) (types.JID, error) {
-	if len(phone) > 0 && phone[0] == '+' {
-		phone = phone[1:]
+// @Summary      Enviar figurinha
+// @Description  Envia uma figurinha WebP, estática ou animada, para um número ou grupo.
+// @Description  A figurinha pode ser enviada em base64 (stickerData) ou por URL (stickerUrl), baixada pelo proxy da sessão.
+// @Description  Somente WebP é aceito; outros formatos precisam ser convertidos antes do envio.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                  true  "ID da sessão"
+// @Param        request    body      dto.SendStickerRequest  true  "Dados da figurinha"
+// @Success      200        {object}  dto.SendStickerResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      413        {object}  dto.MessageErrorResponse
+// @Failure      415        {object}  dto.MessageErrorResponse
+// @Failure      429        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/send/sticker [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) SendSticker(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		h.logger.Error("ID da sessão não fornecido")
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão é obrigatório",
+			"O parâmetro sessionID deve ser fornecido na URL",
+		))
+		return
 	}
 
-	if !strings.ContainsRune(phone, '@') {
-		return types.NewJID(phone, types.DefaultUserServer), nil
+	var req dto.SendStickerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
 	}
 
-	recipient, err := types.ParseJID(phone)
-	if err != nil {
-		h.logger.Error("JID inválido", "phone", phone, "error", err)
-		return types.JID{}, fmt.Errorf("JID inválido: %v", err)
+	if err := req.Validate(); err != nil {
+		h.logger.Error("Figurinha inválida", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Figurinha inválida",
+			err.Error(),
+		))
+		return
 	}
 
-	if recipient.User == "" {
-		h.logger.Error("JID inválido: nenhum servidor especificado", "phone", phone)
-		return types.JID{}, fmt.Errorf("JID inválido: nenhum servidor especificado")
+	if err := h.validateContextInfo(req.ContextInfo); err != nil {
+		h.logger.Error("ContextInfo inválido", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ContextInfo inválido",
+			err.Error(),
+		))
+		return
 	}
 
-	return recipient, nil
-}
+	recipient, err := h.parseJID(req.Phone)
+	if err != nil {
+		h.logger.Error("Erro ao parsear número de telefone", "sessionID", sessionID, "phone", req.Phone, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Número de telefone inválido",
+			err.Error(),
+		))
+		return
+	}
 
-func (h *MessageHandler) validateContextInfo(contextInfo *waE2E.ContextInfo) error {
+	if req.ReplyTo != nil {
+		contextInfo, err := h.buildReplyContext(req.ReplyTo, req.ContextInfo, recipient)
+		if err != nil {
+			h.logger.Error("Resposta inválida", "sessionID", sessionID, "error", err)
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Resposta inválida",
+				err.Error(),
+			))
+			return
+		}
+		req.ContextInfo = contextInfo
+	}
+
+	client, ok := h.getSendClient(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if !h.allowSend(c, sessionID) {
+		return
+	}
+
+	var stickerBytes []byte
+	if req.StickerData != "" {
+		stickerBytes, err = base64.StdEncoding.DecodeString(req.StickerData)
+		if err != nil {
+			h.logger.Error("Erro ao decodificar figurinha", "sessionID", sessionID, "error", err)
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Erro ao decodificar figurinha",
+				err.Error(),
+			))
+			return
+		}
+		if !h.checkMediaSize(c, sessionID, "sticker", len(stickerBytes)) {
+			return
+		}
+	} else {
+		session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+		if err != nil {
+			h.logger.Error("Sessão não encontrada", "sessionID", sessionID, "error", err)
+			c.JSON(http.StatusNotFound, dto.ToMessageErrorResponse(
+				http.StatusNotFound,
+				"Sessão não encontrada",
+				err.Error(),
+			))
+			return
+		}
+
+		stickerBytes, _, err = h.fetchMediaFromURL(c.Request.Context(), session, req.StickerURL, "sticker")
+		if err != nil {
+			h.logger.Error("Erro ao baixar figurinha da URL", "sessionID", sessionID, "stickerUrl", req.StickerURL, "error", err)
+			status := http.StatusBadRequest
+			switch {
+			case errors.Is(err, errMediaTooLarge):
+				status = http.StatusRequestEntityTooLarge
+			case errors.Is(err, errMediaTypeMismatch):
+				status = http.StatusUnsupportedMediaType
+			}
+			c.JSON(status, dto.ToMessageErrorResponse(
+				status,
+				"Erro ao baixar figurinha da URL",
+				err.Error(),
+			))
+			return
+		}
+	}
+
+	info, err := meow.DecodeWebPInfo(stickerBytes)
+	if err != nil {
+		h.logger.Error("Figurinha não é um WebP válido", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusUnsupportedMediaType, dto.ToMessageErrorResponse(
+			http.StatusUnsupportedMediaType,
+			"Formato de figurinha inválido",
+			"A figurinha deve estar no formato WebP: "+err.Error(),
+		))
+		return
+	}
+
+	messageID := req.ID
+	if messageID == "" {
+		messageID = client.GenerateMessageID()
+	}
+
+	uploadCtx, cancelUpload := h.uploadContext(c.Request.Context())
+	uploadResp, err := meow.UploadMedia(uploadCtx, client, stickerBytes, whatsmeow.MediaImage, false, h.logger.With("sessionID", sessionID).With("messageID", messageID))
+	cancelUpload()
+	if err != nil {
+		h.logger.Error("Erro ao fazer upload da figurinha", "sessionID", sessionID, "error", err)
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
+			"Erro ao fazer upload da figurinha",
+			err.Error(),
+		))
+		return
+	}
+
+	msg := &waE2E.Message{
+		StickerMessage: &waE2E.StickerMessage{
+			URL:               proto.String(uploadResp.URL),
+			DirectPath:        proto.String(uploadResp.DirectPath),
+			MediaKey:          uploadResp.MediaKey,
+			Mimetype:          proto.String(dto.StickerMimeType),
+			FileEncSHA256:     uploadResp.FileEncSHA256,
+			FileSHA256:        uploadResp.FileSHA256,
+			FileLength:        proto.Uint64(uploadResp.FileLength),
+			MediaKeyTimestamp: proto.Int64(time.Now().Unix()),
+			Width:             proto.Uint32(info.Width),
+			Height:            proto.Uint32(info.Height),
+			IsAnimated:        proto.Bool(info.Animated),
+			ContextInfo:       req.ContextInfo,
+		},
+	}
+
+	h.logger.Info("Enviando figurinha", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "animated", info.Animated)
+
+	sendCtx, cancel := h.sendContext(c.Request.Context())
+	defer cancel()
+	resp, err := client.SendMessage(sendCtx, recipient, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	h.recordSend(sessionID, err)
+	if err != nil {
+		h.logger.Error("Erro ao enviar figurinha", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "error", err)
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
+			"Erro ao enviar figurinha",
+			err.Error(),
+		))
+		return
+	}
+
+	h.logger.Info("Figurinha enviada com sucesso", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "timestamp", resp.Timestamp)
+
+	c.JSON(http.StatusOK, &dto.SendStickerResponse{
+		Success:   true,
+		MessageID: messageID,
+		Timestamp: resp.Timestamp.Unix(),
+		Details:   "Figurinha enviada com sucesso",
+		Phone:     req.Phone,
+		Width:     info.Width,
+		Height:    info.Height,
+		Animated:  info.Animated,
+	})
+}
+
+// @Summary      Enviar mensagem de fluxo (WhatsApp Flows)
+// @Description  Envia uma mensagem interativa nativa com um botão que abre um fluxo (formulário) do WhatsApp Business.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                      true  "ID da sessão"
+// @Param        request    body      dto.SendFlowMessageRequest  true  "Dados da mensagem de fluxo"
+// @Success      200        {object}  dto.SendTextMessageResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      429        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/send/flow [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) SendFlowMessage(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		h.logger.Error("ID da sessão não fornecido")
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão é obrigatório",
+			"O parâmetro sessionID deve ser fornecido na URL",
+		))
+		return
+	}
+
+	var req dto.SendFlowMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
+	}
+
+	if !req.ValidatePhoneNumber() {
+		h.logger.Error("Formato de telefone inválido", "sessionID", sessionID, "phone", req.Phone)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Formato de telefone inválido",
+			"O número deve conter entre 8 e 15 dígitos",
+		))
+		return
+	}
+
+	if err := req.Flow.Validate(); err != nil {
+		h.logger.Error("Parâmetros do fluxo inválidos", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Parâmetros do fluxo inválidos",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := h.validateContextInfo(req.ContextInfo); err != nil {
+		h.logger.Error("ContextInfo inválido", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ContextInfo inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	buttonParams, err := req.Flow.ToButtonParamsJSON()
+	if err != nil {
+		h.logger.Error("Erro ao serializar parâmetros do fluxo", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Parâmetros do fluxo inválidos",
+			err.Error(),
+		))
+		return
+	}
+
+	client, ok := h.getSendClient(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if !h.allowSend(c, sessionID) {
+		return
+	}
+
+	recipient, err := h.parseJID(req.Phone)
+	if err != nil {
+		h.logger.Error("Erro ao parsear número de telefone", "sessionID", sessionID, "phone", req.Phone, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Número de telefone inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	messageID := req.ID
+	if messageID == "" {
+		messageID = client.GenerateMessageID()
+	}
+
+	interactive := &waE2E.InteractiveMessage{
+		Body: &waE2E.InteractiveMessage_Body{
+			Text: proto.String(req.Body),
+		},
+		InteractiveMessage: &waE2E.InteractiveMessage_NativeFlowMessage_{
+			NativeFlowMessage: &waE2E.InteractiveMessage_NativeFlowMessage{
+				Buttons: []*waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton{
+					{
+						Name:             proto.String("flow"),
+						ButtonParamsJSON: proto.String(buttonParams),
+					},
+				},
+				MessageVersion: proto.Int32(1),
+			},
+		},
+		ContextInfo: req.ContextInfo,
+	}
+
+	if req.Header != "" {
+		interactive.Header = &waE2E.InteractiveMessage_Header{
+			Title:              proto.String(req.Header),
+			HasMediaAttachment: proto.Bool(false),
+		}
+	}
+
+	if req.Footer != "" {
+		interactive.Footer = &waE2E.InteractiveMessage_Footer{
+			Text: proto.String(req.Footer),
+		}
+	}
+
+	msg := &waE2E.Message{
+		InteractiveMessage: interactive,
+	}
+
+	h.logger.Info("Enviando mensagem de fluxo", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "flowID", req.Flow.FlowID)
+
+	sendCtx, cancel := h.sendContext(c.Request.Context())
+	defer cancel()
+	resp, err := client.SendMessage(sendCtx, recipient, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	h.recordSend(sessionID, err)
+	if err != nil {
+		h.logger.Error("Erro ao enviar mensagem de fluxo", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "error", err)
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
+			"Erro ao enviar mensagem de fluxo",
+			err.Error(),
+		))
+		return
+	}
+
+	h.logger.Info("Mensagem de fluxo enviada com sucesso", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "timestamp", resp.Timestamp)
+
+	response := dto.ToMessageSuccessResponse(messageID, req.Phone)
+	response.Timestamp = resp.Timestamp.Unix()
+	response.Details = "Mensagem de fluxo enviada com sucesso"
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Enviar enquete
+// @Description  Envia uma enquete com 2 a 12 opções. Opções duplicadas são removidas antes do envio.
+// @Description  O messageId retornado identifica a enquete nos eventos PollUpdate de votos.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string               true  "ID da sessão"
+// @Param        request    body      dto.SendPollRequest  true  "Dados da enquete"
+// @Success      200        {object}  dto.SendPollResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      429        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/send/poll [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) SendPoll(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		h.logger.Error("ID da sessão não fornecido")
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão é obrigatório",
+			"O parâmetro sessionID deve ser fornecido na URL",
+		))
+		return
+	}
+
+	var req dto.SendPollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
+	}
+
+	options := req.NormalizeOptions()
+	if err := req.Validate(options); err != nil {
+		h.logger.Error("Enquete inválida", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Enquete inválida",
+			err.Error(),
+		))
+		return
+	}
+
+	recipient, err := h.parseJID(req.Phone)
+	if err != nil {
+		h.logger.Error("Erro ao parsear número de telefone", "sessionID", sessionID, "phone", req.Phone, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Número de telefone inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	client, ok := h.getSendClient(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if !h.allowSend(c, sessionID) {
+		return
+	}
+
+	messageID := req.ID
+	if messageID == "" {
+		messageID = client.GenerateMessageID()
+	}
+
+	msg := client.BuildPollCreation(req.Question, options, req.SelectableCount)
+
+	h.logger.Info("Enviando enquete", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "options", len(options))
+
+	sendCtx, cancel := h.sendContext(c.Request.Context())
+	defer cancel()
+	resp, err := client.SendMessage(sendCtx, recipient, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	h.recordSend(sessionID, err)
+	if err != nil {
+		h.logger.Error("Erro ao enviar enquete", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "error", err)
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
+			"Erro ao enviar enquete",
+			err.Error(),
+		))
+		return
+	}
+
+	h.logger.Info("Enquete enviada com sucesso", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "timestamp", resp.Timestamp)
+
+	c.JSON(http.StatusOK, &dto.SendPollResponse{
+		Success:         true,
+		MessageID:       messageID,
+		Timestamp:       resp.Timestamp.Unix(),
+		Details:         "Enquete enviada com sucesso",
+		Phone:           req.Phone,
+		Options:         options,
+		SelectableCount: req.SelectableCount,
+	})
+}
+
+// @Summary      Enviar mensagem de lista
+// @Description  Envia um menu interativo de seleção única com título, descrição, texto do botão e seções de opções.
+// @Description  Cada opção tem um rowId, que precisa ser único na lista e é retornado quando o destinatário escolhe a opção.
+// @Description  O WhatsApp aceita até 10 opções somando todas as seções.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                      true  "ID da sessão"
+// @Param        request    body      dto.SendListMessageRequest  true  "Dados da lista"
+// @Success      200        {object}  dto.SendListMessageResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      429        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/send/list [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) SendListMessage(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		h.logger.Error("ID da sessão não fornecido")
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão é obrigatório",
+			"O parâmetro sessionID deve ser fornecido na URL",
+		))
+		return
+	}
+
+	var req dto.SendListMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.Error("Lista inválida", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Lista inválida",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := h.validateContextInfo(req.ContextInfo); err != nil {
+		h.logger.Error("ContextInfo inválido", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ContextInfo inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	recipient, err := h.parseJID(req.Phone)
+	if err != nil {
+		h.logger.Error("Erro ao parsear número de telefone", "sessionID", sessionID, "phone", req.Phone, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Número de telefone inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	client, ok := h.getSendClient(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if !h.allowSend(c, sessionID) {
+		return
+	}
+
+	messageID := req.ID
+	if messageID == "" {
+		messageID = client.GenerateMessageID()
+	}
+
+	msg := &waE2E.Message{
+		ListMessage: h.buildListMessage(&req),
+	}
+
+	rows := req.RowCount()
+	h.logger.Info("Enviando mensagem de lista", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "sections", len(req.Sections), "rows", rows)
+
+	sendCtx, cancel := h.sendContext(c.Request.Context())
+	defer cancel()
+	resp, err := client.SendMessage(sendCtx, recipient, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	h.recordSend(sessionID, err)
+	if err != nil {
+		h.logger.Error("Erro ao enviar lista", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "error", err)
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
+			"Erro ao enviar lista",
+			err.Error(),
+		))
+		return
+	}
+
+	h.logger.Info("Lista enviada com sucesso", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "timestamp", resp.Timestamp)
+
+	c.JSON(http.StatusOK, &dto.SendListMessageResponse{
+		Success:   true,
+		MessageID: messageID,
+		Timestamp: resp.Timestamp.Unix(),
+		Details:   "Lista enviada com sucesso",
+		Phone:     req.Phone,
+		Rows:      rows,
+	})
+}
+
+// @Summary      Enviar mensagem com botões
+// @Description  Envia um texto com até 3 botões de resposta rápida. Cada botão tem um id, que precisa ser único na
+// @Description  mensagem e é retornado quando o destinatário toca no botão. A resposta chega como evento de mensagem
+// @Description  do tipo buttonResponse, que cita (quoted.messageId) o messageId retornado aqui.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                         true  "ID da sessão"
+// @Param        request    body      dto.SendButtonsMessageRequest  true  "Dados dos botões"
+// @Success      200        {object}  dto.SendButtonsMessageResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      429        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/send/buttons [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) SendButtonsMessage(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		h.logger.Error("ID da sessão não fornecido")
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão é obrigatório",
+			"O parâmetro sessionID deve ser fornecido na URL",
+		))
+		return
+	}
+
+	var req dto.SendButtonsMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.Error("Botões inválidos", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Botões inválidos",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := h.validateContextInfo(req.ContextInfo); err != nil {
+		h.logger.Error("ContextInfo inválido", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ContextInfo inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	recipient, err := h.parseJID(req.Phone)
+	if err != nil {
+		h.logger.Error("Erro ao parsear número de telefone", "sessionID", sessionID, "phone", req.Phone, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Número de telefone inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	client, ok := h.getSendClient(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if !h.allowSend(c, sessionID) {
+		return
+	}
+
+	messageID := req.ID
+	if messageID == "" {
+		messageID = client.GenerateMessageID()
+	}
+
+	msg := &waE2E.Message{
+		ButtonsMessage: h.buildButtonsMessage(&req),
+	}
+
+	h.logger.Info("Enviando mensagem com botões", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "buttons", len(req.Buttons))
+
+	sendCtx, cancel := h.sendContext(c.Request.Context())
+	defer cancel()
+	resp, err := client.SendMessage(sendCtx, recipient, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	h.recordSend(sessionID, err)
+	if err != nil {
+		h.logger.Error("Erro ao enviar botões", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "error", err)
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
+			"Erro ao enviar botões",
+			err.Error(),
+		))
+		return
+	}
+
+	h.logger.Info("Botões enviados com sucesso", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "timestamp", resp.Timestamp)
+
+	c.JSON(http.StatusOK, &dto.SendButtonsMessageResponse{
+		Success:   true,
+		MessageID: messageID,
+		Timestamp: resp.Timestamp.Unix(),
+		Details:   "Botões enviados com sucesso",
+		Phone:     req.Phone,
+		Buttons:   len(req.Buttons),
+	})
+}
+
+// @Summary      Encaminhar mensagem
+// @Description  Encaminha uma mensagem existente para outro destino, marcando-a como encaminhada e incrementando o
+// @Description  forwardingScore. O conteúdo original é lido do campo message (payload bruto recebido no webhook);
+// @Description  sem ele, apenas mensagens de texto com histórico de edição podem ser reconstruídas. Quando não for
+// @Description  possível reconstruir o conteúdo, a resposta é 422.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                     true  "ID da sessão"
+// @Param        request    body      dto.ForwardMessageRequest  true  "Mensagem de origem e destino"
+// @Success      200        {object}  dto.SendTextMessageResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      422        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/forward [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) ForwardMessage(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		h.logger.Error("ID da sessão não fornecido")
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão é obrigatório",
+			"O parâmetro sessionID deve ser fornecido na URL",
+		))
+		return
+	}
+
+	var req dto.ForwardMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
+	}
+
+	sourceChat, err := h.parseJID(req.Chat)
+	if err != nil {
+		h.logger.Error("Conversa de origem inválida", "sessionID", sessionID, "chat", req.Chat, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Conversa de origem inválida",
+			err.Error(),
+		))
+		return
+	}
+
+	recipient, err := h.parseJID(req.Phone)
+	if err != nil {
+		h.logger.Error("Erro ao parsear número de telefone", "sessionID", sessionID, "phone", req.Phone, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Número de telefone inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	client, ok := h.getSendClient(c, sessionID)
+	if !ok {
+		return
+	}
+
+	original := req.Message
+	if original == nil {
+		original = h.reconstructFromEdits(c.Request.Context(), sessionID, req.MessageID, sourceChat)
+	}
+
+	msg, err := buildForwardedMessage(original)
+	if err != nil {
+		h.logger.Warn("Não foi possível reconstruir a mensagem original", "sessionID", sessionID, "chat", req.Chat, "messageID", req.MessageID, "fromMe", req.FromMe, "error", err)
+		c.JSON(http.StatusUnprocessableEntity, dto.ToMessageErrorResponse(
+			http.StatusUnprocessableEntity,
+			"Não foi possível reconstruir a mensagem original",
+			fmt.Sprintf("%v; envie o payload bruto da mensagem no campo message", err),
+		))
+		return
+	}
+
+	if !h.allowSend(c, sessionID) {
+		return
+	}
+
+	messageID := req.ID
+	if messageID == "" {
+		messageID = client.GenerateMessageID()
+	}
+
+	h.logger.Info("Encaminhando mensagem", "sessionID", sessionID, "chat", req.Chat, "originalID", req.MessageID, "phone", req.Phone, "messageID", messageID)
+
+	sendCtx, cancel := h.sendContext(c.Request.Context())
+	defer cancel()
+	resp, err := client.SendMessage(sendCtx, recipient, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	h.recordSend(sessionID, err)
+	if err != nil {
+		h.logger.Error("Erro ao encaminhar mensagem", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "error", err)
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
+			"Erro ao encaminhar mensagem",
+			err.Error(),
+		))
+		return
+	}
+
+	h.logger.Info("Mensagem encaminhada com sucesso", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID, "timestamp", resp.Timestamp)
+
+	response := dto.ToMessageSuccessResponse(messageID, req.Phone)
+	response.Timestamp = resp.Timestamp.Unix()
+	response.Details = "Mensagem encaminhada com sucesso"
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Editar mensagem de texto
+// @Description  Edita uma mensagem de texto enviada por esta sessão e registra a nova versão no histórico de edições
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                  true  "ID da sessão"
+// @Param        messageID  path      string                  true  "ID da mensagem original"
+// @Param        request    body      dto.EditMessageRequest  true  "Novo conteúdo"
+// @Success      200        {object}  dto.SendTextMessageResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/{messageID}/edit [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) EditMessage(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	messageID := c.Param("messageID")
+	if sessionID == "" || messageID == "" {
+		h.logger.Error("ID da sessão ou da mensagem não fornecido", "sessionID", sessionID, "messageID", messageID)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão e da mensagem são obrigatórios",
+			"Os parâmetros sessionID e messageID devem ser fornecidos na URL",
+		))
+		return
+	}
+
+	var req dto.EditMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
+	}
+
+	client, ok := h.getSendClient(c, sessionID)
+	if !ok {
+		return
+	}
+
+	chat, err := h.parseJID(req.Phone)
+	if err != nil {
+		h.logger.Error("Erro ao parsear número de telefone", "sessionID", sessionID, "phone", req.Phone, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Número de telefone inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	newContent := &waE2E.Message{
+		Conversation: proto.String(req.Message),
+	}
+
+	h.logger.Info("Editando mensagem", "sessionID", sessionID, "phone", req.Phone, "messageID", messageID)
+
+	sendCtx, cancel := h.sendContext(c.Request.Context())
+	defer cancel()
+	resp, err := client.SendMessage(sendCtx, chat, client.BuildEdit(chat, messageID, newContent))
+	if err != nil {
+		h.logger.Error("Erro ao editar mensagem", "sessionID", sessionID, "messageID", messageID, "error", err)
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
+			"Erro ao editar mensagem",
+			err.Error(),
+		))
+		return
+	}
+
+	edit := &models.MessageEdit{
+		SessionID: sessionID,
+		MessageID: messageID,
+		ChatJID:   chat.String(),
+		Content:   req.Message,
+		IsFromMe:  true,
+		Source:    models.EditSourceAPI,
+		EditedAt:  resp.Timestamp,
+	}
+	if client.Store.ID != nil {
+		edit.SenderJID = client.Store.ID.ToNonAD().String()
+	}
+
+	if err := h.sessionManager.GetMessageEditRepository().Create(c.Request.Context(), edit); err != nil {
+		h.logger.Warn("Mensagem editada, mas não foi possível registrar o histórico", "sessionID", sessionID, "messageID", messageID, "error", err)
+	}
+
+	h.logger.Info("Mensagem editada com sucesso", "sessionID", sessionID, "messageID", messageID, "timestamp", resp.Timestamp)
+
+	response := dto.ToMessageSuccessResponse(messageID, req.Phone)
+	response.Timestamp = resp.Timestamp.Unix()
+	response.Details = "Mensagem editada com sucesso"
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Histórico de edições da mensagem
+// @Description  Retorna as versões registradas de uma mensagem, tanto das edições feitas pela API quanto das recebidas em eventos
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        messageID  path      string  true  "ID da mensagem original"
+// @Success      200        {object}  dto.MessageEditHistoryResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/{messageID}/edits [get]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) GetMessageEdits(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	messageID := c.Param("messageID")
+	if sessionID == "" || messageID == "" {
+		h.logger.Error("ID da sessão ou da mensagem não fornecido", "sessionID", sessionID, "messageID", messageID)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão e da mensagem são obrigatórios",
+			"Os parâmetros sessionID e messageID devem ser fornecidos na URL",
+		))
+		return
+	}
+
+	edits, err := h.sessionManager.GetMessageEditRepository().ListByMessageID(c.Request.Context(), sessionID, messageID)
+	if err != nil {
+		h.logger.Error("Erro ao buscar histórico de edições", "sessionID", sessionID, "messageID", messageID, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ToMessageErrorResponse(
+			http.StatusInternalServerError,
+			"Erro ao buscar histórico de edições",
+			err.Error(),
+		))
+		return
+	}
+
+	response := &dto.MessageEditHistoryResponse{
+		MessageID: messageID,
+		Edits:     make([]dto.MessageEditResponse, 0, len(edits)),
+		Total:     len(edits),
+	}
+
+	for _, edit := range edits {
+		response.ChatJID = edit.ChatJID
+		response.Edits = append(response.Edits, dto.MessageEditResponse{
+			Content:   edit.Content,
+			EditedAt:  edit.EditedAt.Unix(),
+			IsFromMe:  edit.IsFromMe,
+			SenderJID: edit.SenderJID,
+			Source:    string(edit.Source),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Limites da listagem de mensagens armazenadas
+const (
+	defaultStoredMessagesLimit = 50
+	maxStoredMessagesLimit     = 500
+)
+
+// @Summary      Listar mensagens armazenadas
+// @Description  Lista as mensagens armazenadas da sessão, da mais recente para a mais antiga. Só há mensagens quando o
+// @Description  armazenamento está habilitado no servidor (MESSAGE_STORE_ENABLED) e a sessão optou por ele em
+// @Description  PUT /sessions/{sessionID}/messages/storage; mensagens anteriores à opção ou fora da retenção não constam.
+// @Description  Para paginar, repita a consulta com before e beforeId iguais a nextBefore e nextBeforeId da resposta.
+// @Tags         messages
+// @Produce      json
+// @Param        sessionID  path      string  true   "ID da sessão"
+// @Param        chat       query     string  false  "Número ou JID da conversa; vazio inclui todas"
+// @Param        limit      query     int     false  "Quantidade máxima de mensagens (padrão 50, máximo 500)"
+// @Param        before     query     int     false  "Timestamp Unix; retorna apenas mensagens anteriores a ele"
+// @Param        beforeId   query     string  false  "ID da última mensagem da página anterior, usado junto com before"
+// @Success      200        {object}  dto.StoredMessagesResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      401        {object}  map[string]interface{}
+// @Failure      403        {object}  map[string]interface{}
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/messages [get]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) ListMessages(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var chatJID string
+	if chat := c.Query("chat"); chat != "" {
+		jid, err := parseChatJID(chat)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Conversa inválida",
+				err.Error(),
+			))
+			return
+		}
+		chatJID = jid.String()
+	}
+
+	limit := defaultStoredMessagesLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxStoredMessagesLimit {
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Limite inválido",
+				fmt.Sprintf("limit deve ser um número entre 1 e %d", maxStoredMessagesLimit),
+			))
+			return
+		}
+		limit = parsed
+	}
+
+	var before time.Time
+	if value := c.Query("before"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"Parâmetro before inválido",
+				"before deve ser um timestamp Unix em segundos",
+			))
+			return
+		}
+		before = time.Unix(parsed, 0)
+	}
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		c.JSON(http.StatusNotFound, dto.ToMessageErrorResponse(
+			http.StatusNotFound,
+			"Sessão não encontrada",
+			err.Error(),
+		))
+		return
+	}
+
+	messages, err := h.sessionManager.GetMessageRepository().List(c.Request.Context(), sessionID, chatJID, before, c.Query("beforeId"), limit)
+	if err != nil {
+		h.logger.Error("Erro ao listar mensagens armazenadas", "sessionID", sessionID, "chat", chatJID, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ToMessageErrorResponse(
+			http.StatusInternalServerError,
+			"Erro ao listar mensagens",
+			err.Error(),
+		))
+		return
+	}
+
+	response := &dto.StoredMessagesResponse{
+		Messages: make([]dto.StoredMessageResponse, 0, len(messages)),
+		Total:    len(messages),
+	}
+	for _, message := range messages {
+		response.Messages = append(response.Messages, dto.StoredMessageResponse{
+			MessageID: message.MessageID,
+			ChatJID:   message.ChatJID,
+			SenderJID: message.SenderJID,
+			IsFromMe:  message.IsFromMe,
+			IsGroup:   message.IsGroup,
+			Type:      message.Type,
+			Text:      message.Text,
+			SentAt:    message.SentAt.Unix(),
+		})
+	}
+	if len(messages) == limit {
+		last := messages[len(messages)-1]
+		response.NextBefore = last.SentAt.Unix()
+		response.NextBeforeID = last.MessageID
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Status de entrega da mensagem
+// @Description  Retorna o último status conhecido (sent, delivered, read ou played) de uma mensagem enviada, a partir dos
+// @Description  recibos recebidos desde que a sessão foi iniciada. Os status ficam em memória por até 72 horas.
+// @Description  Mensagens enviadas com requestReceipts=true incluem sentAt e, em participants, os recibos de cada destinatário.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        messageID  path      string  true  "ID da mensagem"
+// @Success      200        {object}  dto.MessageStatusResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/{messageID}/status [get]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) GetMessageStatus(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	messageID := c.Param("messageID")
+	if sessionID == "" || messageID == "" {
+		h.logger.Error("ID da sessão ou da mensagem não fornecido", "sessionID", sessionID, "messageID", messageID)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão e da mensagem são obrigatórios",
+			"Os parâmetros sessionID e messageID devem ser fornecidos na URL",
+		))
+		return
+	}
+
+	zpigoClient, exists := h.sessionManager.GetZPigoClient(sessionID)
+	if !exists {
+		c.JSON(http.StatusNotFound, dto.ToMessageErrorResponse(
+			http.StatusNotFound,
+			"Sessão não encontrada",
+			"Sessão não está ativa no gerenciador",
+		))
+		return
+	}
+
+	status, found := zpigoClient.GetMessageStatus(messageID)
+	if !found {
+		c.JSON(http.StatusNotFound, dto.ToMessageErrorResponse(
+			http.StatusNotFound,
+			"Status da mensagem desconhecido",
+			"Nenhum recibo foi recebido para esta mensagem",
+		))
+		return
+	}
+
+	response := &dto.MessageStatusResponse{
+		MessageID: status.MessageID,
+		ChatJID:   status.Chat.String(),
+		Status:    status.Status(),
+		UpdatedAt: status.UpdatedAt.Unix(),
+	}
+	if !status.DeliveredAt.IsZero() {
+		response.DeliveredAt = status.DeliveredAt.Unix()
+	}
+	if !status.ReadAt.IsZero() {
+		response.ReadAt = status.ReadAt.Unix()
+	}
+	if !status.PlayedAt.IsZero() {
+		response.PlayedAt = status.PlayedAt.Unix()
+	}
+	if !status.SentAt.IsZero() {
+		response.SentAt = status.SentAt.Unix()
+	}
+	for jid, participant := range status.Participants {
+		entry := dto.MessageParticipantStatus{JID: jid.String()}
+		if !participant.DeliveredAt.IsZero() {
+			entry.DeliveredAt = participant.DeliveredAt.Unix()
+		}
+		if !participant.ReadAt.IsZero() {
+			entry.ReadAt = participant.ReadAt.Unix()
+		}
+		if !participant.PlayedAt.IsZero() {
+			entry.PlayedAt = participant.PlayedAt.Unix()
+		}
+		response.Participants = append(response.Participants, entry)
+	}
+	sort.Slice(response.Participants, func(i, j int) bool {
+		return response.Participants[i].JID < response.Participants[j].JID
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Leitores da mensagem em grupo
+// @Description  Lista os participantes que leram (ou reproduziram) uma mensagem enviada em grupo, a partir dos recibos por participante
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        messageID  path      string  true  "ID da mensagem"
+// @Success      200        {object}  dto.MessageReadersResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/{messageID}/readers [get]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) GetMessageReaders(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	messageID := c.Param("messageID")
+	if sessionID == "" || messageID == "" {
+		h.logger.Error("ID da sessão ou da mensagem não fornecido", "sessionID", sessionID, "messageID", messageID)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão e da mensagem são obrigatórios",
+			"Os parâmetros sessionID e messageID devem ser fornecidos na URL",
+		))
+		return
+	}
+
+	receipts, err := h.sessionManager.GetMessageReceiptRepository().ListByMessageID(c.Request.Context(), sessionID, messageID)
+	if err != nil {
+		h.logger.Error("Erro ao buscar leitores da mensagem", "sessionID", sessionID, "messageID", messageID, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ToMessageErrorResponse(
+			http.StatusInternalServerError,
+			"Erro ao buscar leitores da mensagem",
+			err.Error(),
+		))
+		return
+	}
+
+	response := &dto.MessageReadersResponse{
+		MessageID: messageID,
+		Readers:   make([]dto.MessageReaderResponse, 0, len(receipts)),
+	}
+
+	positions := make(map[string]int, len(receipts))
+	for _, receipt := range receipts {
+		response.ChatJID = receipt.ChatJID
+
+		index, exists := positions[receipt.ReaderJID]
+		if !exists {
+			index = len(response.Readers)
+			positions[receipt.ReaderJID] = index
+			response.Readers = append(response.Readers, dto.MessageReaderResponse{ReaderJID: receipt.ReaderJID})
+		}
+
+		switch types.ReceiptType(receipt.ReceiptType) {
+		case types.ReceiptTypeRead:
+			response.Readers[index].ReadAt = receipt.ReceivedAt.Unix()
+		case types.ReceiptTypePlayed:
+			response.Readers[index].PlayedAt = receipt.ReceivedAt.Unix()
+		}
+	}
+	response.Total = len(response.Readers)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// getSendClient verifica se a sessão existe e está conectada, tanto no banco quanto no gerenciador,
+// e retorna o cliente pronto para envio. Em caso de falha a resposta de erro já é escrita.
+func (h *MessageHandler) getSendClient(c *gin.Context, sessionID string) (*whatsmeow.Client, bool) {
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Sessão não encontrada", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, dto.ToMessageErrorResponse(
+			http.StatusNotFound,
+			"Sessão não encontrada",
+			err.Error(),
+		))
+		return nil, false
+	}
+
+	if !session.IsConnected() {
+		h.logger.Error("Sessão não está conectada", "sessionID", sessionID, "status", session.Status)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Sessão não conectada",
+			"A sessão precisa estar conectada para enviar mensagens",
+		))
+		return nil, false
+	}
+
+	client, exists := h.sessionManager.GetSession(sessionID)
+	if !exists {
+		h.logger.Error("Cliente WhatsApp não encontrado", "sessionID", sessionID)
+		c.JSON(http.StatusInternalServerError, dto.ToMessageErrorResponse(
+			http.StatusInternalServerError,
+			"Cliente WhatsApp não encontrado",
+			"Sessão não está ativa no gerenciador",
+		))
+		return nil, false
+	}
+
+	if !client.IsConnected() {
+		h.logger.Error("Cliente WhatsApp não está conectado", "sessionID", sessionID)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Cliente WhatsApp não conectado",
+			"O cliente WhatsApp precisa estar conectado",
+		))
+		return nil, false
+	}
+
+	return client, true
+}
+
+// checkMediaSize responde 413 com o limite no detalhe quando a mídia decodificada excede o tamanho
+// máximo configurado para o tipo
+func (h *MessageHandler) checkMediaSize(c *gin.Context, sessionID, mediaType string, size int) bool {
+	maxSize := h.mediaConfig.MaxSize(mediaType)
+	if maxSize <= 0 || int64(size) <= maxSize {
+		return true
+	}
+
+	h.logger.Warn("Mídia excede o tamanho máximo", "sessionID", sessionID, "mediaType", mediaType, "size", size, "maxSize", maxSize)
+	c.JSON(http.StatusRequestEntityTooLarge, dto.ToMessageErrorResponse(
+		http.StatusRequestEntityTooLarge,
+		"Mídia excede o tamanho máximo",
+		fmt.Sprintf("%s: %d bytes (limite %d bytes)", mediaType, size, maxSize),
+	))
+	return false
+}
+
+// fetchMediaFromURL baixa a mídia de mediaURL usando o proxy da sessão, respeitando o menor entre o
+// tamanho máximo de download por URL e o limite do tipo de mídia, e retorna os bytes junto com o
+// content-type validado contra o mediaType
+func (h *MessageHandler) fetchMediaFromURL(ctx context.Context, session *models.Session, mediaURL, mediaType string) ([]byte, string, error) {
+	maxSize := h.mediaConfig.MaxURLDownloadSize
+	if typeLimit := h.mediaConfig.MaxSize(mediaType); typeLimit > 0 && (maxSize <= 0 || typeLimit < maxSize) {
+		maxSize = typeLimit
+	}
+
+	var proxyURL string
+	if session.HasProxy() {
+		proxyURL = session.GetProxyURL()
+	}
+
+	// mediaUrl vem do cliente: apenas destinos públicos, para não expor serviços internos
+	httpClient := meow.NewPublicHTTPClient(proxyURL)
+	httpClient.SetTimeout(time.Duration(h.mediaConfig.URLDownloadTimeout) * time.Second)
+
+	resp, err := httpClient.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(mediaURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro na requisição: %w", err)
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return nil, "", fmt.Errorf("URL retornou status %d", resp.StatusCode())
+	}
+
+	if resp.RawResponse.ContentLength > maxSize {
+		return nil, "", fmt.Errorf("%w: %d bytes (limite %d bytes)", errMediaTooLarge, resp.RawResponse.ContentLength, maxSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, "", fmt.Errorf("%w: limite %d bytes", errMediaTooLarge, maxSize)
+	}
+
+	contentType := resp.Header().Get("Content-Type")
+	if contentType == "" || strings.HasPrefix(contentType, "application/octet-stream") {
+		contentType = http.DetectContentType(data)
+	}
+
+	if !dto.MediaMatchesContentType(mediaType, contentType) {
+		return nil, "", fmt.Errorf("%w: recebido '%s' para mediaType '%s'", errMediaTypeMismatch, contentType, mediaType)
+	}
+
+	mimeType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mimeType = contentType
+	}
+
+	return data, mimeType, nil
+}
+
+// @Summary      Baixar mídia recebida
+// @Description  Baixa e descriptografa a mídia de uma mensagem recebida a partir dos campos do evento Message
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                    true  "ID da sessão"
+// @Param        request    body      dto.DownloadMediaRequest  true  "Campos da mídia"
+// @Success      200        {object}  dto.DownloadMediaResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Failure      504        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/message/download [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) DownloadMedia(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		h.logger.Error("ID da sessão não fornecido")
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"ID da sessão é obrigatório",
+			"O parâmetro sessionID deve ser fornecido na URL",
+		))
+		return
+	}
+
+	var req dto.DownloadMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
+	}
+
+	if !req.ValidateMediaType() {
+		h.logger.Error("Tipo de mídia inválido", "sessionID", sessionID, "mediaType", req.MediaType)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Tipo de mídia inválido",
+			"Tipos suportados: image, audio, video, document, sticker",
+		))
+		return
+	}
+
+	if req.URL == "" && req.DirectPath == "" {
+		h.logger.Error("URL e directPath não fornecidos", "sessionID", sessionID)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Localização da mídia é obrigatória",
+			"Ao menos um dos campos 'url' ou 'directPath' deve ser fornecido",
+		))
+		return
+	}
+
+	client, exists := h.sessionManager.GetSession(sessionID)
+	if !exists {
+		h.logger.Error("Cliente WhatsApp não encontrado", "sessionID", sessionID)
+		c.JSON(http.StatusNotFound, dto.ToMessageErrorResponse(
+			http.StatusNotFound,
+			"Cliente WhatsApp não encontrado",
+			"Sessão não está ativa no gerenciador",
+		))
+		return
+	}
+
+	if !client.IsConnected() {
+		h.logger.Error("Cliente WhatsApp não está conectado", "sessionID", sessionID)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Cliente WhatsApp não conectado",
+			"O cliente WhatsApp precisa estar conectado",
+		))
+		return
+	}
+
+	h.logger.Info("Baixando mídia", "sessionID", sessionID, "mediaType", req.MediaType, "fileLength", req.FileLength)
+
+	downloadCtx, cancel := h.transferContext(c.Request.Context())
+	defer cancel()
+
+	data, err := client.Download(downloadCtx, h.buildDownloadableMessage(&req))
+	if err != nil {
+		h.logger.Error("Erro ao baixar mídia", "sessionID", sessionID, "mediaType", req.MediaType, "error", err)
+		status := sendErrorStatus(err)
+		c.JSON(status, dto.ToMessageErrorResponse(
+			status,
+			"Erro ao baixar mídia",
+			err.Error(),
+		))
+		return
+	}
+
+	mimeType := req.MimeType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	h.logger.Info("Mídia baixada com sucesso", "sessionID", sessionID, "mediaType", req.MediaType, "size", len(data), "mimeType", mimeType)
+
+	c.JSON(http.StatusOK, &dto.DownloadMediaResponse{
+		Success:   true,
+		MediaType: strings.ToLower(req.MediaType),
+		MimeType:  mimeType,
+		Size:      len(data),
+		Data:      base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+// buildDownloadableMessage monta a submensagem correspondente ao tipo de mídia,
+// que o whatsmeow usa para identificar as chaves de descriptografia
+func (h *MessageHandler) buildDownloadableMessage(req *dto.DownloadMediaRequest) whatsmeow.DownloadableMessage {
+	switch strings.ToLower(req.MediaType) {
+	case "image":
+		return &waE2E.ImageMessage{
+			URL:           proto.String(req.URL),
+			DirectPath:    proto.String(req.DirectPath),
+			MediaKey:      req.MediaKey,
+			FileEncSHA256: req.FileEncSHA256,
+			FileSHA256:    req.FileSHA256,
+			FileLength:    proto.Uint64(req.FileLength),
+		}
+	case "audio":
+		return &waE2E.AudioMessage{
+			URL:           proto.String(req.URL),
+			DirectPath:    proto.String(req.DirectPath),
+			MediaKey:      req.MediaKey,
+			FileEncSHA256: req.FileEncSHA256,
+			FileSHA256:    req.FileSHA256,
+			FileLength:    proto.Uint64(req.FileLength),
+		}
+	case "video":
+		return &waE2E.VideoMessage{
+			URL:           proto.String(req.URL),
+			DirectPath:    proto.String(req.DirectPath),
+			MediaKey:      req.MediaKey,
+			FileEncSHA256: req.FileEncSHA256,
+			FileSHA256:    req.FileSHA256,
+			FileLength:    proto.Uint64(req.FileLength),
+		}
+	case "sticker":
+		return &waE2E.StickerMessage{
+			URL:           proto.String(req.URL),
+			DirectPath:    proto.String(req.DirectPath),
+			MediaKey:      req.MediaKey,
+			FileEncSHA256: req.FileEncSHA256,
+			FileSHA256:    req.FileSHA256,
+			FileLength:    proto.Uint64(req.FileLength),
+		}
+	default:
+		return &waE2E.DocumentMessage{
+			URL:           proto.String(req.URL),
+			DirectPath:    proto.String(req.DirectPath),
+			MediaKey:      req.MediaKey,
+			FileEncSHA256: req.FileEncSHA256,
+			FileSHA256:    req.FileSHA256,
+			FileLength:    proto.Uint64(req.FileLength),
+		}
+	}
+}
+
+// parseJID aceita um número de telefone (com ou sem +) ou um JID completo de contato (telefone ou
+// LID), grupo, canal (ex: 120363000000000000@newsletter) ou lista de transmissão
+func (h *MessageHandler) parseJID(phone string) (types.JID, error) {
+	recipient, err := parseRecipientJID(phone)
+	if err != nil {
+		h.logger.Error("JID inválido", "phone", phone, "error", err)
+		return types.JID{}, err
+	}
+
+	return recipient, nil
+}
+
+// parseRecipientJID converte o destinatário informado na requisição em JID. Números sem servidor usam
+// s.whatsapp.net; JIDs @lid (contas identificadas pelo LID, sem número exposto) são mantidos como
+// vieram, pois o whatsmeow roteia o envio pelo próprio LID. O sufixo legado @c.us é aceito como
+// s.whatsapp.net.
+func parseRecipientJID(value string) (types.JID, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "+")
+
+	if !strings.ContainsRune(value, '@') {
+		return types.NewJID(value, types.DefaultUserServer), nil
+	}
+
+	recipient, err := types.ParseJID(value)
+	if err != nil {
+		return types.JID{}, fmt.Errorf("JID inválido: %v", err)
+	}
+
+	if recipient.User == "" {
+		return types.JID{}, fmt.Errorf("JID inválido: nenhum usuário especificado")
+	}
+
+	switch recipient.Server {
+	case types.LegacyUserServer:
+		recipient.Server = types.DefaultUserServer
+	case types.DefaultUserServer, types.HiddenUserServer, types.GroupServer, types.NewsletterServer, types.BroadcastServer, types.BotServer:
+	default:
+		return types.JID{}, fmt.Errorf("JID inválido: servidor '%s' não suportado", recipient.Server)
+	}
+
+	// Mensagens são endereçadas à conta, não a um dispositivo específico (ex: 123:12@lid)
+	if isUserJID(recipient) {
+		recipient = recipient.ToNonAD()
+	}
+
+	return recipient, nil
+}
+
+// checkNewsletterAdmin responde 403 quando a sessão não é dona nem administradora do canal, pois apenas
+// administradores podem publicar em canais
+func (h *MessageHandler) checkNewsletterAdmin(c *gin.Context, client *whatsmeow.Client, sessionID string, newsletter types.JID) bool {
+	meta, err := client.GetNewsletterInfo(newsletter)
+	if err != nil || meta == nil {
+		h.logger.Error("Erro ao buscar informações do canal", "sessionID", sessionID, "newsletter", newsletter, "error", err)
+		c.JSON(http.StatusNotFound, dto.ToMessageErrorResponse(
+			http.StatusNotFound,
+			"Canal não encontrado",
+			fmt.Sprint(err),
+		))
+		return false
+	}
+
+	if meta.ViewerMeta != nil {
+		switch meta.ViewerMeta.Role {
+		case types.NewsletterRoleOwner, types.NewsletterRoleAdmin:
+			return true
+		}
+	}
+
+	h.logger.Warn("Sessão não é administradora do canal", "sessionID", sessionID, "newsletter", newsletter)
+	c.JSON(http.StatusForbidden, dto.ToMessageErrorResponse(
+		http.StatusForbidden,
+		"Sessão não é administradora do canal",
+		"Apenas o dono ou administradores podem enviar mensagens para o canal",
+	))
+	return false
+}
+
+// validateReceiptRecipient verifica se o destinatário envia recibos individuais.
+// Listas de transmissão e newsletters não enviam recibos por participante.
+func (h *MessageHandler) validateReceiptRecipient(recipient types.JID) error {
+	switch recipient.Server {
+	case types.DefaultUserServer, types.HiddenUserServer, types.GroupServer:
+		return nil
+	default:
+		return fmt.Errorf("recibos detalhados não são suportados para destinatários '%s'", recipient.Server)
+	}
+}
+
+// @Summary      Validar vCard
+// @Description  Valida um vCard (ou monta um a partir de name e phone) e retorna a versão normalizada e os campos extraídos, sem enviar
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                       true  "ID da sessão"
+// @Param        request    body      dto.ValidateContactRequest   true  "vCard ou nome e telefone"
+// @Success      200        {object}  dto.ValidateContactResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/contact/validate [post]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) ValidateContact(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.ValidateContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondMessageBindingError(c, err)
+		return
+	}
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		c.JSON(http.StatusNotFound, dto.ToMessageErrorResponse(
+			http.StatusNotFound,
+			"Sessão não encontrada",
+			err.Error(),
+		))
+		return
+	}
+
+	raw := req.VCard
+	if strings.TrimSpace(raw) == "" {
+		if req.Name == "" && req.Phone == "" {
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"vCard inválido",
+				"Informe vcard ou name e phone",
+			))
+			return
+		}
+
+		built, err := dto.BuildVCard(req.Name, req.Phone)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+				http.StatusBadRequest,
+				"vCard inválido",
+				err.Error(),
+			))
+			return
+		}
+		raw = built
+	}
+
+	fields, err := dto.ParseVCard(raw)
+	if err != nil {
+		h.logger.Debug("vCard inválido", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"vCard inválido",
+			err.Error(),
+		))
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.ValidateContactResponse{
+		Valid:       true,
+		DisplayName: fields.FullName,
+		VCard:       dto.NormalizeVCard(fields),
+		Fields:      fields,
+	})
+}
+
+// @Summary      Resolver JID do destinatário
+// @Description  Aplica ao número a mesma normalização usada nos envios e retorna o JID exato de destino, incluindo o
+// @Description  servidor. Com check=true, consulta também se o número está registrado no WhatsApp e o JID canônico
+// @Description  retornado, o que exige a sessão conectada.
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true   "ID da sessão"
+// @Param        phone      query     string  true   "Número do telefone ou JID"
+// @Param        check      query     bool    false  "Consultar o número no WhatsApp"
+// @Success      200        {object}  dto.ResolveJIDResponse
+// @Failure      400        {object}  dto.MessageErrorResponse
+// @Failure      404        {object}  dto.MessageErrorResponse
+// @Failure      500        {object}  dto.MessageErrorResponse
+// @Router       /sessions/{sessionID}/resolve/jid [get]
+// @Security     ApiKeyAuth
+func (h *MessageHandler) ResolveJID(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	phone := c.Query("phone")
+
+	normalized := strings.TrimSpace(phone)
+	if normalized == "" {
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Número de telefone é obrigatório",
+			"Informe o parâmetro phone",
+		))
+		return
+	}
+
+	check, err := strconv.ParseBool(c.DefaultQuery("check", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Parâmetro check inválido",
+			"Use true ou false",
+		))
+		return
+	}
+
+	jid, err := h.parseJID(normalized)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Não foi possível resolver o JID",
+			err.Error(),
+		))
+		return
+	}
+
+	resp := &dto.ResolveJIDResponse{
+		Phone:           phone,
+		NormalizedPhone: jid.User,
+		JID:             jid.String(),
+		Server:          jid.Server,
+		Warnings:        []string{},
+	}
+
+	if jid.Server == types.DefaultUserServer {
+		if !isDigits(jid.User) {
+			resp.Warnings = append(resp.Warnings, "o número contém caracteres além de dígitos; remova espaços, hífens e parênteses")
+		} else if len(jid.User) < 8 || len(jid.User) > 15 {
+			resp.Warnings = append(resp.Warnings, fmt.Sprintf("o número tem %d dígitos; números internacionais têm entre 8 e 15", len(jid.User)))
+		}
+	}
+
+	if !check {
+		if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+			c.JSON(http.StatusNotFound, dto.ToMessageErrorResponse(
+				http.StatusNotFound,
+				"Sessão não encontrada",
+				err.Error(),
+			))
+			return
+		}
+
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	if jid.Server != types.DefaultUserServer {
+		c.JSON(http.StatusBadRequest, dto.ToMessageErrorResponse(
+			http.StatusBadRequest,
+			"Consulta não suportada",
+			fmt.Sprintf("Apenas números de telefone podem ser consultados no WhatsApp, recebido servidor %s", jid.Server),
+		))
+		return
+	}
+
+	client, ok := h.getSendClient(c, sessionID)
+	if !ok {
+		return
+	}
+
+	registered, err := client.IsOnWhatsApp([]string{"+" + jid.User})
+	if err != nil {
+		h.logger.Error("Erro ao verificar número no WhatsApp", "sessionID", sessionID, "phone", jid.User, "error", err)
+		c.JSON(http.StatusInternalServerError, dto.ToMessageErrorResponse(
+			http.StatusInternalServerError,
+			"Erro ao verificar número no WhatsApp",
+			err.Error(),
+		))
+		return
+	}
+
+	resp.Checked = true
+	isRegistered := len(registered) > 0 && registered[0].IsIn
+	resp.Registered = &isRegistered
+	if isRegistered {
+		resp.RegisteredJID = registered[0].JID.String()
+		if registered[0].JID.ToNonAD() != jid.ToNonAD() {
+			resp.Warnings = append(resp.Warnings, "o WhatsApp registra este número com outro JID; envie para registeredJid")
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// allowSend aplica o limite de envios por minuto da sessão, respondendo 429 quando excedido
+func (h *MessageHandler) allowSend(c *gin.Context, sessionID string) bool {
+	allowed, retryAfter := h.sessionManager.AllowSend(sessionID)
+	if allowed {
+		return true
+	}
+
+	retrySeconds := int(math.Ceil(retryAfter.Seconds()))
+	h.logger.Warn("Limite de envio atingido", "sessionID", sessionID, "retryAfter", retrySeconds)
+	c.Header("Retry-After", strconv.Itoa(retrySeconds))
+	c.JSON(http.StatusTooManyRequests, dto.ToMessageErrorResponse(
+		http.StatusTooManyRequests,
+		"Limite de envio atingido",
+		fmt.Sprintf("Aguarde %d segundos antes de enviar novas mensagens", retrySeconds),
+	))
+	return false
+}
+
+// recordSend atualiza os contadores do cliente ZPigo após uma tentativa de envio
+func (h *MessageHandler) recordSend(sessionID string, err error) {
+	zpigoClient, exists := h.sessionManager.GetZPigoClient(sessionID)
+	if !exists {
+		return
+	}
+
+	if err != nil {
+		zpigoClient.RecordError(fmt.Sprintf("erro ao enviar mensagem: %v", err))
+		return
+	}
+
+	zpigoClient.RecordMessageSent()
+}
+
+// sendMessage envia a mensagem; com requestReceipts o envio passa pelo ZPigoClient, que registra a
+// mensagem para recibos detalhados antes de enviá-la
+func (h *MessageHandler) sendMessage(ctx context.Context, sessionID string, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, extra whatsmeow.SendRequestExtra, requestReceipts bool) (whatsmeow.SendResponse, error) {
+	if requestReceipts {
+		if zpigoClient, exists := h.sessionManager.GetZPigoClient(sessionID); exists {
+			return zpigoClient.SendMessage(ctx, recipient, msg, extra, true)
+		}
+		h.logger.Warn("Cliente ZPigo não encontrado para rastrear recibos", "sessionID", sessionID, "messageID", extra.ID)
+	}
+
+	return client.SendMessage(ctx, recipient, msg, extra)
+}
+
+func (h *MessageHandler) validateContextInfo(contextInfo *waE2E.ContextInfo) error {
 	if contextInfo == nil {
 		return nil // ContextInfo é opcional
 	}
@@ -508,23 +2864,297 @@ func (h *MessageHandler) validateContextInfo(contextInfo *waE2E.ContextInfo) err
 		if contextInfo.StanzaID == nil {
 			return fmt.Errorf("stanzaID é obrigatório quando Participant é fornecido")
 		}
+
+		participant, err := types.ParseJID(contextInfo.GetParticipant())
+		if err != nil {
+			return fmt.Errorf("participant inválido: %v", err)
+		}
+		if !isUserJID(participant) {
+			return fmt.Errorf("participant deve ser o JID de um usuário, recebido %s", contextInfo.GetParticipant())
+		}
+	}
+
+	if contextInfo.RemoteJID != nil {
+		if _, err := types.ParseJID(contextInfo.GetRemoteJID()); err != nil {
+			return fmt.Errorf("remoteJID inválido: %v", err)
+		}
+	}
+
+	for i, mentioned := range contextInfo.MentionedJID {
+		jid, err := types.ParseJID(mentioned)
+		if err != nil {
+			return fmt.Errorf("mentionedJID[%d] inválido (%s): %v", i, mentioned, err)
+		}
+		if !isUserJID(jid) {
+			return fmt.Errorf("mentionedJID[%d] deve ser o JID de um usuário, recebido %s", i, mentioned)
+		}
+	}
+
+	return nil
+}
+
+// buildReplyContext monta o contexto de citação a partir de replyTo, preservando menções já
+// informadas em contextInfo. RemoteJID só é preenchido quando a mensagem citada está em outra
+// conversa; nesse caso, e em grupos, o autor da mensagem citada é obrigatório.
+func (h *MessageHandler) buildReplyContext(replyTo *dto.ReplyTo, contextInfo *waE2E.ContextInfo, recipient types.JID) (*waE2E.ContextInfo, error) {
+	if replyTo.MessageID == "" {
+		return nil, fmt.Errorf("replyTo.messageId é obrigatório")
+	}
+
+	if contextInfo == nil {
+		contextInfo = &waE2E.ContextInfo{}
+	} else if contextInfo.StanzaID != nil {
+		return nil, fmt.Errorf("informe a mensagem citada em replyTo ou em contextInfo, não em ambos")
+	}
+
+	sourceChat := recipient
+	if replyTo.RemoteJID != "" {
+		remoteJID, err := types.ParseJID(replyTo.RemoteJID)
+		if err != nil {
+			return nil, fmt.Errorf("replyTo.remoteJid inválido: %v", err)
+		}
+		if remoteJID.User == "" {
+			return nil, fmt.Errorf("replyTo.remoteJid inválido: %s", replyTo.RemoteJID)
+		}
+		sourceChat = remoteJID.ToNonAD()
+	}
+	crossChat := sourceChat != recipient.ToNonAD()
+
+	participant := replyTo.Participant
+	if participant == "" {
+		if crossChat || !isUserJID(sourceChat) {
+			return nil, fmt.Errorf("replyTo.participant é obrigatório para citações em grupos ou de outra conversa")
+		}
+		participant = sourceChat.String()
+	}
+
+	participantJID, err := types.ParseJID(participant)
+	if err != nil {
+		return nil, fmt.Errorf("replyTo.participant inválido: %v", err)
+	}
+	if !isUserJID(participantJID) {
+		return nil, fmt.Errorf("replyTo.participant deve ser o JID de um usuário, recebido %s", participant)
+	}
+
+	contextInfo.StanzaID = proto.String(replyTo.MessageID)
+	contextInfo.Participant = proto.String(participantJID.ToNonAD().String())
+	contextInfo.QuotedMessage = &waE2E.Message{Conversation: proto.String(replyTo.Text)}
+	if crossChat {
+		contextInfo.RemoteJID = proto.String(sourceChat.String())
+	} else {
+		contextInfo.RemoteJID = nil
+	}
+
+	return contextInfo, nil
+}
+
+// reconstructFromEdits recupera o texto de uma mensagem a partir da última versão registrada no
+// histórico de edições. Retorna nil quando não há histórico para a mensagem naquela conversa.
+func (h *MessageHandler) reconstructFromEdits(ctx context.Context, sessionID, messageID string, chat types.JID) *waE2E.Message {
+	edits, err := h.sessionManager.GetMessageEditRepository().ListByMessageID(ctx, sessionID, messageID)
+	if err != nil {
+		h.logger.Warn("Erro ao consultar histórico de edições", "sessionID", sessionID, "messageID", messageID, "error", err)
+		return nil
+	}
+
+	for i := len(edits) - 1; i >= 0; i-- {
+		edit := edits[i]
+		if edit.ChatJID != "" && edit.ChatJID != chat.String() {
+			continue
+		}
+		if edit.Content != "" {
+			return &waE2E.Message{Conversation: proto.String(edit.Content)}
+		}
 	}
 
 	return nil
 }
 
-func (h *MessageHandler) createMediaMessage(mediaType string, uploadResp whatsmeow.UploadResponse, fileName, mimeType, caption string, contextInfo *waE2E.ContextInfo) (*waE2E.Message, error) {
-	switch strings.ToLower(mediaType) {
+// buildForwardedMessage copia o conteúdo original e marca a cópia como encaminhada. Citações e
+// menções da mensagem original são descartadas, como faz o aplicativo ao encaminhar.
+func buildForwardedMessage(original *waE2E.Message) (*waE2E.Message, error) {
+	if original == nil {
+		return nil, fmt.Errorf("o conteúdo da mensagem não está disponível")
+	}
+
+	msg := proto.Clone(original).(*waE2E.Message)
+	msg.MessageContextInfo = nil
+
+	if msg.Conversation != nil {
+		msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{Text: msg.Conversation}
+		msg.Conversation = nil
+	}
+
+	var target **waE2E.ContextInfo
+	switch {
+	case msg.ExtendedTextMessage != nil:
+		target = &msg.ExtendedTextMessage.ContextInfo
+	case msg.ImageMessage != nil:
+		target = &msg.ImageMessage.ContextInfo
+	case msg.VideoMessage != nil:
+		target = &msg.VideoMessage.ContextInfo
+	case msg.AudioMessage != nil:
+		target = &msg.AudioMessage.ContextInfo
+	case msg.DocumentMessage != nil:
+		target = &msg.DocumentMessage.ContextInfo
+	case msg.StickerMessage != nil:
+		target = &msg.StickerMessage.ContextInfo
+	case msg.ContactMessage != nil:
+		target = &msg.ContactMessage.ContextInfo
+	case msg.ContactsArrayMessage != nil:
+		target = &msg.ContactsArrayMessage.ContextInfo
+	case msg.LocationMessage != nil:
+		target = &msg.LocationMessage.ContextInfo
+	default:
+		return nil, fmt.Errorf("tipo de mensagem não suportado para encaminhamento")
+	}
+
+	score := (*target).GetForwardingScore() + 1
+	*target = &waE2E.ContextInfo{
+		IsForwarded:     proto.Bool(true),
+		ForwardingScore: proto.Uint32(score),
+	}
+
+	return msg, nil
+}
+
+// isUserJID indica se o JID identifica um usuário (número de telefone ou LID)
+func isUserJID(jid types.JID) bool {
+	if jid.User == "" {
+		return false
+	}
+	return jid.Server == types.DefaultUserServer || jid.Server == types.HiddenUserServer
+}
+
+// buildListMessage monta a ListMessage de seleção única a partir da requisição
+func (h *MessageHandler) buildListMessage(req *dto.SendListMessageRequest) *waE2E.ListMessage {
+	sections := make([]*waE2E.ListMessage_Section, 0, len(req.Sections))
+	for _, section := range req.Sections {
+		rows := make([]*waE2E.ListMessage_Row, 0, len(section.Rows))
+		for _, row := range section.Rows {
+			listRow := &waE2E.ListMessage_Row{
+				RowID: proto.String(row.RowID),
+				Title: proto.String(row.Title),
+			}
+			if row.Description != "" {
+				listRow.Description = proto.String(row.Description)
+			}
+			rows = append(rows, listRow)
+		}
+
+		listSection := &waE2E.ListMessage_Section{Rows: rows}
+		if section.Title != "" {
+			listSection.Title = proto.String(section.Title)
+		}
+		sections = append(sections, listSection)
+	}
+
+	listMsg := &waE2E.ListMessage{
+		Description: proto.String(req.Description),
+		ButtonText:  proto.String(req.ButtonText),
+		ListType:    waE2E.ListMessage_SINGLE_SELECT.Enum(),
+		Sections:    sections,
+		ContextInfo: req.ContextInfo,
+	}
+	if req.Title != "" {
+		listMsg.Title = proto.String(req.Title)
+	}
+	if req.Footer != "" {
+		listMsg.FooterText = proto.String(req.Footer)
+	}
+
+	return listMsg
+}
+
+// buildButtonsMessage monta a ButtonsMessage com botões de resposta rápida a partir da requisição
+func (h *MessageHandler) buildButtonsMessage(req *dto.SendButtonsMessageRequest) *waE2E.ButtonsMessage {
+	buttons := make([]*waE2E.ButtonsMessage_Button, 0, len(req.Buttons))
+	for _, button := range req.Buttons {
+		buttons = append(buttons, &waE2E.ButtonsMessage_Button{
+			ButtonID:   proto.String(button.ID),
+			ButtonText: &waE2E.ButtonsMessage_Button_ButtonText{DisplayText: proto.String(button.Text)},
+			Type:       waE2E.ButtonsMessage_Button_RESPONSE.Enum(),
+		})
+	}
+
+	buttonsMsg := &waE2E.ButtonsMessage{
+		ContentText: proto.String(req.Text),
+		Buttons:     buttons,
+		HeaderType:  waE2E.ButtonsMessage_EMPTY.Enum(),
+		ContextInfo: req.ContextInfo,
+	}
+	if req.Title != "" {
+		buttonsMsg.Header = &waE2E.ButtonsMessage_Text{Text: req.Title}
+		buttonsMsg.HeaderType = waE2E.ButtonsMessage_TEXT.Enum()
+	}
+	if req.Footer != "" {
+		buttonsMsg.FooterText = proto.String(req.Footer)
+	}
+
+	return buttonsMsg
+}
+
+func (h *MessageHandler) createMediaMessage(req *dto.SendMediaRequest, uploadResp whatsmeow.UploadResponse, mediaBytes []byte, fileName, mimeType string) (*waE2E.Message, error) {
+	switch strings.ToLower(req.MediaType) {
 	case "image":
-		return h.createImageMessage(uploadResp, fileName, mimeType, caption, contextInfo), nil
+		msg := h.createImageMessage(uploadResp, fileName, mimeType, req.Caption, req.ContextInfo)
+		h.applyImageInfo(msg.ImageMessage, mediaBytes)
+		return msg, nil
 	case "audio":
-		return h.createAudioMessage(uploadResp, fileName, mimeType, contextInfo), nil
+		msg := h.createAudioMessage(uploadResp, fileName, mimeType, req.ViewOnce, req.ContextInfo)
+		if req.PTT {
+			msg.AudioMessage.PTT = proto.Bool(true)
+		}
+		if req.Seconds > 0 {
+			msg.AudioMessage.Seconds = proto.Uint32(req.Seconds)
+		}
+		if len(req.Waveform) > 0 {
+			msg.AudioMessage.Waveform = req.Waveform
+		}
+		return msg, nil
 	case "video":
-		return h.createVideoMessage(uploadResp, fileName, mimeType, caption, contextInfo), nil
+		msg := h.createVideoMessage(uploadResp, fileName, mimeType, req.Caption, req.ContextInfo)
+		h.applyVideoInfo(msg.VideoMessage, mediaBytes)
+		return msg, nil
 	case "document":
-		return h.createDocumentMessage(uploadResp, fileName, mimeType, contextInfo), nil
+		return h.createDocumentMessage(uploadResp, fileName, mimeType, req.ContextInfo), nil
 	default:
-		return nil, fmt.Errorf("tipo de mídia não suportado: %s", mediaType)
+		return nil, fmt.Errorf("tipo de mídia não suportado: %s", req.MediaType)
+	}
+}
+
+// applyImageInfo preenche dimensões e miniatura da imagem. Falhas de decodificação
+// não impedem o envio: a mídia segue sem os campos que não puderam ser obtidos.
+func (h *MessageHandler) applyImageInfo(imageMsg *waE2E.ImageMessage, mediaBytes []byte) {
+	info, err := meow.DecodeImageInfo(mediaBytes)
+	if info != nil {
+		imageMsg.Width = proto.Uint32(info.Width)
+		imageMsg.Height = proto.Uint32(info.Height)
+		if len(info.Thumbnail) > 0 {
+			imageMsg.JPEGThumbnail = info.Thumbnail
+		}
+	}
+	if err != nil {
+		h.logger.Debug("Não foi possível gerar miniatura da imagem", "error", err)
+	}
+}
+
+// applyVideoInfo preenche dimensões e duração a partir do cabeçalho MP4, quando disponível.
+// Miniatura de vídeo está fora do escopo: exigiria decodificar quadros H.264/H.265, o que a
+// biblioteca padrão não faz, então o vídeo é enviado sem JPEGThumbnail.
+func (h *MessageHandler) applyVideoInfo(videoMsg *waE2E.VideoMessage, mediaBytes []byte) {
+	info, err := meow.DecodeMP4Info(mediaBytes)
+	if err != nil {
+		h.logger.Debug("Não foi possível ler o cabeçalho do vídeo", "error", err)
+		return
+	}
+
+	if info.Width > 0 && info.Height > 0 {
+		videoMsg.Width = proto.Uint32(info.Width)
+		videoMsg.Height = proto.Uint32(info.Height)
+	}
+	if info.Seconds > 0 {
+		videoMsg.Seconds = proto.Uint32(info.Seconds)
 	}
 }
 
@@ -552,7 +3182,7 @@ func (h *MessageHandler) createImageMessage(uploadResp whatsmeow.UploadResponse,
 	return msg
 }
 
-func (h *MessageHandler) createAudioMessage(uploadResp whatsmeow.UploadResponse, _ string, mimeType string, contextInfo *waE2E.ContextInfo) *waE2E.Message {
+func (h *MessageHandler) createAudioMessage(uploadResp whatsmeow.UploadResponse, _ string, mimeType string, viewOnce bool, contextInfo *waE2E.ContextInfo) *waE2E.Message {
 	msg := &waE2E.Message{
 		AudioMessage: &waE2E.AudioMessage{
 			URL:           proto.String(uploadResp.URL),
@@ -565,6 +3195,10 @@ func (h *MessageHandler) createAudioMessage(uploadResp whatsmeow.UploadResponse,
 		},
 	}
 
+	if viewOnce {
+		msg.AudioMessage.ViewOnce = proto.Bool(true)
+	}
+
 	if contextInfo != nil {
 		msg.AudioMessage.ContextInfo = contextInfo
 	}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestParseRecipientJID(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    types.JID
+		wantErr bool
+	}{
+		{"número sem servidor", "5511999999999", types.NewJID("5511999999999", types.DefaultUserServer), false},
+		{"número com +", "+5511999999999", types.NewJID("5511999999999", types.DefaultUserServer), false},
+		{"usuário", "5511999999999@s.whatsapp.net", types.NewJID("5511999999999", types.DefaultUserServer), false},
+		{"sufixo legado @c.us", "5511999999999@c.us", types.NewJID("5511999999999", types.DefaultUserServer), false},
+		{"usuário com dispositivo", "5511999999999:3@s.whatsapp.net", types.NewJID("5511999999999", types.DefaultUserServer), false},
+		{"grupo", "120363012345678901@g.us", types.NewJID("120363012345678901", types.GroupServer), false},
+		{"lid", "123456789012345@lid", types.NewJID("123456789012345", types.HiddenUserServer), false},
+		{"lid com dispositivo", "123:12@lid", types.NewJID("123", types.HiddenUserServer), false},
+		{"canal", "120363098765432109@newsletter", types.NewJID("120363098765432109", types.NewsletterServer), false},
+		{"sem usuário", "@s.whatsapp.net", types.JID{}, true},
+		{"servidor não suportado", "5511999999999@example.com", types.JID{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRecipientJID(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("esperado erro, obtido %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("JID = %s, esperado %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"zpigo/internal/api/dto"
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
+)
+
+type MetricsHandler struct {
+	*BaseHandler
+	sessionRepo    store.SessionRepositoryInterface
+	sessionManager *meow.SessionManager
+}
+
+func NewMetricsHandler(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager) *MetricsHandler {
+	return &MetricsHandler{
+		BaseHandler:    NewBaseHandler("MetricsHandler"),
+		sessionRepo:    sessionRepo,
+		sessionManager: sessionManager,
+	}
+}
+
+// @Summary      Obter métricas
+// @Description  Retorna estatísticas de webhooks e sessões no formato de texto do Prometheus. Use format=json para obter JSON
+// @Tags         health
+// @Produce      plain
+// @Produce      json
+// @Param        format  query     string  false  "Formato da resposta (prometheus ou json)"
+// @Success      200     {object}  dto.MetricsResponse
+// @Failure      500     {object}  map[string]interface{}
+// @Router       /metrics [get]
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	byStatus, err := h.sessionRepo.CountByStatus(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Erro ao contar sessões por status", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao coletar métricas",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	stats := h.sessionManager.GetWebhookManager().GetStats()
+	loaded, connected, loggedIn := h.sessionManager.ClientStats()
+
+	metrics := &dto.MetricsResponse{
+		Webhooks: &dto.WebhookMetricsResponse{
+			QueueSize:      stats.QueueSize,
+			TotalSent:      stats.TotalSent,
+			TotalSuccess:   stats.TotalSuccess,
+			TotalFailed:    stats.TotalFailed,
+			TotalRetries:   stats.TotalRetries,
+			TotalDropped:   stats.TotalDropped,
+			AverageLatency: stats.AverageLatency,
+		},
+		Sessions: &dto.SessionMetricsResponse{
+			Loaded:    loaded,
+			Connected: connected,
+			LoggedIn:  loggedIn,
+			ByStatus:  make(map[string]int, len(byStatus)),
+		},
+	}
+	for status, count := range byStatus {
+		metrics.Sessions.ByStatus[string(status)] = count
+	}
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, metrics)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(formatPrometheusMetrics(metrics)))
+}
+
+// formatPrometheusMetrics serializa as métricas no formato de exposição de texto do Prometheus
+func formatPrometheusMetrics(metrics *dto.MetricsResponse) string {
+	var b strings.Builder
+
+	writeMetric := func(name, metricType, help string, value interface{}) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, metricType, name, value)
+	}
+
+	writeMetric("zpigo_webhook_queue_size", "gauge", "Entregas de webhook aguardando na fila", metrics.Webhooks.QueueSize)
+	writeMetric("zpigo_webhook_sent_total", "counter", "Entregas de webhook enfileiradas", metrics.Webhooks.TotalSent)
+	writeMetric("zpigo_webhook_success_total", "counter", "Entregas de webhook concluídas com sucesso", metrics.Webhooks.TotalSuccess)
+	writeMetric("zpigo_webhook_failed_total", "counter", "Entregas de webhook que falharam definitivamente", metrics.Webhooks.TotalFailed)
+	writeMetric("zpigo_webhook_retries_total", "counter", "Novas tentativas de entrega de webhook", metrics.Webhooks.TotalRetries)
+	writeMetric("zpigo_webhook_dropped_total", "counter", "Entregas de webhook descartadas com a fila cheia", metrics.Webhooks.TotalDropped)
+	writeMetric("zpigo_sessions_loaded", "gauge", "Clientes WhatsApp carregados no gerenciador", metrics.Sessions.Loaded)
+	writeMetric("zpigo_sessions_connected", "gauge", "Clientes WhatsApp com socket conectado", metrics.Sessions.Connected)
+	writeMetric("zpigo_sessions_logged_in", "gauge", "Clientes WhatsApp autenticados", metrics.Sessions.LoggedIn)
+
+	statuses := make([]string, 0, len(metrics.Sessions.ByStatus))
+	for status := range metrics.Sessions.ByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	b.WriteString("# HELP zpigo_sessions_by_status Sessões cadastradas por status\n# TYPE zpigo_sessions_by_status gauge\n")
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "zpigo_sessions_by_status{status=%q} %d\n", status, metrics.Sessions.ByStatus[status])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+
+	"zpigo/internal/api/dto"
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
+)
+
+type NewsletterHandler struct {
+	*BaseHandler
+	sessionRepo    store.SessionRepositoryInterface
+	sessionManager *meow.SessionManager
+}
+
+func NewNewsletterHandler(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager) *NewsletterHandler {
+	return &NewsletterHandler{
+		BaseHandler:    NewBaseHandler("NewsletterHandler"),
+		sessionRepo:    sessionRepo,
+		sessionManager: sessionManager,
+	}
+}
+
+// @Summary      Seguir canal
+// @Description  Segue um canal (newsletter) pelo JID ou pelo link/código de convite e retorna seus metadados
+// @Tags         newsletters
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                       true  "ID da sessão"
+// @Param        request    body      dto.NewsletterFollowRequest  true  "Canal a seguir"
+// @Success      200        {object}  dto.NewsletterActionResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/newsletter/follow [post]
+// @Security     ApiKeyAuth
+func (h *NewsletterHandler) FollowNewsletter(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	client, jid, meta, ok := h.resolveNewsletter(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if err := client.FollowNewsletter(jid); err != nil {
+		h.logger.Error("Erro ao seguir canal", "sessionID", sessionID, "jid", jid, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao seguir canal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Consulta novamente para refletir o papel da sessão após seguir
+	if updated, err := client.GetNewsletterInfo(jid); err != nil {
+		h.logger.Warn("Erro ao buscar metadados do canal após seguir", "sessionID", sessionID, "jid", jid, "error", err)
+	} else {
+		meta = updated
+	}
+
+	h.logger.Info("Canal seguido", "sessionID", sessionID, "jid", jid)
+
+	c.JSON(http.StatusOK, &dto.NewsletterActionResponse{
+		Success:    true,
+		Message:    "Canal seguido",
+		JID:        jid.String(),
+		Newsletter: dto.ToNewsletterResponse(meta),
+	})
+}
+
+// @Summary      Deixar de seguir canal
+// @Description  Deixa de seguir um canal (newsletter) informado pelo JID ou pelo link/código de convite
+// @Tags         newsletters
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                       true  "ID da sessão"
+// @Param        request    body      dto.NewsletterFollowRequest  true  "Canal a deixar de seguir"
+// @Success      200        {object}  dto.NewsletterActionResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/newsletter/unfollow [post]
+// @Security     ApiKeyAuth
+func (h *NewsletterHandler) UnfollowNewsletter(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	client, jid, _, ok := h.resolveNewsletter(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if err := client.UnfollowNewsletter(jid); err != nil {
+		h.logger.Error("Erro ao deixar de seguir canal", "sessionID", sessionID, "jid", jid, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao deixar de seguir canal",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Canal deixado de seguir", "sessionID", sessionID, "jid", jid)
+
+	c.JSON(http.StatusOK, &dto.NewsletterActionResponse{
+		Success: true,
+		Message: "Canal deixado de seguir",
+		JID:     jid.String(),
+	})
+}
+
+// @Summary      Obter informações do canal
+// @Description  Retorna nome, descrição, quantidade de seguidores e o papel da sessão em um canal (newsletter)
+// @Tags         newsletters
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        jid        path      string  true  "JID do canal"
+// @Success      200        {object}  dto.NewsletterResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/newsletter/{jid} [get]
+// @Security     ApiKeyAuth
+func (h *NewsletterHandler) GetNewsletterInfo(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	jid, err := parseNewsletterJID(c.Param("jid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "JID do canal inválido",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	meta, err := client.GetNewsletterInfo(jid)
+	if err != nil {
+		h.logger.Error("Erro ao buscar informações do canal", "sessionID", sessionID, "jid", jid, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao buscar informações do canal",
+			"details": err.Error(),
+		})
+		return
+	}
+	if meta == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Canal não encontrado",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToNewsletterResponse(meta))
+}
+
+// resolveNewsletter lê a requisição e identifica o canal pelo JID ou pelo código de convite; neste caso os
+// metadados obtidos na resolução também são retornados. Em caso de falha a resposta de erro já é escrita.
+func (h *NewsletterHandler) resolveNewsletter(c *gin.Context, sessionID string) (*whatsmeow.Client, types.JID, *types.NewsletterMetadata, bool) {
+	var req dto.NewsletterFollowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return nil, types.JID{}, nil, false
+	}
+
+	inviteCode := parseNewsletterInviteCode(req.InviteCode)
+	if req.JID == "" && inviteCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "JID ou código de convite do canal é obrigatório",
+		})
+		return nil, types.JID{}, nil, false
+	}
+
+	var jid types.JID
+	if req.JID != "" {
+		parsed, err := parseNewsletterJID(req.JID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "JID do canal inválido",
+				"details": err.Error(),
+			})
+			return nil, types.JID{}, nil, false
+		}
+		jid = parsed
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return nil, types.JID{}, nil, false
+	}
+
+	if !jid.IsEmpty() {
+		return client, jid, nil, true
+	}
+
+	meta, err := client.GetNewsletterInfoWithInvite(inviteCode)
+	if err != nil || meta == nil {
+		h.logger.Error("Erro ao resolver convite do canal", "sessionID", sessionID, "inviteCode", inviteCode, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Canal não encontrado para o convite informado",
+			"details": fmt.Sprint(err),
+		})
+		return nil, types.JID{}, nil, false
+	}
+
+	return client, meta.ID, meta, true
+}
+
+// parseNewsletterJID aceita o JID completo de um canal ou apenas a parte numérica
+func parseNewsletterJID(value string) (types.JID, error) {
+	value = strings.TrimSpace(value)
+	if isDigits(value) {
+		return types.NewJID(value, types.NewsletterServer), nil
+	}
+
+	jid, err := types.ParseJID(value)
+	if err != nil {
+		return types.JID{}, err
+	}
+	if jid.Server != types.NewsletterServer {
+		return types.JID{}, fmt.Errorf("servidor '%s' não é um servidor de canais", jid.Server)
+	}
+
+	return jid, nil
+}
+
+// parseNewsletterInviteCode aceita o link do canal completo ou apenas o código
+func parseNewsletterInviteCode(raw string) string {
+	code := strings.TrimSpace(raw)
+	code = strings.TrimPrefix(code, "https://")
+	code = strings.TrimPrefix(code, "http://")
+	code = strings.TrimPrefix(code, "www.")
+	code = strings.TrimPrefix(code, "whatsapp.com/channel/")
+	return strings.Trim(code, "/")
+}
@@ -2,18 +2,29 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mau.fi/whatsmeow/store/sqlstore"
 
 	"zpigo/internal/api/dto"
+	"zpigo/internal/api/middleware"
 	"zpigo/internal/meow"
 	"zpigo/internal/store"
 	"zpigo/internal/store/models"
+	"zpigo/internal/webhook"
 )
 
+// dashboardCacheTTL limita a frequência com que o painel da sessão é recalculado
+const dashboardCacheTTL = 5 * time.Second
+
 type SessionHandler struct {
 	*BaseHandler
 	sessionRepo    store.SessionRepositoryInterface
@@ -38,6 +49,11 @@ func NewSessionHandler(sessionRepo store.SessionRepositoryInterface, container *
 	}
 }
 
+// GetSessionManager retorna o gerenciador de sessões usado pelo handler
+func (h *SessionHandler) GetSessionManager() *meow.SessionManager {
+	return h.sessionManager
+}
+
 func NewSessionHandlerWithManager(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager) *SessionHandler {
 	return &SessionHandler{
 		BaseHandler:    NewBaseHandler("SessionHandler"),
@@ -48,24 +64,43 @@ func NewSessionHandlerWithManager(sessionRepo store.SessionRepositoryInterface,
 }
 
 // @Summary      Criar nova sessão WhatsApp
-// @Description  Cria uma nova sessão WhatsApp com o nome especificado
+// @Description  Cria uma nova sessão WhatsApp com o nome especificado. Exige uma API key global; chaves
+// @Description  restritas a uma sessão recebem 403.
 // @Tags         sessions
 // @Accept       json
 // @Produce      json
 // @Param        request  body      dto.CreateSessionRequest  true  "Dados da sessão"
 // @Success      201      {object}  dto.CreateSessionResponse
 // @Failure      400      {object}  map[string]interface{}
+// @Failure      401      {object}  map[string]interface{}
+// @Failure      403      {object}  map[string]interface{}
 // @Failure      500      {object}  map[string]interface{}
 // @Router       /sessions/add [post]
+// @Security     ApiKeyAuth
 func (h *SessionHandler) AddSession(c *gin.Context) {
+	authCtx, ok := middleware.GetAuthContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Autenticação necessária",
+		})
+		return
+	}
+
+	if !authCtx.Key.IsGlobal() {
+		h.logger.Warn("API Key restrita tentou criar sessão", "keyID", authCtx.Key.ID)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "API Key sem permissão para criar sessões",
+			"details": "Apenas chaves globais podem criar sessões",
+		})
+		return
+	}
+
 	var req dto.CreateSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Erro ao decodificar request", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   true,
-			"message": "Dados inválidos",
-			"details": err.Error(),
-		})
+		respondBindingError(c, err)
 		return
 	}
 
@@ -84,8 +119,8 @@ func (h *SessionHandler) AddSession(c *gin.Context) {
 		Status: models.StatusDisconnected,
 	}
 
-	if err := h.sessionRepo.Create(c.Request.Context(), session); err != nil {
-		h.logger.Error("Erro ao criar sessão no banco", "error", err, "name", req.Name)
+	if _, err := h.sessionManager.AddSession(c.Request.Context(), session); err != nil {
+		h.logger.Error("Erro ao criar sessão", "error", err, "name", req.Name, "sessionID", session.ID)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
 			"message": "Erro ao criar sessão",
@@ -94,17 +129,6 @@ func (h *SessionHandler) AddSession(c *gin.Context) {
 		return
 	}
 
-	_, err := h.sessionManager.CreateSession(session.ID)
-	if err != nil {
-		h.logger.Error("Erro ao inicializar sessão no manager", "error", err, "sessionID", session.ID)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   true,
-			"message": "Erro ao inicializar sessão",
-			"details": err.Error(),
-		})
-		return
-	}
-
 	h.logger.Info("Sessão criada com sucesso", "sessionID", session.ID, "name", session.Name)
 
 	response := &dto.CreateSessionResponse{
@@ -116,14 +140,25 @@ func (h *SessionHandler) AddSession(c *gin.Context) {
 }
 
 // @Summary      Listar todas as sessões
-// @Description  Retorna uma lista com todas as sessões WhatsApp criadas
+// @Description  Retorna as sessões WhatsApp que a API key autenticada pode acessar
 // @Tags         sessions
 // @Accept       json
 // @Produce      json
 // @Success      200  {object}  dto.SessionListResponse
+// @Failure      401  {object}  map[string]interface{}
 // @Failure      500  {object}  map[string]interface{}
 // @Router       /sessions/list [get]
+// @Security     ApiKeyAuth
 func (h *SessionHandler) ListSessions(c *gin.Context) {
+	authCtx, ok := middleware.GetAuthContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Autenticação necessária",
+		})
+		return
+	}
+
 	h.logger.Debug("Listando sessões")
 
 	sessions, err := h.sessionRepo.List(c.Request.Context())
@@ -137,6 +172,14 @@ func (h *SessionHandler) ListSessions(c *gin.Context) {
 		return
 	}
 
+	allowed := sessions[:0]
+	for _, session := range sessions {
+		if authCtx.Key.AllowsSession(session.ID) {
+			allowed = append(allowed, session)
+		}
+	}
+	sessions = allowed
+
 	h.logger.Info("Sessões listadas com sucesso", "total", len(sessions))
 
 	response := &dto.SessionListResponse{
@@ -147,6 +190,127 @@ func (h *SessionHandler) ListSessions(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// @Summary      Renomear sessão
+// @Description  Altera o nome de exibição da sessão e atualiza as entradas em cache
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                    true  "ID da sessão"
+// @Param        request    body      dto.RenameSessionRequest  true  "Novo nome"
+// @Success      200        {object}  dto.SessionResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/name [patch]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) RenameSession(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.RenameSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request de renomeação", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 255 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Nome inválido",
+			"details": "O nome deve ter entre 1 e 255 caracteres",
+		})
+		return
+	}
+
+	if err := h.sessionRepo.UpdateName(c.Request.Context(), sessionID, name); err != nil {
+		h.logger.Error("Erro ao renomear sessão", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.sessionManager.GetCacheManager().UpdateSessionInfoByID(sessionID, "Name", name)
+
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Erro ao buscar sessão após renomear", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Sessão renomeada", "sessionID", sessionID, "name", name)
+
+	c.JSON(http.StatusOK, dto.ToSessionResponse(session))
+}
+
+// @Summary      Buscar sessões pelo nome
+// @Description  Retorna as sessões cujo nome é exatamente igual ao informado. Como o nome não é único,
+// @Description  todas as correspondências são retornadas, ordenadas da mais recente para a mais antiga.
+// @Description  Apenas as sessões que a API key autenticada pode acessar são consideradas.
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        name  path      string  true  "Nome da sessão"
+// @Success      200   {object}  dto.SessionListResponse
+// @Failure      401   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /sessions/by-name/{name} [get]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) GetSessionsByName(c *gin.Context) {
+	name := c.Param("name")
+
+	authCtx, ok := middleware.GetAuthContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Autenticação necessária",
+		})
+		return
+	}
+
+	h.logger.Debug("Buscando sessões pelo nome", "name", name)
+
+	sessions, err := h.sessionRepo.GetByName(c.Request.Context(), name)
+	if err != nil {
+		h.logger.Error("Erro ao buscar sessões pelo nome", "name", name, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao buscar sessões",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	allowed := sessions[:0]
+	for _, session := range sessions {
+		if authCtx.Key.AllowsSession(session.ID) {
+			allowed = append(allowed, session)
+		}
+	}
+	sessions = allowed
+
+	if len(sessions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Nenhuma sessão encontrada com o nome informado",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.SessionListResponse{
+		Sessions: dto.ToSessionResponseList(sessions),
+		Total:    len(sessions),
+	})
+}
+
 // @Summary      Obter informações da sessão
 // @Description  Retorna informações detalhadas de uma sessão específica
 // @Tags         sessions
@@ -192,18 +356,465 @@ func (h *SessionHandler) GetSessionInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// @Summary      Verificar status da sessão
-// @Description  Verifica o status atual de conexão de uma sessão WhatsApp
+// @Summary      Verificar status da sessão
+// @Description  Verifica o status atual de conexão de uma sessão WhatsApp, incluindo o JID do dispositivo e o nome de exibição da conta
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.SessionStatusResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/status [get]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) GetSessionStatus(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "ID da sessão é obrigatório",
+		})
+		return
+	}
+
+	h.logger.Debug("Verificando status da sessão", "sessionID", sessionID)
+
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Sessão não encontrada para verificar status", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	isConnected, isLoggedIn, err := h.sessionManager.GetSessionStatus(sessionID)
+	if err != nil {
+		h.logger.Warn("Erro ao verificar status no manager", "sessionID", sessionID, "error", err)
+		isConnected = session.IsConnected()
+		isLoggedIn = session.Status == models.StatusConnected
+	}
+
+	h.logger.Info("Status da sessão verificado", "sessionID", sessionID, "connected", isConnected, "loggedIn", isLoggedIn)
+
+	response := &dto.SessionStatusResponse{
+		SessionID: sessionID,
+		Connected: isConnected,
+		LoggedIn:  isLoggedIn,
+		Status:    session.Status,
+		Phone:     session.Phone,
+		DeviceJID: session.DeviceJid,
+		HasProxy:  session.HasProxy(),
+		Timestamp: session.UpdatedAt.Unix(),
+	}
+
+	if client, exists := h.sessionManager.GetSession(sessionID); exists && client.Store != nil {
+		if response.DeviceJID == "" && client.Store.ID != nil {
+			response.DeviceJID = client.Store.ID.String()
+		}
+		response.PushName = client.Store.PushName
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// sseKeepAliveInterval é o intervalo dos comentários enviados no stream de eventos para manter a conexão
+// aberta em proxies que encerram conexões ociosas
+const sseKeepAliveInterval = 25 * time.Second
+
+// @Summary      Stream de eventos da sessão
+// @Description  Abre um stream Server-Sent Events com os eventos da sessão (conexão, mensagens, recibos...), no mesmo
+// @Description  formato do payload dos webhooks; o nome de cada evento SSE é o tipo do evento. O parâmetro events
+// @Description  filtra os tipos entregues (separados por vírgula). Eventos são descartados se o cliente não os
+// @Description  consumir a tempo. Requer a API key da sessão.
+// @Tags         sessions
+// @Produce      text/event-stream
+// @Param        sessionID  path      string  true   "ID da sessão"
+// @Param        events     query     string  false  "Tipos de evento, separados por vírgula (ex: Message,Receipt)"
+// @Success      200        {object}  webhook.Payload
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      401        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/events [get]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) StreamEvents(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var events []webhook.EventType
+	for _, name := range strings.Split(c.Query("events"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !meow.IsHandledEventType(name) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Tipo de evento inválido",
+				"details": fmt.Sprintf("evento não suportado: %s", name),
+			})
+			return
+		}
+		events = append(events, webhook.EventType(name))
+	}
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		h.logger.Error("Sessão não encontrada para stream de eventos", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	stream := h.sessionManager.GetEventStream()
+	sub := stream.Subscribe(sessionID, events)
+	defer stream.Unsubscribe(sub)
+
+	h.logger.Info("Stream de eventos aberto", "sessionID", sessionID, "events", events, "subscribers", stream.SubscriberCount(sessionID))
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case payload, ok := <-sub.Events():
+			if !ok {
+				return false
+			}
+			data, err := json.Marshal(payload)
+			if err != nil {
+				h.logger.Error("Erro ao serializar evento do stream", "sessionID", sessionID, "eventType", payload.Type, "error", err)
+				return true
+			}
+			c.SSEvent(payload.Type, string(data))
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keepalive\n\n")
+			return err == nil
+		}
+	})
+
+	h.logger.Info("Stream de eventos encerrado", "sessionID", sessionID, "dropped", sub.Dropped())
+}
+
+// @Summary      Consultar dispositivo da sessão
+// @Description  Retorna o dispositivo vinculado à sessão: JID, nome de exibição, plataforma do celular, nome comercial
+// @Description  e data do emparelhamento. Com o cliente ativo os dados vêm do store do whatsmeow (source=live); caso
+// @Description  contrário apenas o JID e o número gravados no banco são retornados (source=stored).
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.DeviceInfoResponse
+// @Failure      404        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/device [get]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) GetDevice(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Sessão não encontrada para consultar dispositivo", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response := &dto.DeviceInfoResponse{
+		SessionID: sessionID,
+		Source:    dto.DeviceSourceStored,
+		JID:       session.DeviceJid,
+		Phone:     session.Phone,
+	}
+
+	if client, exists := h.sessionManager.GetSession(sessionID); exists && client.Store != nil && client.Store.ID != nil {
+		device := client.Store
+		response.Source = dto.DeviceSourceLive
+		response.JID = device.ID.String()
+		if !device.LID.IsEmpty() {
+			response.LID = device.LID.String()
+		}
+		if response.Phone == "" {
+			response.Phone = device.ID.User
+		}
+		response.PushName = device.PushName
+		response.Platform = device.Platform
+		response.BusinessName = device.BusinessName
+		if pairedAt, ok := meow.DevicePairedAt(device); ok {
+			timestamp := pairedAt.Unix()
+			response.PairedAt = &timestamp
+		}
+	}
+
+	if response.JID == "" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão sem dispositivo emparelhado",
+			"details": "Conecte a sessão e leia o QR code ou use o código de pareamento",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Reiniciar sessão
+// @Description  Desconecta o cliente WhatsApp da sessão, descarta-o do gerenciador e o recria a partir do dispositivo
+// @Description  já emparelhado, reconectando em seguida. Útil para destravar sessões sem precisar removê-las.
+// @Description  Se a reconexão falhar, a resposta é 500 e o cliente anterior continua registrado, desconectado.
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.SessionStatusResponse
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/restart [post]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) RestartSession(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		h.logger.Error("Sessão não encontrada para reiniciar", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Reiniciando sessão", "sessionID", sessionID)
+
+	if err := h.sessionManager.RestartSession(sessionID); err != nil {
+		h.logger.Error("Erro ao reiniciar sessão", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao reiniciar sessão",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Erro ao buscar sessão após reiniciar", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	isConnected, isLoggedIn, _ := h.sessionManager.GetSessionStatus(sessionID)
+
+	h.logger.Info("Sessão reiniciada", "sessionID", sessionID, "connected", isConnected, "loggedIn", isLoggedIn)
+
+	c.JSON(http.StatusOK, &dto.SessionStatusResponse{
+		SessionID: sessionID,
+		Connected: isConnected,
+		LoggedIn:  isLoggedIn,
+		Status:    session.Status,
+		Phone:     session.Phone,
+		HasProxy:  session.HasProxy(),
+		Timestamp: session.UpdatedAt.Unix(),
+	})
+}
+
+// @Summary      Obter uptime da sessão
+// @Description  Retorna há quanto tempo a sessão está conectada e quantas reconexões ocorreram desde o início do processo
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.SessionUptimeResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/uptime [get]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) GetSessionUptime(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "ID da sessão é obrigatório",
+		})
+		return
+	}
+
+	h.logger.Debug("Verificando uptime da sessão", "sessionID", sessionID)
+
+	zpigoClient, exists := h.sessionManager.GetZPigoClient(sessionID)
+	if !exists {
+		h.logger.Warn("Sessão não está ativa no manager", "sessionID", sessionID)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": "Sessão não está ativa no gerenciador",
+		})
+		return
+	}
+
+	connectedAt, uptime, reconnects := zpigoClient.GetUptime()
+
+	response := &dto.SessionUptimeResponse{
+		SessionID:          sessionID,
+		Connected:          zpigoClient.IsClientActive(),
+		ConnectedAt:        connectedAt,
+		LastDisconnectedAt: zpigoClient.GetLastDisconnectedAt(),
+		UptimeSeconds:      int64(uptime.Seconds()),
+		Uptime:             meow.FormatDuration(uptime),
+		Reconnects:         reconnects,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Consultar notificações da sessão
+// @Description  Indica se o dispositivo está passivo ou recebendo os eventos pendentes do servidor e retorna o resultado
+// @Description  da última sincronização offline (eventos OfflineSyncPreview e OfflineSyncCompleted). Útil para diagnosticar
+// @Description  sessões que conectam mas não recebem as mensagens acumuladas.
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.SessionNotificationsResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/notifications [get]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) GetNotifications(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "ID da sessão é obrigatório",
+		})
+		return
+	}
+
+	zpigoClient, exists := h.sessionManager.GetZPigoClient(sessionID)
+	if !exists {
+		h.logger.Warn("Sessão não está ativa no manager", "sessionID", sessionID)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": "Sessão não está ativa no gerenciador",
+		})
+		return
+	}
+
+	connected := zpigoClient.IsClientActive()
+	passive := zpigoClient.IsPassive()
+
+	response := &dto.SessionNotificationsResponse{
+		SessionID:                     sessionID,
+		Connected:                     connected,
+		Passive:                       passive,
+		ReceivingOfflineNotifications: connected && !passive,
+	}
+
+	if offlineSync := zpigoClient.GetOfflineSync(); offlineSync.PreviewAt != nil || offlineSync.CompletedAt != nil {
+		response.OfflineSync = &dto.OfflineSyncResponse{
+			InProgress:     offlineSync.InProgress,
+			Total:          offlineSync.Total,
+			AppDataChanges: offlineSync.AppDataChanges,
+			Messages:       offlineSync.Messages,
+			Notifications:  offlineSync.Notifications,
+			Receipts:       offlineSync.Receipts,
+			Delivered:      offlineSync.Delivered,
+			PreviewAt:      offlineSync.PreviewAt,
+			CompletedAt:    offlineSync.CompletedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Configurar notificações da sessão
+// @Description  Marca o dispositivo como passivo ou ativo no servidor do WhatsApp. Dispositivos passivos deixam de receber
+// @Description  eventos em tempo real e pendentes. A sessão volta a ser ativa automaticamente a cada nova conexão.
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                       true  "ID da sessão"
+// @Param        request    body      dto.SetNotificationsRequest  true  "Modo do dispositivo"
+// @Success      200        {object}  dto.SessionNotificationsResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/notifications/set [post]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) SetNotifications(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "ID da sessão é obrigatório",
+		})
+		return
+	}
+
+	var req dto.SetNotificationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request de notificações", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	if err := client.SetPassive(c.Request.Context(), *req.Passive); err != nil {
+		h.logger.Error("Erro ao alterar modo passivo", "sessionID", sessionID, "passive", *req.Passive, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao configurar notificações",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if zpigoClient, exists := h.sessionManager.GetZPigoClient(sessionID); exists {
+		zpigoClient.SetPassiveMode(*req.Passive)
+	}
+
+	h.logger.Info("Modo passivo alterado", "sessionID", sessionID, "passive", *req.Passive)
+
+	h.GetNotifications(c)
+}
+
+// @Summary      Obter painel da sessão
+// @Description  Agrega status, uptime, erro recente, contadores de mensagens, eventos inscritos e estatísticas de webhook
+// @Description  em uma única resposta. O resultado é mantido em cache por alguns segundos.
 // @Tags         sessions
 // @Accept       json
 // @Produce      json
 // @Param        sessionID  path      string  true  "ID da sessão"
-// @Success      200        {object}  dto.SessionStatusResponse
+// @Success      200        {object}  dto.SessionDashboardResponse
 // @Failure      400        {object}  map[string]interface{}
 // @Failure      404        {object}  map[string]interface{}
-// @Router       /sessions/{sessionID}/status [get]
+// @Router       /sessions/{sessionID}/dashboard [get]
 // @Security     ApiKeyAuth
-func (h *SessionHandler) GetSessionStatus(c *gin.Context) {
+func (h *SessionHandler) GetSessionDashboard(c *gin.Context) {
 	sessionID := c.Param("sessionID")
 	if sessionID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -213,11 +824,16 @@ func (h *SessionHandler) GetSessionStatus(c *gin.Context) {
 		return
 	}
 
-	h.logger.Debug("Verificando status da sessão", "sessionID", sessionID)
+	cacheKey := "dashboard:" + sessionID
+	cacheManager := h.sessionManager.GetCacheManager()
+	if cached, found := cacheManager.Get(cacheKey); found {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
 
 	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
 	if err != nil {
-		h.logger.Error("Sessão não encontrada para verificar status", "sessionID", sessionID, "error", err)
+		h.logger.Error("Sessão não encontrada para montar painel", "sessionID", sessionID, "error", err)
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   true,
 			"message": "Sessão não encontrada",
@@ -226,30 +842,67 @@ func (h *SessionHandler) GetSessionStatus(c *gin.Context) {
 		return
 	}
 
-	isConnected, isLoggedIn, err := h.sessionManager.GetSessionStatus(sessionID)
-	if err != nil {
-		h.logger.Warn("Erro ao verificar status no manager", "sessionID", sessionID, "error", err)
-		isConnected = session.IsConnected()
-		isLoggedIn = session.Status == models.StatusConnected
+	response := &dto.SessionDashboardResponse{
+		SessionID:     sessionID,
+		Name:          session.Name,
+		Status:        session.Status,
+		Phone:         session.Phone,
+		Uptime:        meow.FormatDuration(0),
+		Subscriptions: []string{},
+		Messages:      &dto.DashboardMessagesResponse{},
+		GeneratedAt:   time.Now(),
 	}
 
-	h.logger.Info("Status da sessão verificado", "sessionID", sessionID, "connected", isConnected, "loggedIn", isLoggedIn)
+	if isConnected, isLoggedIn, err := h.sessionManager.GetSessionStatus(sessionID); err == nil {
+		response.Connected = isConnected
+		response.LoggedIn = isLoggedIn
+	}
 
-	response := &dto.SessionStatusResponse{
-		SessionID: sessionID,
-		Connected: isConnected,
-		LoggedIn:  isLoggedIn,
-		Status:    session.Status,
-		Phone:     session.Phone,
-		HasProxy:  session.HasProxy(),
-		Timestamp: session.UpdatedAt.Unix(),
+	if zpigoClient, exists := h.sessionManager.GetZPigoClient(sessionID); exists {
+		connectedAt, uptime, reconnects := zpigoClient.GetUptime()
+		sent, received := zpigoClient.GetMessageCounters()
+
+		response.Active = zpigoClient.IsClientActive()
+		response.ConnectedAt = connectedAt
+		response.UptimeSeconds = int64(uptime.Seconds())
+		response.Uptime = meow.FormatDuration(uptime)
+		response.Reconnects = reconnects
+		response.Subscriptions = zpigoClient.GetSubscriptions()
+		response.Messages.Sent = sent
+		response.Messages.Received = received
+
+		if lastError, occurredAt := zpigoClient.GetLastError(); lastError != "" {
+			response.RecentError = &dto.DashboardErrorResponse{
+				Message:    lastError,
+				OccurredAt: occurredAt,
+			}
+		}
 	}
 
+	webhookManager := h.sessionManager.GetWebhookManager()
+	stats := webhookManager.GetSessionStats(sessionID)
+	response.Webhook = &dto.DashboardWebhookResponse{
+		Events:       []string{},
+		TotalSent:    stats.TotalSent,
+		TotalSuccess: stats.TotalSuccess,
+		TotalFailed:  stats.TotalFailed,
+		TotalRetries: stats.TotalRetries,
+	}
+	if config, exists := webhookManager.GetConfig(sessionID); exists {
+		response.Webhook.Configured = true
+		response.Webhook.Enabled = config.Enabled
+		response.Webhook.URL = config.URL
+		response.Webhook.Events = webhook.EventTypeStrings(config.Events)
+	}
+
+	cacheManager.SetWithExpiration(cacheKey, response, dashboardCacheTTL)
+
 	c.JSON(http.StatusOK, response)
 }
 
 // @Summary      Deletar sessão
-// @Description  Remove uma sessão WhatsApp e todos os seus dados
+// @Description  Remove uma sessão WhatsApp e todos os seus dados: faz logout quando logada, apaga o device do
+// @Description  whatsmeow, os webhooks, o histórico de mensagens e as entradas de cache. A resposta informa o que foi removido.
 // @Tags         sessions
 // @Accept       json
 // @Produce      json
@@ -269,11 +922,20 @@ func (h *SessionHandler) DeleteSession(c *gin.Context) {
 		return
 	}
 
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Sessão não encontrada para remoção", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	h.logger.Info("Removendo sessão", "sessionID", sessionID)
 
-	if err := h.sessionManager.DeleteSession(sessionID); err != nil {
-		h.logger.Warn("Erro ao remover sessão do manager", "sessionID", sessionID, "error", err)
-	}
+	cleanup := h.sessionManager.TeardownSession(c.Request.Context(), sessionID, session.DeviceJid)
 
 	if err := h.sessionRepo.Delete(c.Request.Context(), sessionID); err != nil {
 		h.logger.Error("Erro ao remover sessão do banco", "sessionID", sessionID, "error", err)
@@ -285,11 +947,25 @@ func (h *SessionHandler) DeleteSession(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("Sessão removida com sucesso", "sessionID", sessionID)
+	h.logger.Info("Sessão removida com sucesso", "sessionID", sessionID,
+		"loggedOut", cleanup.LoggedOut,
+		"deviceDeleted", cleanup.DeviceDeleted,
+		"cacheEntriesEvicted", cleanup.CacheEntriesEvicted,
+	)
 
 	response := &dto.DeleteSessionResponse{
 		Message: "Sessão removida com sucesso",
 		Success: true,
+		Cleanup: &dto.SessionCleanupResponse{
+			BroadcastsStopped:    cleanup.BroadcastsStopped,
+			LoggedOut:            cleanup.LoggedOut,
+			DeviceDeleted:        cleanup.DeviceDeleted,
+			ManagerEntryRemoved:  cleanup.ManagerEntryRemoved,
+			WebhookConfigRemoved: cleanup.WebhookConfigRemoved,
+			WebhooksDeleted:      cleanup.WebhooksDeleted,
+			MessageDataDeleted:   cleanup.MessageDataDeleted,
+			CacheEntriesEvicted:  cleanup.CacheEntriesEvicted,
+		},
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -304,6 +980,7 @@ func (h *SessionHandler) DeleteSession(c *gin.Context) {
 // @Success      200        {object}  dto.ConnectSessionResponse
 // @Failure      400        {object}  map[string]interface{}
 // @Failure      404        {object}  map[string]interface{}
+// @Failure      429        {object}  map[string]interface{}
 // @Failure      500        {object}  map[string]interface{}
 // @Router       /sessions/{sessionID}/connect [post]
 // @Security     ApiKeyAuth
@@ -331,6 +1008,20 @@ func (h *SessionHandler) ConnectSession(c *gin.Context) {
 	}
 
 	if err := h.sessionManager.ConnectSession(sessionID); err != nil {
+		if errors.Is(err, meow.ErrPairingLimitReached) {
+			inProgress, limit := h.sessionManager.PairingStats()
+			h.logger.Warn("Limite de emparelhamentos simultâneos atingido", "sessionID", sessionID, "inProgress", inProgress, "limit", limit)
+			c.Header("Retry-After", "20")
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":      true,
+				"message":    "Muitos emparelhamentos em andamento",
+				"details":    err.Error(),
+				"inProgress": inProgress,
+				"limit":      limit,
+			})
+			return
+		}
+
 		h.logger.Error("Erro ao conectar sessão", "sessionID", sessionID, "error", err)
 
 		if updateErr := h.sessionRepo.UpdateStatus(c.Request.Context(), sessionID, models.StatusDisconnected); updateErr != nil {
@@ -420,14 +1111,88 @@ func (h *SessionHandler) LogoutSession(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// @Summary      Fazer logout de todas as sessões da API key
+// @Description  Faz logout de todas as sessões que a API key autenticada pode acessar (todas, para chaves sem
+// @Description  restrição de sessão), continuando após falhas individuais.
+// @Description  Retorna 200 quando todos os logouts têm sucesso e 207 com o resultado por sessão quando algum falha.
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.LogoutAllResponse
+// @Success      207  {object}  dto.LogoutAllResponse
+// @Failure      401  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /sessions/logout-all [post]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) LogoutAllSessions(c *gin.Context) {
+	authCtx, ok := middleware.GetAuthContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Autenticação necessária",
+		})
+		return
+	}
+
+	sessions, err := h.sessionManager.ListSessionsByAPIKey(c.Request.Context(), authCtx.Key)
+	if err != nil {
+		h.logger.Error("Erro ao listar sessões da API key", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao listar sessões",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ID < sessions[j].ID
+	})
+
+	h.logger.Info("Fazendo logout de todas as sessões da API key", "userID", authCtx.UserID, "sessions", len(sessions))
+
+	response := &dto.LogoutAllResponse{
+		Total:   len(sessions),
+		Results: make([]dto.LogoutAllResult, 0, len(sessions)),
+	}
+
+	for _, session := range sessions {
+		result := dto.LogoutAllResult{SessionID: session.ID}
+
+		if err := h.sessionManager.LogoutSessionByAPIKey(authCtx.APIKey, authCtx.Key, session.ID); err != nil {
+			h.logger.Warn("Erro ao fazer logout da sessão", "sessionID", session.ID, "error", err)
+			result.Error = err.Error()
+			response.Failed++
+		} else {
+			if err := h.sessionRepo.SetDisconnected(c.Request.Context(), session.ID); err != nil {
+				h.logger.Warn("Erro ao atualizar status da sessão", "sessionID", session.ID, "error", err)
+			}
+			result.Success = true
+			response.Succeeded++
+		}
+
+		response.Results = append(response.Results, result)
+	}
+
+	h.logger.Info("Logout em lote concluído", "userID", authCtx.UserID, "succeeded", response.Succeeded, "failed", response.Failed)
+
+	status := http.StatusOK
+	if response.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, response)
+}
+
 // @Summary      Gerar QR Code para conexão
-// @Description  Gera um QR Code para conectar o WhatsApp Web
+// @Description  Gera um QR Code para conectar o WhatsApp Web. Códigos expirados não são retornados: a resposta é 410
+// @Description  e a sessão precisa ser reconectada (POST /sessions/{sessionID}/connect) para emitir um novo código.
 // @Tags         sessions
 // @Accept       json
 // @Produce      json
 // @Param        sessionID  path      string  true  "ID da sessão"
 // @Success      200        {object}  dto.QRCodeResponse
 // @Failure      400        {object}  map[string]interface{}
+// @Failure      410        {object}  map[string]interface{}
 // @Failure      500        {object}  map[string]interface{}
 // @Router       /sessions/{sessionID}/qr [get]
 // @Security     ApiKeyAuth
@@ -443,7 +1208,16 @@ func (h *SessionHandler) GetQRCode(c *gin.Context) {
 
 	h.logger.Info("Gerando QR Code para sessão", "sessionID", sessionID)
 
-	qrCode, err := h.sessionManager.GenerateQRCode(sessionID)
+	qrCode, expiresIn, err := h.sessionManager.GenerateQRCode(sessionID)
+	if errors.Is(err, meow.ErrQRCodeExpired) {
+		h.logger.Warn("QR code expirado", "sessionID", sessionID)
+		c.JSON(http.StatusGone, gin.H{
+			"error":   true,
+			"message": "QR code expirado",
+			"details": "Reconecte a sessão para gerar um novo QR code",
+		})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Erro ao gerar QR code", "sessionID", sessionID, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -454,23 +1228,24 @@ func (h *SessionHandler) GetQRCode(c *gin.Context) {
 		return
 	}
 
-	if err := h.sessionRepo.UpdateQRCode(c.Request.Context(), sessionID, qrCode); err != nil {
-		h.logger.Warn("Erro ao salvar QR code no banco", "sessionID", sessionID, "error", err)
-	}
-
-	h.logger.Info("QR Code gerado com sucesso", "sessionID", sessionID)
+	h.logger.Info("QR Code gerado com sucesso", "sessionID", sessionID, "expiresIn", expiresIn)
 
 	response := &dto.QRCodeResponse{
-		SessionID: sessionID,
-		QRCode:    qrCode,
-		ExpiresIn: 60,
+		SessionID:   sessionID,
+		QRCode:      qrCode,
+		ExpiresIn:   int(expiresIn.Seconds()),
+		QRExpiresAt: time.Now().Add(expiresIn),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 // @Summary      Emparelhar telefone
-// @Description  Emparelha um número de telefone com a sessão WhatsApp
+// @Description  Emparelha um número de telefone com a sessão WhatsApp usando código de vinculação.
+// @Description  clientType aceita chrome, edge, firefox, ie, opera, safari, electron, uwp ou other;
+// @Description  clientDisplayName deve seguir o formato "Navegador (SO)" e é validado pelo WhatsApp.
+// @Description  O código é gerado pelo WhatsApp e retornado em code (ABCDEFGH) e formattedCode (ABCD-EFGH) para
+// @Description  ser digitado no celular; expiresIn/expiresAt indicam o prazo máximo, contado da conexão da sessão.
 // @Tags         sessions
 // @Accept       json
 // @Produce      json
@@ -495,25 +1270,46 @@ func (h *SessionHandler) PairPhone(c *gin.Context) {
 	var req dto.PairPhoneRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Erro ao decodificar request de emparelhamento", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	if err := req.ValidatePhoneNumber(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Número do telefone inválido",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	clientType, err := meow.ParsePairClientType(req.ClientType)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
-			"message": "Dados inválidos",
+			"message": "Tipo de cliente inválido",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	if req.PhoneNumber == "" {
+	if err := req.ValidateClientDisplayName(); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
-			"message": "Número do telefone é obrigatório",
+			"message": "Nome de exibição inválido",
+			"details": err.Error(),
 		})
 		return
 	}
 
-	h.logger.Info("Iniciando emparelhamento de telefone", "sessionID", sessionID, "phone", req.PhoneNumber)
+	clientDisplayName := req.ClientDisplayName
+	if clientDisplayName == "" {
+		clientDisplayName = meow.DefaultPairClientDisplayName
+	}
+
+	h.logger.Info("Iniciando emparelhamento de telefone", "sessionID", sessionID, "phone", req.PhoneNumber, "clientType", req.ClientType, "clientDisplayName", clientDisplayName)
 
-	linkingCode, err := h.sessionManager.PairPhone(sessionID, req.PhoneNumber)
+	linkingCode, err := h.sessionManager.PairPhone(sessionID, req.GetPhoneNumber(), clientType, clientDisplayName)
 	if err != nil {
 		h.logger.Error("Erro ao emparelhar telefone", "sessionID", sessionID, "phone", req.PhoneNumber, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -535,24 +1331,33 @@ func (h *SessionHandler) PairPhone(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("Emparelhamento iniciado com sucesso", "sessionID", sessionID, "linkingCode", linkingCode)
+	formattedCode := meow.FormatPairCode(linkingCode)
+	h.logger.Info("Emparelhamento iniciado com sucesso", "sessionID", sessionID, "linkingCode", formattedCode)
 
 	response := &dto.PairPhoneResponse{
-		Session: dto.ToSessionResponse(session),
-		Message: fmt.Sprintf("Código de emparelhamento: %s", linkingCode),
-		Success: true,
+		Session:       dto.ToSessionResponse(session),
+		Code:          meow.RawPairCode(linkingCode),
+		FormattedCode: formattedCode,
+		ExpiresIn:     int(meow.PairCodeValidity.Seconds()),
+		ExpiresAt:     time.Now().Add(meow.PairCodeValidity),
+		Message:       fmt.Sprintf("Código de emparelhamento: %s", formattedCode),
+		Success:       true,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 // @Summary      Configurar proxy
-// @Description  Configura um proxy para a sessão WhatsApp
+// @Description  Configura um proxy (http ou socks5, com usuário e senha opcionais) para a sessão WhatsApp.
+// @Description  O proxy é usado pelo websocket do WhatsApp e pelo upload e download de mídias; com a sessão
+// @Description  conectada, passa a valer na próxima conexão. Com validate=true (no corpo ou na query) o proxy é
+// @Description  testado antes de salvar, abrindo um túnel até o WhatsApp através dele; a validação é opcional.
 // @Tags         sessions
 // @Accept       json
 // @Produce      json
-// @Param        sessionID  path      string                true  "ID da sessão"
-// @Param        request    body      dto.SetProxyRequest   true  "Dados do proxy"
+// @Param        sessionID  path      string                true   "ID da sessão"
+// @Param        validate   query     bool                  false  "Verifica se o proxy está acessível antes de salvar"
+// @Param        request    body      dto.SetProxyRequest   true   "Dados do proxy"
 // @Success      200        {object}  dto.SetProxyResponse
 // @Failure      400        {object}  map[string]interface{}
 // @Failure      404        {object}  map[string]interface{}
@@ -571,24 +1376,48 @@ func (h *SessionHandler) SetProxy(c *gin.Context) {
 	var req dto.SetProxyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Erro ao decodificar request de proxy", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	if req.Host == "" || req.Port == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
-			"message": "Dados inválidos",
-			"details": err.Error(),
+			"message": "Host e porta são obrigatórios",
 		})
 		return
 	}
 
-	if req.Host == "" || req.Port == 0 {
+	if !req.Type.IsValid() {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
-			"message": "Host e porta são obrigatórios",
+			"message": "Tipo de proxy inválido",
+			"details": "valores aceitos: http, socks5",
 		})
 		return
 	}
 
 	h.logger.Info("Configurando proxy para sessão", "sessionID", sessionID, "host", req.Host, "port", req.Port, "type", req.Type)
 
+	if req.Validate || c.Query("validate") == "true" {
+		candidate := &models.Session{
+			ProxyHost: req.Host,
+			ProxyPort: req.Port,
+			ProxyType: req.Type,
+			ProxyUser: req.Username,
+			ProxyPass: req.Password,
+		}
+		if err := meow.ValidateProxy(c.Request.Context(), candidate.GetProxyURL()); err != nil {
+			h.logger.Warn("Proxy inacessível", "sessionID", sessionID, "host", req.Host, "port", req.Port, "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Proxy inacessível",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
 	err := h.sessionRepo.UpdateProxy(c.Request.Context(), sessionID, req.Host, req.Port, req.Type, req.Username, req.Password)
 	if err != nil {
 		h.logger.Error("Erro ao atualizar proxy no banco", "sessionID", sessionID, "error", err)
@@ -611,6 +1440,12 @@ func (h *SessionHandler) SetProxy(c *gin.Context) {
 		return
 	}
 
+	if _, exists := h.sessionManager.GetSession(sessionID); exists {
+		if err := h.sessionManager.SetProxy(sessionID, session); err != nil {
+			h.logger.Warn("Erro ao aplicar proxy ao cliente ativo", "sessionID", sessionID, "error", err)
+		}
+	}
+
 	h.logger.Info("Proxy configurado com sucesso", "sessionID", sessionID)
 
 	response := &dto.SetProxyResponse{
@@ -620,3 +1455,236 @@ func (h *SessionHandler) SetProxy(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// @Summary      Remover proxy
+// @Description  Remove o proxy configurado para a sessão WhatsApp. As próximas conexões e transferências de mídia
+// @Description  passam a ser diretas; com a sessão conectada, a conexão atual é mantida até a próxima reconexão.
+// @Tags         sessions
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.SetProxyResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/proxy [delete]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) ClearProxy(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "ID da sessão é obrigatório",
+		})
+		return
+	}
+
+	h.logger.Info("Removendo proxy da sessão", "sessionID", sessionID)
+
+	if err := h.sessionRepo.ClearProxy(c.Request.Context(), sessionID); err != nil {
+		h.logger.Error("Erro ao remover proxy no banco", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Erro ao buscar sessão após remover proxy", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if _, exists := h.sessionManager.GetSession(sessionID); exists {
+		if err := h.sessionManager.ClearProxy(sessionID); err != nil {
+			h.logger.Warn("Erro ao remover proxy do cliente ativo", "sessionID", sessionID, "error", err)
+		}
+	}
+
+	h.logger.Info("Proxy removido com sucesso", "sessionID", sessionID)
+
+	response := &dto.SetProxyResponse{
+		Session: dto.ToSessionResponse(session),
+		Message: "Proxy removido com sucesso",
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary      Configurar plataforma do dispositivo
+// @Description  Define a plataforma (DeviceProps.PlatformType) e o nome do SO informados ao WhatsApp no emparelhamento,
+// @Description  que determinam como o aparelho vinculado aparece no celular. Valores aceitos para platform são os nomes
+// @Description  do enum do whatsmeow, como CHROME, FIREFOX, SAFARI, EDGE, DESKTOP, IPAD e ANDROID_TABLET.
+// @Description  A alteração vale para o próximo emparelhamento; dispositivos já vinculados mantêm a identidade original.
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                  true  "ID da sessão"
+// @Param        request    body      dto.SetPlatformRequest  true  "Plataforma do dispositivo"
+// @Success      200        {object}  dto.SetPlatformResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/platform [put]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) SetPlatform(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.SetPlatformRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request de plataforma", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	platform := strings.ToUpper(strings.TrimSpace(req.Platform))
+	if platform != "" {
+		if _, err := meow.ParseDevicePlatform(platform); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Plataforma inválida",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	osName := strings.TrimSpace(req.OSName)
+	if len(osName) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Nome do SO inválido",
+			"details": "O campo osName deve ter no máximo 100 caracteres",
+		})
+		return
+	}
+
+	if err := h.sessionRepo.UpdatePlatform(c.Request.Context(), sessionID, platform, osName); err != nil {
+		h.logger.Error("Erro ao atualizar plataforma no banco", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.sessionManager.SetDevicePlatform(sessionID, platform, osName); err != nil {
+		h.logger.Error("Erro ao aplicar plataforma ao cliente", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Erro ao aplicar plataforma",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Erro ao buscar sessão após configurar plataforma", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Plataforma configurada com sucesso", "sessionID", sessionID, "platform", platform, "osName", osName)
+
+	message := "Plataforma configurada com sucesso"
+	if session.DeviceJid != "" {
+		message = "Plataforma salva; será aplicada no próximo emparelhamento da sessão"
+	}
+
+	c.JSON(http.StatusOK, &dto.SetPlatformResponse{
+		Session: dto.ToSessionResponse(session),
+		Message: message,
+	})
+}
+
+// @Summary      Configurar armazenamento de mensagens
+// @Description  Define se a sessão armazena as mensagens recebidas para consulta em GET /sessions/{sessionID}/messages.
+// @Description  O armazenamento é opcional por sessão e só pode ser habilitado com MESSAGE_STORE_ENABLED no servidor;
+// @Description  as mensagens ficam guardadas pelo período de MESSAGE_STORE_RETENTION_DAYS. Com purge, as mensagens já
+// @Description  armazenadas da sessão são apagadas.
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                        true  "ID da sessão"
+// @Param        request    body      dto.SetMessageStorageRequest  true  "Opção de armazenamento"
+// @Success      200        {object}  dto.SetMessageStorageResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      401        {object}  map[string]interface{}
+// @Failure      403        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      409        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/messages/storage [put]
+// @Security     ApiKeyAuth
+func (h *SessionHandler) SetMessageStorage(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.SetMessageStorageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.sessionManager.SetSessionMessageStorage(c.Request.Context(), sessionID, *req.Enabled, req.Purge); err != nil {
+		if errors.Is(err, meow.ErrMessageStoreDisabled) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   true,
+				"message": "Armazenamento de mensagens indisponível",
+				"details": "Habilite MESSAGE_STORE_ENABLED no servidor para que as sessões possam armazenar mensagens",
+			})
+			return
+		}
+		h.logger.Error("Erro ao configurar armazenamento de mensagens", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao configurar armazenamento de mensagens",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	session, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Erro ao buscar sessão após configurar armazenamento", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Armazenamento de mensagens configurado", "sessionID", sessionID, "enabled", *req.Enabled, "purge", req.Purge)
+
+	message := "Armazenamento de mensagens desabilitado"
+	if *req.Enabled {
+		message = "Armazenamento de mensagens habilitado"
+	}
+	if req.Purge {
+		message += "; mensagens armazenadas apagadas"
+	}
+
+	c.JSON(http.StatusOK, &dto.SetMessageStorageResponse{
+		Session: dto.ToSessionResponse(session),
+		Message: message,
+	})
+}
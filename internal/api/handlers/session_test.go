@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"zpigo/internal/api/dto"
+	"zpigo/internal/api/middleware"
+	"zpigo/internal/store"
+	"zpigo/internal/store/models"
+)
+
+// fakeSessionRepo implementa apenas List; os demais métodos não são usados nestes testes
+type fakeSessionRepo struct {
+	store.SessionRepositoryInterface
+	sessions []*models.Session
+}
+
+func (r *fakeSessionRepo) List(ctx context.Context) ([]*models.Session, error) {
+	return append([]*models.Session{}, r.sessions...), nil
+}
+
+// withKey simula o AuthMiddleware, registrando a chave informada no contexto
+func withKey(key *models.APIKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(string(middleware.AuthContextKeyValue), &middleware.AuthContext{Key: key})
+	}
+}
+
+func newTestSessionHandler() *SessionHandler {
+	return &SessionHandler{
+		BaseHandler: NewBaseHandler("SessionHandler"),
+		sessionRepo: &fakeSessionRepo{sessions: []*models.Session{
+			{ID: "session-a", Name: "A"},
+			{ID: "session-b", Name: "B"},
+		}},
+	}
+}
+
+func TestListSessionsFiltersByKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newTestSessionHandler()
+
+	tests := []struct {
+		name string
+		key  *models.APIKey
+		want []string
+	}{
+		{"chave global", &models.APIKey{ID: "global"}, []string{"session-a", "session-b"}},
+		{"chave restrita", &models.APIKey{ID: "scoped", SessionID: "session-b"}, []string{"session-b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/sessions/list", withKey(tt.key), h.ListSessions)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sessions/list", nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, esperado 200", w.Code)
+			}
+
+			var resp dto.SessionListResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+			if resp.Total != len(tt.want) || len(resp.Sessions) != len(tt.want) {
+				t.Fatalf("total = %d, esperado %d", resp.Total, len(tt.want))
+			}
+			for i, id := range tt.want {
+				if resp.Sessions[i].ID != id {
+					t.Errorf("sessão %d = %s, esperado %s", i, resp.Sessions[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestAddSessionRequiresGlobalKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := newTestSessionHandler()
+
+	r := gin.New()
+	r.POST("/sessions/add", withKey(&models.APIKey{ID: "scoped", SessionID: "session-a"}), h.AddSession)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sessions/add", nil))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, esperado 403", w.Code)
+	}
+}
@@ -0,0 +1,457 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+
+	"zpigo/internal/api/dto"
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
+)
+
+const blockedByCaveat = "O WhatsApp não informa se um contato bloqueou a sessão. O resultado é inferido " +
+	"de sinais indiretos (foto de perfil e recado ocultos, ausência de dispositivos) que também " +
+	"ocorrem por configurações de privacidade, portanto deve ser tratado como uma estimativa."
+
+type UserHandler struct {
+	*BaseHandler
+	sessionRepo    store.SessionRepositoryInterface
+	sessionManager *meow.SessionManager
+}
+
+func NewUserHandler(sessionRepo store.SessionRepositoryInterface, sessionManager *meow.SessionManager) *UserHandler {
+	return &UserHandler{
+		BaseHandler:    NewBaseHandler("UserHandler"),
+		sessionRepo:    sessionRepo,
+		sessionManager: sessionManager,
+	}
+}
+
+// @Summary      Verificar se o contato bloqueou a sessão
+// @Description  Infere, a partir de sinais indiretos, se o contato aparenta ter bloqueado a sessão. O WhatsApp não expõe essa informação, então o resultado é uma estimativa com nível de confiança
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        phone      query     string  true  "Número do contato"
+// @Success      200        {object}  dto.BlockedByResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/user/blocked-by [get]
+// @Security     ApiKeyAuth
+func (h *UserHandler) GetBlockedBy(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	phone := strings.TrimPrefix(strings.TrimSpace(c.Query("phone")), "+")
+
+	if !isDigits(phone) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Número de telefone inválido",
+			"details": "Informe apenas dígitos no parâmetro phone",
+		})
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	resp := &dto.BlockedByResponse{
+		Phone:      phone,
+		Confidence: dto.BlockConfidenceNone,
+		Signals:    []string{},
+		Caveat:     blockedByCaveat,
+	}
+
+	registered, err := client.IsOnWhatsApp([]string{"+" + phone})
+	if err != nil {
+		h.logger.Error("Erro ao verificar número no WhatsApp", "sessionID", sessionID, "phone", phone, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao verificar número no WhatsApp",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(registered) == 0 || !registered[0].IsIn {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	jid := registered[0].JID
+	resp.JID = jid.String()
+	resp.Registered = true
+
+	if blocklist, err := client.GetBlocklist(); err != nil {
+		h.logger.Warn("Erro ao buscar lista de bloqueio", "sessionID", sessionID, "error", err)
+	} else {
+		for _, blocked := range blocklist.JIDs {
+			if blocked.ToNonAD() == jid.ToNonAD() {
+				resp.BlockedBySession = true
+				break
+			}
+		}
+	}
+
+	_, err = client.GetProfilePictureInfo(jid, &whatsmeow.GetProfilePictureParams{})
+	if errors.Is(err, whatsmeow.ErrProfilePictureUnauthorized) {
+		resp.Signals = append(resp.Signals, dto.BlockSignalProfilePictureHidden)
+	} else if err != nil && !errors.Is(err, whatsmeow.ErrProfilePictureNotSet) {
+		h.logger.Warn("Erro ao buscar foto de perfil", "sessionID", sessionID, "jid", jid, "error", err)
+	}
+
+	infos, err := client.GetUserInfo([]types.JID{jid})
+	if err != nil {
+		h.logger.Warn("Erro ao buscar informações do usuário", "sessionID", sessionID, "jid", jid, "error", err)
+	} else if info, ok := infos[jid]; ok {
+		if info.Status == "" {
+			resp.Signals = append(resp.Signals, dto.BlockSignalStatusHidden)
+		}
+		if len(info.Devices) == 0 {
+			resp.Signals = append(resp.Signals, dto.BlockSignalNoDevices)
+		}
+	}
+
+	// Foto e recado ocultos juntos é o padrão mais comum de bloqueio, mas
+	// também é o resultado de privacidade "ninguém", por isso nunca passa de médio
+	switch {
+	case len(resp.Signals) >= 2:
+		resp.LikelyBlocked = true
+		resp.Confidence = dto.BlockConfidenceMedium
+	case len(resp.Signals) == 1:
+		resp.Confidence = dto.BlockConfidenceLow
+	}
+
+	h.logger.Debug("Inferência de bloqueio concluída", "sessionID", sessionID, "jid", jid, "signals", resp.Signals, "likelyBlocked", resp.LikelyBlocked)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary      Consultar recado do usuário
+// @Description  Retorna o recado (about) do usuário. A data da alteração só é conhecida quando a sessão recebeu o
+// @Description  evento UserAbout correspondente, pois o WhatsApp não a informa na consulta.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        jid        path      string  true  "Número do telefone ou JID do usuário"
+// @Success      200        {object}  dto.UserAboutResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/user/{jid}/about [get]
+// @Security     ApiKeyAuth
+func (h *UserHandler) GetUserStatus(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	jid, err := parseUserJID(c.Param("jid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "JID inválido",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	users, ok := h.fetchUserAbout(c, sessionID, []types.JID{jid})
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, users[0])
+}
+
+// @Summary      Consultar recados de vários usuários
+// @Description  Retorna o recado (about) de cada usuário informado, na ordem da requisição, em uma única consulta ao WhatsApp
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                true  "ID da sessão"
+// @Param        request    body      dto.UserAboutRequest  true  "Usuários consultados"
+// @Success      200        {object}  dto.UserAboutListResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/user/about [post]
+// @Security     ApiKeyAuth
+func (h *UserHandler) GetUserStatuses(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.UserAboutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindingError(c, err)
+		return
+	}
+
+	if len(req.JIDs) == 0 || len(req.JIDs) > dto.MaxUserAboutBatch {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Quantidade de JIDs inválida",
+			"details": fmt.Sprintf("Informe entre 1 e %d JIDs", dto.MaxUserAboutBatch),
+		})
+		return
+	}
+
+	jids := make([]types.JID, 0, len(req.JIDs))
+	for _, value := range req.JIDs {
+		jid, err := parseUserJID(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "JID inválido",
+				"details": fmt.Sprintf("%s: %v", value, err),
+			})
+			return
+		}
+		jids = append(jids, jid)
+	}
+
+	users, ok := h.fetchUserAbout(c, sessionID, jids)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.UserAboutListResponse{
+		Users: users,
+		Total: len(users),
+	})
+}
+
+// @Summary      Inscrever-se na presença do usuário
+// @Description  Solicita ao WhatsApp as atualizações de presença (online, visto por último) do usuário, entregues
+// @Description  pelo evento Presence. O WhatsApp só envia essas atualizações enquanto a própria sessão estiver com
+// @Description  presença disponível (available), e a inscrição precisa ser refeita após cada reconexão.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Param        jid        path      string  true  "Número do telefone ou JID do usuário"
+// @Success      200        {object}  dto.SubscribePresenceResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/user/{jid}/presence/subscribe [post]
+// @Security     ApiKeyAuth
+func (h *UserHandler) SubscribePresence(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	jid, err := parseUserJID(c.Param("jid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "JID inválido",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return
+	}
+
+	if err := client.SubscribePresence(jid); err != nil {
+		h.logger.Error("Erro ao inscrever-se na presença do usuário", "sessionID", sessionID, "jid", jid, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao inscrever-se na presença do usuário",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Inscrição de presença enviada", "sessionID", sessionID, "jid", jid)
+
+	c.JSON(http.StatusOK, &dto.SubscribePresenceResponse{
+		Success: true,
+		JID:     jid.String(),
+		Message: "Inscrição de presença enviada",
+	})
+}
+
+// @Summary      Listar contatos
+// @Description  Lista os contatos sincronizados do aparelho, com nome da agenda, nome de exibição e nome comercial.
+// @Description  O parâmetro search filtra, sem diferenciar maiúsculas, por qualquer um dos nomes ou pelo número.
+// @Tags         users
+// @Produce      json
+// @Param        sessionID  path      string  true   "ID da sessão"
+// @Param        search     query     string  false  "Trecho do nome ou do número"
+// @Success      200        {object}  dto.ContactsResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/contacts [get]
+// @Security     ApiKeyAuth
+func (h *UserHandler) GetContacts(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+	search := strings.TrimSpace(c.Query("search"))
+
+	client, exists := h.sessionManager.GetSession(sessionID)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Cliente WhatsApp não encontrado",
+			"details": "Sessão não está ativa no gerenciador",
+		})
+		return
+	}
+
+	// Os contatos ficam no store local, então a sessão só precisa estar emparelhada, não conectada
+	if client.Store.ID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Sessão não autenticada",
+			"details": "A sessão precisa estar emparelhada para ter contatos sincronizados",
+		})
+		return
+	}
+
+	contacts, err := client.Store.Contacts.GetAllContacts(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Erro ao buscar contatos", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao buscar contatos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response := &dto.ContactsResponse{
+		Contacts: make([]*dto.ContactResponse, 0, len(contacts)),
+		Search:   search,
+	}
+	for jid, info := range contacts {
+		contact := &dto.ContactResponse{
+			JID:          jid.String(),
+			PushName:     info.PushName,
+			BusinessName: info.BusinessName,
+			FirstName:    info.FirstName,
+			FullName:     info.FullName,
+		}
+		if jid.Server == types.DefaultUserServer {
+			contact.Phone = jid.User
+		}
+		if search != "" && !contactMatches(contact, search) {
+			continue
+		}
+		response.Contacts = append(response.Contacts, contact)
+	}
+
+	sort.Slice(response.Contacts, func(i, j int) bool {
+		a, b := contactDisplayName(response.Contacts[i]), contactDisplayName(response.Contacts[j])
+		if a != b {
+			return a < b
+		}
+		return response.Contacts[i].JID < response.Contacts[j].JID
+	})
+	response.Total = len(response.Contacts)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// contactMatches indica se algum dos nomes ou o número do contato contém o termo buscado
+func contactMatches(contact *dto.ContactResponse, search string) bool {
+	search = strings.ToLower(search)
+	if digits := strings.TrimPrefix(search, "+"); isDigits(digits) && strings.Contains(contact.Phone, digits) {
+		return true
+	}
+
+	for _, name := range []string{contact.FullName, contact.FirstName, contact.PushName, contact.BusinessName} {
+		if name != "" && strings.Contains(strings.ToLower(name), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// contactDisplayName retorna o nome usado na ordenação, na mesma prioridade do WhatsApp: agenda,
+// nome comercial e nome de exibição; contatos sem nome ficam no fim
+func contactDisplayName(contact *dto.ContactResponse) string {
+	for _, name := range []string{contact.FullName, contact.BusinessName, contact.PushName} {
+		if name != "" {
+			return strings.ToLower(name)
+		}
+	}
+	return "\uffff" + contact.JID
+}
+
+// fetchUserAbout consulta os recados no WhatsApp e completa com a data das alterações recebidas
+// pela sessão. Em caso de falha a resposta de erro já é escrita.
+func (h *UserHandler) fetchUserAbout(c *gin.Context, sessionID string, jids []types.JID) ([]*dto.UserAboutResponse, bool) {
+	client, ok := getLoggedInClient(c, h.sessionManager, sessionID)
+	if !ok {
+		return nil, false
+	}
+
+	infos, err := client.GetUserInfo(jids)
+	if err != nil {
+		h.logger.Error("Erro ao buscar informações do usuário", "sessionID", sessionID, "jids", len(jids), "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao buscar recado do usuário",
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+
+	zpigoClient, _ := h.sessionManager.GetZPigoClient(sessionID)
+
+	users := make([]*dto.UserAboutResponse, 0, len(jids))
+	for _, jid := range jids {
+		user := &dto.UserAboutResponse{JID: jid.String()}
+		if info, found := infos[jid]; found {
+			user.Registered = true
+			user.Status = info.Status
+		}
+		if zpigoClient != nil {
+			if changedAt, known := zpigoClient.GetUserAboutTimestamp(jid); known {
+				setAt := changedAt.Unix()
+				user.SetAt = &setAt
+			}
+		}
+		users = append(users, user)
+	}
+
+	return users, true
+}
+
+// parseUserJID aceita um número de telefone (com ou sem +) ou o JID de um usuário
+func parseUserJID(value string) (types.JID, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "+")
+	if isDigits(value) {
+		return types.NewJID(value, types.DefaultUserServer), nil
+	}
+
+	jid, err := types.ParseJID(value)
+	if err != nil {
+		return types.JID{}, err
+	}
+	if !isUserJID(jid) {
+		return types.JID{}, fmt.Errorf("esperado o JID de um usuário, recebido %s", value)
+	}
+
+	return jid.ToNonAD(), nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, char := range s {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
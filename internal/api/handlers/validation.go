@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"zpigo/internal/api/dto"
+)
+
+// init faz o validador do gin reportar os campos pelo nome JSON, e não pelo nome do campo da struct,
+// antes que qualquer struct seja validada (o validador guarda os nomes em cache)
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// validationErrors converte o erro de ShouldBindJSON em erros por campo. Retorna nil quando o erro não
+// se refere a campos específicos (ex: JSON malformado).
+func validationErrors(err error) []dto.ValidationError {
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		result := make([]dto.ValidationError, 0, len(fieldErrs))
+		for _, fe := range fieldErrs {
+			result = append(result, dto.ValidationError{
+				Field:   validationFieldPath(fe),
+				Value:   fmt.Sprintf("%v", fe.Value()),
+				Message: validationMessage(fe),
+			})
+		}
+		return result
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []dto.ValidationError{{
+			Field:   typeErr.Field,
+			Value:   typeErr.Value,
+			Message: fmt.Sprintf("tipo inválido, esperado %s", jsonTypeName(typeErr.Type)),
+		}}
+	}
+
+	return nil
+}
+
+// bindingErrorDetails resume o erro de ShouldBindJSON para o campo details da resposta, sem expor nomes
+// de structs do Go
+func bindingErrorDetails(err error, fieldErrs []dto.ValidationError) string {
+	if len(fieldErrs) == 0 {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return fmt.Sprintf("JSON malformado na posição %d", syntaxErr.Offset)
+		}
+		return err.Error()
+	}
+
+	parts := make([]string, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		parts[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// respondBindingError responde 400 com os erros por campo no formato usado pelos handlers de sessão
+func respondBindingError(c *gin.Context, err error) {
+	fieldErrs := validationErrors(err)
+	if fieldErrs == nil {
+		fieldErrs = []dto.ValidationError{}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":   true,
+		"message": "Dados inválidos",
+		"details": bindingErrorDetails(err, fieldErrs),
+		"errors":  fieldErrs,
+	})
+}
+
+// respondMessageBindingError é o equivalente de respondBindingError para os handlers de mensagem, que
+// respondem no formato de MessageErrorResponse
+func respondMessageBindingError(c *gin.Context, err error) {
+	fieldErrs := validationErrors(err)
+	c.JSON(http.StatusBadRequest, dto.ToMessageValidationErrorResponse(bindingErrorDetails(err, fieldErrs), fieldErrs))
+}
+
+// validationFieldPath retorna o caminho do campo sem o nome da struct raiz (ex: buttons[0].id)
+func validationFieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+func validationMessage(fe validator.FieldError) string {
+	unit := "caracteres"
+	switch fe.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = "itens"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		unit = ""
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "campo obrigatório"
+	case "min", "gte":
+		if unit == "" {
+			return fmt.Sprintf("deve ser maior ou igual a %s", fe.Param())
+		}
+		return fmt.Sprintf("deve ter no mínimo %s %s", fe.Param(), unit)
+	case "max", "lte":
+		if unit == "" {
+			return fmt.Sprintf("deve ser menor ou igual a %s", fe.Param())
+		}
+		return fmt.Sprintf("deve ter no máximo %s %s", fe.Param(), unit)
+	case "len":
+		return fmt.Sprintf("deve ter exatamente %s %s", fe.Param(), unit)
+	case "oneof":
+		return fmt.Sprintf("deve ser um de: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "email":
+		return "deve ser um e-mail válido"
+	case "url":
+		return "deve ser uma URL válida"
+	case "numeric", "number":
+		return "deve conter apenas números"
+	default:
+		return fmt.Sprintf("valor inválido (%s)", fe.Tag())
+	}
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "texto"
+	case reflect.Bool:
+		return "booleano"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "número"
+	case reflect.Slice, reflect.Array:
+		return "lista"
+	default:
+		return "objeto"
+	}
+}
@@ -0,0 +1,529 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"zpigo/internal/api/dto"
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
+	"zpigo/internal/store/models"
+	"zpigo/internal/webhook"
+)
+
+type WebhookHandler struct {
+	*BaseHandler
+	sessionRepo    store.SessionRepositoryInterface
+	webhookRepo    store.WebhookRepositoryInterface
+	sessionManager *meow.SessionManager
+}
+
+func NewWebhookHandler(sessionRepo store.SessionRepositoryInterface, webhookRepo store.WebhookRepositoryInterface, sessionManager *meow.SessionManager) *WebhookHandler {
+	return &WebhookHandler{
+		BaseHandler:    NewBaseHandler("WebhookHandler"),
+		sessionRepo:    sessionRepo,
+		webhookRepo:    webhookRepo,
+		sessionManager: sessionManager,
+	}
+}
+
+// @Summary      Configurar webhook da sessão
+// @Description  Define a URL, os eventos e os filtros opcionais fromMe/isGroup do webhook da sessão, substituindo a configuração anterior
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                 true  "ID da sessão"
+// @Param        request    body      dto.SetWebhookRequest  true  "Configuração do webhook"
+// @Success      200        {object}  dto.WebhookResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/webhook [post]
+// @Security     ApiKeyAuth
+func (h *WebhookHandler) SetWebhook(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.SetWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	if !meow.ValidateWebhookURL(req.URL) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "URL de webhook inválida",
+			"details": "A URL deve começar com http:// ou https://",
+		})
+		return
+	}
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		h.logger.Error("Sessão não encontrada", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	events, err := webhook.ParseEventTypes(req.Events)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Eventos inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(events) == 0 {
+		events = []webhook.EventType{webhook.EventAll}
+	}
+
+	model, ok := h.getSessionWebhook(c, sessionID)
+	if !ok {
+		return
+	}
+
+	exists := model != nil
+	if !exists {
+		model = &models.Webhook{
+			SessionID:  sessionID,
+			MaxRetries: meow.DefaultMaxRetries,
+			RetryDelay: int(meow.DefaultRetryDelay / time.Second),
+			Backoff:    string(webhook.BackoffExponential),
+
+			MaxRetryDelay:    int(webhook.MaxRetryDelay / time.Second),
+			DeliveryDeadline: int(webhook.DefaultDeliveryDeadline / time.Second),
+		}
+	}
+	model.URL = req.URL
+	if err := model.SetEvents(events); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Eventos inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	model.Secret = req.Secret
+	model.FilterFromMe = req.FromMe
+	model.FilterIsGroup = req.IsGroup
+
+	if exists {
+		err = h.webhookRepo.Update(c.Request.Context(), model)
+	} else {
+		err = h.webhookRepo.Create(c.Request.Context(), model)
+	}
+	if err != nil {
+		h.logger.Error("Erro ao salvar webhook", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao salvar webhook",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.sessionManager.ApplyWebhookConfig(sessionID, meow.NewWebhookConfigFromModel(model)); err != nil {
+		h.logger.Error("Erro ao aplicar configuração de webhook", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Erro ao aplicar configuração de webhook",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Webhook configurado", "sessionID", sessionID, "url", model.URL, "events", events)
+
+	c.JSON(http.StatusOK, dto.ToWebhookResponse(model, true))
+}
+
+// @Summary      Consultar webhook da sessão
+// @Description  Retorna a configuração de webhook da sessão, incluindo a política de retry
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.WebhookResponse
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/webhook [get]
+// @Security     ApiKeyAuth
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	model, ok := h.getSessionWebhook(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if model == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Webhook não configurado",
+		})
+		return
+	}
+
+	_, enabled := h.sessionManager.GetWebhookManager().GetConfig(sessionID)
+
+	c.JSON(http.StatusOK, dto.ToWebhookResponse(model, enabled))
+}
+
+// @Summary      Remover webhook da sessão
+// @Description  Remove a configuração de webhook da sessão; os eventos deixam de ser enviados imediatamente
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.DeleteWebhookResponse
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/webhook [delete]
+// @Security     ApiKeyAuth
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	if err := h.webhookRepo.DeleteBySessionID(c.Request.Context(), sessionID); err != nil {
+		h.logger.Error("Erro ao remover webhook", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao remover webhook",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.sessionManager.RemoveWebhookConfig(sessionID)
+
+	h.logger.Info("Webhook removido", "sessionID", sessionID)
+
+	c.JSON(http.StatusOK, &dto.DeleteWebhookResponse{
+		Success: true,
+		Message: "Webhook removido com sucesso",
+	})
+}
+
+// @Summary      Atualizar política de retry do webhook
+// @Description  Altera maxRetries, retryDelay, maxRetryDelay, a estratégia de backoff e o prazo (deadline) das entregas
+// @Description  do webhook da sessão. O intervalo entre tentativas recebe jitter: metade é fixa e metade sorteada.
+// @Description  Entregas cuja próxima tentativa ultrapassaria o prazo são marcadas como expired.
+// @Description  A nova política é persistida e vale para as próximas entregas.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                         true  "ID da sessão"
+// @Param        request    body      dto.UpdateWebhookRetryRequest  true  "Política de retry"
+// @Success      200        {object}  dto.WebhookResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/webhook/retry [patch]
+// @Security     ApiKeyAuth
+func (h *WebhookHandler) UpdateWebhookRetry(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.UpdateWebhookRetryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	model, ok := h.getSessionWebhook(c, sessionID)
+	if !ok {
+		return
+	}
+
+	if model == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Webhook não configurado",
+			"details": "Configure o webhook da sessão antes de alterar a política de retry",
+		})
+		return
+	}
+
+	webhookManager := h.sessionManager.GetWebhookManager()
+	if _, configured := webhookManager.GetConfig(sessionID); !configured {
+		if err := h.sessionManager.ApplyWebhookConfig(sessionID, meow.NewWebhookConfigFromModel(model)); err != nil {
+			h.logger.Error("Erro ao aplicar configuração de webhook", "sessionID", sessionID, "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Erro ao aplicar configuração de webhook",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	if req.MaxRetries != nil {
+		model.MaxRetries = *req.MaxRetries
+	}
+	if req.RetryDelay != nil {
+		model.RetryDelay = *req.RetryDelay
+	}
+	if req.Backoff != nil {
+		model.Backoff = *req.Backoff
+	}
+	if req.MaxRetryDelay != nil {
+		model.MaxRetryDelay = *req.MaxRetryDelay
+	}
+	if req.Deadline != nil {
+		model.DeliveryDeadline = *req.Deadline
+	}
+
+	previous, _ := webhookManager.GetConfig(sessionID)
+
+	_, err := webhookManager.UpdateRetryPolicy(sessionID, webhook.RetryPolicy{
+		MaxRetries:    model.MaxRetries,
+		RetryDelay:    time.Duration(model.RetryDelay) * time.Second,
+		MaxRetryDelay: time.Duration(model.MaxRetryDelay) * time.Second,
+		Backoff:       webhook.BackoffStrategy(model.Backoff),
+		Deadline:      time.Duration(model.DeliveryDeadline) * time.Second,
+	})
+	if err != nil {
+		h.logger.Warn("Política de retry inválida", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Política de retry inválida",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.webhookRepo.Update(c.Request.Context(), model); err != nil {
+		h.logger.Error("Erro ao salvar política de retry", "sessionID", sessionID, "error", err)
+		// Mantém o gerenciador igual ao banco, que continua com a política anterior
+		if previous != nil {
+			_ = webhookManager.SetConfig(sessionID, previous)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao salvar política de retry",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	h.logger.Info("Política de retry atualizada",
+		"sessionID", sessionID,
+		"maxRetries", model.MaxRetries,
+		"retryDelay", model.RetryDelay,
+		"maxRetryDelay", model.MaxRetryDelay,
+		"backoff", model.Backoff,
+		"deadline", model.DeliveryDeadline)
+
+	c.JSON(http.StatusOK, dto.ToWebhookResponse(model, true))
+}
+
+// @Summary      Histórico de entregas do webhook
+// @Description  Lista as entregas recentes do webhook da sessão mantidas em memória, com tentativas, último erro
+// @Description  e o horário agendado para a próxima tentativa (nextRetry) das entregas pendentes
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true   "ID da sessão"
+// @Param        limit      query     int     false  "Quantidade máxima de entregas (padrão: 50)"
+// @Success      200        {object}  dto.WebhookDeliveriesResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/webhook/deliveries [get]
+// @Security     ApiKeyAuth
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > webhook.DeliveryHistorySize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Parâmetro limit inválido",
+			"details": fmt.Sprintf("Informe um número entre 1 e %d", webhook.DeliveryHistorySize),
+		})
+		return
+	}
+
+	deliveries := h.sessionManager.GetWebhookManager().GetDeliveryHistory(sessionID, limit)
+
+	resp := &dto.WebhookDeliveriesResponse{
+		SessionID:  sessionID,
+		Deliveries: make([]*dto.WebhookDeliveryResponse, 0, len(deliveries)),
+		Total:      len(deliveries),
+	}
+	for _, delivery := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, dto.ToWebhookDeliveryResponse(delivery))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// @Summary      Disparar evento sintético no webhook
+// @Description  Enfileira um evento do tipo escolhido com dados arbitrários pelo mesmo pipeline de entrega dos eventos reais,
+// @Description  sem executar nenhuma ação no WhatsApp. O payload inclui data.synthetic=true para identificação pelo consumidor.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string                     true  "ID da sessão"
+// @Param        request    body      dto.TriggerWebhookRequest  true  "Evento sintético"
+// @Success      202        {object}  dto.TriggerWebhookResponse
+// @Failure      400        {object}  map[string]interface{}
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      409        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/webhook/trigger [post]
+// @Security     ApiKeyAuth
+func (h *WebhookHandler) TriggerWebhook(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	var req dto.TriggerWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Erro ao decodificar request", "sessionID", sessionID, "error", err)
+		respondBindingError(c, err)
+		return
+	}
+
+	eventType := webhook.EventType(req.Event)
+	if !eventType.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Tipo de evento inválido",
+			"details": "Evento não suportado: " + req.Event,
+		})
+		return
+	}
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		h.logger.Error("Sessão não encontrada", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	webhookManager := h.sessionManager.GetWebhookManager()
+	if !webhookManager.Accepts(sessionID, eventType, req.Data) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Evento não seria entregue",
+			"details": "Nenhum webhook ativo da sessão ou global está inscrito no evento " + req.Event + " com esses dados",
+		})
+		return
+	}
+
+	webhookManager.Send(sessionID, eventType, req.Data, map[string]interface{}{
+		"synthetic": true,
+	})
+
+	h.logger.Info("Evento sintético enfileirado", "sessionID", sessionID, "event", req.Event)
+
+	c.JSON(http.StatusAccepted, &dto.TriggerWebhookResponse{
+		Success:   true,
+		Message:   "Evento sintético enfileirado",
+		SessionID: sessionID,
+		Event:     req.Event,
+	})
+}
+
+// @Summary      Listar eventos ativos da sessão
+// @Description  Retorna a interseção entre os eventos inscritos na sessão e os eventos efetivamente emitidos pelo ZPigo, sinalizando inscrições que nunca serão entregues
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        sessionID  path      string  true  "ID da sessão"
+// @Success      200        {object}  dto.ActiveEventsResponse
+// @Failure      404        {object}  map[string]interface{}
+// @Failure      500        {object}  map[string]interface{}
+// @Router       /sessions/{sessionID}/events/active [get]
+// @Security     ApiKeyAuth
+func (h *WebhookHandler) GetActiveEvents(c *gin.Context) {
+	sessionID := c.Param("sessionID")
+
+	if _, err := h.sessionRepo.GetByID(c.Request.Context(), sessionID); err != nil {
+		h.logger.Error("Sessão não encontrada", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Sessão não encontrada",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	subscriptions := []string{}
+	if zpigoClient, exists := h.sessionManager.GetZPigoClient(sessionID); exists {
+		subscriptions = zpigoClient.GetSubscriptions()
+	} else {
+		model, ok := h.getSessionWebhook(c, sessionID)
+		if !ok {
+			return
+		}
+		if model != nil {
+			subscriptions = webhook.EventTypeStrings(model.GetEvents())
+		}
+	}
+
+	handled := []string{}
+	for _, eventType := range meow.HandledEventTypes() {
+		handled = append(handled, string(eventType))
+	}
+
+	response := dto.ActiveEventsResponse{
+		SessionID:     sessionID,
+		Subscriptions: subscriptions,
+		Active:        []string{},
+		Unhandled:     []dto.UnhandledEventStatus{},
+		Handled:       handled,
+	}
+
+	allSubscribed := false
+	for _, sub := range subscriptions {
+		switch {
+		case sub == string(webhook.EventAll):
+			allSubscribed = true
+		case meow.IsHandledEventType(sub):
+			response.Active = append(response.Active, sub)
+		case webhook.EventType(sub).IsValid():
+			response.Unhandled = append(response.Unhandled, dto.UnhandledEventStatus{
+				Event:   sub,
+				Warning: "Evento declarado, mas ainda não emitido pelo ZPigo",
+			})
+		default:
+			response.Unhandled = append(response.Unhandled, dto.UnhandledEventStatus{
+				Event:   sub,
+				Warning: "Tipo de evento desconhecido",
+			})
+		}
+	}
+	if allSubscribed {
+		response.Active = handled
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// getSessionWebhook busca o webhook persistido da sessão, retornando nil quando não há webhook.
+// Em caso de falha na busca a resposta de erro já é escrita e ok é false.
+func (h *WebhookHandler) getSessionWebhook(c *gin.Context, sessionID string) (*models.Webhook, bool) {
+	webhooks, err := h.webhookRepo.GetBySessionID(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("Erro ao buscar webhook", "sessionID", sessionID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Erro ao buscar webhook",
+			"details": err.Error(),
+		})
+		return nil, false
+	}
+
+	if len(webhooks) == 0 {
+		return nil, true
+	}
+
+	return webhooks[0], true
+}
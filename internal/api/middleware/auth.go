@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -10,6 +12,7 @@ import (
 
 	"zpigo/internal/logger"
 	"zpigo/internal/meow"
+	"zpigo/internal/store/models"
 )
 
 type AuthContextKey string
@@ -22,6 +25,8 @@ type AuthContext struct {
 	APIKey    string
 	SessionID string
 	UserID    string
+	// Key é o registro da chave, usado para conferir a quais sessões ela dá acesso
+	Key *models.APIKey
 }
 
 func AuthMiddleware(authManager *meow.AuthManager) gin.HandlerFunc {
@@ -56,7 +61,11 @@ func AuthMiddleware(authManager *meow.AuthManager) gin.HandlerFunc {
 			return
 		}
 
-		authCtxResult, err := authManager.ValidateAPIKey(c.Request.Context(), apiKey, "")
+		authCtxResult, err := authManager.ValidateAPIKey(c.Request.Context(), apiKey, c.Param("sessionID"))
+		if errors.Is(err, meow.ErrAPIKeyWrongSession) {
+			abortWrongSession(c, authLogger, apiKey)
+			return
+		}
 		if err != nil || authCtxResult == nil {
 			authLogger.Warn("API Key inválida", "apiKey", maskAPIKey(apiKey), "path", c.Request.URL.Path, "error", err)
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -70,8 +79,10 @@ func AuthMiddleware(authManager *meow.AuthManager) gin.HandlerFunc {
 		}
 
 		authCtx := &AuthContext{
-			APIKey: apiKey,
-			UserID: getUserIDFromAPIKey(apiKey),
+			APIKey:    apiKey,
+			SessionID: authCtxResult.SessionID,
+			UserID:    getUserIDFromAPIKey(authCtxResult.Key),
+			Key:       authCtxResult.Key,
 		}
 
 		c.Set(string(AuthContextKeyValue), authCtx)
@@ -85,32 +96,61 @@ func AuthMiddleware(authManager *meow.AuthManager) gin.HandlerFunc {
 	}
 }
 
-func OptionalAuthMiddleware(authManager *meow.AuthManager) gin.HandlerFunc {
-	authLogger := logger.NewForComponent("OptionalAuthMiddleware")
-
-	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
+// AdminTokenHeader é o cabeçalho que carrega o token das rotas administrativas
+const AdminTokenHeader = "X-Admin-Token"
 
-		if authHeader != "" {
-			apiKey := strings.TrimPrefix(authHeader, "Bearer ")
-			apiKey = strings.TrimSpace(apiKey)
+// AdminAuthMiddleware protege as rotas /admin com o token de ADMIN_TOKEN. Chaves de API não dão acesso a
+// essas rotas; sem token configurado, elas respondem 503.
+func AdminAuthMiddleware(adminToken string) gin.HandlerFunc {
+	authLogger := logger.NewForComponent("AdminAuthMiddleware")
+	if adminToken == "" {
+		authLogger.Warn("ADMIN_TOKEN não configurado, rotas administrativas desativadas")
+	}
 
-			authCtxResult, err := authManager.ValidateAPIKey(c.Request.Context(), apiKey, "")
-			if apiKey != "" && err == nil && authCtxResult != nil {
-				authCtx := &AuthContext{
-					APIKey: apiKey,
-					UserID: getUserIDFromAPIKey(apiKey),
-				}
+	return func(c *gin.Context) {
+		if adminToken == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":     true,
+				"message":   "Rotas administrativas desativadas: defina ADMIN_TOKEN",
+				"code":      http.StatusServiceUnavailable,
+				"timestamp": time.Now().Unix(),
+			})
+			c.Abort()
+			return
+		}
 
-				c.Set(string(AuthContextKeyValue), authCtx)
-				authLogger.Debug("Autenticação opcional bem-sucedida", "apiKey", maskAPIKey(apiKey))
-			}
+		token := strings.TrimSpace(c.GetHeader(AdminTokenHeader))
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			authLogger.Warn("Token administrativo inválido", "path", c.Request.URL.Path)
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":     true,
+				"message":   "Token administrativo inválido",
+				"code":      http.StatusUnauthorized,
+				"timestamp": time.Now().Unix(),
+			})
+			c.Abort()
+			return
 		}
 
 		c.Next()
 	}
 }
 
+// abortWrongSession responde 403 quando a chave é válida, mas restrita a outra sessão
+func abortWrongSession(c *gin.Context, authLogger logger.Logger, apiKey string) {
+	authLogger.Warn("API Key sem acesso à sessão",
+		"apiKey", maskAPIKey(apiKey),
+		"sessionID", c.Param("sessionID"),
+		"path", c.Request.URL.Path)
+	c.JSON(http.StatusForbidden, gin.H{
+		"error":     true,
+		"message":   "API Key sem acesso a esta sessão",
+		"code":      http.StatusForbidden,
+		"timestamp": time.Now().Unix(),
+	})
+	c.Abort()
+}
+
 func GetAuthContext(c *gin.Context) (*AuthContext, bool) {
 	authCtx, exists := c.Get(string(AuthContextKeyValue))
 	if !exists {
@@ -141,7 +181,6 @@ func (e *AuthError) Error() string {
 	return e.Message
 }
 
-
 func maskAPIKey(apiKey string) string {
 	if len(apiKey) <= 8 {
 		return strings.Repeat("*", len(apiKey))
@@ -149,8 +188,9 @@ func maskAPIKey(apiKey string) string {
 	return apiKey[:4] + strings.Repeat("*", len(apiKey)-8) + apiKey[len(apiKey)-4:]
 }
 
-func getUserIDFromAPIKey(apiKey string) string {
-	return "user_" + apiKey[:8]
+// getUserIDFromAPIKey identifica o chamador pelo registro da chave, nunca pelo texto da chave
+func getUserIDFromAPIKey(key *models.APIKey) string {
+	return "key_" + key.ID
 }
 
 func SessionAuthMiddleware() gin.HandlerFunc {
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"zpigo/internal/meow"
+	"zpigo/internal/store"
+	"zpigo/internal/store/models"
+)
+
+type fakeAPIKeyRepo struct {
+	keys map[string]*models.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Create(ctx context.Context, key *models.APIKey) error {
+	r.keys[key.KeyHash] = key
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	key, ok := r.keys[keyHash]
+	if !ok {
+		return nil, errors.New("chave não encontrada")
+	}
+	return key, nil
+}
+
+func (r *fakeAPIKeyRepo) List(ctx context.Context) ([]*models.APIKey, error) {
+	return nil, nil
+}
+
+func (r *fakeAPIKeyRepo) Revoke(ctx context.Context, id string) error {
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) TouchLastUsed(ctx context.Context, id string) error {
+	return nil
+}
+
+// fakeSessionRepo implementa apenas GetByID; os demais métodos não são usados pela autenticação
+type fakeSessionRepo struct {
+	store.SessionRepositoryInterface
+	sessions map[string]*models.Session
+}
+
+func (r *fakeSessionRepo) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, errors.New("sessão não encontrada")
+	}
+	return session, nil
+}
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	keys := &fakeAPIKeyRepo{keys: map[string]*models.APIKey{
+		meow.HashAPIKey("zpg_global"):  {ID: "global"},
+		meow.HashAPIKey("zpg_scopedA"): {ID: "scoped-a", SessionID: "session-a"},
+	}}
+	sessions := &fakeSessionRepo{sessions: map[string]*models.Session{
+		"session-a": {ID: "session-a", Name: "A"},
+		"session-b": {ID: "session-b", Name: "B"},
+	}}
+
+	r := gin.New()
+	r.GET("/sessions/:sessionID/info", AuthMiddleware(meow.NewAuthManagerWithRepositories(sessions, keys)), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestAuthMiddlewareSessionScope(t *testing.T) {
+	r := newTestRouter(t)
+
+	tests := []struct {
+		name    string
+		header  string
+		session string
+		want    int
+	}{
+		{"sem chave", "", "session-a", http.StatusUnauthorized},
+		{"chave desconhecida", "Bearer zpg_unknown", "session-a", http.StatusUnauthorized},
+		{"chave restrita na própria sessão", "Bearer zpg_scopedA", "session-a", http.StatusOK},
+		{"chave restrita em outra sessão", "Bearer zpg_scopedA", "session-b", http.StatusForbidden},
+		{"chave global em qualquer sessão", "Bearer zpg_global", "session-b", http.StatusOK},
+		{"chave global em sessão inexistente", "Bearer zpg_global", "session-x", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/sessions/"+tt.session+"/info", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, esperado %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		configured string
+		header     string
+		want       int
+	}{
+		{"token não configurado", "", "qualquer", http.StatusServiceUnavailable},
+		{"sem token", "segredo", "", http.StatusUnauthorized},
+		{"token errado", "segredo", "outro", http.StatusUnauthorized},
+		{"token correto", "segredo", "segredo", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/admin/cache/stats", AdminAuthMiddleware(tt.configured), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/cache/stats", nil)
+			if tt.header != "" {
+				req.Header.Set(AdminTokenHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, esperado %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -111,8 +114,82 @@ func (m *Middleware) Security() gin.HandlerFunc {
 	}
 }
 
-func (m *Middleware) Timeout(timeout time.Duration) gin.HandlerFunc {
+// MaxBodySize recusa com 413 corpos maiores que limit bytes antes que o handler decodifique o JSON.
+// O Content-Length é verificado primeiro; sem ele (ou se for inferior ao real), o corpo é lido por um
+// http.MaxBytesReader e mantido em memória até o limite. limit <= 0 desativa a verificação.
+func (m *Middleware) MaxBodySize(limit int64) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if limit <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			m.abortBodyTooLarge(c, c.Request.ContentLength, limit)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				m.abortBodyTooLarge(c, -1, limit)
+				return
+			}
+
+			m.logger.Warn("Erro ao ler corpo da requisição", "path", c.Request.URL.Path, "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":     true,
+				"message":   "Erro ao ler corpo da requisição",
+				"code":      http.StatusBadRequest,
+				"timestamp": time.Now().Unix(),
+			})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func (m *Middleware) abortBodyTooLarge(c *gin.Context, size, limit int64) {
+	m.logger.Warn("Corpo da requisição excede o limite",
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"contentLength", size,
+		"limit", limit,
+	)
+
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":     true,
+		"message":   "Corpo da requisição excede o tamanho máximo",
+		"details":   fmt.Sprintf("limite de %d bytes", limit),
+		"code":      http.StatusRequestEntityTooLarge,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// timeoutWriteMargin é a folga dada aos deadlines da conexão em rotas com timeout estendido, para que a
+// resposta 408 ainda possa ser escrita depois que o timeout da requisição expira
+const timeoutWriteMargin = 10 * time.Second
+
+// Timeout limita o processamento de cada requisição. Rotas presentes em overrides (chaveadas pelo
+// caminho registrado no gin, ex: /sessions/:sessionID/message/send/media) usam o timeout informado e
+// têm os deadlines de leitura e escrita da conexão estendidos, pois o http.Server aplica ReadTimeout e
+// WriteTimeout a todas as rotas. Um override <= 0 remove o timeout e os deadlines (streams SSE).
+func (m *Middleware) Timeout(timeout time.Duration, overrides map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := timeout
+		if extended, ok := overrides[c.FullPath()]; ok {
+			if extended <= 0 {
+				m.setConnDeadlines(c, time.Time{})
+				c.Next()
+				return
+			}
+			timeout = extended
+			m.setConnDeadlines(c, time.Now().Add(extended+timeoutWriteMargin))
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 		defer cancel()
 
@@ -145,6 +222,19 @@ func (m *Middleware) Timeout(timeout time.Duration) gin.HandlerFunc {
 	}
 }
 
+// setConnDeadlines altera os deadlines de leitura e escrita da conexão da requisição atual; o valor
+// zero remove os deadlines
+func (m *Middleware) setConnDeadlines(c *gin.Context, deadline time.Time) {
+	rc := http.NewResponseController(c.Writer)
+
+	if err := rc.SetReadDeadline(deadline); err != nil {
+		m.logger.Debug("Não foi possível alterar o deadline de leitura", "path", c.Request.URL.Path, "error", err)
+	}
+	if err := rc.SetWriteDeadline(deadline); err != nil {
+		m.logger.Debug("Não foi possível alterar o deadline de escrita", "path", c.Request.URL.Path, "error", err)
+	}
+}
+
 func generateRequestID() string {
 	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), time.Now().Unix())
 }
@@ -15,7 +15,7 @@ import (
 	"zpigo/internal/store"
 )
 
-func NewRouter(store *store.Store) *gin.Engine {
+func NewRouter(store *store.Store, sessionManager *meow.SessionManager) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
@@ -25,19 +25,20 @@ func NewRouter(store *store.Store) *gin.Engine {
 	r.Use(mw.RequestID())
 	r.Use(mw.Logger())
 	r.Use(mw.Recovery())
-	r.Use(mw.Timeout(60 * time.Second))
+	serverConfig := store.GetConfig().Server
+	mediaTimeout := time.Duration(serverConfig.MediaTimeout) * time.Second
+	r.Use(mw.Timeout(time.Duration(serverConfig.RequestTimeout)*time.Second, map[string]time.Duration{
+		"/sessions/:sessionID/message/send/media":   mediaTimeout,
+		"/sessions/:sessionID/message/send/sticker": mediaTimeout,
+		"/sessions/:sessionID/message/download":     mediaTimeout,
+		"/sessions/:sessionID/events":               0,
+	}))
 	r.Use(mw.CORS())
 	r.Use(mw.Security())
 
 	// Obter repositórios do store
 	sessionRepo := store.GetSessionRepository()
 
-	sessionManager := meow.NewSessionManager(
-		store.GetContainer(),
-		store.GetDB(),
-		sessionRepo,
-	)
-
 	go func() {
 		if err := sessionManager.ConnectOnStartup(); err != nil {
 			fmt.Printf("Erro ao reconectar sessões na inicialização: %v\n", err)
@@ -45,26 +46,86 @@ func NewRouter(store *store.Store) *gin.Engine {
 	}()
 
 	sessionHandler := handlers.NewSessionHandlerWithManager(sessionRepo, sessionManager)
-	messageHandler := handlers.NewMessageHandlerWithManager(sessionRepo, sessionManager)
+	messageHandler := handlers.NewMessageHandlerWithManager(sessionRepo, sessionManager, store.GetConfig().Media)
+	messageHandler.SetSendTimeout(time.Duration(store.GetConfig().Session.SendTimeout) * time.Second)
+	groupHandler := handlers.NewGroupHandler(sessionRepo, sessionManager)
+	userHandler := handlers.NewUserHandler(sessionRepo, sessionManager)
+	chatHandler := handlers.NewChatHandler(sessionRepo, sessionManager)
+	newsletterHandler := handlers.NewNewsletterHandler(sessionRepo, sessionManager)
+	metricsHandler := handlers.NewMetricsHandler(sessionRepo, sessionManager)
 	authManager := meow.NewAuthManager(store.GetDB(), sessionRepo)
+	adminHandler := handlers.NewAdminHandler(sessionRepo, sessionManager, sessionHandler, messageHandler, authManager)
+	webhookHandler := handlers.NewWebhookHandler(sessionRepo, store.GetWebhookRepository(), sessionManager)
+	broadcastHandler := handlers.NewBroadcastHandler(sessionRepo, sessionManager, messageHandler)
 
 	r.GET("/health", func(c *gin.Context) {
-		handlers.HealthCheck(c)
+		handlers.HealthCheck(c, store)
+	})
+
+	r.GET("/metrics", func(c *gin.Context) {
+		metricsHandler.GetMetrics(c)
 	})
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	admin := r.Group("/admin")
+	admin.Use(middleware.AdminAuthMiddleware(serverConfig.AdminToken))
+	{
+		admin.GET("/cache/stats", func(c *gin.Context) {
+			adminHandler.GetCacheStats(c)
+		})
+		admin.POST("/cache/warm", func(c *gin.Context) {
+			adminHandler.WarmCache(c)
+		})
+		admin.GET("/sessions/:sessionID/managers", func(c *gin.Context) {
+			adminHandler.GetSessionManagers(c)
+		})
+		admin.POST("/sessions/sweep-qr", func(c *gin.Context) {
+			adminHandler.SweepStaleQRCodes(c)
+		})
+		admin.GET("/webhook/global", func(c *gin.Context) {
+			adminHandler.GetGlobalWebhook(c)
+		})
+		admin.PUT("/webhook/global", func(c *gin.Context) {
+			adminHandler.SetGlobalWebhook(c)
+		})
+		admin.DELETE("/webhook/global", func(c *gin.Context) {
+			adminHandler.DeleteGlobalWebhook(c)
+		})
+		admin.GET("/devices", func(c *gin.Context) {
+			adminHandler.ListDevices(c)
+		})
+		admin.DELETE("/devices/:jid", func(c *gin.Context) {
+			adminHandler.DeleteDevice(c)
+		})
+		admin.POST("/api-keys", func(c *gin.Context) {
+			adminHandler.CreateAPIKey(c)
+		})
+		admin.GET("/api-keys", func(c *gin.Context) {
+			adminHandler.ListAPIKeys(c)
+		})
+		admin.DELETE("/api-keys/:keyID", func(c *gin.Context) {
+			adminHandler.RevokeAPIKey(c)
+		})
+	}
+
 	sessions := r.Group("/sessions")
 	{
-		sessions.POST("/add", func(c *gin.Context) {
+		sessions.POST("/add", middleware.AuthMiddleware(authManager), func(c *gin.Context) {
 			sessionHandler.AddSession(c)
 		})
-		sessions.GET("/list", func(c *gin.Context) {
+		sessions.GET("/list", middleware.AuthMiddleware(authManager), func(c *gin.Context) {
 			sessionHandler.ListSessions(c)
 		})
+		sessions.GET("/by-name/:name", middleware.AuthMiddleware(authManager), func(c *gin.Context) {
+			sessionHandler.GetSessionsByName(c)
+		})
+		sessions.POST("/logout-all", middleware.AuthMiddleware(authManager), func(c *gin.Context) {
+			sessionHandler.LogoutAllSessions(c)
+		})
 
 		sessionGroup := sessions.Group("/:sessionID")
-		sessionGroup.Use(middleware.OptionalAuthMiddleware(authManager))
+		sessionGroup.Use(middleware.AuthMiddleware(authManager))
 		{
 			sessionGroup.GET("/info", func(c *gin.Context) {
 				sessionHandler.GetSessionInfo(c)
@@ -72,12 +133,33 @@ func NewRouter(store *store.Store) *gin.Engine {
 			sessionGroup.GET("/status", func(c *gin.Context) {
 				sessionHandler.GetSessionStatus(c)
 			})
+			sessionGroup.GET("/device", func(c *gin.Context) {
+				sessionHandler.GetDevice(c)
+			})
+			sessionGroup.GET("/events", func(c *gin.Context) {
+				sessionHandler.StreamEvents(c)
+			})
+			sessionGroup.GET("/uptime", func(c *gin.Context) {
+				sessionHandler.GetSessionUptime(c)
+			})
+			sessionGroup.GET("/dashboard", func(c *gin.Context) {
+				sessionHandler.GetSessionDashboard(c)
+			})
+			sessionGroup.GET("/notifications", func(c *gin.Context) {
+				sessionHandler.GetNotifications(c)
+			})
+			sessionGroup.POST("/notifications/set", func(c *gin.Context) {
+				sessionHandler.SetNotifications(c)
+			})
 			sessionGroup.DELETE("/", func(c *gin.Context) {
 				sessionHandler.DeleteSession(c)
 			})
 			sessionGroup.POST("/connect", func(c *gin.Context) {
 				sessionHandler.ConnectSession(c)
 			})
+			sessionGroup.POST("/restart", func(c *gin.Context) {
+				sessionHandler.RestartSession(c)
+			})
 			sessionGroup.POST("/logout", func(c *gin.Context) {
 				sessionHandler.LogoutSession(c)
 			})
@@ -88,21 +170,197 @@ func NewRouter(store *store.Store) *gin.Engine {
 				sessionHandler.PairPhone(c)
 			})
 
+			sessionGroup.PATCH("/name", func(c *gin.Context) {
+				sessionHandler.RenameSession(c)
+			})
+			sessionGroup.PUT("/platform", func(c *gin.Context) {
+				sessionHandler.SetPlatform(c)
+			})
+
 			proxyGroup := sessionGroup.Group("/proxy")
 			{
+				proxyGroup.DELETE("", func(c *gin.Context) {
+					sessionHandler.ClearProxy(c)
+				})
 				proxyGroup.POST("/set", func(c *gin.Context) {
 					sessionHandler.SetProxy(c)
 				})
 			}
 
+			webhookGroup := sessionGroup.Group("/webhook")
+			{
+				webhookGroup.POST("", func(c *gin.Context) {
+					webhookHandler.SetWebhook(c)
+				})
+				webhookGroup.GET("", func(c *gin.Context) {
+					webhookHandler.GetWebhook(c)
+				})
+				webhookGroup.DELETE("", func(c *gin.Context) {
+					webhookHandler.DeleteWebhook(c)
+				})
+				webhookGroup.POST("/trigger", func(c *gin.Context) {
+					webhookHandler.TriggerWebhook(c)
+				})
+				webhookGroup.PATCH("/retry", func(c *gin.Context) {
+					webhookHandler.UpdateWebhookRetry(c)
+				})
+				webhookGroup.GET("/deliveries", func(c *gin.Context) {
+					webhookHandler.GetWebhookDeliveries(c)
+				})
+			}
+
+			broadcastGroup := sessionGroup.Group("/broadcast")
+			{
+				broadcastGroup.POST("", func(c *gin.Context) {
+					broadcastHandler.CreateBroadcast(c)
+				})
+				broadcastGroup.GET("/:jobID", func(c *gin.Context) {
+					broadcastHandler.GetBroadcast(c)
+				})
+				broadcastGroup.DELETE("/:jobID", func(c *gin.Context) {
+					broadcastHandler.CancelBroadcast(c)
+				})
+			}
+
+			sessionGroup.GET("/events/active", func(c *gin.Context) {
+				webhookHandler.GetActiveEvents(c)
+			})
+
 			messageGroup := sessionGroup.Group("/message")
+			messageGroup.Use(mw.MaxBodySize(serverConfig.MaxBodySize))
 			{
 				messageGroup.POST("/send/text", func(c *gin.Context) {
 					messageHandler.SendTextMessage(c)
 				})
+				messageGroup.POST("/send/bulk", func(c *gin.Context) {
+					messageHandler.SendBulkText(c)
+				})
 				messageGroup.POST("/send/media", func(c *gin.Context) {
 					messageHandler.SendMedia(c)
 				})
+				messageGroup.POST("/send/sticker", func(c *gin.Context) {
+					messageHandler.SendSticker(c)
+				})
+				messageGroup.POST("/send/flow", func(c *gin.Context) {
+					messageHandler.SendFlowMessage(c)
+				})
+				messageGroup.POST("/send/poll", func(c *gin.Context) {
+					messageHandler.SendPoll(c)
+				})
+				messageGroup.POST("/send/list", func(c *gin.Context) {
+					messageHandler.SendListMessage(c)
+				})
+				messageGroup.POST("/send/buttons", func(c *gin.Context) {
+					messageHandler.SendButtonsMessage(c)
+				})
+				messageGroup.POST("/forward", func(c *gin.Context) {
+					messageHandler.ForwardMessage(c)
+				})
+				messageGroup.POST("/download", func(c *gin.Context) {
+					messageHandler.DownloadMedia(c)
+				})
+				messageGroup.POST("/:messageID/edit", func(c *gin.Context) {
+					messageHandler.EditMessage(c)
+				})
+				messageGroup.GET("/:messageID/edits", func(c *gin.Context) {
+					messageHandler.GetMessageEdits(c)
+				})
+				messageGroup.GET("/:messageID/readers", func(c *gin.Context) {
+					messageHandler.GetMessageReaders(c)
+				})
+				messageGroup.GET("/:messageID/status", func(c *gin.Context) {
+					messageHandler.GetMessageStatus(c)
+				})
+				messageGroup.POST("/star", func(c *gin.Context) {
+					chatHandler.StarMessage(c)
+				})
+			}
+
+			groupGroup := sessionGroup.Group("/group")
+			{
+				groupGroup.POST("/join", func(c *gin.Context) {
+					groupHandler.JoinGroupWithLink(c)
+				})
+				groupGroup.GET("/:groupJID", func(c *gin.Context) {
+					groupHandler.GetGroupInfo(c)
+				})
+				groupGroup.POST("/:groupJID/name", func(c *gin.Context) {
+					groupHandler.SetGroupName(c)
+				})
+				groupGroup.POST("/:groupJID/topic", func(c *gin.Context) {
+					groupHandler.SetGroupTopic(c)
+				})
+				groupGroup.GET("/:groupJID/invite", func(c *gin.Context) {
+					groupHandler.GetGroupInviteLink(c)
+				})
+			}
+
+			sessionGroup.GET("/resolve/jid", func(c *gin.Context) {
+				messageHandler.ResolveJID(c)
+			})
+
+			sessionGroup.GET("/contacts", func(c *gin.Context) {
+				userHandler.GetContacts(c)
+			})
+
+			contactGroup := sessionGroup.Group("/contact")
+			{
+				contactGroup.POST("/validate", func(c *gin.Context) {
+					messageHandler.ValidateContact(c)
+				})
+			}
+
+			newsletterGroup := sessionGroup.Group("/newsletter")
+			{
+				newsletterGroup.POST("/follow", func(c *gin.Context) {
+					newsletterHandler.FollowNewsletter(c)
+				})
+				newsletterGroup.POST("/unfollow", func(c *gin.Context) {
+					newsletterHandler.UnfollowNewsletter(c)
+				})
+				newsletterGroup.GET("/:jid", func(c *gin.Context) {
+					newsletterHandler.GetNewsletterInfo(c)
+				})
+			}
+
+			sessionGroup.GET("/messages", func(c *gin.Context) {
+				messageHandler.ListMessages(c)
+			})
+			sessionGroup.PUT("/messages/storage", func(c *gin.Context) {
+				sessionHandler.SetMessageStorage(c)
+			})
+
+			sessionGroup.GET("/chats", func(c *gin.Context) {
+				chatHandler.ListChats(c)
+			})
+
+			chatGroup := sessionGroup.Group("/chat")
+			{
+				chatGroup.POST("/ephemeral", func(c *gin.Context) {
+					chatHandler.SetDisappearingTimer(c)
+				})
+				chatGroup.POST("/delete", func(c *gin.Context) {
+					chatHandler.DeleteChat(c)
+				})
+				chatGroup.POST("/clear", func(c *gin.Context) {
+					chatHandler.ClearChat(c)
+				})
+			}
+
+			userGroup := sessionGroup.Group("/user")
+			{
+				userGroup.GET("/blocked-by", func(c *gin.Context) {
+					userHandler.GetBlockedBy(c)
+				})
+				userGroup.POST("/about", func(c *gin.Context) {
+					userHandler.GetUserStatuses(c)
+				})
+				userGroup.GET("/:jid/about", func(c *gin.Context) {
+					userHandler.GetUserStatus(c)
+				})
+				userGroup.POST("/:jid/presence/subscribe", func(c *gin.Context) {
+					userHandler.SubscribePresence(c)
+				})
 			}
 		}
 	}
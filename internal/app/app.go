@@ -6,55 +6,102 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"zpigo/internal/api/router"
 	"zpigo/internal/config"
 	"zpigo/internal/logger"
+	"zpigo/internal/meow"
 	"zpigo/internal/store"
+	"zpigo/internal/webhook"
 )
 
 type App struct {
-	config *config.Config
-	store  *store.Store
-	server *http.Server
+	config         *config.Config
+	store          *store.Store
+	sessionManager *meow.SessionManager
+	server         *http.Server
 }
 
 func New() (*App, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar configuração: %w", err)
+	}
+
 	logger.Init(logger.Config{
-		Level:  "info",
-		Format: "console",
-		Output: "stdout",
+		Level:  cfg.App.LogLevel,
+		Format: cfg.App.LogFormat,
+		Output: cfg.App.LogOutput,
+		File:   cfg.App.LogFile,
 	})
 
 	log := logger.WithComponent("app")
-	log.Info("Iniciando aplicação")
+	log.Info("Iniciando aplicação", "logLevel", cfg.App.LogLevel, "logFormat", cfg.App.LogFormat, "logOutput", cfg.App.LogOutput)
 
-	cfg, err := config.Load()
+	unifiedStore, err := store.NewStore(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao carregar configuração: %w", err)
+		return nil, fmt.Errorf("erro ao criar store unificado: %w", err)
 	}
 
-	unifiedStore, err := store.NewStore(cfg)
+	sessionManager := meow.NewSessionManager(
+		unifiedStore.GetContainer(),
+		unifiedStore.GetDB(),
+		unifiedStore.GetSessionRepository(),
+	)
+	sessionManager.SetWhatsAppLogLevel(cfg.App.WALogLevel)
+	sessionManager.SetMaxConcurrentPairings(cfg.Session.MaxConcurrentPairings)
+	sessionManager.SetSendRateLimit(cfg.Session.SendRatePerMinute)
+	sessionManager.SetSendTimeout(time.Duration(cfg.Session.SendTimeout) * time.Second)
+	sessionManager.SetWebhookOptions(webhook.Options{
+		Workers:        cfg.Webhook.Workers,
+		QueueSize:      cfg.Webhook.QueueSize,
+		EnqueueTimeout: time.Duration(cfg.Webhook.EnqueueTimeoutMs) * time.Millisecond,
+	})
+	if err := configureGlobalWebhook(sessionManager.GetWebhookManager(), cfg.Webhook); err != nil {
+		return nil, err
+	}
+	sessionManager.StartQRSweeper(time.Duration(cfg.Session.QRSweepInterval) * time.Second)
+	sessionManager.SetMessageStore(
+		cfg.Messages.Enabled,
+		time.Duration(cfg.Messages.RetentionDays)*24*time.Hour,
+		time.Duration(cfg.Messages.PruneInterval)*time.Second,
+	)
+
+	loaded, err := sessionManager.LoadConfigsFromRepository(context.Background(), unifiedStore.GetWebhookRepository())
 	if err != nil {
-		return nil, fmt.Errorf("erro ao criar store unificado: %w", err)
+		return nil, fmt.Errorf("erro ao carregar webhooks: %w", err)
+	}
+	log.Info("Webhooks carregados", "total", loaded)
+
+	if _, err := sessionManager.ReconcileDevices(context.Background()); err != nil {
+		log.Warn("Erro ao verificar devices órfãos", "error", err)
 	}
 
-	handler := router.NewRouter(unifiedStore)
+	handler := router.NewRouter(unifiedStore, sessionManager)
+
+	if cfg.Server.WriteTimeout <= cfg.Server.RequestTimeout {
+		log.Warn("SERVER_WRITE_TIMEOUT deveria ser maior que SERVER_REQUEST_TIMEOUT; respostas de timeout podem ser cortadas",
+			"writeTimeout", cfg.Server.WriteTimeout,
+			"requestTimeout", cfg.Server.RequestTimeout,
+		)
+	}
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:      handler,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  120 * time.Second,
+		ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
 	}
 
 	return &App{
-		config: cfg,
-		store:  unifiedStore,
-		server: server,
+		config:         cfg,
+		store:          unifiedStore,
+		sessionManager: sessionManager,
+		server:         server,
 	}, nil
 }
 
@@ -78,9 +125,18 @@ func (a *App) Run() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := a.server.Shutdown(ctx); err != nil {
-		appLogger.Error("Erro ao parar servidor", "error", err)
-		return err
+	// Primeiro drena as requisições em andamento, depois desconecta as sessões e só então para os
+	// webhooks, para que os eventos gerados no encerramento ainda sejam entregues
+	serverErr := a.server.Shutdown(ctx)
+	if serverErr != nil {
+		appLogger.Error("Erro ao parar servidor", "error", serverErr)
+	}
+
+	a.sessionManager.Shutdown()
+	a.sessionManager.GetWebhookManager().Stop()
+
+	if serverErr != nil {
+		return serverErr
 	}
 
 	logger.Info("Servidor parado")
@@ -88,6 +144,11 @@ func (a *App) Run() error {
 }
 
 func (a *App) Close() error {
+	if a.sessionManager != nil {
+		a.sessionManager.Shutdown()
+		a.sessionManager.GetWebhookManager().Stop()
+	}
+
 	if a.store != nil {
 		if err := a.store.Close(); err != nil {
 			logger.Error("Erro ao fechar store unificado", "error", err)
@@ -96,3 +157,25 @@ func (a *App) Close() error {
 
 	return nil
 }
+
+// configureGlobalWebhook aplica o webhook global configurado em WEBHOOK_GLOBAL_URL, se houver
+func configureGlobalWebhook(manager *webhook.Manager, cfg config.WebhookConfig) error {
+	if cfg.GlobalURL == "" {
+		return nil
+	}
+
+	events, err := webhook.ParseEventTypes(strings.Split(cfg.GlobalEvents, ","))
+	if err != nil {
+		return fmt.Errorf("WEBHOOK_GLOBAL_EVENTS inválido: %w", err)
+	}
+	if len(events) == 0 {
+		events = []webhook.EventType{webhook.EventAll}
+	}
+
+	return manager.SetGlobalConfig(&webhook.Config{
+		URL:     cfg.GlobalURL,
+		Events:  events,
+		Secret:  cfg.GlobalSecret,
+		Enabled: true,
+	})
+}
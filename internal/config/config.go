@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -12,14 +13,28 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	App      AppConfig
+	Media    MediaConfig
+	Session  SessionConfig
+	Webhook  WebhookConfig
+	Messages MessageStoreConfig
 }
 
+// ServerConfig configura o servidor HTTP. Os timeouts são em segundos: ReadTimeout e WriteTimeout valem
+// para a conexão inteira, enquanto RequestTimeout limita o processamento de cada requisição e deve ser
+// menor que WriteTimeout para que a resposta 408 ainda consiga ser escrita. Rotas de mídia (envio de
+// mídia/figurinha e download) usam MediaTimeout no lugar dos três.
 type ServerConfig struct {
-	Port         int
-	Host         string
-	ReadTimeout  int
-	WriteTimeout int
-	IdleTimeout  int
+	Port           int
+	Host           string
+	ReadTimeout    int
+	WriteTimeout   int
+	IdleTimeout    int
+	RequestTimeout int
+	MediaTimeout   int
+	// MaxBodySize limita em bytes o corpo das requisições de envio de mensagens; 0 desativa
+	MaxBodySize int64
+	// AdminToken é exigido no cabeçalho X-Admin-Token pelas rotas /admin; vazio desativa essas rotas
+	AdminToken string
 }
 
 type DatabaseConfig struct {
@@ -32,10 +47,75 @@ type DatabaseConfig struct {
 	DSN      string
 }
 
+type MediaConfig struct {
+	MaxURLDownloadSize int64
+	URLDownloadTimeout int
+	MaxImageSize       int64
+	MaxAudioSize       int64
+	MaxVideoSize       int64
+	MaxDocumentSize    int64
+	// TransferTimeout limita em segundos cada upload ou download de mídia nos servidores do WhatsApp
+	TransferTimeout int
+	// UploadTimeout limita em segundos cada upload de mídia; 0 usa TransferTimeout. Permite dar mais
+	// tempo a vídeos grandes sem alongar downloads nem o SendTimeout do envio da mensagem.
+	UploadTimeout int
+}
+
+// MaxSize retorna o tamanho máximo em bytes aceito para o tipo de mídia; figurinhas usam o limite de
+// imagem. Retorna 0 (sem limite) para tipos desconhecidos ou limites não configurados.
+func (m MediaConfig) MaxSize(mediaType string) int64 {
+	switch strings.ToLower(mediaType) {
+	case "image", "sticker":
+		return m.MaxImageSize
+	case "audio":
+		return m.MaxAudioSize
+	case "video":
+		return m.MaxVideoSize
+	case "document":
+		return m.MaxDocumentSize
+	default:
+		return 0
+	}
+}
+
+type SessionConfig struct {
+	MaxConcurrentPairings int
+	QRSweepInterval       int
+	SendRatePerMinute     int
+	// SendTimeout limita em segundos a espera pela confirmação de cada mensagem enviada
+	SendTimeout int
+}
+
+// MessageStoreConfig controla o armazenamento das mensagens recebidas. Com Enabled, cada sessão ainda
+// precisa optar pelo armazenamento. RetentionDays <= 0 mantém as mensagens indefinidamente;
+// PruneInterval é o intervalo em segundos da remoção das mensagens fora da retenção.
+type MessageStoreConfig struct {
+	Enabled       bool
+	RetentionDays int
+	PruneInterval int
+}
+
+type WebhookConfig struct {
+	Workers          int
+	QueueSize        int
+	EnqueueTimeoutMs int
+	// GlobalURL recebe os eventos de todas as sessões, além dos webhooks de cada sessão; vazio desativa.
+	// GlobalEvents é a lista de eventos ou grupos separados por vírgula.
+	GlobalURL    string
+	GlobalEvents string
+	GlobalSecret string
+}
+
 type AppConfig struct {
 	Environment string
 	LogLevel    string
-	Debug       bool
+	// LogFormat é console (legível) ou json; LogOutput é stdout, stderr ou file (usando LogFile)
+	LogFormat string
+	LogOutput string
+	LogFile   string
+	// WALogLevel é o nível dos logs do whatsmeow; vazio usa LogLevel
+	WALogLevel string
+	Debug      bool
 }
 
 func Load() (*Config, error) {
@@ -45,11 +125,15 @@ func Load() (*Config, error) {
 
 	config := &Config{
 		Server: ServerConfig{
-			Port:         getEnvInt("SERVER_PORT", 8080),
-			Host:         getEnv("SERVER_HOST", "localhost"),
-			ReadTimeout:  getEnvInt("SERVER_READ_TIMEOUT", 30),
-			WriteTimeout: getEnvInt("SERVER_WRITE_TIMEOUT", 30),
-			IdleTimeout:  getEnvInt("SERVER_IDLE_TIMEOUT", 60),
+			Port:           getEnvInt("SERVER_PORT", 8080),
+			Host:           getEnv("SERVER_HOST", "localhost"),
+			ReadTimeout:    getEnvInt("SERVER_READ_TIMEOUT", 30),
+			WriteTimeout:   getEnvInt("SERVER_WRITE_TIMEOUT", 75),
+			IdleTimeout:    getEnvInt("SERVER_IDLE_TIMEOUT", 120),
+			RequestTimeout: getEnvInt("SERVER_REQUEST_TIMEOUT", 60),
+			MediaTimeout:   getEnvInt("SERVER_MEDIA_TIMEOUT", 300),
+			MaxBodySize:    int64(getEnvInt("SERVER_MAX_BODY_SIZE_MB", 150)) * 1024 * 1024,
+			AdminToken:     getEnv("ADMIN_TOKEN", ""),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -62,8 +146,41 @@ func Load() (*Config, error) {
 		App: AppConfig{
 			Environment: getEnv("APP_ENV", "development"),
 			LogLevel:    getEnv("LOG_LEVEL", "info"),
+			LogFormat:   getEnv("LOG_FORMAT", "console"),
+			LogOutput:   getEnv("LOG_OUTPUT", "stdout"),
+			LogFile:     getEnv("LOG_FILE", ""),
+			WALogLevel:  getEnv("WA_LOG_LEVEL", getEnv("WA_DEBUG", "")),
 			Debug:       getEnvBool("DEBUG", false),
 		},
+		Media: MediaConfig{
+			MaxURLDownloadSize: int64(getEnvInt("MEDIA_URL_MAX_SIZE_MB", 64)) * 1024 * 1024,
+			URLDownloadTimeout: getEnvInt("MEDIA_URL_TIMEOUT", 60),
+			MaxImageSize:       int64(getEnvInt("MEDIA_MAX_IMAGE_SIZE_MB", 16)) * 1024 * 1024,
+			MaxAudioSize:       int64(getEnvInt("MEDIA_MAX_AUDIO_SIZE_MB", 16)) * 1024 * 1024,
+			MaxVideoSize:       int64(getEnvInt("MEDIA_MAX_VIDEO_SIZE_MB", 16)) * 1024 * 1024,
+			MaxDocumentSize:    int64(getEnvInt("MEDIA_MAX_DOCUMENT_SIZE_MB", 100)) * 1024 * 1024,
+			TransferTimeout:    getEnvInt("MEDIA_TRANSFER_TIMEOUT", 240),
+			UploadTimeout:      getEnvInt("MEDIA_UPLOAD_TIMEOUT", 0),
+		},
+		Session: SessionConfig{
+			MaxConcurrentPairings: getEnvInt("SESSION_MAX_CONCURRENT_PAIRINGS", 10),
+			QRSweepInterval:       getEnvInt("SESSION_QR_SWEEP_INTERVAL", 60),
+			SendRatePerMinute:     getEnvInt("SESSION_SEND_RATE_PER_MINUTE", 30),
+			SendTimeout:           getEnvInt("SESSION_SEND_TIMEOUT", 30),
+		},
+		Webhook: WebhookConfig{
+			Workers:          getEnvInt("WEBHOOK_WORKERS", 10),
+			QueueSize:        getEnvInt("WEBHOOK_QUEUE_SIZE", 1000),
+			EnqueueTimeoutMs: getEnvInt("WEBHOOK_ENQUEUE_TIMEOUT_MS", 500),
+			GlobalURL:        getEnv("WEBHOOK_GLOBAL_URL", ""),
+			GlobalEvents:     getEnv("WEBHOOK_GLOBAL_EVENTS", "All"),
+			GlobalSecret:     getEnv("WEBHOOK_GLOBAL_SECRET", ""),
+		},
+		Messages: MessageStoreConfig{
+			Enabled:       getEnvBool("MESSAGE_STORE_ENABLED", false),
+			RetentionDays: getEnvInt("MESSAGE_STORE_RETENTION_DAYS", 30),
+			PruneInterval: getEnvInt("MESSAGE_STORE_PRUNE_INTERVAL", 3600),
+		},
 	}
 
 	config.Database.DSN = fmt.Sprintf(
@@ -34,12 +34,11 @@ type ZLogger struct {
 	logger zerolog.Logger
 }
 
+// New cria um logger com nível, formato e saída próprios. O nível vale apenas para este logger e os
+// derivados dele (With/WithComponent), sem alterar outros loggers.
 func New(config Config) Logger {
-	level := parseLevel(config.Level)
-	zerolog.SetGlobalLevel(level)
-
 	output := createOutput(config)
-	logger := createLogger(config, output)
+	logger := createLogger(config, output).Level(parseLevel(config.Level))
 
 	if config.Component != "" {
 		logger = logger.With().Str("component", config.Component).Logger()
@@ -56,13 +55,10 @@ func NewDefault() Logger {
 	})
 }
 
+// NewForComponent deriva um logger do logger global, herdando o nível, o formato e a saída
+// configurados em Init
 func NewForComponent(component string) Logger {
-	return New(Config{
-		Level:     "info",
-		Format:    "console",
-		Output:    "stdout",
-		Component: component,
-	})
+	return Get().WithComponent(component)
 }
 
 func (l *ZLogger) Debug(msg string, fields ...any) {
@@ -165,42 +161,77 @@ func parseLevel(level string) zerolog.Level {
 	}
 }
 
+// WAAdapter expõe um Logger como logger do whatsmeow. Mensagens abaixo de level são descartadas antes
+// da formatação, o que evita o custo do Sprintf nos logs de debug do whatsmeow, que são muito frequentes.
 type WAAdapter struct {
 	logger Logger
+	level  zerolog.Level
 }
 
 func (w *WAAdapter) Debugf(msg string, args ...any) {
+	if w.level > zerolog.DebugLevel {
+		return
+	}
 	w.logger.Debug(fmt.Sprintf(msg, args...))
 }
 
 func (w *WAAdapter) Infof(msg string, args ...any) {
+	if w.level > zerolog.InfoLevel {
+		return
+	}
 	w.logger.Info(fmt.Sprintf(msg, args...))
 }
 
 func (w *WAAdapter) Warnf(msg string, args ...any) {
+	if w.level > zerolog.WarnLevel {
+		return
+	}
 	w.logger.Warn(fmt.Sprintf(msg, args...))
 }
 
 func (w *WAAdapter) Errorf(msg string, args ...any) {
+	if w.level > zerolog.ErrorLevel {
+		return
+	}
 	w.logger.Error(fmt.Sprintf(msg, args...))
 }
 
 func (w *WAAdapter) Sub(module string) waLog.Logger {
 	return &WAAdapter{
 		logger: w.logger.WithComponent(module),
+		level:  w.level,
 	}
 }
 
+// ForWhatsApp cria o logger do whatsmeow no mesmo nível do logger global
 func ForWhatsApp(component string) waLog.Logger {
-	return &WAAdapter{logger: NewForComponent(component)}
+	return NewWhatsAppLogger(component, "")
 }
 
+// NewWhatsAppLogger cria o logger do whatsmeow com nível próprio, independente do nível da aplicação
+// (ex: aplicação em info e whatsmeow em warn). Com level vazio o nível do logger global é usado.
 func NewWhatsAppLogger(component string, level string) waLog.Logger {
-	return ForWhatsApp(component)
+	base := Get()
+	zl, ok := base.(*ZLogger)
+	if !ok {
+		return &WAAdapter{logger: base.WithComponent(component), level: zerolog.InfoLevel}
+	}
+
+	waLevel := zl.logger.GetLevel()
+	if level != "" {
+		waLevel = parseLevel(level)
+	}
+
+	return &WAAdapter{
+		logger: &ZLogger{logger: zl.logger.Level(waLevel).With().Str("component", component).Logger()},
+		level:  waLevel,
+	}
 }
 
 var globalLogger Logger
 
+// Init configura o logger global, do qual derivam os loggers de componentes. Deve ser chamado na
+// inicialização, antes da criação dos componentes.
 func Init(config Config) {
 	globalLogger = New(config)
 }
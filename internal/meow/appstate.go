@@ -0,0 +1,67 @@
+package meow
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waSyncAction"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// chatActionVersion é a versão das mutações deleteChat e clearChat usada pelos clientes oficiais
+const chatActionVersion = 6
+
+// BuildDeleteChat monta o patch de app state que apaga o chat (e suas mídias) em todos os dispositivos.
+// O whatsmeow não oferece esse builder; o formato segue o do WhatsApp Web. lastMessageTimestamp zero usa
+// o horário atual e lastMessageKey é opcional.
+func BuildDeleteChat(target types.JID, lastMessageTimestamp time.Time, lastMessageKey *waCommon.MessageKey) appstate.PatchInfo {
+	return appstate.PatchInfo{
+		Type: appstate.WAPatchRegularHigh,
+		Mutations: []appstate.MutationInfo{{
+			Index:   []string{appstate.IndexDeleteChat, target.String(), "1"},
+			Version: chatActionVersion,
+			Value: &waSyncAction.SyncActionValue{
+				DeleteChatAction: &waSyncAction.DeleteChatAction{
+					MessageRange: chatMessageRange(lastMessageTimestamp, lastMessageKey),
+				},
+			},
+		}},
+	}
+}
+
+// BuildClearChat monta o patch de app state que limpa as mensagens do chat em todos os dispositivos,
+// mantendo o chat na lista e as mensagens favoritadas
+func BuildClearChat(target types.JID, lastMessageTimestamp time.Time, lastMessageKey *waCommon.MessageKey) appstate.PatchInfo {
+	return appstate.PatchInfo{
+		Type: appstate.WAPatchRegularHigh,
+		Mutations: []appstate.MutationInfo{{
+			Index:   []string{appstate.IndexClearChat, target.String(), "1", "0"},
+			Version: chatActionVersion,
+			Value: &waSyncAction.SyncActionValue{
+				ClearChatAction: &waSyncAction.ClearChatAction{
+					MessageRange: chatMessageRange(lastMessageTimestamp, lastMessageKey),
+				},
+			},
+		}},
+	}
+}
+
+func chatMessageRange(lastMessageTimestamp time.Time, lastMessageKey *waCommon.MessageKey) *waSyncAction.SyncActionMessageRange {
+	if lastMessageTimestamp.IsZero() {
+		lastMessageTimestamp = time.Now()
+	}
+
+	messageRange := &waSyncAction.SyncActionMessageRange{
+		LastMessageTimestamp: proto.Int64(lastMessageTimestamp.Unix()),
+	}
+	if lastMessageKey != nil {
+		messageRange.Messages = []*waSyncAction.SyncActionMessage{{
+			Key:       lastMessageKey,
+			Timestamp: proto.Int64(lastMessageTimestamp.Unix()),
+		}}
+	}
+
+	return messageRange
+}
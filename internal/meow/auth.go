@@ -2,29 +2,60 @@ package meow
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"zpigo/internal/logger"
 	"zpigo/internal/store"
 	"zpigo/internal/store/models"
+	"zpigo/internal/store/repositories"
 )
 
+// APIKeyPrefix identifica as chaves geradas pela API
+const APIKeyPrefix = "zpg_"
+
+// ErrAPIKeyWrongSession indica uma chave válida, porém restrita a outra sessão
+var ErrAPIKeyWrongSession = errors.New("API key not authorized for this session")
+
+// apiKeyDisplayLength é quantos caracteres da chave ficam visíveis para identificá-la na listagem
+const apiKeyDisplayLength = 12
+
+// apiKeyTouchInterval é o intervalo mínimo entre duas gravações do último uso de uma mesma chave,
+// para que a validação não escreva no banco a cada requisição
+const apiKeyTouchInterval = time.Minute
+
 type AuthManager struct {
 	db           *sql.DB
 	sessionRepo  store.SessionRepositoryInterface
+	apiKeyRepo   store.APIKeyRepositoryInterface
 	cacheManager *CacheManager
 	logger       logger.Logger
+
+	touchMu sync.Mutex
+	touched map[string]time.Time
 }
 
 func NewAuthManager(db *sql.DB, sessionRepo store.SessionRepositoryInterface) *AuthManager {
+	am := NewAuthManagerWithRepositories(sessionRepo, repositories.NewAPIKeyRepository(db))
+	am.db = db
+	return am
+}
+
+// NewAuthManagerWithRepositories cria o AuthManager sobre repositórios já construídos
+func NewAuthManagerWithRepositories(sessionRepo store.SessionRepositoryInterface, apiKeyRepo store.APIKeyRepositoryInterface) *AuthManager {
 	return &AuthManager{
-		db:           db,
 		sessionRepo:  sessionRepo,
+		apiKeyRepo:   apiKeyRepo,
 		cacheManager: GetGlobalCache(),
 		logger:       NewLoggerForComponent("AuthManager"),
+		touched:      make(map[string]time.Time),
 	}
 }
 
@@ -32,8 +63,11 @@ type AuthContext struct {
 	APIKey    string
 	SessionID string
 	Session   *models.Session
+	Key       *models.APIKey
 }
 
+// ValidateAPIKey confere a chave contra as chaves cadastradas. Sem sessionID apenas a chave é
+// validada; com sessionID a chave precisa ter acesso à sessão e a sessão precisa existir.
 func (am *AuthManager) ValidateAPIKey(ctx context.Context, apiKey, sessionID string) (*AuthContext, error) {
 	am.logger.Debug("Validando API Key", "sessionID", sessionID)
 
@@ -42,9 +76,29 @@ func (am *AuthManager) ValidateAPIKey(ctx context.Context, apiKey, sessionID str
 		return nil, errors.New("API key is required")
 	}
 
+	key, err := am.apiKeyRepo.GetByHash(ctx, HashAPIKey(apiKey))
+	if err != nil {
+		am.logger.Warn("API Key não cadastrada", "error", err)
+		return nil, errors.New("invalid API key")
+	}
+
+	if key.IsRevoked() {
+		am.logger.Warn("API Key revogada", "keyID", key.ID)
+		return nil, errors.New("API key revoked")
+	}
+
+	am.touchLastUsed(ctx, key.ID)
+
 	if sessionID == "" {
-		am.logger.Warn("Session ID não fornecido")
-		return nil, errors.New("session ID is required")
+		return &AuthContext{
+			APIKey: apiKey,
+			Key:    key,
+		}, nil
+	}
+
+	if !key.AllowsSession(sessionID) {
+		am.logger.Warn("API Key sem acesso à sessão", "keyID", key.ID, "sessionID", sessionID)
+		return nil, ErrAPIKeyWrongSession
 	}
 
 	cacheKey := BuildCacheKey(apiKey, sessionID)
@@ -55,6 +109,7 @@ func (am *AuthManager) ValidateAPIKey(ctx context.Context, apiKey, sessionID str
 			APIKey:    apiKey,
 			SessionID: sessionID,
 			Session:   sessionInfo.ToModelSession(),
+			Key:       key,
 		}, nil
 	}
 
@@ -73,9 +128,70 @@ func (am *AuthManager) ValidateAPIKey(ctx context.Context, apiKey, sessionID str
 		APIKey:    apiKey,
 		SessionID: sessionID,
 		Session:   session,
+		Key:       key,
 	}, nil
 }
 
+// touchLastUsed registra o uso da chave no banco no máximo uma vez por apiKeyTouchInterval
+func (am *AuthManager) touchLastUsed(ctx context.Context, keyID string) {
+	now := time.Now()
+
+	am.touchMu.Lock()
+	if last, ok := am.touched[keyID]; ok && now.Sub(last) < apiKeyTouchInterval {
+		am.touchMu.Unlock()
+		return
+	}
+	am.touched[keyID] = now
+	am.touchMu.Unlock()
+
+	if err := am.apiKeyRepo.TouchLastUsed(ctx, keyID); err != nil {
+		am.logger.Warn("Erro ao registrar uso da API Key", "keyID", keyID, "error", err)
+	}
+}
+
+// HashAPIKey retorna o hash SHA-256 usado para armazenar e buscar a chave
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey gera uma nova chave, opcionalmente restrita a uma sessão. A chave em texto puro
+// é retornada apenas aqui; o banco guarda somente o hash.
+func (am *AuthManager) CreateAPIKey(ctx context.Context, label, sessionID string) (string, *models.APIKey, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+	apiKey := APIKeyPrefix + hex.EncodeToString(secret)
+
+	key := &models.APIKey{
+		KeyHash:   HashAPIKey(apiKey),
+		Prefix:    apiKey[:apiKeyDisplayLength],
+		Label:     label,
+		SessionID: sessionID,
+	}
+
+	if err := am.apiKeyRepo.Create(ctx, key); err != nil {
+		return "", nil, err
+	}
+
+	am.logger.Info("API Key criada", "keyID", key.ID, "label", label, "sessionID", sessionID)
+	return apiKey, key, nil
+}
+
+func (am *AuthManager) ListAPIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	return am.apiKeyRepo.List(ctx)
+}
+
+func (am *AuthManager) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := am.apiKeyRepo.Revoke(ctx, id); err != nil {
+		return err
+	}
+
+	am.logger.Info("API Key revogada", "keyID", id)
+	return nil
+}
+
 func (am *AuthManager) ExtractAPIKeyFromRequest(r *http.Request) string {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader != "" {
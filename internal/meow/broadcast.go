@@ -0,0 +1,291 @@
+package meow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+
+	"zpigo/internal/store/models"
+)
+
+// ErrBroadcastFinished indica que o broadcast já terminou e não pode ser cancelado
+var ErrBroadcastFinished = errors.New("broadcast já finalizado")
+
+// ErrBroadcastNotFound indica que o broadcast não existe ou pertence a outra sessão
+var ErrBroadcastNotFound = errors.New("broadcast não encontrado")
+
+// broadcastRunner é um job em execução neste processo; done é fechado quando o runner termina
+type broadcastRunner struct {
+	sessionID string
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// StartBroadcast processa o job em segundo plano. Jobs já em execução neste processo são ignorados.
+func (sm *SessionManager) StartBroadcast(job *models.BroadcastJob) {
+	sm.broadcastMu.Lock()
+	if _, running := sm.broadcasts[job.ID]; running {
+		sm.broadcastMu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := &broadcastRunner{sessionID: job.SessionID, cancel: cancel, done: make(chan struct{})}
+	sm.broadcasts[job.ID] = runner
+	sm.broadcastMu.Unlock()
+
+	go func() {
+		defer func() {
+			sm.broadcastMu.Lock()
+			if sm.broadcasts[job.ID] == runner {
+				delete(sm.broadcasts, job.ID)
+			}
+			sm.broadcastMu.Unlock()
+			cancel()
+			close(runner.done)
+		}()
+
+		sm.runBroadcast(ctx, job)
+	}()
+}
+
+// GetBroadcast retorna o job da sessão; jobs de outras sessões resultam em ErrBroadcastNotFound
+func (sm *SessionManager) GetBroadcast(ctx context.Context, sessionID, jobID string) (*models.BroadcastJob, error) {
+	job, err := sm.broadcastRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBroadcastNotFound, err)
+	}
+
+	if job.SessionID != sessionID {
+		return nil, ErrBroadcastNotFound
+	}
+
+	return job, nil
+}
+
+// CancelBroadcast interrompe o job da sessão e o marca como cancelado. Destinatários ainda não
+// processados permanecem pendentes.
+func (sm *SessionManager) CancelBroadcast(ctx context.Context, sessionID, jobID string) (*models.BroadcastJob, error) {
+	job, err := sm.GetBroadcast(ctx, sessionID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status.IsFinal() {
+		return job, ErrBroadcastFinished
+	}
+
+	sm.broadcastMu.Lock()
+	if runner, running := sm.broadcasts[jobID]; running {
+		runner.cancel()
+	}
+	sm.broadcastMu.Unlock()
+
+	if err := sm.broadcastRepo.UpdateStatus(ctx, jobID, models.BroadcastCancelled, ""); err != nil {
+		return nil, err
+	}
+
+	sm.logger.Info("Broadcast cancelado", "jobID", jobID, "sessionID", job.SessionID)
+
+	return sm.broadcastRepo.GetByID(ctx, jobID)
+}
+
+// ResumeBroadcasts retoma os jobs pendentes ou interrompidos por um reinício da aplicação
+func (sm *SessionManager) ResumeBroadcasts(ctx context.Context) error {
+	jobs, err := sm.broadcastRepo.ListUnfinished(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		sm.logger.Info("Retomando broadcast", "jobID", job.ID, "sessionID", job.SessionID, "pending", job.Pending())
+		sm.StartBroadcast(job)
+	}
+
+	return nil
+}
+
+// stopBroadcasts interrompe os jobs em execução sem alterar o status, para que sejam
+// retomados na próxima inicialização
+func (sm *SessionManager) stopBroadcasts() {
+	sm.broadcastMu.Lock()
+	defer sm.broadcastMu.Unlock()
+
+	for jobID, runner := range sm.broadcasts {
+		runner.cancel()
+		delete(sm.broadcasts, jobID)
+	}
+}
+
+// stopSessionBroadcasts interrompe os jobs em execução da sessão e aguarda o término de cada um,
+// para que nenhum runner grave progresso depois que os dados da sessão forem removidos.
+// Retorna quantos jobs foram interrompidos.
+func (sm *SessionManager) stopSessionBroadcasts(sessionID string) int {
+	var runners []*broadcastRunner
+
+	sm.broadcastMu.Lock()
+	for _, runner := range sm.broadcasts {
+		if runner.sessionID == sessionID {
+			runner.cancel()
+			runners = append(runners, runner)
+		}
+	}
+	sm.broadcastMu.Unlock()
+
+	for _, runner := range runners {
+		<-runner.done
+	}
+
+	return len(runners)
+}
+
+func (sm *SessionManager) runBroadcast(ctx context.Context, job *models.BroadcastJob) {
+	if err := sm.broadcastRepo.UpdateStatus(ctx, job.ID, models.BroadcastRunning, ""); err != nil {
+		sm.logger.Warn("Broadcast não pode ser iniciado", "jobID", job.ID, "error", err)
+		return
+	}
+
+	sm.logger.Info("Broadcast iniciado", "jobID", job.ID, "sessionID", job.SessionID, "total", job.Total)
+
+	for {
+		recipients, err := sm.broadcastRepo.NextPendingRecipients(ctx, job.ID, DefaultBroadcastBatchSize)
+		if err != nil {
+			sm.failBroadcast(ctx, job, err)
+			return
+		}
+
+		if len(recipients) == 0 {
+			if err := sm.broadcastRepo.UpdateStatus(ctx, job.ID, models.BroadcastCompleted, ""); err != nil {
+				sm.logger.Warn("Erro ao concluir broadcast", "jobID", job.ID, "error", err)
+				return
+			}
+			sm.logger.Info("Broadcast concluído", "jobID", job.ID, "sessionID", job.SessionID)
+			return
+		}
+
+		for _, recipient := range recipients {
+			client, err := sm.waitForBroadcastClient(ctx, job.SessionID)
+			if err != nil {
+				sm.failBroadcast(ctx, job, err)
+				return
+			}
+
+			if err := sm.waitForSendSlot(ctx, job.SessionID); err != nil {
+				return
+			}
+
+			if err := sm.sendBroadcastMessage(ctx, client, job.SessionID, recipient); err != nil && ctx.Err() != nil {
+				// Envio interrompido pelo cancelamento, sem confirmação: o destinatário continua pendente
+				return
+			}
+
+			// O resultado do envio é gravado antes de checar o cancelamento, para que uma mensagem
+			// entregue não seja reenviada quando o job for retomado
+			if err := sm.broadcastRepo.MarkRecipient(context.Background(), recipient); err != nil {
+				sm.failBroadcast(ctx, job, err)
+				return
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// failBroadcast marca o job como falho, exceto quando o contexto foi cancelado
+func (sm *SessionManager) failBroadcast(ctx context.Context, job *models.BroadcastJob, cause error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	sm.logger.Error("Broadcast interrompido por erro", "jobID", job.ID, "sessionID", job.SessionID, "error", cause)
+
+	if err := sm.broadcastRepo.UpdateStatus(context.Background(), job.ID, models.BroadcastFailed, cause.Error()); err != nil {
+		sm.logger.Warn("Erro ao marcar broadcast como falho", "jobID", job.ID, "error", err)
+	}
+}
+
+// waitForBroadcastClient aguarda até que a sessão tenha um cliente conectado e autenticado.
+// Retorna erro apenas quando a sessão deixa de existir ou o contexto é cancelado.
+func (sm *SessionManager) waitForBroadcastClient(ctx context.Context, sessionID string) (*whatsmeow.Client, error) {
+	for {
+		if client, exists := sm.GetSession(sessionID); exists && client.IsConnected() && client.IsLoggedIn() {
+			return client, nil
+		}
+
+		if _, err := sm.sessionRepo.GetByID(ctx, sessionID); err != nil {
+			return nil, fmt.Errorf("sessão indisponível: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(DefaultBroadcastClientTimeout):
+		}
+	}
+}
+
+// waitForSendSlot respeita o limite de envio da sessão, aguardando a próxima vaga
+func (sm *SessionManager) waitForSendSlot(ctx context.Context, sessionID string) error {
+	for {
+		allowed, retryAfter := sm.AllowSend(sessionID)
+		if allowed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryAfter):
+		}
+	}
+}
+
+// sendBroadcastMessage envia o texto ao destinatário, preenche o resultado no próprio registro e
+// retorna o erro do envio. Cada envio é limitado por sendTimeout, para que um envio travado não
+// prenda o job inteiro.
+func (sm *SessionManager) sendBroadcastMessage(ctx context.Context, client *whatsmeow.Client, sessionID string, recipient *models.BroadcastRecipient) error {
+	jid, err := types.ParseJID(recipient.JID)
+	if err == nil {
+		msg := &waE2E.Message{
+			ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+				Text: proto.String(recipient.Text),
+			},
+		}
+
+		messageID := client.GenerateMessageID()
+		sendCtx, cancel := sm.sendContext(ctx)
+		_, err = client.SendMessage(sendCtx, jid, msg, whatsmeow.SendRequestExtra{ID: messageID})
+		cancel()
+		if err == nil {
+			recipient.MessageID = messageID
+		}
+	}
+
+	now := time.Now()
+	recipient.SentAt = &now
+
+	zc, hasClient := sm.GetZPigoClient(sessionID)
+
+	if err != nil {
+		recipient.Status = models.RecipientFailed
+		recipient.Error = err.Error()
+		if hasClient {
+			zc.RecordError(fmt.Sprintf("erro ao enviar mensagem: %v", err))
+		}
+		sm.logger.Warn("Falha ao enviar mensagem do broadcast", "jobID", recipient.JobID, "phone", recipient.Phone, "error", err)
+		return err
+	}
+
+	recipient.Status = models.RecipientSent
+	if hasClient {
+		zc.RecordMessageSent()
+	}
+	return nil
+}
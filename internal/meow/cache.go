@@ -1,6 +1,7 @@
 package meow
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -104,10 +105,37 @@ func (cm *CacheManager) UpdateSessionInfo(sessionID, key, value string) bool {
 	return false
 }
 
+// UpdateSessionInfoByID atualiza o campo em todas as entradas da sessão, independente da API key
+// usada na chave do cache, e retorna quantas entradas foram alteradas
+func (cm *CacheManager) UpdateSessionInfoByID(sessionID, key, value string) int {
+	updated := 0
+	for cacheKey, item := range cm.cache.Items() {
+		if sessionInfo, ok := item.Object.(*SessionInfo); ok && sessionInfo.ID == sessionID {
+			sessionInfo.Set(key, value)
+			cm.SetSessionInfo(cacheKey, sessionInfo)
+			updated++
+		}
+	}
+	return updated
+}
+
 func (cm *CacheManager) DeleteSessionInfo(sessionID string) {
 	cm.cache.Delete(sessionID)
 }
 
+// DeleteSessionInfoByID remove todas as entradas da sessão, independente da API key usada na chave do
+// cache, e retorna quantas entradas foram removidas
+func (cm *CacheManager) DeleteSessionInfoByID(sessionID string) int {
+	deleted := 0
+	for cacheKey, item := range cm.cache.Items() {
+		if sessionInfo, ok := item.Object.(*SessionInfo); ok && sessionInfo.ID == sessionID {
+			cm.cache.Delete(cacheKey)
+			deleted++
+		}
+	}
+	return deleted
+}
+
 func (cm *CacheManager) ClearCache() {
 	cm.cache.Flush()
 }
@@ -116,6 +144,37 @@ func (cm *CacheManager) GetCacheStats() (int, int) {
 	return cm.cache.ItemCount(), len(cm.cache.Items())
 }
 
+type CacheStats struct {
+	TotalItems       int
+	LiveItems        int
+	SessionInfoItems int
+	NonExpiringItems int
+	ItemsByType      map[string]int
+}
+
+// GetDetailedStats detalha os itens do cache por tipo e por política de expiração
+func (cm *CacheManager) GetDetailedStats() CacheStats {
+	items := cm.cache.Items()
+
+	stats := CacheStats{
+		TotalItems:  cm.cache.ItemCount(),
+		LiveItems:   len(items),
+		ItemsByType: make(map[string]int),
+	}
+
+	for _, item := range items {
+		if _, ok := item.Object.(*SessionInfo); ok {
+			stats.SessionInfoItems++
+		}
+		if item.Expiration == 0 {
+			stats.NonExpiringItems++
+		}
+		stats.ItemsByType[fmt.Sprintf("%T", item.Object)]++
+	}
+
+	return stats
+}
+
 func (cm *CacheManager) SetWithExpiration(key string, value interface{}, duration time.Duration) {
 	cm.cache.Set(key, value, duration)
 }
@@ -0,0 +1,117 @@
+package meow
+
+import (
+	"context"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"zpigo/internal/logger"
+	"zpigo/internal/store/models"
+)
+
+// recordChatState persiste nos chats da sessão o que a sincronização de histórico e o app state
+// informam. Esses eventos não são entregues ao webhook.
+func (zc *ZPigoClient) recordChatState(rawEvt interface{}) {
+	if zc.Chats == nil {
+		return
+	}
+
+	ctx := context.Background()
+	var jid types.JID
+	var err error
+
+	switch evt := rawEvt.(type) {
+	case *events.HistorySync:
+		zc.recordHistorySyncChats(ctx, evt)
+		return
+	case *events.Archive:
+		jid = evt.JID
+		err = zc.Chats.SetArchived(ctx, zc.SessionID, jid.String(), evt.Action.GetArchived())
+	case *events.Pin:
+		jid = evt.JID
+		err = zc.Chats.SetPinned(ctx, zc.SessionID, jid.String(), evt.Action.GetPinned())
+	case *events.Mute:
+		jid = evt.JID
+		var mutedUntil int64
+		if evt.Action.GetMuted() {
+			mutedUntil = models.ChatMutedForever
+			if end := evt.Action.GetMuteEndTimestamp(); end >= 0 {
+				mutedUntil = time.UnixMilli(end).Unix()
+			}
+		}
+		err = zc.Chats.SetMutedUntil(ctx, zc.SessionID, jid.String(), mutedUntil)
+	case *events.MarkChatAsRead:
+		jid = evt.JID
+		err = zc.Chats.SetRead(ctx, zc.SessionID, jid.String(), evt.Action.GetRead())
+	case *events.DeleteChat:
+		jid = evt.JID
+		err = zc.Chats.Delete(ctx, zc.SessionID, jid.String())
+	default:
+		return
+	}
+
+	if err != nil {
+		logger.WithComponent("EventHandler").Error("Erro ao atualizar estado do chat", "sessionID", zc.SessionID, "jid", jid.String(), "error", err)
+	}
+}
+
+// recordHistorySyncChats grava as conversas de um lote da sincronização de histórico. Pinned e
+// MuteEndTime chegam como timestamps Unix em segundos.
+func (zc *ZPigoClient) recordHistorySyncChats(ctx context.Context, evt *events.HistorySync) {
+	conversations := evt.Data.GetConversations()
+	chats := make([]*models.Chat, 0, len(conversations))
+	for _, conv := range conversations {
+		jid, err := types.ParseJID(conv.GetID())
+		if err != nil || jid == types.StatusBroadcastJID {
+			continue
+		}
+
+		chat := &models.Chat{
+			SessionID:    zc.SessionID,
+			JID:          jid.String(),
+			Name:         conv.GetName(),
+			UnreadCount:  int(conv.GetUnreadCount()),
+			MarkedUnread: conv.GetMarkedAsUnread(),
+			Archived:     conv.GetArchived(),
+			Pinned:       conv.GetPinned() > 0,
+			MutedUntil:   int64(conv.GetMuteEndTime()),
+		}
+		if chat.Name == "" {
+			chat.Name = conv.GetDisplayName()
+		}
+		lastMessage := conv.GetLastMsgTimestamp()
+		if lastMessage == 0 {
+			lastMessage = conv.GetConversationTimestamp()
+		}
+		if lastMessage > 0 {
+			at := time.Unix(int64(lastMessage), 0)
+			chat.LastMessageAt = &at
+		}
+		chats = append(chats, chat)
+	}
+
+	if err := zc.Chats.UpsertMany(ctx, chats); err != nil {
+		logger.WithComponent("EventHandler").Error("Erro ao gravar chats da sincronização de histórico", "sessionID", zc.SessionID, "chats", len(chats), "error", err)
+		return
+	}
+
+	logger.WithComponent("EventHandler").Info("Chats da sincronização de histórico gravados", "sessionID", zc.SessionID, "syncType", evt.Data.GetSyncType().String(), "chats", len(chats))
+}
+
+// recordChatMessage atualiza a última mensagem e os não lidos do chat da mensagem. Edições, revogações
+// e reações não contam como nova mensagem.
+func (zc *ZPigoClient) recordChatMessage(evt *events.Message) {
+	if zc.Chats == nil || evt.Info.Chat == types.StatusBroadcastJID {
+		return
+	}
+	if evt.Message.GetProtocolMessage() != nil || evt.Message.GetReactionMessage() != nil {
+		return
+	}
+
+	chatJID := evt.Info.Chat.ToNonAD().String()
+	if err := zc.Chats.RecordMessage(context.Background(), zc.SessionID, chatJID, evt.Info.Timestamp, !evt.Info.IsFromMe); err != nil {
+		logger.WithComponent("EventHandler").Error("Erro ao atualizar chat da mensagem", "sessionID", zc.SessionID, "jid", chatJID, "error", err)
+	}
+}
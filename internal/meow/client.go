@@ -1,12 +1,18 @@
 package meow
 
 import (
+	"context"
 	"database/sql"
 	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+
+	"zpigo/internal/store"
+	"zpigo/internal/webhook"
 )
 
 type ZPigoClient struct {
@@ -22,13 +28,63 @@ type ZPigoClient struct {
 
 	HTTPClient *resty.Client
 
-	IsActive    bool
-	ConnectedAt *time.Time
-	mu          sync.RWMutex
+	IsActive           bool
+	ConnectedAt        *time.Time
+	LastDisconnectedAt *time.Time
+	ReconnectCount     int
+	MessagesSent       int64
+	MessagesReceived   int64
+	LastError          string
+	LastErrorAt        *time.Time
+	Passive            bool
+	offlineSync        OfflineSync
+	mu                 sync.RWMutex
 
 	KillChannel chan bool
 
 	CacheManager *CacheManager
+
+	MessageEdits store.MessageEditRepositoryInterface
+	Receipts     store.MessageReceiptRepositoryInterface
+	Chats        store.ChatRepositoryInterface
+	Messages     store.MessageRepositoryInterface
+
+	WebhookManager *webhook.Manager
+	EventStream    *EventStream
+
+	trackedReceipts map[string]time.Time
+	aboutUpdates    map[types.JID]aboutUpdate
+	messageStatuses *MessageStatusTracker
+	storeMessages   bool
+}
+
+// OfflineSync resume a última sincronização dos eventos recebidos enquanto a sessão estava offline
+type OfflineSync struct {
+	InProgress     bool
+	Total          int
+	AppDataChanges int
+	Messages       int
+	Notifications  int
+	Receipts       int
+	Delivered      int
+	PreviewAt      *time.Time
+	CompletedAt    *time.Time
+}
+
+// receiptTrackingTTL define por quanto tempo uma mensagem permanece com recibos detalhados
+const receiptTrackingTTL = 24 * time.Hour
+
+const (
+	// aboutUpdateTTL define por quanto tempo a data de alteração de um recado fica disponível
+	aboutUpdateTTL = 7 * 24 * time.Hour
+	// aboutUpdatesMaxEntries limita a quantidade de recados acompanhados por sessão
+	aboutUpdatesMaxEntries = 10000
+)
+
+// aboutUpdate é a alteração de recado recebida em um evento UserAbout
+type aboutUpdate struct {
+	changedAt  time.Time
+	recordedAt time.Time
 }
 
 func NewZPigoClient(sessionID, apiKey string, waClient *whatsmeow.Client, db *sql.DB) *ZPigoClient {
@@ -42,6 +98,10 @@ func NewZPigoClient(sessionID, apiKey string, waClient *whatsmeow.Client, db *sq
 		Subscriptions: []string{},
 		KillChannel:   make(chan bool, 1),
 		CacheManager:  GetGlobalCache(),
+
+		trackedReceipts: make(map[string]time.Time),
+		aboutUpdates:    make(map[types.JID]aboutUpdate),
+		messageStatuses: NewMessageStatusTracker(),
 	}
 
 	if waClient != nil {
@@ -51,10 +111,10 @@ func NewZPigoClient(sessionID, apiKey string, waClient *whatsmeow.Client, db *sq
 	return client
 }
 
-func (zc *ZPigoClient) UpdateSubscriptions(subscriptions []string) {
+func (zc *ZPigoClient) UpdateSubscriptions(subscriptions []webhook.EventType) {
 	zc.mu.Lock()
 	defer zc.mu.Unlock()
-	zc.Subscriptions = subscriptions
+	zc.Subscriptions = webhook.EventTypeStrings(subscriptions)
 }
 
 func (zc *ZPigoClient) SetActive(active bool) {
@@ -69,6 +129,127 @@ func (zc *ZPigoClient) SetActive(active bool) {
 	}
 }
 
+// MarkDisconnected registra o instante da desconexão para que a próxima
+// conexão seja contabilizada como reconexão
+func (zc *ZPigoClient) MarkDisconnected() {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	now := time.Now()
+	zc.LastDisconnectedAt = &now
+}
+
+// MarkConnected incrementa o contador de reconexões quando a conexão ocorre
+// após uma desconexão anterior
+func (zc *ZPigoClient) MarkConnected() {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	if zc.LastDisconnectedAt != nil {
+		zc.ReconnectCount++
+	}
+}
+
+// RecordOfflineSyncPreview registra quantos eventos pendentes o servidor anunciou ao conectar
+func (zc *ZPigoClient) RecordOfflineSyncPreview(total, appDataChanges, messages, notifications, receipts int) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	now := time.Now()
+	zc.offlineSync = OfflineSync{
+		InProgress:     true,
+		Total:          total,
+		AppDataChanges: appDataChanges,
+		Messages:       messages,
+		Notifications:  notifications,
+		Receipts:       receipts,
+		PreviewAt:      &now,
+	}
+}
+
+// RecordOfflineSyncCompleted registra o fim da entrega dos eventos pendentes
+func (zc *ZPigoClient) RecordOfflineSyncCompleted(count int) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	now := time.Now()
+	zc.offlineSync.InProgress = false
+	zc.offlineSync.Delivered = count
+	zc.offlineSync.CompletedAt = &now
+}
+
+// GetOfflineSync retorna uma cópia do estado da última sincronização offline
+func (zc *ZPigoClient) GetOfflineSync() OfflineSync {
+	zc.mu.RLock()
+	defer zc.mu.RUnlock()
+	return zc.offlineSync
+}
+
+// SetPassiveMode registra se o dispositivo foi marcado como passivo no servidor
+func (zc *ZPigoClient) SetPassiveMode(passive bool) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	zc.Passive = passive
+}
+
+func (zc *ZPigoClient) IsPassive() bool {
+	zc.mu.RLock()
+	defer zc.mu.RUnlock()
+	return zc.Passive
+}
+
+// GetUptime retorna o início da conexão atual, o tempo conectado e o total de reconexões
+func (zc *ZPigoClient) GetUptime() (*time.Time, time.Duration, int) {
+	zc.mu.RLock()
+	defer zc.mu.RUnlock()
+
+	var uptime time.Duration
+	if zc.IsActive && zc.ConnectedAt != nil {
+		uptime = time.Since(*zc.ConnectedAt)
+	}
+
+	return zc.ConnectedAt, uptime, zc.ReconnectCount
+}
+
+func (zc *ZPigoClient) GetLastDisconnectedAt() *time.Time {
+	zc.mu.RLock()
+	defer zc.mu.RUnlock()
+	return zc.LastDisconnectedAt
+}
+
+// RecordMessageSent contabiliza uma mensagem enviada pela sessão
+func (zc *ZPigoClient) RecordMessageSent() {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	zc.MessagesSent++
+}
+
+// RecordMessageReceived contabiliza uma mensagem recebida pela sessão
+func (zc *ZPigoClient) RecordMessageReceived() {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	zc.MessagesReceived++
+}
+
+// RecordError guarda o erro mais recente da sessão
+func (zc *ZPigoClient) RecordError(message string) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	now := time.Now()
+	zc.LastError = message
+	zc.LastErrorAt = &now
+}
+
+// GetMessageCounters retorna o total de mensagens enviadas e recebidas desde o início do processo
+func (zc *ZPigoClient) GetMessageCounters() (int64, int64) {
+	zc.mu.RLock()
+	defer zc.mu.RUnlock()
+	return zc.MessagesSent, zc.MessagesReceived
+}
+
+// GetLastError retorna o erro mais recente da sessão e quando ocorreu
+func (zc *ZPigoClient) GetLastError() (string, *time.Time) {
+	zc.mu.RLock()
+	defer zc.mu.RUnlock()
+	return zc.LastError, zc.LastErrorAt
+}
+
 func (zc *ZPigoClient) IsClientActive() bool {
 	zc.mu.RLock()
 	defer zc.mu.RUnlock()
@@ -81,6 +262,118 @@ func (zc *ZPigoClient) GetSubscriptions() []string {
 	return append([]string{}, zc.Subscriptions...)
 }
 
+// SendMessage envia a mensagem pelo cliente da sessão. Com requestReceipts, a mensagem passa a ter
+// recibos detalhados: antes do envio ela é registrada para que cada recibo, inclusive os que o
+// WhatsApp entrega agrupados em grupos, seja guardado por participante no status da mensagem e saia
+// no webhook com detailed, chat e isGroup; o ack do servidor é gravado como status sent. Se o envio
+// falhar, o registro é desfeito.
+func (zc *ZPigoClient) SendMessage(ctx context.Context, to types.JID, msg *waE2E.Message, extra whatsmeow.SendRequestExtra, requestReceipts bool) (whatsmeow.SendResponse, error) {
+	if !requestReceipts {
+		return zc.WAClient.SendMessage(ctx, to, msg, extra)
+	}
+
+	if extra.ID == "" {
+		extra.ID = zc.WAClient.GenerateMessageID()
+	}
+
+	// Registrada antes do envio: o primeiro recibo pode chegar antes de SendMessage retornar
+	zc.TrackReceipts(extra.ID)
+	zc.messageStatuses.Track(extra.ID, to)
+
+	resp, err := zc.WAClient.SendMessage(ctx, to, msg, extra)
+	if err != nil {
+		zc.untrackReceipts(extra.ID)
+		zc.messageStatuses.Forget(extra.ID)
+		return resp, err
+	}
+
+	zc.messageStatuses.RecordSent(extra.ID, resp.Timestamp)
+	return resp, nil
+}
+
+// TrackReceipts marca uma mensagem enviada para que seus eventos Receipt sejam despachados
+// com detailed, chat e isGroup por até receiptTrackingTTL
+func (zc *ZPigoClient) TrackReceipts(messageID string) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	now := time.Now()
+	for id, trackedAt := range zc.trackedReceipts {
+		if now.Sub(trackedAt) > receiptTrackingTTL {
+			delete(zc.trackedReceipts, id)
+		}
+	}
+
+	zc.trackedReceipts[messageID] = now
+}
+
+func (zc *ZPigoClient) untrackReceipts(messageID string) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	delete(zc.trackedReceipts, messageID)
+}
+
+func (zc *ZPigoClient) IsReceiptTracked(messageID string) bool {
+	zc.mu.RLock()
+	defer zc.mu.RUnlock()
+	_, tracked := zc.trackedReceipts[messageID]
+	return tracked
+}
+
+// GetMessageStatus retorna o status de entrega e leitura da mensagem, conhecido a partir dos
+// recibos recebidos desde que a sessão foi iniciada
+func (zc *ZPigoClient) GetMessageStatus(messageID string) (MessageStatus, bool) {
+	return zc.messageStatuses.Get(messageID)
+}
+
+// RecordUserAbout guarda quando o recado do usuário foi alterado, a partir do evento UserAbout.
+// O GetUserInfo do WhatsApp retorna apenas o texto do recado, sem a data. As entradas expiram após
+// aboutUpdateTTL e, acima de aboutUpdatesMaxEntries, a mais antiga é descartada.
+func (zc *ZPigoClient) RecordUserAbout(jid types.JID, changedAt time.Time) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	now := time.Now()
+	jid = jid.ToNonAD()
+
+	if _, exists := zc.aboutUpdates[jid]; !exists && len(zc.aboutUpdates) >= aboutUpdatesMaxEntries {
+		var oldest types.JID
+		var oldestAt time.Time
+		for id, update := range zc.aboutUpdates {
+			if now.Sub(update.recordedAt) > aboutUpdateTTL {
+				delete(zc.aboutUpdates, id)
+				continue
+			}
+			if oldestAt.IsZero() || update.recordedAt.Before(oldestAt) {
+				oldest, oldestAt = id, update.recordedAt
+			}
+		}
+		if len(zc.aboutUpdates) >= aboutUpdatesMaxEntries {
+			delete(zc.aboutUpdates, oldest)
+		}
+	}
+
+	zc.aboutUpdates[jid] = aboutUpdate{changedAt: changedAt, recordedAt: now}
+}
+
+// GetUserAboutTimestamp retorna quando o recado do usuário foi alterado, se a alteração foi
+// recebida por esta sessão há menos de aboutUpdateTTL
+func (zc *ZPigoClient) GetUserAboutTimestamp(jid types.JID) (time.Time, bool) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	jid = jid.ToNonAD()
+	update, ok := zc.aboutUpdates[jid]
+	if !ok {
+		return time.Time{}, false
+	}
+	if time.Since(update.recordedAt) > aboutUpdateTTL {
+		delete(zc.aboutUpdates, jid)
+		return time.Time{}, false
+	}
+	return update.changedAt, true
+}
+
 func (zc *ZPigoClient) GetSessionInfo() (*SessionInfo, bool) {
 	cacheKey := BuildCacheKey(zc.APIKey, zc.SessionID)
 	return zc.CacheManager.GetSessionInfo(cacheKey)
@@ -91,9 +384,12 @@ func (zc *ZPigoClient) UpdateSessionInfo(key, value string) {
 	zc.CacheManager.UpdateSessionInfo(cacheKey, key, value)
 }
 
+// SetProxy configura o proxy do cliente HTTP da sessão; proxyURL vazio remove o proxy
 func (zc *ZPigoClient) SetProxy(proxyURL string) {
 	if proxyURL != "" {
 		zc.HTTPClient.SetProxy(proxyURL)
+	} else {
+		zc.HTTPClient.RemoveProxy()
 	}
 }
 
@@ -113,6 +409,12 @@ func (zc *ZPigoClient) Kill() {
 
 func (zc *ZPigoClient) Cleanup() {
 	zc.SetActive(false)
+
+	// Os recados acompanhados pertencem à sessão e são descartados com ela
+	zc.mu.Lock()
+	zc.aboutUpdates = make(map[types.JID]aboutUpdate)
+	zc.mu.Unlock()
+
 	if zc.WAClient != nil {
 		if zc.EventHandlerID != 0 {
 			zc.WAClient.RemoveEventHandler(zc.EventHandlerID)
@@ -0,0 +1,44 @@
+package meow
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestUserAboutUpdatesAreBounded(t *testing.T) {
+	zc := NewZPigoClient("session", "key", nil, nil)
+	changedAt := time.Unix(1_700_000_000, 0)
+
+	first := types.NewJID("5511900000000", types.DefaultUserServer)
+	zc.RecordUserAbout(types.NewADJID(first.User, 0, 2), changedAt)
+	if got, ok := zc.GetUserAboutTimestamp(first); !ok || !got.Equal(changedAt) {
+		t.Fatalf("GetUserAboutTimestamp = %s, %v", got, ok)
+	}
+
+	for i := 1; i <= aboutUpdatesMaxEntries; i++ {
+		zc.RecordUserAbout(types.NewJID(fmt.Sprintf("55119%08d", i), types.DefaultUserServer), changedAt)
+	}
+	if len(zc.aboutUpdates) != aboutUpdatesMaxEntries {
+		t.Errorf("entradas = %d, esperado o limite de %d", len(zc.aboutUpdates), aboutUpdatesMaxEntries)
+	}
+	if _, ok := zc.GetUserAboutTimestamp(first); ok {
+		t.Error("a entrada mais antiga deveria ter sido descartada ao passar do limite")
+	}
+
+	expired := types.NewJID("5521900000001", types.DefaultUserServer)
+	zc.aboutUpdates[expired] = aboutUpdate{changedAt: changedAt, recordedAt: time.Now().Add(-aboutUpdateTTL - time.Minute)}
+	if _, ok := zc.GetUserAboutTimestamp(expired); ok {
+		t.Error("entrada expirada não deveria ser retornada")
+	}
+	if _, exists := zc.aboutUpdates[expired]; exists {
+		t.Error("entrada expirada deveria ser removida na leitura")
+	}
+
+	zc.Cleanup()
+	if len(zc.aboutUpdates) != 0 {
+		t.Errorf("Cleanup manteve %d recados", len(zc.aboutUpdates))
+	}
+}
@@ -0,0 +1,181 @@
+package meow
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+// MessageContent resume o conteúdo de uma mensagem independente do tipo usado pelo whatsmeow
+type MessageContent struct {
+	Type        string
+	Body        string
+	MediaType   string
+	ContextInfo *waE2E.ContextInfo
+}
+
+// ExtractMessageContent identifica o tipo da mensagem e extrai o texto principal (texto, legenda,
+// nome do contato, emoji da reação...), o tipo de mídia e o ContextInfo com a mensagem citada
+func ExtractMessageContent(msg *waE2E.Message) MessageContent {
+	switch {
+	case msg == nil:
+		return MessageContent{Type: "unknown"}
+	case msg.Conversation != nil:
+		return MessageContent{Type: "text", Body: msg.GetConversation()}
+	case msg.ExtendedTextMessage != nil:
+		ext := msg.GetExtendedTextMessage()
+		return MessageContent{Type: "text", Body: ext.GetText(), ContextInfo: ext.GetContextInfo()}
+	case msg.ImageMessage != nil:
+		img := msg.GetImageMessage()
+		return MessageContent{Type: "image", Body: img.GetCaption(), MediaType: "image", ContextInfo: img.GetContextInfo()}
+	case msg.VideoMessage != nil:
+		video := msg.GetVideoMessage()
+		return MessageContent{Type: "video", Body: video.GetCaption(), MediaType: "video", ContextInfo: video.GetContextInfo()}
+	case msg.AudioMessage != nil:
+		audio := msg.GetAudioMessage()
+		return MessageContent{Type: "audio", MediaType: "audio", ContextInfo: audio.GetContextInfo()}
+	case msg.DocumentMessage != nil:
+		doc := msg.GetDocumentMessage()
+		return MessageContent{Type: "document", Body: doc.GetCaption(), MediaType: "document", ContextInfo: doc.GetContextInfo()}
+	case msg.StickerMessage != nil:
+		sticker := msg.GetStickerMessage()
+		return MessageContent{Type: "sticker", MediaType: "sticker", ContextInfo: sticker.GetContextInfo()}
+	case msg.LocationMessage != nil:
+		location := msg.GetLocationMessage()
+		return MessageContent{Type: "location", Body: location.GetName(), ContextInfo: location.GetContextInfo()}
+	case msg.LiveLocationMessage != nil:
+		location := msg.GetLiveLocationMessage()
+		return MessageContent{Type: "liveLocation", Body: location.GetCaption(), ContextInfo: location.GetContextInfo()}
+	case msg.ContactMessage != nil:
+		contact := msg.GetContactMessage()
+		return MessageContent{Type: "contact", Body: contact.GetDisplayName(), ContextInfo: contact.GetContextInfo()}
+	case msg.ContactsArrayMessage != nil:
+		contacts := msg.GetContactsArrayMessage()
+		return MessageContent{Type: "contacts", Body: contacts.GetDisplayName(), ContextInfo: contacts.GetContextInfo()}
+	case msg.ReactionMessage != nil:
+		return MessageContent{Type: "reaction", Body: msg.GetReactionMessage().GetText()}
+	case msg.PollCreationMessage != nil:
+		poll := msg.GetPollCreationMessage()
+		return MessageContent{Type: "poll", Body: poll.GetName(), ContextInfo: poll.GetContextInfo()}
+	case msg.PollCreationMessageV2 != nil:
+		poll := msg.GetPollCreationMessageV2()
+		return MessageContent{Type: "poll", Body: poll.GetName(), ContextInfo: poll.GetContextInfo()}
+	case msg.PollCreationMessageV3 != nil:
+		poll := msg.GetPollCreationMessageV3()
+		return MessageContent{Type: "poll", Body: poll.GetName(), ContextInfo: poll.GetContextInfo()}
+	case msg.PollUpdateMessage != nil:
+		return MessageContent{Type: "pollVote"}
+	case msg.ButtonsMessage != nil:
+		buttons := msg.GetButtonsMessage()
+		return MessageContent{Type: "buttons", Body: buttons.GetContentText(), ContextInfo: buttons.GetContextInfo()}
+	case msg.ButtonsResponseMessage != nil:
+		resp := msg.GetButtonsResponseMessage()
+		return MessageContent{Type: "buttonResponse", Body: resp.GetSelectedDisplayText(), ContextInfo: resp.GetContextInfo()}
+	case msg.ListResponseMessage != nil:
+		resp := msg.GetListResponseMessage()
+		return MessageContent{Type: "listResponse", Body: resp.GetTitle(), ContextInfo: resp.GetContextInfo()}
+	case msg.TemplateButtonReplyMessage != nil:
+		resp := msg.GetTemplateButtonReplyMessage()
+		return MessageContent{Type: "buttonResponse", Body: resp.GetSelectedDisplayText(), ContextInfo: resp.GetContextInfo()}
+	case msg.InteractiveResponseMessage != nil:
+		resp := msg.GetInteractiveResponseMessage()
+		return MessageContent{Type: "interactiveResponse", Body: resp.GetBody().GetText(), ContextInfo: resp.GetContextInfo()}
+	case msg.ProtocolMessage != nil:
+		return MessageContent{Type: "protocol"}
+	default:
+		return MessageContent{Type: "unknown"}
+	}
+}
+
+// quotedMessagePayload monta os dados da mensagem citada para o webhook, ou nil quando a mensagem
+// não é uma resposta
+func quotedMessagePayload(contextInfo *waE2E.ContextInfo) map[string]interface{} {
+	if contextInfo.GetStanzaID() == "" {
+		return nil
+	}
+
+	quoted := ExtractMessageContent(contextInfo.GetQuotedMessage())
+	payload := map[string]interface{}{
+		"messageId":   contextInfo.GetStanzaID(),
+		"participant": contextInfo.GetParticipant(),
+		"type":        quoted.Type,
+		"body":        quoted.Body,
+	}
+	if contextInfo.GetRemoteJID() != "" {
+		payload["chat"] = contextInfo.GetRemoteJID()
+	}
+	if quoted.MediaType != "" {
+		payload["mediaType"] = quoted.MediaType
+	}
+
+	return payload
+}
+
+// NormalizedMessage é o formato estável da mensagem entregue aos webhooks, igual para todos os tipos
+type NormalizedMessage struct {
+	Type          string         `json:"type"`
+	Text          string         `json:"text,omitempty"`
+	Caption       string         `json:"caption,omitempty"`
+	MediaType     string         `json:"mediaType,omitempty"`
+	MediaMetadata *MediaMetadata `json:"mediaMetadata,omitempty"`
+	Mentions      []string       `json:"mentions"`
+	IsReply       bool           `json:"isReply"`
+}
+
+// MediaMetadata reúne os atributos de mídia informados pelo remetente
+type MediaMetadata struct {
+	MimeType   string `json:"mimeType,omitempty"`
+	FileName   string `json:"fileName,omitempty"`
+	FileLength uint64 `json:"fileLength,omitempty"`
+	Width      uint32 `json:"width,omitempty"`
+	Height     uint32 `json:"height,omitempty"`
+	Seconds    uint32 `json:"seconds,omitempty"`
+	PTT        bool   `json:"ptt,omitempty"`
+	Animated   bool   `json:"animated,omitempty"`
+}
+
+// NormalizeMessage converte a mensagem para o formato estável dos webhooks. O texto principal vai em
+// text, exceto em mídias, em que vai em caption.
+func NormalizeMessage(msg *waE2E.Message) *NormalizedMessage {
+	content := ExtractMessageContent(msg)
+
+	normalized := &NormalizedMessage{
+		Type:          content.Type,
+		MediaType:     content.MediaType,
+		MediaMetadata: extractMediaMetadata(msg),
+		Mentions:      content.ContextInfo.GetMentionedJID(),
+		IsReply:       content.ContextInfo.GetStanzaID() != "",
+	}
+	if normalized.Mentions == nil {
+		normalized.Mentions = []string{}
+	}
+
+	if content.MediaType != "" {
+		normalized.Caption = content.Body
+	} else {
+		normalized.Text = content.Body
+	}
+
+	return normalized
+}
+
+// extractMediaMetadata retorna os atributos da mídia, ou nil quando a mensagem não contém mídia
+func extractMediaMetadata(msg *waE2E.Message) *MediaMetadata {
+	switch {
+	case msg.GetImageMessage() != nil:
+		img := msg.GetImageMessage()
+		return &MediaMetadata{MimeType: img.GetMimetype(), FileLength: img.GetFileLength(), Width: img.GetWidth(), Height: img.GetHeight()}
+	case msg.GetVideoMessage() != nil:
+		video := msg.GetVideoMessage()
+		return &MediaMetadata{MimeType: video.GetMimetype(), FileLength: video.GetFileLength(), Width: video.GetWidth(), Height: video.GetHeight(), Seconds: video.GetSeconds(), Animated: video.GetGifPlayback()}
+	case msg.GetAudioMessage() != nil:
+		audio := msg.GetAudioMessage()
+		return &MediaMetadata{MimeType: audio.GetMimetype(), FileLength: audio.GetFileLength(), Seconds: audio.GetSeconds(), PTT: audio.GetPTT()}
+	case msg.GetDocumentMessage() != nil:
+		doc := msg.GetDocumentMessage()
+		return &MediaMetadata{MimeType: doc.GetMimetype(), FileName: doc.GetFileName(), FileLength: doc.GetFileLength()}
+	case msg.GetStickerMessage() != nil:
+		sticker := msg.GetStickerMessage()
+		return &MediaMetadata{MimeType: sticker.GetMimetype(), FileLength: sticker.GetFileLength(), Width: sticker.GetWidth(), Height: sticker.GetHeight(), Animated: sticker.GetIsAnimated()}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,126 @@
+package meow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+var (
+	// ErrDeviceNotFound indica que o device não existe no container do whatsmeow
+	ErrDeviceNotFound = errors.New("device não encontrado")
+	// ErrDeviceInUse indica que o device ainda pertence a uma sessão e não pode ser removido pelo admin
+	ErrDeviceInUse = errors.New("device vinculado a uma sessão")
+)
+
+// DeviceInfo descreve um device salvo no container do whatsmeow e a sessão a que pertence, se houver
+type DeviceInfo struct {
+	JID       string
+	PushName  string
+	Platform  string
+	SessionID string
+	Orphan    bool
+}
+
+// ListDevices lista os devices do container do whatsmeow, indicando a sessão de cada um. Devices sem
+// sessão correspondente (Orphan) sobram quando a sessão é removida sem fazer logout e só ocupam espaço.
+func (sm *SessionManager) ListDevices(ctx context.Context) ([]DeviceInfo, error) {
+	devices, err := sm.container.GetAllDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar devices: %w", err)
+	}
+
+	sessions, err := sm.sessionRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar sessões: %w", err)
+	}
+
+	sessionByDevice := make(map[string]string, len(sessions))
+	for _, session := range sessions {
+		if session.DeviceJid != "" {
+			sessionByDevice[session.DeviceJid] = session.ID
+		}
+	}
+
+	result := make([]DeviceInfo, 0, len(devices))
+	for _, device := range devices {
+		if device.ID == nil {
+			continue
+		}
+
+		jid := device.ID.String()
+		sessionID := sessionByDevice[jid]
+		result = append(result, DeviceInfo{
+			JID:       jid,
+			PushName:  device.PushName,
+			Platform:  device.Platform,
+			SessionID: sessionID,
+			Orphan:    sessionID == "",
+		})
+	}
+
+	return result, nil
+}
+
+// ReconcileDevices procura devices sem sessão correspondente e registra um aviso para cada um, para que
+// o operador decida removê-los via DELETE /admin/devices/{jid}. Nada é apagado automaticamente.
+func (sm *SessionManager) ReconcileDevices(ctx context.Context) ([]DeviceInfo, error) {
+	devices, err := sm.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := make([]DeviceInfo, 0)
+	for _, device := range devices {
+		if device.Orphan {
+			orphans = append(orphans, device)
+			sm.logger.Warn("Device sem sessão correspondente no banco", "deviceJid", device.JID, "pushName", device.PushName)
+		}
+	}
+
+	if len(orphans) > 0 {
+		sm.logger.Warn("Devices órfãos encontrados; remova-os em DELETE /admin/devices/{jid}", "total", len(orphans))
+	}
+
+	return orphans, nil
+}
+
+// DeleteDevice remove o device do container do whatsmeow. Devices ainda vinculados a uma sessão são
+// recusados com ErrDeviceInUse: para eles a sessão deve ser removida pelos endpoints de sessão.
+func (sm *SessionManager) DeleteDevice(ctx context.Context, jid types.JID) error {
+	device, err := sm.container.GetDevice(ctx, jid)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar device: %w", err)
+	}
+	if device == nil {
+		return ErrDeviceNotFound
+	}
+
+	devices, err := sm.ListDevices(ctx)
+	if err != nil {
+		return err
+	}
+	for _, info := range devices {
+		if info.JID == jid.String() && !info.Orphan {
+			return fmt.Errorf("%w: %s", ErrDeviceInUse, info.SessionID)
+		}
+	}
+
+	sm.mu.RLock()
+	for sessionID, client := range sm.whatsmeowClients {
+		if client.Store.ID != nil && *client.Store.ID == jid {
+			sm.mu.RUnlock()
+			return fmt.Errorf("%w: %s", ErrDeviceInUse, sessionID)
+		}
+	}
+	sm.mu.RUnlock()
+
+	if err := sm.container.DeleteDevice(ctx, device); err != nil {
+		return fmt.Errorf("erro ao remover device: %w", err)
+	}
+
+	sm.logger.Info("Device removido do container", "deviceJid", jid)
+	return nil
+}
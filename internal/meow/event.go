@@ -1,15 +1,45 @@
 package meow
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 
 	"zpigo/internal/logger"
+	"zpigo/internal/store/models"
 	"zpigo/internal/webhook"
 )
 
+// handledEventTypes lista os eventos tratados em EventHandler e efetivamente entregues ao webhook.
+// Deve acompanhar os cases do switch em EventHandler.
+var handledEventTypes = []webhook.EventType{
+	webhook.EventConnected, webhook.EventDisconnected, webhook.EventPairSuccess, webhook.EventPairError,
+	webhook.EventQR, webhook.EventLoggedOut, webhook.EventStreamReplaced, webhook.EventStreamError,
+	webhook.EventConnectFailure, webhook.EventClientOutdated, webhook.EventTemporaryBan, webhook.EventMessage,
+	webhook.EventFBMessage, webhook.EventReceipt, webhook.EventUndecryptableMessage, webhook.EventPresence,
+	webhook.EventChatPresence, webhook.EventGroupInfo, webhook.EventJoinedGroup, webhook.EventContact,
+	webhook.EventPushName, webhook.EventBusinessName, webhook.EventPicture, webhook.EventOfflineSyncPreview,
+	webhook.EventOfflineSyncCompleted, webhook.EventUserAbout, webhook.EventQRTimeout,
+}
+
+// HandledEventTypes retorna os tipos de evento que o EventHandler realmente emite
+func HandledEventTypes() []webhook.EventType {
+	return append([]webhook.EventType{}, handledEventTypes...)
+}
+
+// IsHandledEventType indica se o tipo de evento é emitido pelo EventHandler
+func IsHandledEventType(eventType string) bool {
+	for _, handled := range handledEventTypes {
+		if string(handled) == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 // logEventPayload loga o payload do evento no console
 func (zc *ZPigoClient) logEventPayload(eventType string, rawEvt interface{}) {
 	eventLogger := logger.WithComponent("EventPayload").With("sessionID", zc.SessionID)
@@ -17,13 +47,6 @@ func (zc *ZPigoClient) logEventPayload(eventType string, rawEvt interface{}) {
 }
 
 func (zc *ZPigoClient) EventHandler(rawEvt interface{}) {
-	zc.mu.RLock()
-	defer zc.mu.RUnlock()
-
-	if !zc.IsActive {
-		return
-	}
-
 	eventLogger := logger.WithComponent("EventHandler").With("sessionID", zc.SessionID)
 
 	postmap := make(map[string]interface{})
@@ -172,6 +195,29 @@ func (zc *ZPigoClient) EventHandler(rawEvt interface{}) {
 		eventLogger.Debug("Foto atualizada", "jid", evt.JID.String(), "remove", evt.Remove)
 		zc.handlePictureEvent(evt, postmap)
 
+	case *events.UserAbout:
+		eventType = string(webhook.EventUserAbout)
+		shouldCallWebhook = true
+		eventLogger.Debug("Recado atualizado", "jid", evt.JID.String())
+		zc.handleUserAboutEvent(evt, postmap)
+
+	case *events.OfflineSyncPreview:
+		eventType = string(webhook.EventOfflineSyncPreview)
+		shouldCallWebhook = true
+		eventLogger.Info("Sincronização offline iniciada", "total", evt.Total, "messages", evt.Messages, "notifications", evt.Notifications)
+		zc.RecordOfflineSyncPreview(evt.Total, evt.AppDataChanges, evt.Messages, evt.Notifications, evt.Receipts)
+
+	case *events.OfflineSyncCompleted:
+		eventType = string(webhook.EventOfflineSyncCompleted)
+		shouldCallWebhook = true
+		eventLogger.Info("Sincronização offline concluída", "count", evt.Count)
+		zc.RecordOfflineSyncCompleted(evt.Count)
+
+	case *events.HistorySync, *events.Archive, *events.Pin, *events.Mute, *events.MarkChatAsRead, *events.DeleteChat:
+		// Alimentam a listagem de chats; não fazem parte dos eventos entregues ao webhook
+		eventLogger.Debug("Estado de chat recebido", "type", fmt.Sprintf("%T", evt))
+		zc.recordChatState(evt)
+
 	default:
 		eventType = fmt.Sprintf("UnhandledEvent_%T", rawEvt)
 		eventLogger.Debug("Evento não tratado", "type", fmt.Sprintf("%T", rawEvt))
@@ -182,43 +228,86 @@ func (zc *ZPigoClient) EventHandler(rawEvt interface{}) {
 	}
 
 	if shouldCallWebhook {
-		postmap["type"] = eventType
-
 		// Logar o payload do evento no console
 		zc.logEventPayload(eventType, rawEvt)
 
-		if zc.shouldSendEvent(eventType) {
-			eventLogger.Debug("Enviando webhook", "eventType", eventType)
-			go zc.callWebhook(postmap)
-		}
+		zc.dispatchEvent(eventType, postmap)
 	}
 }
 
-func (zc *ZPigoClient) shouldSendEvent(eventType string) bool {
-	if len(zc.Subscriptions) == 0 {
-		return false
+// EmitEvent entrega aos webhooks e streams da sessão um evento gerado pelo próprio ZPigo, sem evento
+// correspondente no whatsmeow (ex: QRTimeout). data vira o campo event do payload.
+func (zc *ZPigoClient) EmitEvent(eventType webhook.EventType, data map[string]interface{}) {
+	postmap := map[string]interface{}{
+		"event":     data,
+		"sessionId": zc.SessionID,
+		"timestamp": time.Now().Unix(),
+	}
+
+	zc.logEventPayload(string(eventType), data)
+	zc.dispatchEvent(string(eventType), postmap)
+}
+
+// dispatchEvent envia o evento ao webhook, quando assinado, e aos streams SSE da sessão
+func (zc *ZPigoClient) dispatchEvent(eventType string, postmap map[string]interface{}) {
+	postmap["type"] = eventType
+
+	if zc.shouldSendEvent(eventType) {
+		logger.WithComponent("EventHandler").With("sessionID", zc.SessionID).Debug("Enviando webhook", "eventType", eventType)
+		go zc.callWebhook(postmap)
 	}
 
-	for _, sub := range zc.Subscriptions {
+	zc.publishEvent(postmap)
+}
+
+// publishEvent entrega o evento aos streams SSE da sessão no mesmo formato do payload do webhook
+func (zc *ZPigoClient) publishEvent(postmap map[string]interface{}) {
+	if zc.EventStream == nil || zc.EventStream.SubscriberCount(zc.SessionID) == 0 {
+		return
+	}
+
+	eventData := make(map[string]interface{}, len(postmap))
+	for k, v := range postmap {
+		if k != "type" && k != "sessionId" && k != "timestamp" {
+			eventData[k] = v
+		}
+	}
+
+	zc.EventStream.Publish(&webhook.Payload{
+		Type:      postmap["type"].(string),
+		SessionID: zc.SessionID,
+		Timestamp: postmap["timestamp"].(int64),
+		Event:     eventData,
+	})
+}
+
+// shouldSendEvent indica se o evento interessa ao webhook da sessão ou ao webhook global
+func (zc *ZPigoClient) shouldSendEvent(eventType string) bool {
+	for _, sub := range zc.GetSubscriptions() {
 		if sub == "All" || sub == eventType {
 			return true
 		}
 	}
 
-	return false
+	return zc.WebhookManager != nil && zc.WebhookManager.GlobalSubscribes(webhook.EventType(eventType))
 }
 
 func (zc *ZPigoClient) handleConnectedEvent() {
+	zc.MarkConnected()
+	// O whatsmeow marca o dispositivo como ativo (SetPassive(false)) a cada conexão
+	zc.SetPassiveMode(false)
 	zc.SetActive(true)
 	zc.UpdateSessionInfo("Status", "connected")
 }
 
 func (zc *ZPigoClient) handleDisconnectedEvent() {
+	zc.MarkDisconnected()
 	zc.SetActive(false)
 	zc.UpdateSessionInfo("Status", "disconnected")
 }
 
 func (zc *ZPigoClient) handlePairErrorEvent(evt *events.PairError, postmap map[string]interface{}) {
+	zc.RecordError(fmt.Sprintf("erro no pareamento: %v", evt.Error))
 	postmap["error"] = evt.Error.Error()
 	postmap["jid"] = evt.ID.String()
 	postmap["lid"] = evt.LID.String()
@@ -239,17 +328,20 @@ func (zc *ZPigoClient) handleStreamReplacedEvent() {
 }
 
 func (zc *ZPigoClient) handleStreamErrorEvent(evt *events.StreamError, postmap map[string]interface{}) {
+	zc.RecordError(fmt.Sprintf("erro de stream: %s", evt.Code))
 	postmap["code"] = evt.Code
 	postmap["raw"] = evt.Raw
 }
 
 func (zc *ZPigoClient) handleConnectFailureEvent(evt *events.ConnectFailure, postmap map[string]interface{}) {
+	zc.RecordError(fmt.Sprintf("falha na conexão: %s %s", evt.Reason, evt.Message))
 	postmap["reason"] = evt.Reason
 	postmap["message"] = evt.Message
 	postmap["raw"] = evt.Raw
 }
 
 func (zc *ZPigoClient) handleTemporaryBanEvent(evt *events.TemporaryBan, postmap map[string]interface{}) {
+	zc.RecordError(fmt.Sprintf("ban temporário: %s", evt.String()))
 	postmap["code"] = evt.Code
 	postmap["expire"] = evt.Expire.String()
 }
@@ -271,6 +363,62 @@ func (zc *ZPigoClient) handleMessageEvent(evt *events.Message, postmap map[strin
 	postmap["isEdit"] = evt.IsEdit
 	postmap["retryCount"] = evt.RetryCount
 
+	content := ExtractMessageContent(evt.Message)
+	postmap["messageType"] = content.Type
+	postmap["body"] = content.Body
+	if content.MediaType != "" {
+		postmap["mediaType"] = content.MediaType
+	}
+	if quoted := quotedMessagePayload(content.ContextInfo); quoted != nil {
+		postmap["quoted"] = quoted
+	}
+	postmap["message"] = NormalizeMessage(evt.Message)
+
+	if evt.Info.IsFromMe {
+		zc.RecordMessageSent()
+	} else {
+		zc.RecordMessageReceived()
+	}
+
+	if evt.IsEdit {
+		zc.recordIncomingEdit(evt, postmap)
+	}
+
+	zc.recordChatMessage(evt)
+	zc.storeMessage(evt, content)
+}
+
+// recordIncomingEdit registra no histórico a nova versão de uma mensagem editada.
+// O ID do evento é o da mensagem de edição; o ID original vem na chave do ProtocolMessage.
+func (zc *ZPigoClient) recordIncomingEdit(evt *events.Message, postmap map[string]interface{}) {
+	protocolMsg := evt.Message.GetProtocolMessage()
+	originalID := protocolMsg.GetKey().GetID()
+	if originalID == "" {
+		return
+	}
+
+	content := ExtractMessageText(protocolMsg.GetEditedMessage())
+	postmap["editedMessageId"] = originalID
+	postmap["editedContent"] = content
+
+	if zc.MessageEdits == nil {
+		return
+	}
+
+	edit := &models.MessageEdit{
+		SessionID: zc.SessionID,
+		MessageID: originalID,
+		ChatJID:   evt.Info.Chat.String(),
+		SenderJID: evt.Info.Sender.String(),
+		Content:   content,
+		IsFromMe:  evt.Info.IsFromMe,
+		Source:    models.EditSourceEvent,
+		EditedAt:  evt.Info.Timestamp,
+	}
+
+	if err := zc.MessageEdits.Create(context.Background(), edit); err != nil {
+		logger.WithComponent("EventHandler").Error("Erro ao registrar edição de mensagem", "sessionID", zc.SessionID, "messageID", originalID, "error", err)
+	}
 }
 
 func (zc *ZPigoClient) handleFBMessageEvent(evt *events.FBMessage, postmap map[string]interface{}) {
@@ -295,6 +443,50 @@ func (zc *ZPigoClient) handleReceiptEvent(evt *events.Receipt, postmap map[strin
 	postmap["messageIds"] = evt.MessageIDs
 	postmap["receiptType"] = string(evt.Type)
 	postmap["timestamp"] = evt.Timestamp.Unix()
+	postmap["sender"] = evt.Sender.String()
+
+	zc.messageStatuses.Record(evt)
+
+	if evt.IsGroup {
+		zc.recordGroupReceipt(evt)
+	}
+
+	for _, messageID := range evt.MessageIDs {
+		if zc.IsReceiptTracked(messageID) {
+			postmap["detailed"] = true
+			postmap["chat"] = evt.Chat.String()
+			postmap["sender"] = evt.Sender.String()
+			postmap["isGroup"] = evt.IsGroup
+			break
+		}
+	}
+}
+
+// recordGroupReceipt persiste quem leu ou reproduziu mensagens em grupos, já que nesses chats
+// o WhatsApp envia um recibo por participante
+func (zc *ZPigoClient) recordGroupReceipt(evt *events.Receipt) {
+	if zc.Receipts == nil {
+		return
+	}
+
+	if evt.Type != types.ReceiptTypeRead && evt.Type != types.ReceiptTypePlayed {
+		return
+	}
+
+	for _, messageID := range evt.MessageIDs {
+		receipt := &models.MessageReceipt{
+			SessionID:   zc.SessionID,
+			MessageID:   messageID,
+			ChatJID:     evt.Chat.String(),
+			ReaderJID:   evt.Sender.ToNonAD().String(),
+			ReceiptType: string(evt.Type),
+			ReceivedAt:  evt.Timestamp,
+		}
+
+		if err := zc.Receipts.Create(context.Background(), receipt); err != nil {
+			logger.WithComponent("EventHandler").Error("Erro ao registrar recibo de leitura", "sessionID", zc.SessionID, "messageID", messageID, "error", err)
+		}
+	}
 }
 
 func (zc *ZPigoClient) handlePresenceEvent(evt *events.Presence, postmap map[string]interface{}) {
@@ -332,22 +524,42 @@ func (zc *ZPigoClient) handleGroupInfoEvent(evt *events.GroupInfo, postmap map[s
 		postmap["sender"] = evt.Sender.String()
 	}
 	if evt.Name != nil {
-		postmap["name"] = evt.Name
+		postmap["name"] = evt.Name.Name
 	}
 	if evt.Topic != nil {
-		postmap["topic"] = evt.Topic
+		postmap["topic"] = evt.Topic.Topic
+	}
+
+	// Campos de configuração seguem o mesmo formato de GroupInfoResponse.Settings
+	settings := make(map[string]interface{})
+	if evt.Locked != nil {
+		settings["locked"] = evt.Locked.IsLocked
+	}
+	if evt.Announce != nil {
+		settings["announce"] = evt.Announce.IsAnnounce
+	}
+	if evt.Ephemeral != nil {
+		settings["ephemeral"] = evt.Ephemeral.IsEphemeral
+		settings["disappearingTimer"] = evt.Ephemeral.DisappearingTimer
+	}
+	if evt.MembershipApprovalMode != nil {
+		settings["joinApprovalRequired"] = evt.MembershipApprovalMode.IsJoinApprovalRequired
+	}
+	if len(settings) > 0 {
+		postmap["settings"] = settings
 	}
+
 	if len(evt.Join) > 0 {
-		postmap["join"] = evt.Join
+		postmap["join"] = jidsToStrings(evt.Join)
 	}
 	if len(evt.Leave) > 0 {
-		postmap["leave"] = evt.Leave
+		postmap["leave"] = jidsToStrings(evt.Leave)
 	}
 	if len(evt.Promote) > 0 {
-		postmap["promote"] = evt.Promote
+		postmap["promote"] = jidsToStrings(evt.Promote)
 	}
 	if len(evt.Demote) > 0 {
-		postmap["demote"] = evt.Demote
+		postmap["demote"] = jidsToStrings(evt.Demote)
 	}
 }
 
@@ -397,6 +609,21 @@ func (zc *ZPigoClient) handlePictureEvent(evt *events.Picture, postmap map[strin
 	postmap["pictureId"] = evt.PictureID
 }
 
+func (zc *ZPigoClient) handleUserAboutEvent(evt *events.UserAbout, postmap map[string]interface{}) {
+	zc.RecordUserAbout(evt.JID, evt.Timestamp)
+	postmap["jid"] = evt.JID.String()
+	postmap["status"] = evt.Status
+	postmap["timestamp"] = evt.Timestamp.Unix()
+}
+
+func jidsToStrings(jids []types.JID) []string {
+	result := make([]string, len(jids))
+	for i, jid := range jids {
+		result[i] = jid.String()
+	}
+	return result
+}
+
 func (zc *ZPigoClient) callWebhook(postmap map[string]interface{}) {
 	webhookLogger := logger.WithComponent("Webhook").With("sessionID", zc.SessionID)
 
@@ -415,8 +642,15 @@ func (zc *ZPigoClient) callWebhook(postmap map[string]interface{}) {
 		}
 	}
 
-	webhookLogger.Info("Webhook preparado para envio",
+	if zc.WebhookManager == nil {
+		webhookLogger.Warn("Gerenciador de webhooks não disponível", "eventType", eventType)
+		return
+	}
+
+	webhookLogger.Debug("Webhook preparado para envio",
 		"eventType", eventType,
 		"sessionID", zc.SessionID,
 		"dataKeys", len(eventData))
+
+	zc.WebhookManager.Send(zc.SessionID, eventType, eventData, nil)
 }
@@ -0,0 +1,64 @@
+package meow
+
+import (
+	"sync"
+	"time"
+)
+
+// IdempotencyTTL é a janela em que um envio com o mesmo ID informado pelo cliente devolve o resultado
+// anterior em vez de enviar a mensagem de novo
+const IdempotencyTTL = 10 * time.Minute
+
+// IdempotentSend acompanha um envio identificado pelo ID do cliente. Enquanto o envio está em
+// andamento a entrada não tem resultado; depois de concluído guarda a resposta devolvida ao cliente.
+type IdempotentSend struct {
+	mu        sync.RWMutex
+	completed bool
+	result    interface{}
+}
+
+// Complete registra a resposta do envio concluído
+func (s *IdempotentSend) Complete(result interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.completed = true
+	s.result = result
+}
+
+// Result retorna a resposta do envio e se ele já foi concluído
+func (s *IdempotentSend) Result() (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.result, s.completed
+}
+
+func idempotencyKey(sessionID, clientID string) string {
+	return "idempotency:" + sessionID + ":" + clientID
+}
+
+// ReserveSend reserva o ID do cliente para um envio da sessão. Retorna a nova entrada e true quando o
+// ID está livre, ou a entrada existente e false quando o ID já foi usado dentro de IdempotencyTTL.
+func (cm *CacheManager) ReserveSend(sessionID, clientID string) (*IdempotentSend, bool) {
+	key := idempotencyKey(sessionID, clientID)
+
+	for {
+		entry := &IdempotentSend{}
+		if err := cm.cache.Add(key, entry, IdempotencyTTL); err == nil {
+			return entry, true
+		}
+
+		// A entrada pode expirar entre o Add e o Get; nesse caso a reserva é tentada de novo
+		if item, found := cm.cache.Get(key); found {
+			if existing, ok := item.(*IdempotentSend); ok {
+				return existing, false
+			}
+		}
+	}
+}
+
+// ReleaseSend libera o ID reservado quando o envio falha, para que o cliente possa tentar de novo
+func (cm *CacheManager) ReleaseSend(sessionID, clientID string) {
+	cm.cache.Delete(idempotencyKey(sessionID, clientID))
+}
@@ -0,0 +1,165 @@
+package meow
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// LinkPreview contém os metadados Open Graph usados na prévia de links
+type LinkPreview struct {
+	MatchedText string
+	Title       string
+	Description string
+	Thumbnail   []byte
+}
+
+var (
+	urlPattern       = regexp.MustCompile(`https?://[^\s<>"]+`)
+	metaTagPattern   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	metaAttrPattern  = regexp.MustCompile(`(?is)([a-z:-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	titleTagPattern  = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	headClosePattern = regexp.MustCompile(`(?i)</head>`)
+)
+
+// FindFirstURL retorna a primeira URL http(s) do texto, sem a pontuação final que costuma
+// encerrar frases, ou "" quando o texto não contém URLs
+func FindFirstURL(text string) string {
+	match := urlPattern.FindString(text)
+	return strings.TrimRight(match, ".,;:!?)]}'")
+}
+
+// FetchLinkPreview baixa a página e extrai título, descrição e imagem Open Graph, com fallback para
+// <title> e meta description. A imagem é reduzida a uma miniatura JPEG; falhas ao obtê-la não
+// invalidam a prévia. proxyURL vazio faz a requisição sem proxy. A URL vem do texto da mensagem, então
+// só destinos públicos são buscados (ver NewPublicHTTPClient).
+func FetchLinkPreview(ctx context.Context, pageURL, proxyURL string) (*LinkPreview, error) {
+	httpClient := NewPublicHTTPClient(proxyURL)
+	httpClient.SetTimeout(DefaultLinkPreviewTimeout)
+
+	resp, err := httpClient.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		SetHeader("Accept", "text/html").
+		Get(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("erro na requisição: %w", err)
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return nil, fmt.Errorf("URL retornou status %d", resp.StatusCode())
+	}
+	if contentType := resp.Header().Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "html") {
+		return nil, fmt.Errorf("URL não retornou HTML: %s", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, DefaultLinkPreviewMaxHTMLSize))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	page := string(data)
+	if loc := headClosePattern.FindStringIndex(page); loc != nil {
+		page = page[:loc[0]]
+	}
+
+	meta := parseMetaTags(page)
+
+	preview := &LinkPreview{
+		MatchedText: pageURL,
+		Title:       firstNonEmpty(meta["og:title"], meta["twitter:title"]),
+		Description: firstNonEmpty(meta["og:description"], meta["twitter:description"], meta["description"]),
+	}
+	if preview.Title == "" {
+		if match := titleTagPattern.FindStringSubmatch(page); match != nil {
+			preview.Title = strings.TrimSpace(html.UnescapeString(match[1]))
+		}
+	}
+	if preview.Title == "" && preview.Description == "" {
+		return nil, fmt.Errorf("página sem título ou descrição")
+	}
+
+	if imageURL := firstNonEmpty(meta["og:image"], meta["twitter:image"]); imageURL != "" {
+		if ref, err := url.Parse(imageURL); err == nil {
+			imageURL = resp.RawResponse.Request.URL.ResolveReference(ref).String()
+			if thumbnail, err := fetchPreviewThumbnail(ctx, imageURL, proxyURL); err == nil {
+				preview.Thumbnail = thumbnail
+			}
+		}
+	}
+
+	return preview, nil
+}
+
+func fetchPreviewThumbnail(ctx context.Context, imageURL, proxyURL string) ([]byte, error) {
+	httpClient := NewPublicHTTPClient(proxyURL)
+	httpClient.SetTimeout(DefaultLinkPreviewTimeout)
+
+	resp, err := httpClient.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(imageURL)
+	if err != nil {
+		return nil, err
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return nil, fmt.Errorf("imagem retornou status %d", resp.StatusCode())
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, DefaultLinkPreviewMaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > DefaultLinkPreviewMaxImageSize {
+		return nil, fmt.Errorf("imagem excede %d bytes", DefaultLinkPreviewMaxImageSize)
+	}
+
+	info, err := DecodeImageInfo(data)
+	if err != nil {
+		return nil, err
+	}
+	return info.Thumbnail, nil
+}
+
+// parseMetaTags mapeia property/name (em minúsculas) para content das tags <meta>. A primeira
+// ocorrência de cada chave prevalece.
+func parseMetaTags(page string) map[string]string {
+	meta := make(map[string]string)
+	for _, tag := range metaTagPattern.FindAllString(page, -1) {
+		var key, content string
+		for _, attr := range metaAttrPattern.FindAllStringSubmatch(tag, -1) {
+			value := attr[2] + attr[3]
+			switch strings.ToLower(attr[1]) {
+			case "property", "name":
+				key = strings.ToLower(strings.TrimSpace(value))
+			case "content":
+				content = strings.TrimSpace(html.UnescapeString(value))
+			}
+		}
+		if key == "" || content == "" {
+			continue
+		}
+		if _, exists := meta[key]; !exists {
+			meta[key] = content
+		}
+	}
+	return meta
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
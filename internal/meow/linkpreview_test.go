@@ -0,0 +1,36 @@
+package meow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchLinkPreviewRefusesLoopback(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>interno</title></head></html>`))
+	}))
+	defer server.Close()
+
+	for _, pageURL := range []string{server.URL + "/pagina", "http://127.0.0.1/pagina"} {
+		_, err := FetchLinkPreview(context.Background(), pageURL, "")
+		if !errors.Is(err, ErrNonPublicAddress) {
+			t.Errorf("FetchLinkPreview(%s) = %v, esperado ErrNonPublicAddress", pageURL, err)
+		}
+	}
+	if requested {
+		t.Error("o servidor em loopback não deveria ter recebido a requisição")
+	}
+}
+
+func TestFetchPreviewThumbnailRefusesLinkLocal(t *testing.T) {
+	_, err := fetchPreviewThumbnail(context.Background(), "http://169.254.169.254/latest/meta-data", "")
+	if !errors.Is(err, ErrNonPublicAddress) {
+		t.Errorf("erro = %v, esperado ErrNonPublicAddress", err)
+	}
+}
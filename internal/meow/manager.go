@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/mdp/qrterminal/v3"
@@ -20,44 +21,264 @@ import (
 	"zpigo/internal/logger"
 	"zpigo/internal/store"
 	"zpigo/internal/store/models"
+	"zpigo/internal/store/repositories"
+	"zpigo/internal/webhook"
 )
 
 type SessionManager struct {
 	whatsmeowClients map[string]*whatsmeow.Client
+	zpigoClients     map[string]*ZPigoClient
 	httpClients      map[string]*resty.Client
 
 	container *sqlstore.Container
 
-	db          *sql.DB
-	sessionRepo store.SessionRepositoryInterface
+	db              *sql.DB
+	sessionRepo     store.SessionRepositoryInterface
+	webhookRepo     store.WebhookRepositoryInterface
+	messageEditRepo store.MessageEditRepositoryInterface
+	receiptRepo     store.MessageReceiptRepositoryInterface
+	chatRepo        store.ChatRepositoryInterface
+	messageRepo     store.MessageRepositoryInterface
+	broadcastRepo   store.BroadcastRepositoryInterface
 
-	cacheManager *CacheManager
+	cacheManager   *CacheManager
+	webhookManager *webhook.Manager
+	eventStream    *EventStream
+
+	waLogLevel string
 
 	mu sync.RWMutex
 
 	logger logger.Logger
 
 	killChannels map[string]chan bool
+
+	pairingMu   sync.Mutex
+	pairings    map[string]time.Time
+	maxPairings int
+	qrHandlers  map[string]*qrHandler
+
+	sweeperStop chan struct{}
+	prunerStop  chan struct{}
+
+	messageStoreEnabled bool
+
+	sendLimiter *SendRateLimiter
+	sendTimeout time.Duration
+
+	broadcastMu sync.Mutex
+	broadcasts  map[string]*broadcastRunner
 }
 
 func NewSessionManager(container *sqlstore.Container, db *sql.DB, sessionRepo store.SessionRepositoryInterface) *SessionManager {
 	return &SessionManager{
 		whatsmeowClients: make(map[string]*whatsmeow.Client),
+		zpigoClients:     make(map[string]*ZPigoClient),
 		httpClients:      make(map[string]*resty.Client),
 		container:        container,
 		db:               db,
 		sessionRepo:      sessionRepo,
+		webhookRepo:      repositories.NewWebhookRepository(db),
+		messageEditRepo:  repositories.NewMessageEditRepository(db),
+		receiptRepo:      repositories.NewMessageReceiptRepository(db),
+		chatRepo:         repositories.NewChatRepository(db),
+		messageRepo:      repositories.NewMessageRepository(db),
+		broadcastRepo:    repositories.NewBroadcastRepository(db),
 		cacheManager:     GetGlobalCache(),
-		logger:           NewLoggerForComponent("SessionManager"),
-		killChannels:     make(map[string]chan bool),
+		webhookManager: webhook.NewManager(webhook.Options{
+			Workers:        DefaultWebhookWorkers,
+			EnqueueTimeout: webhook.DefaultEnqueueTimeout,
+		}),
+		eventStream:  NewEventStream(),
+		logger:       NewLoggerForComponent("SessionManager"),
+		killChannels: make(map[string]chan bool),
+		pairings:     make(map[string]time.Time),
+		maxPairings:  DefaultMaxConcurrentPairings,
+		qrHandlers:   make(map[string]*qrHandler),
+		sendLimiter:  NewSendRateLimiter(DefaultSendRatePerMinute),
+		sendTimeout:  DefaultSendTimeout,
+		broadcasts:   make(map[string]*broadcastRunner),
 	}
 }
 
+// SetWhatsAppLogLevel define o nível dos logs do whatsmeow nos clientes criados a partir de agora;
+// vazio usa o nível do logger global
+func (sm *SessionManager) SetWhatsAppLogLevel(level string) {
+	sm.waLogLevel = level
+}
+
+// SetSendRateLimit define quantas mensagens cada sessão pode enviar por minuto. Zero ou negativo
+// desativa o limite.
+func (sm *SessionManager) SetSendRateLimit(perMinute int) {
+	sm.sendLimiter.SetLimit(perMinute)
+}
+
+// SetSendTimeout define o tempo máximo de espera pela confirmação de cada mensagem enviada pelo
+// próprio gerenciador, como as dos broadcasts. Zero ou negativo remove o limite.
+func (sm *SessionManager) SetSendTimeout(timeout time.Duration) {
+	sm.sendTimeout = timeout
+}
+
+// sendContext deriva de ctx o contexto de um único SendMessage, limitado por sendTimeout
+func (sm *SessionManager) sendContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if sm.sendTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, sm.sendTimeout)
+}
+
+// AllowSend consome uma vaga de envio da sessão, retornando quanto esperar quando o limite foi atingido
+func (sm *SessionManager) AllowSend(sessionID string) (bool, time.Duration) {
+	return sm.sendLimiter.Allow(sessionID)
+}
+
 func (sm *SessionManager) GetDB() *sql.DB {
 	return sm.db
 }
 
+func (sm *SessionManager) GetCacheManager() *CacheManager {
+	return sm.cacheManager
+}
+
+func (sm *SessionManager) GetMessageEditRepository() store.MessageEditRepositoryInterface {
+	return sm.messageEditRepo
+}
+
+func (sm *SessionManager) GetMessageReceiptRepository() store.MessageReceiptRepositoryInterface {
+	return sm.receiptRepo
+}
+
+func (sm *SessionManager) GetChatRepository() store.ChatRepositoryInterface {
+	return sm.chatRepo
+}
+
+func (sm *SessionManager) GetMessageRepository() store.MessageRepositoryInterface {
+	return sm.messageRepo
+}
+
+func (sm *SessionManager) GetBroadcastRepository() store.BroadcastRepositoryInterface {
+	return sm.broadcastRepo
+}
+
+// SetWebhookOptions recria o gerenciador de webhooks com o pool e a fila informados. Deve ser
+// chamado na inicialização, antes de carregar as configurações de webhook e de conectar sessões.
+func (sm *SessionManager) SetWebhookOptions(opts webhook.Options) {
+	previous := sm.webhookManager
+	sm.webhookManager = webhook.NewManager(opts)
+	previous.Stop()
+}
+
+func (sm *SessionManager) GetWebhookManager() *webhook.Manager {
+	return sm.webhookManager
+}
+
+// GetEventStream retorna o distribuidor de eventos usado pelos streams SSE das sessões
+func (sm *SessionManager) GetEventStream() *EventStream {
+	return sm.eventStream
+}
+
+func (sm *SessionManager) newZPigoClient(sessionID string, client *whatsmeow.Client) *ZPigoClient {
+	zc := sm.buildZPigoClient(sessionID, client)
+	sm.loadMessageStorage(sessionID, zc)
+	return zc
+}
+
+// buildZPigoClient cria o ZPigoClient da sessão sem ler do banco as opções persistidas
+func (sm *SessionManager) buildZPigoClient(sessionID string, client *whatsmeow.Client) *ZPigoClient {
+	zc := NewZPigoClient(sessionID, "", client, sm.db)
+	zc.MessageEdits = sm.messageEditRepo
+	zc.Receipts = sm.receiptRepo
+	zc.Chats = sm.chatRepo
+	zc.Messages = sm.messageRepo
+	zc.WebhookManager = sm.webhookManager
+	zc.EventStream = sm.eventStream
+	if config, exists := sm.webhookManager.GetConfig(sessionID); exists {
+		zc.UpdateSubscriptions(config.Events)
+	}
+	return zc
+}
+
+// ApplyWebhookConfig registra a configuração no gerenciador de webhooks e atualiza
+// as inscrições de eventos do cliente ativo da sessão, se houver
+func (sm *SessionManager) ApplyWebhookConfig(sessionID string, config *webhook.Config) error {
+	if err := sm.webhookManager.SetConfig(sessionID, config); err != nil {
+		return err
+	}
+
+	if zc, exists := sm.GetZPigoClient(sessionID); exists {
+		zc.UpdateSubscriptions(config.Events)
+	}
+
+	return nil
+}
+
+// LoadConfigsFromRepository registra no gerenciador de webhooks as configurações persistidas,
+// que de outra forma se perderiam a cada reinício. Deve rodar antes de as sessões reconectarem,
+// para que os clientes já nasçam com as inscrições de eventos. Quando a sessão tem mais de um
+// webhook, vale o mais recente, como nos handlers. Configurações inválidas são ignoradas.
+func (sm *SessionManager) LoadConfigsFromRepository(ctx context.Context, repo store.WebhookRepositoryInterface) (int, error) {
+	webhooks, err := repo.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao listar webhooks: %w", err)
+	}
+
+	loaded := 0
+	seen := make(map[string]bool, len(webhooks))
+	for _, model := range webhooks {
+		if seen[model.SessionID] {
+			continue
+		}
+		seen[model.SessionID] = true
+
+		if err := sm.ApplyWebhookConfig(model.SessionID, NewWebhookConfigFromModel(model)); err != nil {
+			sm.logger.Warn("Configuração de webhook ignorada", "sessionID", model.SessionID, "webhookID", model.ID, "error", err)
+			continue
+		}
+		loaded++
+	}
+
+	return loaded, nil
+}
+
+// RemoveWebhookConfig remove a configuração de webhook e as inscrições de eventos da sessão
+func (sm *SessionManager) RemoveWebhookConfig(sessionID string) {
+	sm.webhookManager.DeleteConfig(sessionID)
+
+	if zc, exists := sm.GetZPigoClient(sessionID); exists {
+		zc.UpdateSubscriptions(nil)
+	}
+}
+
+// AddSession grava a sessão no banco e registra seu cliente no gerenciador como uma única operação:
+// a inserção só é confirmada depois do registro, e o registro é desfeito se a confirmação falhar.
+// Assim não fica sessão no banco sem cliente, nem cliente sem sessão no banco.
+func (sm *SessionManager) AddSession(ctx context.Context, session *models.Session) (*whatsmeow.Client, error) {
+	var client *whatsmeow.Client
+	err := sm.sessionRepo.CreateWith(ctx, session, func(session *models.Session) error {
+		var err error
+		client, err = sm.createSession(session.ID, session)
+		return err
+	})
+	if err != nil {
+		if client != nil {
+			sm.logger.Warn("Erro ao gravar sessão, desfazendo registro do cliente", "sessionID", session.ID, "error", err)
+			if delErr := sm.DeleteSession(session.ID); delErr != nil {
+				sm.logger.Error("Erro ao desfazer registro do cliente", "sessionID", session.ID, "error", delErr)
+			}
+		}
+		return nil, err
+	}
+
+	return client, nil
+}
+
 func (sm *SessionManager) CreateSession(sessionID string) (*whatsmeow.Client, error) {
+	return sm.createSession(sessionID, nil)
+}
+
+// createSession cria e registra o cliente da sessão. Com session nil, plataforma, proxy e armazenamento
+// de mensagens são lidos do banco; caso contrário vêm de session, que pode ainda não estar confirmada
+func (sm *SessionManager) createSession(sessionID string, session *models.Session) (*whatsmeow.Client, error) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
@@ -70,13 +291,28 @@ func (sm *SessionManager) CreateSession(sessionID string) (*whatsmeow.Client, er
 
 	deviceStore := sm.container.NewDevice()
 
-	waLogger := logger.ForWhatsApp("WhatsApp")
+	waLogger := NewWhatsAppLogger("WhatsApp", sm.waLogLevel)
 	client := whatsmeow.NewClient(deviceStore, waLogger)
 
+	var zc *ZPigoClient
+	var proxyURL string
+	if session == nil {
+		sm.loadDevicePlatform(sessionID, client)
+		proxyURL = sm.loadProxy(sessionID, client)
+		zc = sm.newZPigoClient(sessionID, client)
+	} else {
+		sm.applySessionPlatform(session, client)
+		proxyURL = sm.applySessionProxy(session, client)
+		zc = sm.buildZPigoClient(sessionID, client)
+		sm.applyMessageStorage(session, zc)
+	}
+	zc.SetProxy(proxyURL)
+
 	// Adicionar event handler para logging
 	client.AddEventHandler(sm.createEventHandler(sessionID))
 
 	sm.whatsmeowClients[sessionID] = client
+	sm.zpigoClients[sessionID] = zc
 	sm.logger.Info("Sessão criada com sucesso", "sessionID", sessionID)
 
 	return client, nil
@@ -86,6 +322,9 @@ func (sm *SessionManager) SetWhatsmeowClient(sessionID string, client *whatsmeow
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 	sm.whatsmeowClients[sessionID] = client
+	if _, exists := sm.zpigoClients[sessionID]; !exists {
+		sm.zpigoClients[sessionID] = sm.newZPigoClient(sessionID, client)
+	}
 	sm.logger.Info("Cliente WhatsApp adicionado ao SessionManager", "sessionID", sessionID, "totalSessions", len(sm.whatsmeowClients))
 }
 
@@ -101,6 +340,13 @@ func (sm *SessionManager) DeleteWhatsmeowClient(sessionID string) {
 	delete(sm.whatsmeowClients, sessionID)
 }
 
+func (sm *SessionManager) GetZPigoClient(sessionID string) (*ZPigoClient, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	zc, exists := sm.zpigoClients[sessionID]
+	return zc, exists
+}
+
 func (sm *SessionManager) SetHTTPClient(sessionID string, client *resty.Client) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
@@ -154,15 +400,67 @@ func (sm *SessionManager) DeleteSession(sessionID string) error {
 		return fmt.Errorf("sessão %s não encontrada", sessionID)
 	}
 
+	sm.cancelQRHandler(sessionID)
+
 	if client.IsConnected() {
 		client.Disconnect()
 	}
 
+	if zc, exists := sm.zpigoClients[sessionID]; exists {
+		zc.Cleanup()
+		delete(sm.zpigoClients, sessionID)
+	}
+
 	delete(sm.whatsmeowClients, sessionID)
+	sm.releasePairing(sessionID)
+	sm.sendLimiter.Remove(sessionID)
+	sm.eventStream.CloseSession(sessionID)
 
 	return nil
 }
 
+// Shutdown desconecta todas as sessões ativas, interrompe os handlers de QR e encerra os
+// clientes ZPigo. Deve ser chamado no encerramento da aplicação; chamadas repetidas são seguras.
+func (sm *SessionManager) Shutdown() {
+	sm.pairingMu.Lock()
+	for sessionID, handler := range sm.qrHandlers {
+		handler.cancel()
+		delete(sm.qrHandlers, sessionID)
+	}
+	sm.pairings = make(map[string]time.Time)
+	sm.pairingMu.Unlock()
+
+	sm.stopQRSweeper()
+	sm.stopMessagePruner()
+	sm.stopBroadcasts()
+	sm.eventStream.CloseAll()
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.logger.Info("Encerrando sessões ativas", "total", len(sm.whatsmeowClients))
+
+	for sessionID, zc := range sm.zpigoClients {
+		zc.Cleanup()
+		delete(sm.zpigoClients, sessionID)
+	}
+
+	for sessionID, client := range sm.whatsmeowClients {
+		if client.IsConnected() {
+			client.Disconnect()
+			sm.logger.Info("Sessão desconectada no encerramento", "sessionID", sessionID)
+		}
+		delete(sm.whatsmeowClients, sessionID)
+	}
+
+	for sessionID, killChan := range sm.killChannels {
+		close(killChan)
+		delete(sm.killChannels, sessionID)
+	}
+
+	sm.logger.Info("Sessões encerradas")
+}
+
 func (sm *SessionManager) ConnectSession(sessionID string) error {
 	client, exists := sm.GetSession(sessionID)
 	if !exists {
@@ -173,35 +471,65 @@ func (sm *SessionManager) ConnectSession(sessionID string) error {
 		return fmt.Errorf("sessão %s já está conectada", sessionID)
 	}
 
-	if client.Store.ID == nil {
-		qrChan, err := client.GetQRChannel(context.Background())
-		if err != nil {
-			if !errors.Is(err, whatsmeow.ErrQRStoreContainsID) {
-				return fmt.Errorf("erro ao obter canal QR: %v", err)
-			}
-		} else {
-			err = client.Connect()
-			if err != nil {
-				return fmt.Errorf("erro ao conectar: %v", err)
-			}
+	if client.Store.ID != nil {
+		return client.Connect()
+	}
+
+	if err := sm.acquirePairing(sessionID); err != nil {
+		return err
+	}
 
-			go sm.handleQREvents(sessionID, qrChan)
-			return nil
+	ctx := sm.startQRHandler(sessionID)
+
+	qrChan, err := client.GetQRChannel(ctx)
+	if err != nil {
+		sm.cancelQRHandler(sessionID)
+		sm.releasePairing(sessionID)
+
+		// O dispositivo pode ter sido emparelhado entre a verificação acima e a criação do canal
+		if errors.Is(err, whatsmeow.ErrQRStoreContainsID) {
+			return client.Connect()
 		}
+		return fmt.Errorf("erro ao obter canal QR: %v", err)
 	}
 
-	return client.Connect()
+	if err := client.Connect(); err != nil {
+		sm.cancelQRHandler(sessionID)
+		sm.releasePairing(sessionID)
+		return fmt.Errorf("erro ao conectar: %v", err)
+	}
+
+	go sm.handleQREvents(ctx, sessionID, qrChan)
+	return nil
 }
 
-func (sm *SessionManager) handleQREvents(sessionID string, qrChan <-chan whatsmeow.QRChannelItem) {
+func (sm *SessionManager) handleQREvents(ctx context.Context, sessionID string, qrChan <-chan whatsmeow.QRChannelItem) {
 	logger := sm.logger.With("sessionID", sessionID).With("component", "QRHandler")
+	defer sm.finishQRHandler(sessionID, ctx)
+	defer sm.releasePairing(sessionID)
 
 	var wasSuccessful bool
+	// lastEvent guarda o último evento não tratado (ex: err-client-outdated), que explica o fechamento do canal
+	var lastEvent string
+
+	for {
+		var evt whatsmeow.QRChannelItem
+		var ok bool
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Handler de QR cancelado", "sessionID", sessionID)
+			return
+		case evt, ok = <-qrChan:
+		}
+
+		if !ok {
+			break
+		}
 
-	for evt := range qrChan {
 		switch evt.Event {
 		case "code":
-			logger.Info("QR code gerado", "code", evt.Code)
+			logger.Info("QR code gerado", "code", evt.Code, "timeout", evt.Timeout)
 
 			qrterminal.GenerateHalfBlock(evt.Code, qrterminal.L, os.Stdout)
 			fmt.Println("QR code:", evt.Code)
@@ -214,7 +542,7 @@ func (sm *SessionManager) handleQREvents(sessionID string, qrChan <-chan whatsme
 
 			base64QRCode := "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrImage)
 
-			err = sm.sessionRepo.UpdateQRCode(context.Background(), sessionID, base64QRCode)
+			err = sm.sessionRepo.UpdateQRCode(context.Background(), sessionID, base64QRCode, evt.Timeout)
 			if err != nil {
 				logger.Error("Erro ao salvar QR code no banco", "error", err)
 			} else {
@@ -224,6 +552,10 @@ func (sm *SessionManager) handleQREvents(sessionID string, qrChan <-chan whatsme
 		case "timeout":
 			logger.Warn("QR code expirou")
 
+			if err := sm.sessionRepo.UpdateQRCode(context.Background(), sessionID, "", 0); err != nil {
+				logger.Error("Erro ao limpar QR code após timeout", "error", err)
+			}
+
 			err := sm.sessionRepo.SetDisconnected(context.Background(), sessionID)
 			if err != nil {
 				logger.Error("Erro ao atualizar sessão após timeout", "error", err)
@@ -238,11 +570,13 @@ func (sm *SessionManager) handleQREvents(sessionID string, qrChan <-chan whatsme
 				}
 			}
 
+			sm.emitQRTimeout(sessionID, "timeout", "")
 			return
 
 		case "success":
 			logger.Info("QR code autenticado com sucesso!")
 			wasSuccessful = true
+			sm.releasePairing(sessionID)
 
 			client, exists := sm.GetSession(sessionID)
 			deviceJid := ""
@@ -261,7 +595,7 @@ func (sm *SessionManager) handleQREvents(sessionID string, qrChan <-chan whatsme
 				logger.Info("Sessão marcada como conectada após autenticação bem-sucedida", "sessionID", sessionID, "phone", phone, "deviceJid", deviceJid)
 			}
 
-			err = sm.sessionRepo.UpdateQRCode(context.Background(), sessionID, "")
+			err = sm.sessionRepo.UpdateQRCode(context.Background(), sessionID, "", 0)
 			if err != nil {
 				logger.Error("Erro ao limpar QR code", "error", err)
 			} else {
@@ -270,6 +604,7 @@ func (sm *SessionManager) handleQREvents(sessionID string, qrChan <-chan whatsme
 
 		default:
 			logger.Info("Evento QR recebido", "event", evt.Event)
+			lastEvent = evt.Event
 		}
 	}
 
@@ -293,6 +628,28 @@ func (sm *SessionManager) handleQREvents(sessionID string, qrChan <-chan whatsme
 			logger.Info("Cliente WhatsApp desconectado após fechamento do canal QR", "sessionID", sessionID)
 		}
 	}
+
+	sm.emitQRTimeout(sessionID, "channel_closed", lastEvent)
+}
+
+// emitQRTimeout avisa webhooks e streams que o emparelhamento por QR terminou sem sucesso e a sessão
+// precisa ser conectada de novo para gerar outro QR. reason é timeout quando os QR codes se esgotaram
+// ou channel_closed quando o canal fechou antes; lastEvent é o último evento do canal, se houver.
+func (sm *SessionManager) emitQRTimeout(sessionID, reason, lastEvent string) {
+	zc, exists := sm.GetZPigoClient(sessionID)
+	if !exists {
+		return
+	}
+
+	data := map[string]interface{}{
+		"reason": reason,
+		"status": string(models.StatusDisconnected),
+	}
+	if lastEvent != "" {
+		data["lastEvent"] = lastEvent
+	}
+
+	zc.EmitEvent(webhook.EventQRTimeout, data)
 }
 
 func (sm *SessionManager) DisconnectSession(sessionID string) error {
@@ -305,6 +662,7 @@ func (sm *SessionManager) DisconnectSession(sessionID string) error {
 		return fmt.Errorf("sessão %s não está conectada", sessionID)
 	}
 
+	sm.cancelQRHandler(sessionID)
 	client.Disconnect()
 	return nil
 }
@@ -322,32 +680,39 @@ func (sm *SessionManager) LogoutSession(sessionID string) error {
 	return client.Logout(context.Background())
 }
 
-func (sm *SessionManager) GenerateQRCode(sessionID string) (string, error) {
+// GenerateQRCode retorna o QR code atual da sessão e o tempo restante até o próximo código ser emitido.
+// Um código vencido não é retornado: o erro é ErrQRCodeExpired e a sessão precisa ser reconectada.
+func (sm *SessionManager) GenerateQRCode(sessionID string) (string, time.Duration, error) {
 	client, exists := sm.GetSession(sessionID)
 	if !exists {
-		return "", fmt.Errorf("sessão %s não encontrada", sessionID)
+		return "", 0, fmt.Errorf("sessão %s não encontrada", sessionID)
 	}
 
 	if client.IsLoggedIn() {
-		return "", fmt.Errorf("sessão %s já está autenticada", sessionID)
+		return "", 0, fmt.Errorf("sessão %s já está autenticada", sessionID)
 	}
 
 	session, err := sm.sessionRepo.GetByID(context.Background(), sessionID)
 	if err != nil {
-		return "", fmt.Errorf("erro ao buscar QR code: %v", err)
+		return "", 0, fmt.Errorf("erro ao buscar QR code: %v", err)
 	}
 
 	if session.QRCode == "" {
-		return "", fmt.Errorf("QR code não disponível. Certifique-se de que a sessão está conectada")
+		return "", 0, fmt.Errorf("QR code não disponível. Certifique-se de que a sessão está conectada")
+	}
+
+	now := time.Now()
+	if session.QRCodeExpired(now) {
+		return "", 0, ErrQRCodeExpired
 	}
 
-	return session.QRCode, nil
+	return session.QRCode, session.QRCodeExpiresIn(now), nil
 }
 
 func (sm *SessionManager) ConnectOnStartup() error {
 	sm.logger.Info("Verificando sessões para reconexão")
 
-	sessions, err := sm.sessionRepo.GetAll(context.Background())
+	sessions, err := sm.sessionRepo.List(context.Background())
 	if err != nil {
 		sm.logger.Error("Erro ao buscar sessões para reconexão", "error", err)
 		return err
@@ -362,21 +727,7 @@ func (sm *SessionManager) ConnectOnStartup() error {
 				"name", session.Name,
 				"deviceJid", session.DeviceJid)
 
-			go func(sess models.Session) {
-				err := sm.reconnectSession(sess.ID, sess.DeviceJid)
-				if err != nil {
-					sm.logger.Error("❌ Erro ao reconectar sessão",
-						"sessionID", sess.ID,
-						"name", sess.Name,
-						"error", err)
-
-					sm.sessionRepo.UpdateStatus(context.Background(), sess.ID, models.StatusDisconnected)
-				} else {
-					sm.logger.Info("Sessão reconectada",
-						"sessionID", sess.ID,
-						"name", sess.Name)
-				}
-			}(session)
+			go sm.reconnectWithBackoff(session)
 		}
 	}
 
@@ -386,9 +737,75 @@ func (sm *SessionManager) ConnectOnStartup() error {
 		sm.logger.Info("Nenhuma sessão para reconectar")
 	}
 
+	if err := sm.ResumeBroadcasts(context.Background()); err != nil {
+		sm.logger.Error("Erro ao retomar broadcasts", "error", err)
+	}
+
 	return nil
 }
 
+// reconnectWithBackoff tenta reconectar a sessão até DefaultReconnectAttempts vezes, dobrando o
+// intervalo entre tentativas. Falhas definitivas (device inválido ou removido) não são repetidas;
+// a sessão só é marcada como desconectada depois que todas as tentativas falham.
+func (sm *SessionManager) reconnectWithBackoff(session *models.Session) {
+	delay := DefaultReconnectBaseDelay
+
+	for attempt := 1; attempt <= DefaultReconnectAttempts; attempt++ {
+		sm.logger.Info("Tentativa de reconexão",
+			"sessionID", session.ID,
+			"name", session.Name,
+			"attempt", attempt,
+			"maxAttempts", DefaultReconnectAttempts)
+
+		err := sm.reconnectSession(session.ID, session.DeviceJid)
+		if err == nil {
+			sm.logger.Info("Sessão reconectada",
+				"sessionID", session.ID,
+				"name", session.Name,
+				"attempt", attempt)
+			return
+		}
+
+		if errors.Is(err, errDeviceUnavailable) {
+			sm.logger.Error("❌ Erro definitivo ao reconectar sessão",
+				"sessionID", session.ID,
+				"name", session.Name,
+				"attempt", attempt,
+				"error", err)
+			return
+		}
+
+		if attempt == DefaultReconnectAttempts {
+			sm.logger.Error("❌ Erro ao reconectar sessão, tentativas esgotadas",
+				"sessionID", session.ID,
+				"name", session.Name,
+				"attempt", attempt,
+				"error", err)
+			break
+		}
+
+		sm.logger.Warn("Falha na tentativa de reconexão",
+			"sessionID", session.ID,
+			"name", session.Name,
+			"attempt", attempt,
+			"nextAttemptIn", delay,
+			"error", err)
+
+		time.Sleep(delay)
+		delay = min(delay*2, DefaultReconnectMaxDelay)
+	}
+
+	sm.sessionRepo.UpdateStatus(context.Background(), session.ID, models.StatusDisconnected)
+}
+
+// errDeviceUnavailable indica que o device da sessão não pode ser usado e repetir a reconexão não adianta
+var errDeviceUnavailable = errors.New("device indisponível")
+
+// ErrQRCodeExpired indica que o último QR code emitido expirou e a sessão precisa ser reconectada
+var ErrQRCodeExpired = errors.New("QR code expirado")
+
+// reconnectSession faz uma tentativa de reconexão com o device salvo. Falhas definitivas retornam
+// errDeviceUnavailable e já marcam a sessão como desconectada; falhas de conexão ficam a cargo de quem chama.
 func (sm *SessionManager) reconnectSession(sessionID, deviceJid string) error {
 	sm.logger.Info("Iniciando reconexão da sessão", "sessionID", sessionID, "deviceJid", deviceJid)
 
@@ -401,32 +818,46 @@ func (sm *SessionManager) reconnectSession(sessionID, deviceJid string) error {
 	if err != nil {
 		sm.logger.Error("Erro ao fazer parse do deviceJid", "sessionID", sessionID, "deviceJid", deviceJid, "error", err)
 		sm.sessionRepo.UpdateStatus(context.Background(), sessionID, models.StatusDisconnected)
-		return fmt.Errorf("erro ao fazer parse do deviceJid: %w", err)
+		return fmt.Errorf("%w: erro ao fazer parse do deviceJid: %w", errDeviceUnavailable, err)
 	}
 
 	deviceStore, err := sm.container.GetDevice(context.Background(), jid)
 	if err != nil || deviceStore == nil {
 		sm.logger.Warn("Device não encontrado no banco, sessão foi removida do WhatsApp", "sessionID", sessionID, "deviceJid", deviceJid, "error", err)
 		sm.sessionRepo.SetDisconnected(context.Background(), sessionID)
-		return fmt.Errorf("device não encontrado: %w", err)
+		return fmt.Errorf("%w: device não encontrado: %v", errDeviceUnavailable, err)
 	}
 
 	if deviceStore.ID == nil {
 		sm.logger.Warn("Device store sem ID válido, sessão precisa ser reconectada manualmente", "sessionID", sessionID)
 		sm.sessionRepo.UpdateStatus(context.Background(), sessionID, models.StatusDisconnected)
-		return fmt.Errorf("device store sem ID válido")
+		return fmt.Errorf("%w: device store sem ID válido", errDeviceUnavailable)
 	}
 
-	waLogger := logger.ForWhatsApp("WhatsApp")
+	waLogger := NewWhatsAppLogger("WhatsApp", sm.waLogLevel)
 	client := whatsmeow.NewClient(deviceStore, waLogger)
+	sm.loadDevicePlatform(sessionID, client)
+	proxyURL := sm.loadProxy(sessionID, client)
 
 	// Adicionar event handler para logging
 	client.AddEventHandler(sm.createEventHandler(sessionID))
 
+	zc := sm.newZPigoClient(sessionID, client)
+	zc.SetProxy(proxyURL)
+
+	sm.mu.Lock()
+	sm.zpigoClients[sessionID] = zc
+	sm.mu.Unlock()
+
 	err = client.Connect()
 	if err != nil {
 		sm.logger.Error("Erro ao conectar cliente na reconexão", "sessionID", sessionID, "deviceJid", deviceJid, "error", err)
-		sm.sessionRepo.UpdateStatus(context.Background(), sessionID, models.StatusDisconnected)
+		sm.mu.Lock()
+		if zc, exists := sm.zpigoClients[sessionID]; exists {
+			zc.Cleanup()
+			delete(sm.zpigoClients, sessionID)
+		}
+		sm.mu.Unlock()
 		return fmt.Errorf("erro ao conectar cliente: %w", err)
 	}
 
@@ -436,7 +867,74 @@ func (sm *SessionManager) reconnectSession(sessionID, deviceJid string) error {
 	return nil
 }
 
-func (sm *SessionManager) PairPhone(sessionID, phoneNumber string) (string, error) {
+// RestartSession descarta o cliente atual da sessão, removendo seus event handlers, e o recria a partir
+// do DeviceJid persistido com a mesma lógica da reconexão na inicialização
+func (sm *SessionManager) RestartSession(sessionID string) error {
+	session, err := sm.sessionRepo.GetByID(context.Background(), sessionID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar sessão: %w", err)
+	}
+
+	if session.DeviceJid == "" {
+		return fmt.Errorf("sessão %s não está autenticada; use o endpoint de conexão para emparelhar", sessionID)
+	}
+
+	sm.mu.Lock()
+	client := sm.whatsmeowClients[sessionID]
+	zc := sm.zpigoClients[sessionID]
+	delete(sm.whatsmeowClients, sessionID)
+	delete(sm.zpigoClients, sessionID)
+	sm.mu.Unlock()
+
+	if zc != nil {
+		zc.Cleanup()
+	}
+
+	if client != nil {
+		client.RemoveEventHandlers()
+		if client.IsConnected() {
+			client.Disconnect()
+		}
+	}
+
+	sm.logger.Info("Reiniciando sessão", "sessionID", sessionID, "deviceJid", session.DeviceJid)
+
+	if err := sm.reconnectSession(sessionID, session.DeviceJid); err != nil {
+		if !errors.Is(err, errDeviceUnavailable) {
+			sm.sessionRepo.UpdateStatus(context.Background(), sessionID, models.StatusDisconnected)
+		}
+		if client != nil {
+			sm.restoreClient(session, client)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// restoreClient devolve ao gerenciador o cliente anterior, desconectado, quando o reinício falha. Sem
+// isso a sessão sumiria do gerenciador e não poderia mais ser conectada, emparelhada ou encerrada.
+// Se outro cliente foi registrado para a sessão nesse meio tempo, ele é mantido.
+func (sm *SessionManager) restoreClient(session *models.Session, client *whatsmeow.Client) {
+	zc := sm.newZPigoClient(session.ID, client)
+	zc.SetProxy(sm.applySessionProxy(session, client))
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if _, exists := sm.whatsmeowClients[session.ID]; exists {
+		zc.Cleanup()
+		return
+	}
+
+	client.AddEventHandler(sm.createEventHandler(session.ID))
+	sm.whatsmeowClients[session.ID] = client
+	sm.zpigoClients[session.ID] = zc
+
+	sm.logger.Warn("Reinício falhou, cliente anterior mantido desconectado", "sessionID", session.ID)
+}
+
+func (sm *SessionManager) PairPhone(sessionID, phoneNumber string, clientType whatsmeow.PairClientType, clientDisplayName string) (string, error) {
 	client, exists := sm.GetSession(sessionID)
 	if !exists {
 		return "", fmt.Errorf("sessão %s não encontrada", sessionID)
@@ -452,7 +950,7 @@ func (sm *SessionManager) PairPhone(sessionID, phoneNumber string) (string, erro
 		}
 	}
 
-	linkingCode, err := client.PairPhone(context.Background(), phoneNumber, true, whatsmeow.PairClientChrome, "Chrome (Linux)")
+	linkingCode, err := client.PairPhone(context.Background(), phoneNumber, true, clientType, clientDisplayName)
 	if err != nil {
 		return "", fmt.Errorf("erro ao emparelhar telefone: %v", err)
 	}
@@ -469,19 +967,107 @@ func (sm *SessionManager) GetSessionStatus(sessionID string) (bool, bool, error)
 	return client.IsConnected(), client.IsLoggedIn(), nil
 }
 
+// SetProxy aplica o proxy da sessão ao cliente whatsmeow (websocket e mídias) e ao cliente HTTP da sessão.
+// Com a sessão conectada, o websocket atual é mantido e o proxy passa a valer na próxima conexão.
 func (sm *SessionManager) SetProxy(sessionID string, proxyConfig *models.Session) error {
 	client, exists := sm.GetSession(sessionID)
 	if !exists {
 		return fmt.Errorf("sessão %s não encontrada", sessionID)
 	}
 
+	proxyURL := proxyConfig.GetProxyURL()
+	if err := applyProxy(client, proxyURL); err != nil {
+		return fmt.Errorf("erro ao aplicar proxy: %w", err)
+	}
+
+	if zc, exists := sm.GetZPigoClient(sessionID); exists {
+		zc.SetProxy(proxyURL)
+	}
+
+	if client.IsConnected() {
+		sm.logger.Info("Proxy aplicado; a conexão atual será mantida até a próxima reconexão", "sessionID", sessionID)
+	}
+
+	return nil
+}
+
+// ClearProxy remove o proxy do cliente whatsmeow e do cliente HTTP da sessão, para que as próximas
+// conexões e transferências de mídia sejam diretas. Com a sessão conectada, o websocket atual é mantido.
+func (sm *SessionManager) ClearProxy(sessionID string) error {
+	client, exists := sm.GetSession(sessionID)
+	if !exists {
+		return fmt.Errorf("sessão %s não encontrada", sessionID)
+	}
+
+	if err := applyProxy(client, ""); err != nil {
+		return fmt.Errorf("erro ao remover proxy: %w", err)
+	}
+
+	if zc, exists := sm.GetZPigoClient(sessionID); exists {
+		zc.SetProxy("")
+	}
+
 	if client.IsConnected() {
-		return fmt.Errorf("não é possível configurar proxy com sessão conectada")
+		sm.logger.Info("Proxy removido; a conexão atual será mantida até a próxima reconexão", "sessionID", sessionID)
 	}
 
 	return nil
 }
 
+// SetDevicePlatform aplica a plataforma e o SO informados ao cliente carregado da sessão.
+// A identidade é enviada ao WhatsApp no emparelhamento, por isso não altera dispositivos já vinculados.
+func (sm *SessionManager) SetDevicePlatform(sessionID, platform, osName string) error {
+	client, exists := sm.GetSession(sessionID)
+	if !exists {
+		return nil
+	}
+
+	return applyDevicePlatform(client, platform, osName)
+}
+
+// loadDevicePlatform aplica ao cliente recém-criado a plataforma persistida na sessão
+func (sm *SessionManager) loadDevicePlatform(sessionID string, client *whatsmeow.Client) {
+	session, err := sm.sessionRepo.GetByID(context.Background(), sessionID)
+	if err != nil {
+		return
+	}
+
+	sm.applySessionPlatform(session, client)
+}
+
+// applySessionPlatform aplica ao cliente recém-criado a plataforma da sessão
+func (sm *SessionManager) applySessionPlatform(session *models.Session, client *whatsmeow.Client) {
+	if err := applyDevicePlatform(client, session.Platform, session.OSName); err != nil {
+		sm.logger.Warn("Plataforma da sessão inválida, usando padrão", "sessionID", session.ID, "platform", session.Platform, "error", err)
+	}
+}
+
+// loadProxy aplica ao cliente o proxy persistido da sessão e retorna sua URL, ou "" quando não há proxy
+func (sm *SessionManager) loadProxy(sessionID string, client *whatsmeow.Client) string {
+	session, err := sm.sessionRepo.GetByID(context.Background(), sessionID)
+	if err != nil {
+		return ""
+	}
+
+	return sm.applySessionProxy(session, client)
+}
+
+// applySessionProxy aplica ao cliente o proxy da sessão e retorna sua URL, ou "" quando não há proxy
+func (sm *SessionManager) applySessionProxy(session *models.Session, client *whatsmeow.Client) string {
+	if !session.HasProxy() {
+		return ""
+	}
+
+	proxyURL := session.GetProxyURL()
+	if err := applyProxy(client, proxyURL); err != nil {
+		sm.logger.Warn("Proxy da sessão inválido, conectando sem proxy", "sessionID", session.ID, "proxyType", session.ProxyType, "error", err)
+		return ""
+	}
+
+	sm.logger.Info("Proxy aplicado à sessão", "sessionID", session.ID, "proxyType", session.ProxyType, "proxyHost", session.ProxyHost)
+	return proxyURL
+}
+
 func (sm *SessionManager) ListSessions() []string {
 	sm.mu.RLock()
 	defer sm.mu.RUnlock()
@@ -494,6 +1080,24 @@ func (sm *SessionManager) ListSessions() []string {
 	return sessions
 }
 
+// ClientStats retorna o total de clientes carregados no manager e quantos estão conectados e autenticados
+func (sm *SessionManager) ClientStats() (int, int, int) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	connected, loggedIn := 0, 0
+	for _, client := range sm.whatsmeowClients {
+		if client.IsConnected() {
+			connected++
+		}
+		if client.IsLoggedIn() {
+			loggedIn++
+		}
+	}
+
+	return len(sm.whatsmeowClients), connected, loggedIn
+}
+
 func (sm *SessionManager) AddEventHandler(sessionID string, handler func(any)) error {
 	client, exists := sm.GetSession(sessionID)
 	if !exists {
@@ -504,6 +1108,58 @@ func (sm *SessionManager) AddEventHandler(sessionID string, handler func(any)) e
 	return nil
 }
 
+// WarmCache popula o cache com o SessionInfo de todas as sessões do banco para a API key informada,
+// processando no máximo concurrency sessões em paralelo
+func (sm *SessionManager) WarmCache(ctx context.Context, apiKey string, concurrency int) (int, error) {
+	sessions, err := sm.sessionRepo.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao listar sessões: %w", err)
+	}
+
+	if concurrency <= 0 {
+		concurrency = DefaultCacheWarmConcurrency
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		warmed int
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, session := range sessions {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(session *models.Session) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			sessionInfo := NewSessionInfoFromModel(session, apiKey)
+			if client, exists := sm.GetSession(session.ID); exists && client.Store.ID != nil {
+				sessionInfo.JID = client.Store.ID.String()
+			} else if session.DeviceJid != "" {
+				sessionInfo.JID = session.DeviceJid
+			}
+
+			sm.cacheManager.SetSessionInfo(BuildCacheKey(apiKey, session.ID), sessionInfo)
+
+			mu.Lock()
+			warmed++
+			mu.Unlock()
+		}(session)
+	}
+
+	wg.Wait()
+
+	sm.logger.Info("Cache aquecido", "sessions", len(sessions), "warmed", warmed, "concurrency", concurrency)
+
+	return warmed, ctx.Err()
+}
+
 func (sm *SessionManager) GetSessionByAPIKey(apiKey, sessionID string) (*SessionInfo, error) {
 	cacheKey := BuildCacheKey(apiKey, sessionID)
 
@@ -515,16 +1171,18 @@ func (sm *SessionManager) GetSessionByAPIKey(apiKey, sessionID string) (*Session
 	return sessionInfo, nil
 }
 
-func (sm *SessionManager) ListSessionsByAPIKey(apiKey string) ([]*SessionInfo, error) {
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
-
-	var sessions []*SessionInfo
+// ListSessionsByAPIKey lista as sessões do banco que a chave pode acessar e que têm cliente no gerenciador.
+// O acesso vem do escopo da chave, nunca do cache, que pode ser preenchido para qualquer sessão.
+func (sm *SessionManager) ListSessionsByAPIKey(ctx context.Context, key *models.APIKey) ([]*models.Session, error) {
+	all, err := sm.sessionRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	for sessionID := range sm.whatsmeowClients {
-		cacheKey := BuildCacheKey(apiKey, sessionID)
-		if sessionInfo, found := sm.cacheManager.GetSessionInfo(cacheKey); found {
-			sessions = append(sessions, sessionInfo)
+	var sessions []*models.Session
+	for _, session := range all {
+		if key.AllowsSession(session.ID) && sm.sessionExists(session.ID) {
+			sessions = append(sessions, session)
 		}
 	}
 
@@ -553,17 +1211,19 @@ func (sm *SessionManager) ConnectSessionByAPIKey(apiKey, sessionID string) error
 	return nil
 }
 
-func (sm *SessionManager) LogoutSessionByAPIKey(apiKey, sessionID string) error {
+// LogoutSessionByAPIKey faz logout da sessão se a chave tiver acesso a ela, atualizando a entrada de cache da chave
+func (sm *SessionManager) LogoutSessionByAPIKey(apiKey string, key *models.APIKey, sessionID string) error {
 	client, exists := sm.GetSession(sessionID)
 	if !exists {
 		return fmt.Errorf("sessão não encontrada: %s", sessionID)
 	}
 
-	cacheKey := BuildCacheKey(apiKey, sessionID)
-	if _, found := sm.cacheManager.GetSessionInfo(cacheKey); !found {
+	if !key.AllowsSession(sessionID) {
 		return fmt.Errorf("sessão não autorizada: %s", sessionID)
 	}
 
+	cacheKey := BuildCacheKey(apiKey, sessionID)
+
 	if err := client.Logout(context.Background()); err != nil {
 		sm.logger.Error("Erro ao fazer logout", "sessionID", sessionID, "error", err)
 		return fmt.Errorf("erro ao fazer logout: %v", err)
@@ -0,0 +1,246 @@
+package meow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+)
+
+// ThumbnailMaxSize é o tamanho máximo (em pixels) do maior lado da miniatura JPEG
+const ThumbnailMaxSize = 72
+
+// ThumbnailMaxSourcePixels limita a área da imagem decodificada para gerar a miniatura. Um arquivo
+// pequeno pode declarar dimensões enormes e exigir gigabytes de memória ao ser decodificado.
+const ThumbnailMaxSourcePixels = 25_000_000
+
+// ImageInfo contém as dimensões de uma imagem e sua miniatura JPEG
+type ImageInfo struct {
+	Width     uint32
+	Height    uint32
+	Thumbnail []byte
+}
+
+// WebPInfo contém as dimensões do canvas de uma imagem WebP e se ela é animada
+type WebPInfo struct {
+	Width    uint32
+	Height   uint32
+	Animated bool
+}
+
+// VideoInfo contém os metadados extraídos do cabeçalho de um vídeo MP4
+type VideoInfo struct {
+	Width   uint32
+	Height  uint32
+	Seconds uint32
+}
+
+// DecodeImageInfo lê as dimensões da imagem e gera uma miniatura JPEG.
+// Se a imagem não puder ser decodificada por completo, ou for grande demais para isso (ver
+// ThumbnailMaxSourcePixels), as dimensões lidas do cabeçalho ainda são retornadas.
+func DecodeImageInfo(data []byte) (*ImageInfo, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ImageInfo{
+		Width:  uint32(cfg.Width),
+		Height: uint32(cfg.Height),
+	}
+
+	if int64(cfg.Width)*int64(cfg.Height) > ThumbnailMaxSourcePixels {
+		return info, fmt.Errorf("imagem de %dx%d excede o limite de %d pixels para gerar miniatura", cfg.Width, cfg.Height, ThumbnailMaxSourcePixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return info, err
+	}
+
+	thumbnail, err := GenerateThumbnail(img)
+	if err != nil {
+		return info, err
+	}
+	info.Thumbnail = thumbnail
+
+	return info, nil
+}
+
+// GenerateThumbnail reduz a imagem para no máximo ThumbnailMaxSize pixels no maior lado e codifica em JPEG
+func GenerateThumbnail(src image.Image) ([]byte, error) {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW == 0 || srcH == 0 {
+		return nil, errors.New("imagem sem dimensões")
+	}
+
+	dstW, dstH := srcW, srcH
+	if srcW > ThumbnailMaxSize || srcH > ThumbnailMaxSize {
+		if srcW >= srcH {
+			dstW = ThumbnailMaxSize
+			dstH = max(1, srcH*ThumbnailMaxSize/srcW)
+		} else {
+			dstH = ThumbnailMaxSize
+			dstW = max(1, srcW*ThumbnailMaxSize/srcH)
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		srcY := bounds.Min.Y + (2*y+1)*srcH/(2*dstH)
+		for x := 0; x < dstW; x++ {
+			srcX := bounds.Min.X + (2*x+1)*srcW/(2*dstW)
+			dst.Set(x, y, src.At(srcX, srcY))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 60}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeMP4Info lê a duração (mvhd) e as dimensões da trilha de vídeo (tkhd) do cabeçalho MP4
+func DecodeMP4Info(data []byte) (*VideoInfo, error) {
+	moov := findMP4Box(data, "moov")
+	if moov == nil {
+		return nil, errors.New("box moov não encontrado")
+	}
+
+	info := &VideoInfo{}
+
+	if mvhd := findMP4Box(moov, "mvhd"); len(mvhd) >= 20 {
+		var timescale uint32
+		var duration uint64
+		if mvhd[0] == 1 && len(mvhd) >= 32 {
+			timescale = binary.BigEndian.Uint32(mvhd[20:24])
+			duration = binary.BigEndian.Uint64(mvhd[24:32])
+		} else {
+			timescale = binary.BigEndian.Uint32(mvhd[12:16])
+			duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+		}
+		if timescale > 0 {
+			info.Seconds = uint32(duration / uint64(timescale))
+		}
+	}
+
+	for _, trak := range findMP4Boxes(moov, "trak") {
+		tkhd := findMP4Box(trak, "tkhd")
+		if len(tkhd) < 8 {
+			continue
+		}
+		width := binary.BigEndian.Uint32(tkhd[len(tkhd)-8:]) >> 16
+		height := binary.BigEndian.Uint32(tkhd[len(tkhd)-4:]) >> 16
+		if width > 0 && height > 0 {
+			info.Width = width
+			info.Height = height
+			break
+		}
+	}
+
+	return info, nil
+}
+
+// DecodeWebPInfo valida o contêiner RIFF/WEBP e lê as dimensões e a flag de animação.
+// Os formatos VP8 (com perdas), VP8L (sem perdas) e VP8X (estendido) são suportados.
+func DecodeWebPInfo(data []byte) (*WebPInfo, error) {
+	if len(data) < 20 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errors.New("arquivo não é WebP")
+	}
+
+	chunk := data[12:]
+	fourCC := string(chunk[0:4])
+	size := int(binary.LittleEndian.Uint32(chunk[4:8]))
+	payload := chunk[8:]
+	if size > len(payload) {
+		return nil, errors.New("chunk WebP truncado")
+	}
+	payload = payload[:size]
+
+	switch fourCC {
+	case "VP8X":
+		if len(payload) < 10 {
+			return nil, errors.New("cabeçalho VP8X inválido")
+		}
+		return &WebPInfo{
+			Width:    readUint24LE(payload[4:7]) + 1,
+			Height:   readUint24LE(payload[7:10]) + 1,
+			Animated: payload[0]&0x02 != 0,
+		}, nil
+	case "VP8 ":
+		// Frame tag (3 bytes) + start code 9d 01 2a + largura e altura de 14 bits
+		if len(payload) < 10 || payload[3] != 0x9d || payload[4] != 0x01 || payload[5] != 0x2a {
+			return nil, errors.New("cabeçalho VP8 inválido")
+		}
+		return &WebPInfo{
+			Width:  uint32(binary.LittleEndian.Uint16(payload[6:8]) & 0x3fff),
+			Height: uint32(binary.LittleEndian.Uint16(payload[8:10]) & 0x3fff),
+		}, nil
+	case "VP8L":
+		// Assinatura 0x2f + largura-1 e altura-1 em 14 bits cada
+		if len(payload) < 5 || payload[0] != 0x2f {
+			return nil, errors.New("cabeçalho VP8L inválido")
+		}
+		bits := binary.LittleEndian.Uint32(payload[1:5])
+		return &WebPInfo{
+			Width:  bits&0x3fff + 1,
+			Height: (bits>>14)&0x3fff + 1,
+		}, nil
+	default:
+		return nil, errors.New("chunk WebP desconhecido: " + fourCC)
+	}
+}
+
+func readUint24LE(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+}
+
+// findMP4Box retorna o conteúdo do primeiro box com o tipo informado
+func findMP4Box(data []byte, boxType string) []byte {
+	boxes := findMP4Boxes(data, boxType)
+	if len(boxes) == 0 {
+		return nil
+	}
+	return boxes[0]
+}
+
+// findMP4Boxes percorre os boxes do nível atual e retorna o conteúdo dos que têm o tipo informado
+func findMP4Boxes(data []byte, boxType string) [][]byte {
+	var boxes [][]byte
+
+	for offset := 0; offset+8 <= len(data); {
+		size := uint64(binary.BigEndian.Uint32(data[offset : offset+4]))
+		name := string(data[offset+4 : offset+8])
+		header := uint64(8)
+
+		switch size {
+		case 0:
+			size = uint64(len(data) - offset)
+		case 1:
+			if offset+16 > len(data) {
+				return boxes
+			}
+			size = binary.BigEndian.Uint64(data[offset+8 : offset+16])
+			header = 16
+		}
+
+		if size < header || uint64(offset)+size > uint64(len(data)) {
+			return boxes
+		}
+
+		if name == boxType {
+			boxes = append(boxes, data[uint64(offset)+header:uint64(offset)+size])
+		}
+
+		offset += int(size)
+	}
+
+	return boxes
+}
@@ -0,0 +1,60 @@
+package meow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// pngHeader monta apenas a assinatura e o chunk IHDR de um PNG, o suficiente para image.DecodeConfig
+func pngHeader(width, height uint32) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], width)
+	binary.BigEndian.PutUint32(ihdr[4:8], height)
+	ihdr[8] = 8 // profundidade de bits
+	ihdr[9] = 6 // RGBA
+
+	chunk := append([]byte("IHDR"), ihdr...)
+	binary.Write(&buf, binary.BigEndian, uint32(len(ihdr)))
+	buf.Write(chunk)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+
+	return buf.Bytes()
+}
+
+func TestDecodeImageInfoGeneratesThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 200, 100))); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+
+	info, err := DecodeImageInfo(buf.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeImageInfo: %v", err)
+	}
+	if info.Width != 200 || info.Height != 100 {
+		t.Errorf("dimensões = %dx%d, esperado 200x100", info.Width, info.Height)
+	}
+	if len(info.Thumbnail) == 0 {
+		t.Error("miniatura não foi gerada")
+	}
+}
+
+func TestDecodeImageInfoRejectsOversizedImage(t *testing.T) {
+	info, err := DecodeImageInfo(pngHeader(100000, 100000))
+	if err == nil {
+		t.Fatal("esperado erro para imagem acima do limite de pixels")
+	}
+	if info == nil || info.Width != 100000 || info.Height != 100000 {
+		t.Fatalf("dimensões do cabeçalho deveriam ser retornadas, obtido %+v", info)
+	}
+	if len(info.Thumbnail) != 0 {
+		t.Error("miniatura não deveria ser gerada")
+	}
+}
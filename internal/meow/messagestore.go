@@ -0,0 +1,180 @@
+package meow
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"zpigo/internal/logger"
+	"zpigo/internal/store/models"
+)
+
+// ErrMessageStoreDisabled indica que o armazenamento de mensagens está desativado no servidor
+var ErrMessageStoreDisabled = errors.New("armazenamento de mensagens desativado no servidor")
+
+// SetMessageStore habilita o armazenamento de mensagens no servidor e inicia a remoção periódica das
+// mensagens com mais de retention. Com o armazenamento desativado, a remoção ainda roda enquanto houver
+// mensagens gravadas antes, para que não fiquem guardadas para sempre. Retenção ou intervalo zero ou
+// negativos desativam a remoção. Deve ser chamado na inicialização, antes de conectar sessões.
+func (sm *SessionManager) SetMessageStore(enabled bool, retention, pruneInterval time.Duration) {
+	sm.messageStoreEnabled = enabled
+
+	if retention <= 0 || pruneInterval <= 0 {
+		if enabled {
+			sm.logger.Info("Armazenamento de mensagens habilitado sem limite de retenção")
+		}
+		return
+	}
+
+	if !enabled {
+		hasMessages, err := sm.messageRepo.HasAny(context.Background())
+		if err != nil {
+			sm.logger.Warn("Erro ao verificar mensagens armazenadas, mantendo a remoção por retenção", "error", err)
+		} else if !hasMessages {
+			return
+		}
+		sm.logger.Info("Armazenamento de mensagens desativado; mensagens já armazenadas seguem a retenção", "retention", retention)
+	}
+
+	sm.pairingMu.Lock()
+	if sm.prunerStop != nil {
+		sm.pairingMu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	sm.prunerStop = stop
+	sm.pairingMu.Unlock()
+
+	if enabled {
+		sm.logger.Info("Armazenamento de mensagens habilitado", "retention", retention, "pruneInterval", pruneInterval)
+	}
+
+	go func() {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+
+		sm.pruneMessages(retention)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				sm.pruneMessages(retention)
+			}
+		}
+	}()
+}
+
+// pruneMessages remove as mensagens de todas as sessões com mais de retention
+func (sm *SessionManager) pruneMessages(retention time.Duration) {
+	deleted, err := sm.messageRepo.DeleteOlderThan(context.Background(), time.Now().Add(-retention))
+	if err != nil {
+		sm.logger.Error("Erro ao remover mensagens fora da retenção", "error", err)
+	} else if deleted > 0 {
+		sm.logger.Info("Mensagens fora da retenção removidas", "total", deleted)
+	}
+}
+
+// stopMessagePruner interrompe a remoção periódica de mensagens, se estiver ativa
+func (sm *SessionManager) stopMessagePruner() {
+	sm.pairingMu.Lock()
+	defer sm.pairingMu.Unlock()
+
+	if sm.prunerStop != nil {
+		close(sm.prunerStop)
+		sm.prunerStop = nil
+	}
+}
+
+// MessageStoreEnabled indica se o servidor permite o armazenamento de mensagens
+func (sm *SessionManager) MessageStoreEnabled() bool {
+	return sm.messageStoreEnabled
+}
+
+// SetSessionMessageStorage grava a opção da sessão pelo armazenamento de mensagens e a aplica ao cliente
+// ativo. Habilitar com o armazenamento desativado no servidor retorna ErrMessageStoreDisabled; purge
+// apaga as mensagens já armazenadas da sessão.
+func (sm *SessionManager) SetSessionMessageStorage(ctx context.Context, sessionID string, enabled, purge bool) error {
+	if enabled && !sm.messageStoreEnabled {
+		return ErrMessageStoreDisabled
+	}
+
+	if err := sm.sessionRepo.UpdateStoreMessages(ctx, sessionID, enabled); err != nil {
+		return err
+	}
+
+	if zc, exists := sm.GetZPigoClient(sessionID); exists {
+		zc.SetStoreMessages(enabled)
+	}
+
+	if purge {
+		return sm.messageRepo.DeleteBySessionID(ctx, sessionID)
+	}
+	return nil
+}
+
+// loadMessageStorage aplica ao cliente a opção persistida da sessão pelo armazenamento de mensagens
+func (sm *SessionManager) loadMessageStorage(sessionID string, zc *ZPigoClient) {
+	if !sm.messageStoreEnabled {
+		return
+	}
+
+	session, err := sm.sessionRepo.GetByID(context.Background(), sessionID)
+	if err != nil {
+		sm.logger.Warn("Erro ao carregar opção de armazenamento de mensagens", "sessionID", sessionID, "error", err)
+		return
+	}
+	sm.applyMessageStorage(session, zc)
+}
+
+// applyMessageStorage aplica ao cliente a opção da sessão pelo armazenamento de mensagens
+func (sm *SessionManager) applyMessageStorage(session *models.Session, zc *ZPigoClient) {
+	if !sm.messageStoreEnabled {
+		return
+	}
+	zc.SetStoreMessages(session.StoreMessages)
+}
+
+// SetStoreMessages define se as mensagens da sessão são armazenadas
+func (zc *ZPigoClient) SetStoreMessages(enabled bool) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+	zc.storeMessages = enabled
+}
+
+// StoresMessages indica se as mensagens da sessão são armazenadas
+func (zc *ZPigoClient) StoresMessages() bool {
+	zc.mu.RLock()
+	defer zc.mu.RUnlock()
+	return zc.storeMessages
+}
+
+// storeMessage armazena a mensagem quando a sessão optou pelo armazenamento. Edições e revogações
+// (mensagens de protocolo) não são armazenadas.
+func (zc *ZPigoClient) storeMessage(evt *events.Message, content MessageContent) {
+	if zc.Messages == nil || !zc.StoresMessages() {
+		return
+	}
+	if evt.Info.Chat == types.StatusBroadcastJID || evt.Message.GetProtocolMessage() != nil {
+		return
+	}
+
+	message := &models.Message{
+		SessionID: zc.SessionID,
+		ChatJID:   evt.Info.Chat.ToNonAD().String(),
+		MessageID: evt.Info.ID,
+		SenderJID: evt.Info.Sender.ToNonAD().String(),
+		IsFromMe:  evt.Info.IsFromMe,
+		IsGroup:   evt.Info.IsGroup,
+		Type:      content.Type,
+		Text:      content.Body,
+		SentAt:    evt.Info.Timestamp,
+	}
+
+	if err := zc.Messages.Create(context.Background(), message); err != nil {
+		logger.WithComponent("EventHandler").Error("Erro ao armazenar mensagem", "sessionID", zc.SessionID, "messageID", evt.Info.ID, "error", err)
+	}
+}
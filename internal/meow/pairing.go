@@ -0,0 +1,94 @@
+package meow
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPairingLimitReached indica que o limite de emparelhamentos simultâneos foi atingido
+var ErrPairingLimitReached = errors.New("limite de emparelhamentos simultâneos atingido")
+
+// SetMaxConcurrentPairings define quantas sessões podem estar emparelhando via QR ao mesmo tempo.
+// Zero ou negativo desativa o limite.
+func (sm *SessionManager) SetMaxConcurrentPairings(limit int) {
+	sm.pairingMu.Lock()
+	defer sm.pairingMu.Unlock()
+	sm.maxPairings = limit
+}
+
+// PairingStats retorna quantos emparelhamentos estão em andamento e o limite configurado
+func (sm *SessionManager) PairingStats() (int, int) {
+	sm.pairingMu.Lock()
+	defer sm.pairingMu.Unlock()
+	return len(sm.pairings), sm.maxPairings
+}
+
+// acquirePairing reserva uma vaga de emparelhamento para a sessão. Uma sessão que já possui
+// vaga não consome outra.
+func (sm *SessionManager) acquirePairing(sessionID string) error {
+	sm.pairingMu.Lock()
+	defer sm.pairingMu.Unlock()
+
+	if _, exists := sm.pairings[sessionID]; exists {
+		return nil
+	}
+
+	if sm.maxPairings > 0 && len(sm.pairings) >= sm.maxPairings {
+		return ErrPairingLimitReached
+	}
+
+	sm.pairings[sessionID] = time.Now()
+	return nil
+}
+
+// releasePairing libera a vaga de emparelhamento da sessão, se houver
+func (sm *SessionManager) releasePairing(sessionID string) {
+	sm.pairingMu.Lock()
+	defer sm.pairingMu.Unlock()
+	delete(sm.pairings, sessionID)
+}
+
+// qrHandler guarda o contexto do goroutine que consome o canal QR de uma sessão
+type qrHandler struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// startQRHandler cria o contexto do handler de QR da sessão, cancelando um handler anterior
+// que ainda esteja ativo
+func (sm *SessionManager) startQRHandler(sessionID string) context.Context {
+	sm.pairingMu.Lock()
+	defer sm.pairingMu.Unlock()
+
+	if previous, exists := sm.qrHandlers[sessionID]; exists {
+		previous.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sm.qrHandlers[sessionID] = &qrHandler{ctx: ctx, cancel: cancel}
+	return ctx
+}
+
+// cancelQRHandler interrompe o handler de QR da sessão, se houver
+func (sm *SessionManager) cancelQRHandler(sessionID string) {
+	sm.pairingMu.Lock()
+	defer sm.pairingMu.Unlock()
+
+	if handler, exists := sm.qrHandlers[sessionID]; exists {
+		handler.cancel()
+		delete(sm.qrHandlers, sessionID)
+	}
+}
+
+// finishQRHandler remove o handler de QR quando o goroutine termina, preservando um handler
+// mais recente registrado para a mesma sessão
+func (sm *SessionManager) finishQRHandler(sessionID string, ctx context.Context) {
+	sm.pairingMu.Lock()
+	defer sm.pairingMu.Unlock()
+
+	if handler, exists := sm.qrHandlers[sessionID]; exists && handler.ctx == ctx {
+		handler.cancel()
+		delete(sm.qrHandlers, sessionID)
+	}
+}
@@ -0,0 +1,86 @@
+package meow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waAdv"
+	"go.mau.fi/whatsmeow/proto/waCompanionReg"
+	"go.mau.fi/whatsmeow/proto/waWa6"
+	"go.mau.fi/whatsmeow/store"
+	"google.golang.org/protobuf/proto"
+)
+
+// ParseDevicePlatform converte o nome da plataforma (ex: CHROME, DESKTOP, SAFARI) para o
+// DeviceProps_PlatformType do whatsmeow, aceitando apenas os valores definidos no protocolo
+func ParseDevicePlatform(name string) (waCompanionReg.DeviceProps_PlatformType, error) {
+	value, ok := waCompanionReg.DeviceProps_PlatformType_value[strings.ToUpper(strings.TrimSpace(name))]
+	if !ok {
+		return waCompanionReg.DeviceProps_UNKNOWN, fmt.Errorf("plataforma inválida: %s", name)
+	}
+
+	return waCompanionReg.DeviceProps_PlatformType(value), nil
+}
+
+// applyDevicePlatform ajusta as DeviceProps enviadas no registro do dispositivo, que definem como o
+// aparelho vinculado aparece no celular. As props globais do whatsmeow não são alteradas, pois são
+// compartilhadas por todas as sessões. Sem plataforma nem SO, o payload padrão é mantido.
+func applyDevicePlatform(client *whatsmeow.Client, platform, osName string) error {
+	if platform == "" && osName == "" {
+		client.GetClientPayload = nil
+		return nil
+	}
+
+	var platformType *waCompanionReg.DeviceProps_PlatformType
+	if platform != "" {
+		parsed, err := ParseDevicePlatform(platform)
+		if err != nil {
+			return err
+		}
+		platformType = parsed.Enum()
+	}
+
+	client.GetClientPayload = func() *waWa6.ClientPayload {
+		payload := client.Store.GetClientPayload()
+		if payload.DevicePairingData == nil {
+			return payload
+		}
+
+		props := &waCompanionReg.DeviceProps{}
+		if err := proto.Unmarshal(payload.DevicePairingData.DeviceProps, props); err != nil {
+			return payload
+		}
+
+		if platformType != nil {
+			props.PlatformType = platformType
+		}
+		if osName != "" {
+			props.Os = proto.String(osName)
+		}
+
+		if encoded, err := proto.Marshal(props); err == nil {
+			payload.DevicePairingData.DeviceProps = encoded
+		}
+
+		return payload
+	}
+
+	return nil
+}
+
+// DevicePairedAt extrai o momento do emparelhamento da identidade assinada do dispositivo (ADVDeviceIdentity),
+// retornando false quando o dispositivo ainda não foi emparelhado
+func DevicePairedAt(device *store.Device) (time.Time, bool) {
+	if device == nil || device.Account == nil || len(device.Account.GetDetails()) == 0 {
+		return time.Time{}, false
+	}
+
+	var identity waAdv.ADVDeviceIdentity
+	if err := proto.Unmarshal(device.Account.GetDetails(), &identity); err != nil || identity.GetTimestamp() == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(identity.GetTimestamp()), 0), true
+}
@@ -0,0 +1,110 @@
+package meow
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"golang.org/x/net/proxy"
+)
+
+// DefaultProxyCheckTimeout limita o tempo da verificação de alcance do proxy
+const DefaultProxyCheckTimeout = 10 * time.Second
+
+// proxyCheckTarget é o endereço alcançado através do proxy na verificação, o mesmo do websocket do WhatsApp
+const proxyCheckTarget = "web.whatsapp.com:443"
+
+// applyProxy configura o proxy do websocket e das mídias (upload e download) do cliente whatsmeow.
+// URLs socks5 usam um dialer com as credenciais embutidas na URL; proxyURL vazio remove o proxy.
+// Precisa ser chamado antes de Connect para valer na conexão atual.
+func applyProxy(client *whatsmeow.Client, proxyURL string) error {
+	return client.SetProxyAddress(proxyURL)
+}
+
+// ValidateProxy verifica se o proxy está acessível abrindo uma conexão até o WhatsApp através dele
+// (handshake SOCKS5 ou CONNECT HTTP), o que também valida usuário e senha.
+func ValidateProxy(ctx context.Context, proxyURL string) error {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("URL de proxy inválida: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultProxyCheckTimeout)
+	defer cancel()
+
+	dialer := &net.Dialer{Timeout: DefaultProxyCheckTimeout}
+
+	var conn net.Conn
+	switch parsed.Scheme {
+	case "socks5":
+		socksDialer, err := proxy.FromURL(parsed, dialer)
+		if err != nil {
+			return fmt.Errorf("erro ao criar dialer SOCKS5: %w", err)
+		}
+		contextDialer, ok := socksDialer.(proxy.ContextDialer)
+		if !ok {
+			return fmt.Errorf("dialer SOCKS5 não suporta contexto")
+		}
+		conn, err = contextDialer.DialContext(ctx, "tcp", proxyCheckTarget)
+		if err != nil {
+			return fmt.Errorf("proxy SOCKS5 inacessível: %w", err)
+		}
+	case "http", "https":
+		conn, err = dialer.DialContext(ctx, "tcp", parsed.Host)
+		if err != nil {
+			return fmt.Errorf("proxy HTTP inacessível: %w", err)
+		}
+		if err := httpConnectHandshake(ctx, conn, parsed); err != nil {
+			conn.Close()
+			return err
+		}
+	default:
+		return fmt.Errorf("tipo de proxy não suportado: %s", parsed.Scheme)
+	}
+
+	return conn.Close()
+}
+
+// httpConnectHandshake pede ao proxy HTTP um túnel até proxyCheckTarget e exige resposta 200
+func httpConnectHandshake(ctx context.Context, conn net.Conn, proxyURL *url.URL) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: proxyCheckTarget},
+		Host:   proxyCheckTarget,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("proxy HTTP inacessível: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("proxy HTTP não respondeu ao CONNECT: %w", err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusProxyAuthRequired:
+		return fmt.Errorf("proxy HTTP recusou as credenciais (%s)", resp.Status)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("proxy HTTP recusou o túnel até %s (%s)", proxyCheckTarget, resp.Status)
+	}
+
+	return nil
+}
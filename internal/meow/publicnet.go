@@ -0,0 +1,122 @@
+package meow
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ErrNonPublicAddress indica um destino em rede privada, loopback, link-local ou outro endereço
+// que não é roteável pela internet
+var ErrNonPublicAddress = errors.New("endereço de destino não é público")
+
+// sharedAddressSpace é a faixa 100.64.0.0/10 (CGNAT), que net.IP.IsPrivate não cobre
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// NewPublicHTTPClient cria um cliente como NewHTTPClient que só alcança endereços públicos, para
+// buscar URLs informadas pelo usuário sem expor serviços internos (SSRF). O host da requisição e de
+// cada redirecionamento é resolvido e verificado antes do envio. Sem proxy, a verificação também é
+// feita no IP efetivamente conectado, o que cobre DNS que muda de resposta entre a checagem e a
+// conexão; com proxy, quem conecta ao destino é o proxy e vale apenas a checagem do host.
+func NewPublicHTTPClient(proxyURL string) *resty.Client {
+	client := NewHTTPClient()
+
+	if proxyURL == "" {
+		dialer := &net.Dialer{
+			Timeout:   DefaultHTTPTimeout,
+			KeepAlive: 30 * time.Second,
+			Control:   publicAddressControl,
+		}
+		client.SetTransport(&http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		})
+	} else {
+		client.SetProxy(proxyURL)
+	}
+
+	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		target, err := url.Parse(req.URL)
+		if err != nil {
+			return err
+		}
+		return CheckPublicHost(req.Context(), target.Hostname())
+	})
+	client.SetRedirectPolicy(
+		resty.FlexibleRedirectPolicy(15),
+		resty.RedirectPolicyFunc(func(req *http.Request, _ []*http.Request) error {
+			return CheckPublicHost(req.Context(), req.URL.Hostname())
+		}),
+	)
+
+	return client
+}
+
+// CheckPublicHost resolve o host e retorna ErrNonPublicAddress se algum dos endereços não for público
+func CheckPublicHost(ctx context.Context, host string) error {
+	if host == "" {
+		return fmt.Errorf("%w: host vazio", ErrNonPublicAddress)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return checkPublicIP(ip)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("erro ao resolver %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := checkPublicIP(addr.IP); err != nil {
+			return fmt.Errorf("%s: %w", host, err)
+		}
+	}
+
+	return nil
+}
+
+// publicAddressControl recusa a conexão quando o IP resolvido pelo dialer não é público
+func publicAddressControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, address)
+	}
+	return checkPublicIP(ip)
+}
+
+func checkPublicIP(ip net.IP) error {
+	if !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, ip)
+	}
+	return nil
+}
+
+// IsPublicIP indica se o IP é roteável pela internet: exclui loopback, redes privadas, link-local,
+// CGNAT, multicast e o endereço não especificado
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip))
+}
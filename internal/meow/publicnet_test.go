@@ -0,0 +1,69 @@
+package meow
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("IsPublicIP(%s) = %v, esperado %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPublicHostRejectsLiteralPrivateIP(t *testing.T) {
+	for _, host := range []string{"127.0.0.1", "169.254.169.254", "::1", ""} {
+		if err := CheckPublicHost(context.Background(), host); !errors.Is(err, ErrNonPublicAddress) {
+			t.Errorf("CheckPublicHost(%q) = %v, esperado ErrNonPublicAddress", host, err)
+		}
+	}
+}
+
+func TestPublicHTTPClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := NewPublicHTTPClient("").R().Get(server.URL)
+	if !errors.Is(err, ErrNonPublicAddress) {
+		t.Fatalf("erro = %v, esperado ErrNonPublicAddress", err)
+	}
+}
+
+func TestPublicAddressControlChecksDialedIP(t *testing.T) {
+	if err := publicAddressControl("tcp", "127.0.0.1:80", nil); !errors.Is(err, ErrNonPublicAddress) {
+		t.Errorf("loopback: erro = %v, esperado ErrNonPublicAddress", err)
+	}
+	if err := publicAddressControl("tcp", "8.8.8.8:443", nil); err != nil {
+		t.Errorf("IP público: erro inesperado %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package meow
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// SendRateLimiter limita o envio de mensagens por sessão usando um token bucket. Cada sessão
+// acumula até perMinute tokens, repostos continuamente ao longo de um minuto.
+type SendRateLimiter struct {
+	mu        sync.Mutex
+	perMinute int
+	buckets   map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+func NewSendRateLimiter(perMinute int) *SendRateLimiter {
+	return &SendRateLimiter{
+		perMinute: perMinute,
+		buckets:   make(map[string]*tokenBucket),
+	}
+}
+
+// SetLimit altera o limite de mensagens por minuto. Zero ou negativo desativa o limite.
+func (l *SendRateLimiter) SetLimit(perMinute int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.perMinute = perMinute
+	l.buckets = make(map[string]*tokenBucket)
+}
+
+// Allow consome um token da sessão. Quando não há token disponível retorna false e o tempo
+// até o próximo token ser reposto.
+func (l *SendRateLimiter) Allow(sessionID string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.perMinute <= 0 {
+		return true, 0
+	}
+
+	now := time.Now()
+	capacity := float64(l.perMinute)
+	ratePerSecond := capacity / 60
+
+	bucket, exists := l.buckets[sessionID]
+	if !exists {
+		bucket = &tokenBucket{tokens: capacity, lastRefill: now}
+		l.buckets[sessionID] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastRefill).Seconds()
+	bucket.tokens = math.Min(capacity, bucket.tokens+elapsed*ratePerSecond)
+	bucket.lastRefill = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / ratePerSecond * float64(time.Second))
+		return false, wait
+	}
+
+	bucket.tokens--
+	return true, 0
+}
+
+// Remove descarta o estado da sessão
+func (l *SendRateLimiter) Remove(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.buckets, sessionID)
+}
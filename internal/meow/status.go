@@ -0,0 +1,217 @@
+package meow
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	// messageStatusTTL define por quanto tempo o status de uma mensagem permanece disponível
+	messageStatusTTL = 72 * time.Hour
+	// messageStatusMaxEntries limita a quantidade de mensagens acompanhadas por sessão
+	messageStatusMaxEntries = 10000
+)
+
+// MessageStatus guarda quando a mensagem foi entregue, lida e reproduzida pela primeira vez.
+// Em grupos, cada horário corresponde ao primeiro participante que enviou o recibo.
+type MessageStatus struct {
+	MessageID   string
+	Chat        types.JID
+	SentAt      time.Time
+	DeliveredAt time.Time
+	ReadAt      time.Time
+	PlayedAt    time.Time
+	UpdatedAt   time.Time
+
+	// Participants traz os recibos de cada destinatário; preenchido apenas nas mensagens enviadas
+	// com recibos detalhados (ver Track)
+	Participants map[types.JID]ParticipantStatus
+
+	firstSeen time.Time
+	detailed  bool
+}
+
+// ParticipantStatus guarda os recibos de um destinatário da mensagem
+type ParticipantStatus struct {
+	DeliveredAt time.Time
+	ReadAt      time.Time
+	PlayedAt    time.Time
+}
+
+// Status retorna o estado mais avançado conhecido: played, read, delivered, sent (aceita pelo
+// servidor, sem recibo ainda) ou pending
+func (s *MessageStatus) Status() string {
+	switch {
+	case !s.PlayedAt.IsZero():
+		return "played"
+	case !s.ReadAt.IsZero():
+		return "read"
+	case !s.DeliveredAt.IsZero():
+		return "delivered"
+	case !s.SentAt.IsZero():
+		return "sent"
+	default:
+		return "pending"
+	}
+}
+
+// MessageStatusTracker acompanha, em memória, os recibos das mensagens enviadas pela sessão.
+// As entradas expiram após messageStatusTTL e as mais antigas são descartadas ao passar de
+// messageStatusMaxEntries.
+type MessageStatusTracker struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func NewMessageStatusTracker() *MessageStatusTracker {
+	return &MessageStatusTracker{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Record aplica o recibo às mensagens referenciadas. Recibos da própria conta (read-self,
+// played-self) e de controle (retry, sender etc.) são ignorados.
+func (t *MessageStatusTracker) Record(evt *events.Receipt) {
+	switch evt.Type {
+	case types.ReceiptTypeDelivered, types.ReceiptTypeRead, types.ReceiptTypePlayed:
+	default:
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for _, messageID := range evt.MessageIDs {
+		var status *MessageStatus
+		if element, exists := t.entries[messageID]; exists {
+			status = element.Value.(*MessageStatus)
+		} else {
+			status = &MessageStatus{MessageID: messageID, Chat: evt.Chat, firstSeen: now}
+			t.entries[messageID] = t.order.PushBack(status)
+		}
+
+		applyReceipt(&status.DeliveredAt, &status.ReadAt, &status.PlayedAt, evt)
+		if status.detailed && !evt.Sender.IsEmpty() {
+			sender := evt.Sender.ToNonAD()
+			participant := status.Participants[sender]
+			applyReceipt(&participant.DeliveredAt, &participant.ReadAt, &participant.PlayedAt, evt)
+			status.Participants[sender] = participant
+		}
+		status.UpdatedAt = now
+	}
+
+	t.evict(now)
+}
+
+// Track passa a acompanhar os recibos de cada destinatário da mensagem. Deve ser chamado antes do
+// envio, já que os primeiros recibos podem chegar antes de SendMessage retornar.
+func (t *MessageStatusTracker) Track(messageID string, chat types.JID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if element, exists := t.entries[messageID]; exists {
+		status := element.Value.(*MessageStatus)
+		if !status.detailed {
+			status.detailed = true
+			status.Participants = make(map[types.JID]ParticipantStatus)
+		}
+		return
+	}
+
+	status := &MessageStatus{
+		MessageID:    messageID,
+		Chat:         chat,
+		UpdatedAt:    now,
+		Participants: make(map[types.JID]ParticipantStatus),
+		firstSeen:    now,
+		detailed:     true,
+	}
+	t.entries[messageID] = t.order.PushBack(status)
+
+	t.evict(now)
+}
+
+// RecordSent registra o ack do servidor para uma mensagem acompanhada por Track
+func (t *MessageStatusTracker) RecordSent(messageID string, sentAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if element, exists := t.entries[messageID]; exists {
+		status := element.Value.(*MessageStatus)
+		setFirst(&status.SentAt, sentAt)
+		status.UpdatedAt = time.Now()
+	}
+}
+
+// Forget descarta a mensagem, usado quando o envio falha depois de Track
+func (t *MessageStatusTracker) Forget(messageID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if element, exists := t.entries[messageID]; exists {
+		t.order.Remove(element)
+		delete(t.entries, messageID)
+	}
+}
+
+// Get retorna uma cópia do status conhecido da mensagem
+func (t *MessageStatusTracker) Get(messageID string) (MessageStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.evict(time.Now())
+
+	element, exists := t.entries[messageID]
+	if !exists {
+		return MessageStatus{}, false
+	}
+
+	status := *element.Value.(*MessageStatus)
+	if status.Participants != nil {
+		participants := make(map[types.JID]ParticipantStatus, len(status.Participants))
+		for jid, participant := range status.Participants {
+			participants[jid] = participant
+		}
+		status.Participants = participants
+	}
+	return status, true
+}
+
+// evict remove as entradas expiradas e as mais antigas acima do limite. Deve ser chamado com mu travado.
+func (t *MessageStatusTracker) evict(now time.Time) {
+	for front := t.order.Front(); front != nil; front = t.order.Front() {
+		status := front.Value.(*MessageStatus)
+		if t.order.Len() <= messageStatusMaxEntries && now.Sub(status.firstSeen) <= messageStatusTTL {
+			return
+		}
+		t.order.Remove(front)
+		delete(t.entries, status.MessageID)
+	}
+}
+
+// applyReceipt aplica o recibo aos horários de entrega, leitura e reprodução
+func applyReceipt(deliveredAt, readAt, playedAt *time.Time, evt *events.Receipt) {
+	switch evt.Type {
+	case types.ReceiptTypePlayed:
+		setFirst(playedAt, evt.Timestamp)
+		setFirst(readAt, evt.Timestamp)
+	case types.ReceiptTypeRead:
+		setFirst(readAt, evt.Timestamp)
+	}
+	// Leitura implica entrega, mesmo que o recibo de entrega não tenha chegado
+	setFirst(deliveredAt, evt.Timestamp)
+}
+
+func setFirst(field *time.Time, value time.Time) {
+	if field.IsZero() || value.Before(*field) {
+		*field = value
+	}
+}
@@ -0,0 +1,74 @@
+package meow
+
+import (
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func groupReceipt(messageID string, sender types.JID, receiptType types.ReceiptType, at time.Time) *events.Receipt {
+	return &events.Receipt{
+		MessageSource: types.MessageSource{
+			Chat:    types.NewJID("120363012345678901", types.GroupServer),
+			Sender:  sender,
+			IsGroup: true,
+		},
+		MessageIDs: []types.MessageID{messageID},
+		Timestamp:  at,
+		Type:       receiptType,
+	}
+}
+
+func TestMessageStatusTrackerDetailedReceipts(t *testing.T) {
+	tracker := NewMessageStatusTracker()
+	group := types.NewJID("120363012345678901", types.GroupServer)
+	alice := types.NewJID("5511900000001", types.DefaultUserServer)
+	bob := types.NewJID("5511900000002", types.DefaultUserServer)
+	base := time.Unix(1_700_000_000, 0)
+
+	tracker.Track("detailed", group)
+	if status, _ := tracker.Get("detailed"); status.Status() != "pending" {
+		t.Errorf("status antes do ack = %s, esperado pending", status.Status())
+	}
+
+	tracker.RecordSent("detailed", base)
+	tracker.Record(groupReceipt("detailed", alice, types.ReceiptTypeDelivered, base.Add(time.Second)))
+	tracker.Record(groupReceipt("detailed", types.NewADJID(bob.User, 0, 3), types.ReceiptTypeDelivered, base.Add(2*time.Second)))
+	tracker.Record(groupReceipt("detailed", alice, types.ReceiptTypeRead, base.Add(3*time.Second)))
+
+	status, found := tracker.Get("detailed")
+	if !found {
+		t.Fatal("mensagem acompanhada não encontrada")
+	}
+	if status.Status() != "read" || !status.SentAt.Equal(base) {
+		t.Errorf("status = %s, sentAt = %s", status.Status(), status.SentAt)
+	}
+	if len(status.Participants) != 2 {
+		t.Fatalf("participantes = %v, esperado alice e bob", status.Participants)
+	}
+	if p := status.Participants[alice]; !p.DeliveredAt.Equal(base.Add(time.Second)) || !p.ReadAt.Equal(base.Add(3*time.Second)) {
+		t.Errorf("recibos de alice = %+v", p)
+	}
+	if p := status.Participants[bob]; !p.DeliveredAt.Equal(base.Add(2*time.Second)) || !p.ReadAt.IsZero() {
+		t.Errorf("recibos de bob = %+v", p)
+	}
+
+	// Get devolve uma cópia: alterá-la não afeta o acompanhamento
+	delete(status.Participants, alice)
+	if again, _ := tracker.Get("detailed"); len(again.Participants) != 2 {
+		t.Error("Get expôs o mapa interno de participantes")
+	}
+
+	// Mensagens sem Track guardam apenas o agregado
+	tracker.Record(groupReceipt("plain", alice, types.ReceiptTypeDelivered, base))
+	if plain, _ := tracker.Get("plain"); plain.Participants != nil || plain.Status() != "delivered" {
+		t.Errorf("mensagem sem recibos detalhados = %+v", plain)
+	}
+
+	tracker.Forget("detailed")
+	if _, found := tracker.Get("detailed"); found {
+		t.Error("mensagem esquecida continua acompanhada")
+	}
+}
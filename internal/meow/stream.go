@@ -0,0 +1,140 @@
+package meow
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"zpigo/internal/webhook"
+)
+
+// DefaultStreamBufferSize é quantos eventos cada assinante do stream acumula antes de começar a
+// descartar eventos, para que um cliente lento não bloqueie o processamento dos eventos da sessão
+const DefaultStreamBufferSize = 64
+
+// EventStream distribui os eventos das sessões para os assinantes conectados (ex: streams SSE). Fica no
+// SessionManager, e não no ZPigoClient, para que as assinaturas sobrevivam às reconexões da sessão.
+type EventStream struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*StreamSubscription]struct{}
+}
+
+// StreamSubscription é a assinatura de um cliente aos eventos de uma sessão
+type StreamSubscription struct {
+	sessionID string
+	events    map[webhook.EventType]bool
+	ch        chan *webhook.Payload
+	dropped   atomic.Int64
+}
+
+func NewEventStream() *EventStream {
+	return &EventStream{
+		subscribers: make(map[string]map[*StreamSubscription]struct{}),
+	}
+}
+
+// Events retorna o canal de eventos da assinatura, fechado quando a assinatura é cancelada
+func (s *StreamSubscription) Events() <-chan *webhook.Payload {
+	return s.ch
+}
+
+// Dropped retorna quantos eventos foram descartados porque o assinante não os consumiu a tempo
+func (s *StreamSubscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Subscribe assina os eventos da sessão. Com events vazio todos os eventos são entregues. A assinatura
+// precisa ser cancelada com Unsubscribe quando o cliente desconectar.
+func (es *EventStream) Subscribe(sessionID string, events []webhook.EventType) *StreamSubscription {
+	sub := &StreamSubscription{
+		sessionID: sessionID,
+		ch:        make(chan *webhook.Payload, DefaultStreamBufferSize),
+	}
+	if len(events) > 0 {
+		sub.events = make(map[webhook.EventType]bool, len(events))
+		for _, event := range events {
+			sub.events[event] = true
+		}
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if es.subscribers[sessionID] == nil {
+		es.subscribers[sessionID] = make(map[*StreamSubscription]struct{})
+	}
+	es.subscribers[sessionID][sub] = struct{}{}
+
+	return sub
+}
+
+// Unsubscribe cancela a assinatura e fecha seu canal. Pode ser chamado mais de uma vez.
+func (es *EventStream) Unsubscribe(sub *StreamSubscription) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	subs, exists := es.subscribers[sub.sessionID]
+	if !exists {
+		return
+	}
+	if _, subscribed := subs[sub]; !subscribed {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.ch)
+	if len(subs) == 0 {
+		delete(es.subscribers, sub.sessionID)
+	}
+}
+
+// Publish entrega o evento aos assinantes da sessão sem bloquear: assinantes com o buffer cheio perdem
+// o evento
+func (es *EventStream) Publish(payload *webhook.Payload) {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+
+	for sub := range es.subscribers[payload.SessionID] {
+		if sub.events != nil && !sub.events[webhook.EventType(payload.Type)] {
+			continue
+		}
+
+		select {
+		case sub.ch <- payload:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// SubscriberCount retorna quantos assinantes a sessão tem
+func (es *EventStream) SubscriberCount(sessionID string) int {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+
+	return len(es.subscribers[sessionID])
+}
+
+// CloseSession cancela todas as assinaturas da sessão, encerrando os streams abertos
+func (es *EventStream) CloseSession(sessionID string) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	for sub := range es.subscribers[sessionID] {
+		close(sub.ch)
+	}
+	delete(es.subscribers, sessionID)
+}
+
+// CloseAll cancela as assinaturas de todas as sessões; usado no encerramento da aplicação para que os
+// streams abertos não segurem o desligamento do servidor HTTP
+func (es *EventStream) CloseAll() {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	for sessionID, subs := range es.subscribers {
+		for sub := range subs {
+			close(sub.ch)
+		}
+		delete(es.subscribers, sessionID)
+	}
+}
@@ -0,0 +1,80 @@
+package meow
+
+import (
+	"context"
+	"time"
+)
+
+// StartQRSweeper inicia a limpeza periódica de QR codes expirados. Um intervalo zero ou
+// negativo desativa a limpeza.
+func (sm *SessionManager) StartQRSweeper(interval time.Duration) {
+	if interval <= 0 {
+		sm.logger.Info("Limpeza de QR codes expirados desativada")
+		return
+	}
+
+	sm.pairingMu.Lock()
+	if sm.sweeperStop != nil {
+		sm.pairingMu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	sm.sweeperStop = stop
+	sm.pairingMu.Unlock()
+
+	sm.logger.Info("Limpeza de QR codes expirados iniciada", "interval", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if _, err := sm.SweepStaleQRCodes(context.Background()); err != nil {
+					sm.logger.Error("Erro na limpeza de QR codes expirados", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// stopQRSweeper interrompe a limpeza periódica, se estiver ativa
+func (sm *SessionManager) stopQRSweeper() {
+	sm.pairingMu.Lock()
+	defer sm.pairingMu.Unlock()
+
+	if sm.sweeperStop != nil {
+		close(sm.sweeperStop)
+		sm.sweeperStop = nil
+	}
+}
+
+// SweepStaleQRCodes volta para disconnected as sessões presas em connecting com QR expirado há
+// mais de DefaultQRSweepGrace, liberando o handler de QR e a vaga de emparelhamento que restarem.
+// Retorna os IDs das sessões limpas.
+func (sm *SessionManager) SweepStaleQRCodes(ctx context.Context) ([]string, error) {
+	swept, err := sm.sessionRepo.ClearStaleQRCodes(ctx, time.Now().Add(-DefaultQRSweepGrace))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sessionID := range swept {
+		sm.cancelQRHandler(sessionID)
+		sm.releasePairing(sessionID)
+
+		if client, exists := sm.GetSession(sessionID); exists && client.IsConnected() && !client.IsLoggedIn() {
+			client.Disconnect()
+		}
+
+		sm.logger.Info("QR code expirado removido", "sessionID", sessionID)
+	}
+
+	if len(swept) > 0 {
+		sm.logger.Info("Limpeza de QR codes expirados concluída", "total", len(swept))
+	}
+
+	return swept, nil
+}
@@ -0,0 +1,108 @@
+package meow
+
+import (
+	"context"
+
+	"go.mau.fi/whatsmeow/types"
+
+	"zpigo/internal/logger"
+)
+
+// SessionCleanup informa o que foi removido por TeardownSession
+type SessionCleanup struct {
+	BroadcastsStopped    int
+	LoggedOut            bool
+	DeviceDeleted        bool
+	ManagerEntryRemoved  bool
+	WebhookConfigRemoved bool
+	WebhooksDeleted      bool
+	MessageDataDeleted   bool
+	CacheEntriesEvicted  int
+}
+
+// TeardownSession remove tudo o que a sessão deixa fora da tabela sessions: interrompe os broadcasts
+// em execução, faz logout no WhatsApp quando logada, apaga o device do container do whatsmeow, remove
+// o cliente do gerenciador, a configuração e as linhas de webhook, o histórico de edições e recibos, os
+// chats, as mensagens armazenadas e as entradas de cache.
+// deviceJid é o device persistido na sessão, usado quando o cliente não está carregado. Falhas em uma
+// etapa são registradas e não interrompem as demais; a linha da sessão fica a cargo de quem chama.
+func (sm *SessionManager) TeardownSession(ctx context.Context, sessionID, deviceJid string) *SessionCleanup {
+	cleanup := &SessionCleanup{}
+	log := sm.logger.With("sessionID", sessionID)
+
+	// Os runners precisam terminar antes da remoção, senão continuam gravando progresso da sessão
+	cleanup.BroadcastsStopped = sm.stopSessionBroadcasts(sessionID)
+
+	if client, exists := sm.GetSession(sessionID); exists {
+		if client.Store.ID != nil {
+			deviceJid = client.Store.ID.String()
+		}
+		if client.IsLoggedIn() {
+			// Logout também apaga o device do container
+			if err := client.Logout(ctx); err != nil {
+				log.Warn("Erro ao fazer logout na remoção da sessão", "error", err)
+			} else {
+				cleanup.LoggedOut = true
+				cleanup.DeviceDeleted = true
+			}
+		}
+		if err := sm.DeleteSession(sessionID); err != nil {
+			log.Warn("Erro ao remover sessão do manager", "error", err)
+		} else {
+			cleanup.ManagerEntryRemoved = true
+		}
+	}
+
+	if !cleanup.DeviceDeleted && deviceJid != "" {
+		cleanup.DeviceDeleted = sm.deleteSessionDevice(ctx, deviceJid, log)
+	}
+
+	sm.RemoveWebhookConfig(sessionID)
+	cleanup.WebhookConfigRemoved = true
+
+	if err := sm.webhookRepo.DeleteBySessionID(ctx, sessionID); err != nil {
+		log.Warn("Erro ao remover webhooks da sessão", "error", err)
+	} else {
+		cleanup.WebhooksDeleted = true
+	}
+
+	editErr := sm.messageEditRepo.DeleteBySessionID(ctx, sessionID)
+	receiptErr := sm.receiptRepo.DeleteBySessionID(ctx, sessionID)
+	chatErr := sm.chatRepo.DeleteBySessionID(ctx, sessionID)
+	messageErr := sm.messageRepo.DeleteBySessionID(ctx, sessionID)
+	if editErr != nil || receiptErr != nil || chatErr != nil || messageErr != nil {
+		log.Warn("Erro ao remover histórico de mensagens da sessão",
+			"editsError", editErr,
+			"receiptsError", receiptErr,
+			"chatsError", chatErr,
+			"messagesError", messageErr,
+		)
+	} else {
+		cleanup.MessageDataDeleted = true
+	}
+
+	cleanup.CacheEntriesEvicted = sm.cacheManager.DeleteSessionInfoByID(sessionID)
+
+	return cleanup
+}
+
+// deleteSessionDevice apaga do container o device persistido na sessão; retorna se ele foi removido
+func (sm *SessionManager) deleteSessionDevice(ctx context.Context, deviceJid string, log logger.Logger) bool {
+	jid, err := types.ParseJID(deviceJid)
+	if err != nil {
+		log.Warn("deviceJid inválido, device não removido", "deviceJid", deviceJid, "error", err)
+		return false
+	}
+
+	device, err := sm.container.GetDevice(ctx, jid)
+	if err != nil || device == nil {
+		return false
+	}
+
+	if err := sm.container.DeleteDevice(ctx, device); err != nil {
+		log.Warn("Erro ao remover device do container", "deviceJid", deviceJid, "error", err)
+		return false
+	}
+
+	return true
+}
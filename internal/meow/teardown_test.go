@@ -0,0 +1,162 @@
+package meow
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"zpigo/internal/store"
+	"zpigo/internal/store/models"
+)
+
+// blockingBroadcastRepo mantém o runner parado em NextPendingRecipients até o cancelamento e
+// registra as escritas feitas depois dele
+type blockingBroadcastRepo struct {
+	store.BroadcastRepositoryInterface
+
+	mu       sync.Mutex
+	started  map[string]chan struct{}
+	finished map[string]bool
+}
+
+func newBlockingBroadcastRepo(jobIDs ...string) *blockingBroadcastRepo {
+	r := &blockingBroadcastRepo{started: make(map[string]chan struct{}), finished: make(map[string]bool)}
+	for _, id := range jobIDs {
+		r.started[id] = make(chan struct{})
+	}
+	return r
+}
+
+func (r *blockingBroadcastRepo) UpdateStatus(ctx context.Context, id string, status models.BroadcastStatus, lastError string) error {
+	return nil
+}
+
+func (r *blockingBroadcastRepo) NextPendingRecipients(ctx context.Context, jobID string, limit int) ([]*models.BroadcastRecipient, error) {
+	close(r.started[jobID])
+	<-ctx.Done()
+
+	// Um runner que demora a sair evidencia quem não espera por ele
+	time.Sleep(50 * time.Millisecond)
+
+	r.mu.Lock()
+	r.finished[jobID] = true
+	r.mu.Unlock()
+
+	return nil, ctx.Err()
+}
+
+func (r *blockingBroadcastRepo) isFinished(jobID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.finished[jobID]
+}
+
+// sessionDataDeletes conta as remoções por sessão e falha se alguma ocorrer com o runner ativo
+type sessionDataDeletes struct {
+	t       *testing.T
+	repo    *blockingBroadcastRepo
+	jobID   string
+	deletes int
+}
+
+func (d *sessionDataDeletes) record() error {
+	if !d.repo.isFinished(d.jobID) {
+		d.t.Error("dados da sessão removidos com o broadcast ainda em execução")
+	}
+	d.deletes++
+	return nil
+}
+
+type fakeWebhookDeleteRepo struct {
+	store.WebhookRepositoryInterface
+	*sessionDataDeletes
+}
+
+func (r *fakeWebhookDeleteRepo) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	return r.record()
+}
+
+type fakeEditDeleteRepo struct {
+	store.MessageEditRepositoryInterface
+	*sessionDataDeletes
+}
+
+func (r *fakeEditDeleteRepo) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	return r.record()
+}
+
+type fakeReceiptDeleteRepo struct {
+	store.MessageReceiptRepositoryInterface
+	*sessionDataDeletes
+}
+
+func (r *fakeReceiptDeleteRepo) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	return r.record()
+}
+
+type fakeChatDeleteRepo struct {
+	store.ChatRepositoryInterface
+	*sessionDataDeletes
+}
+
+func (r *fakeChatDeleteRepo) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	return r.record()
+}
+
+type fakeMessageDeleteRepo struct {
+	store.MessageRepositoryInterface
+	*sessionDataDeletes
+}
+
+func (r *fakeMessageDeleteRepo) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	return r.record()
+}
+
+func TestTeardownSessionStopsBroadcastsBeforeDeleting(t *testing.T) {
+	repo := newBlockingBroadcastRepo("job-a", "job-b")
+	deletes := &sessionDataDeletes{t: t, repo: repo, jobID: "job-a"}
+
+	sm := NewSessionManager(nil, nil, nil)
+	defer sm.GetWebhookManager().Stop()
+	defer sm.stopBroadcasts()
+
+	sm.broadcastRepo = repo
+	sm.webhookRepo = &fakeWebhookDeleteRepo{sessionDataDeletes: deletes}
+	sm.messageEditRepo = &fakeEditDeleteRepo{sessionDataDeletes: deletes}
+	sm.receiptRepo = &fakeReceiptDeleteRepo{sessionDataDeletes: deletes}
+	sm.chatRepo = &fakeChatDeleteRepo{sessionDataDeletes: deletes}
+	sm.messageRepo = &fakeMessageDeleteRepo{sessionDataDeletes: deletes}
+
+	sm.StartBroadcast(&models.BroadcastJob{ID: "job-a", SessionID: "session-a"})
+	sm.StartBroadcast(&models.BroadcastJob{ID: "job-b", SessionID: "session-b"})
+	<-repo.started["job-a"]
+	<-repo.started["job-b"]
+
+	cleanup := sm.TeardownSession(context.Background(), "session-a", "")
+
+	if cleanup.BroadcastsStopped != 1 {
+		t.Errorf("BroadcastsStopped = %d, esperado 1", cleanup.BroadcastsStopped)
+	}
+	if !repo.isFinished("job-a") {
+		t.Error("TeardownSession retornou antes do broadcast da sessão terminar")
+	}
+	if deletes.deletes != 5 {
+		t.Errorf("remoções = %d, esperado 5", deletes.deletes)
+	}
+	if !cleanup.WebhooksDeleted || !cleanup.MessageDataDeleted {
+		t.Errorf("limpeza incompleta: %+v", cleanup)
+	}
+
+	sm.broadcastMu.Lock()
+	_, aRunning := sm.broadcasts["job-a"]
+	_, bRunning := sm.broadcasts["job-b"]
+	sm.broadcastMu.Unlock()
+
+	if aRunning {
+		t.Error("broadcast da sessão removida continua registrado")
+	}
+	if !bRunning || repo.isFinished("job-b") {
+		t.Error("broadcast de outra sessão não deveria ser interrompido")
+	}
+}
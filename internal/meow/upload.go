@@ -0,0 +1,185 @@
+package meow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+
+	"zpigo/internal/logger"
+)
+
+// Parâmetros do retry de upload de mídia: até UploadMaxAttempts tentativas, com intervalo dobrando a
+// partir de uploadInitialBackoff até uploadMaxBackoff
+const (
+	UploadMaxAttempts    = 3
+	uploadInitialBackoff = 500 * time.Millisecond
+	uploadMaxBackoff     = 4 * time.Second
+)
+
+// StreamUploadThreshold é o tamanho em bytes a partir do qual a mídia é enviada em streaming: a mídia é
+// criptografada em um arquivo temporário (em vez de uma segunda cópia em memória) e o progresso do envio
+// é registrado em log a cada uploadProgressStep por cento
+const (
+	StreamUploadThreshold = 16 * 1024 * 1024
+	uploadProgressStep    = 10
+)
+
+// uploadStatusPattern extrai o status HTTP do erro do whatsmeow, que não expõe um tipo próprio
+var uploadStatusPattern = regexp.MustCompile(`upload failed with status code (\d+)`)
+
+// UploadMedia envia a mídia aos servidores do WhatsApp repetindo as falhas transitórias (rede, 5xx,
+// 408 e 429). Canais usam o upload sem criptografia. Mídias a partir de StreamUploadThreshold são
+// enviadas em streaming com progresso em log; inclua o messageID em log para correlacionar. Apenas o
+// upload é repetido: o envio da mensagem não, para não duplicar mensagens. Se todas as tentativas
+// falharem, o último erro é retornado.
+func UploadMedia(ctx context.Context, client *whatsmeow.Client, data []byte, mediaType whatsmeow.MediaType, newsletter bool, log logger.Logger) (whatsmeow.UploadResponse, error) {
+	backoff := uploadInitialBackoff
+	streamed := len(data) >= StreamUploadThreshold
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		var resp whatsmeow.UploadResponse
+		var err error
+		switch {
+		case streamed:
+			resp, err = uploadStream(ctx, client, data, mediaType, newsletter, log)
+		case newsletter:
+			resp, err = client.UploadNewsletter(ctx, data, mediaType)
+		default:
+			resp, err = client.Upload(ctx, data, mediaType)
+		}
+		if err == nil {
+			log.Info("Upload de mídia concluído",
+				"size", len(data),
+				"streamed", streamed,
+				"attempt", attempt,
+				"duration", time.Since(start),
+			)
+			return resp, nil
+		}
+		if attempt >= UploadMaxAttempts || !IsRetryableUploadError(err) {
+			return resp, err
+		}
+
+		log.Warn("Falha transitória no upload de mídia, tentando novamente",
+			"attempt", attempt,
+			"maxAttempts", UploadMaxAttempts,
+			"retryIn", backoff,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return resp, fmt.Errorf("%w (última falha: %v)", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, uploadMaxBackoff)
+	}
+}
+
+// uploadStream envia a mídia com os uploads baseados em io.Reader do whatsmeow. O upload criptografado
+// precisa de um arquivo temporário, removido ao final de cada tentativa.
+func uploadStream(ctx context.Context, client *whatsmeow.Client, data []byte, mediaType whatsmeow.MediaType, newsletter bool, log logger.Logger) (whatsmeow.UploadResponse, error) {
+	if newsletter {
+		progress := &uploadProgress{file: bytes.NewReader(data), total: int64(len(data)), log: log, start: time.Now()}
+		return client.UploadNewsletterReader(ctx, progress, mediaType)
+	}
+
+	tempFile, err := os.CreateTemp("", "zpigo-upload-*")
+	if err != nil {
+		return whatsmeow.UploadResponse{}, fmt.Errorf("erro ao criar arquivo temporário do upload: %w", err)
+	}
+	defer func() {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+	}()
+
+	progress := &uploadProgress{file: tempFile, log: log, start: time.Now()}
+	return client.UploadReader(ctx, bytes.NewReader(data), progress, mediaType)
+}
+
+// uploadProgress registra em log quantos bytes da mídia já foram lidos pelo upload. Leituras só contam
+// após o primeiro Seek ao início: antes dele o whatsmeow ainda está criptografando (escritas no arquivo
+// temporário) ou calculando o hash da mídia de canal.
+type uploadProgress struct {
+	file      io.ReadSeeker
+	total     int64
+	sent      int64
+	lastStep  int64
+	uploading bool
+	log       logger.Logger
+	start     time.Time
+}
+
+func (p *uploadProgress) Read(b []byte) (int, error) {
+	n, err := p.file.Read(b)
+	if !p.uploading || p.total <= 0 {
+		return n, err
+	}
+
+	p.sent += int64(n)
+	step := p.sent * 100 / p.total / uploadProgressStep * uploadProgressStep
+	if step > p.lastStep {
+		p.lastStep = step
+		p.log.Info("Progresso do upload de mídia",
+			"percent", step,
+			"sent", p.sent,
+			"total", p.total,
+			"elapsed", time.Since(p.start),
+		)
+	}
+	return n, err
+}
+
+// Write recebe a mídia criptografada; o total a enviar é o que foi escrito no arquivo temporário
+func (p *uploadProgress) Write(b []byte) (int, error) {
+	w, ok := p.file.(io.Writer)
+	if !ok {
+		return 0, errors.New("arquivo do upload não aceita escrita")
+	}
+	n, err := w.Write(b)
+	p.total += int64(n)
+	return n, err
+}
+
+func (p *uploadProgress) Seek(offset int64, whence int) (int64, error) {
+	pos, err := p.file.Seek(offset, whence)
+	if err == nil && pos == 0 {
+		p.uploading = true
+		p.sent = 0
+		p.lastStep = 0
+	}
+	return pos, err
+}
+
+// IsRetryableUploadError indica se a falha de upload é transitória. Cancelamentos e prazos esgotados
+// do contexto, respostas 4xx e erros de sessão não são repetidos.
+func IsRetryableUploadError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if match := uploadStatusPattern.FindStringSubmatch(err.Error()); match != nil {
+		status, _ := strconv.Atoi(match[1])
+		return status >= http.StatusInternalServerError ||
+			status == http.StatusRequestTimeout ||
+			status == http.StatusTooManyRequests
+	}
+
+	if errors.Is(err, whatsmeow.ErrIQTimedOut) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -2,13 +2,18 @@ package meow
 
 import (
 	"crypto/tls"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
 	waLog "go.mau.fi/whatsmeow/util/log"
 
 	"zpigo/internal/logger"
 	"zpigo/internal/store/models"
+	"zpigo/internal/webhook"
 )
 
 func BuildCacheKey(apiKey, sessionID string) string {
@@ -30,6 +35,23 @@ func NewSessionInfoFromModel(session *models.Session, apiKey string) *SessionInf
 	}
 }
 
+// NewWebhookConfigFromModel converte o webhook persistido na configuração usada pelo gerenciador de webhooks
+func NewWebhookConfigFromModel(model *models.Webhook) *webhook.Config {
+	return &webhook.Config{
+		URL:        model.URL,
+		Events:     model.GetEvents(),
+		MaxRetries: model.MaxRetries,
+		RetryDelay: time.Duration(model.RetryDelay) * time.Second,
+		Backoff:    webhook.BackoffStrategy(model.Backoff),
+		Enabled:    true,
+		Secret:     model.Secret,
+
+		MaxRetryDelay: time.Duration(model.MaxRetryDelay) * time.Second,
+		Deadline:      time.Duration(model.DeliveryDeadline) * time.Second,
+		Filter:        webhook.NewFilter(model.FilterFromMe, model.FilterIsGroup),
+	}
+}
+
 func (s *SessionInfo) ToModelSession() *models.Session {
 	return &models.Session{
 		ID:     s.ID,
@@ -40,6 +62,24 @@ func (s *SessionInfo) ToModelSession() *models.Session {
 	}
 }
 
+// ExtractMessageText retorna o texto de uma mensagem, incluindo legendas de mídia
+func ExtractMessageText(msg *waE2E.Message) string {
+	switch {
+	case msg.GetConversation() != "":
+		return msg.GetConversation()
+	case msg.GetExtendedTextMessage().GetText() != "":
+		return msg.GetExtendedTextMessage().GetText()
+	case msg.GetImageMessage().GetCaption() != "":
+		return msg.GetImageMessage().GetCaption()
+	case msg.GetVideoMessage().GetCaption() != "":
+		return msg.GetVideoMessage().GetCaption()
+	case msg.GetDocumentMessage().GetCaption() != "":
+		return msg.GetDocumentMessage().GetCaption()
+	default:
+		return ""
+	}
+}
+
 func NewHTTPClient() *resty.Client {
 	client := resty.New()
 	client.SetRedirectPolicy(resty.FlexibleRedirectPolicy(15))
@@ -60,7 +100,7 @@ func NewLoggerForComponent(component string) logger.Logger {
 }
 
 func NewWhatsAppLogger(component, level string) waLog.Logger {
-	return logger.ForWhatsApp(component)
+	return logger.NewWhatsAppLogger(component, level)
 }
 
 const (
@@ -69,15 +109,92 @@ const (
 	DefaultCacheExpiry  = 24 * time.Hour
 	DefaultCacheCleanup = 1 * time.Hour
 
+	DefaultCacheWarmConcurrency = 8
+
+	DefaultMaxConcurrentPairings = 10
+
+	DefaultQRSweepGrace = 30 * time.Second
+
+	DefaultSendRatePerMinute = 30
+	DefaultSendTimeout       = 30 * time.Second
+
+	DefaultBroadcastBatchSize     = 50
+	DefaultBroadcastClientTimeout = 5 * time.Second
+
+	DefaultLinkPreviewTimeout      = 10 * time.Second
+	DefaultLinkPreviewMaxHTMLSize  = 512 * 1024
+	DefaultLinkPreviewMaxImageSize = 5 * 1024 * 1024
+
+	DefaultReconnectAttempts  = 5
+	DefaultReconnectBaseDelay = 2 * time.Second
+	DefaultReconnectMaxDelay  = 1 * time.Minute
+
 	DefaultMaxRetries = 3
 	DefaultRetryDelay = 5 * time.Second
 
 	DefaultWebhookTimeout = 10 * time.Second
+	DefaultWebhookWorkers = 10
 
 	DefaultLogLevel      = "INFO"
 	DefaultDebugLogLevel = "DEBUG"
+
+	DefaultPairClientType        = "chrome"
+	DefaultPairClientDisplayName = "Chrome (Linux)"
+
+	// PairCodeValidity é o prazo máximo do código de vinculação: o WhatsApp não informa a validade exata,
+	// mas fecha o websocket de login quando os QR codes acabam, cerca de 160 segundos após a conexão
+	PairCodeValidity = 160 * time.Second
 )
 
+// pairClientTypes mapeia os nomes aceitos em clientType para as constantes PairClient* do whatsmeow
+var pairClientTypes = map[string]whatsmeow.PairClientType{
+	"chrome":   whatsmeow.PairClientChrome,
+	"edge":     whatsmeow.PairClientEdge,
+	"firefox":  whatsmeow.PairClientFirefox,
+	"ie":       whatsmeow.PairClientIE,
+	"opera":    whatsmeow.PairClientOpera,
+	"safari":   whatsmeow.PairClientSafari,
+	"electron": whatsmeow.PairClientElectron,
+	"uwp":      whatsmeow.PairClientUWP,
+	"other":    whatsmeow.PairClientOtherWebClient,
+}
+
+// ParsePairClientType converte o nome do cliente (chrome, edge, firefox, ie, opera, safari, electron, uwp, other)
+// para o PairClientType correspondente. Nome vazio usa DefaultPairClientType.
+func ParsePairClientType(name string) (whatsmeow.PairClientType, error) {
+	if name == "" {
+		name = DefaultPairClientType
+	}
+
+	clientType, ok := pairClientTypes[strings.ToLower(name)]
+	if !ok {
+		return whatsmeow.PairClientUnknown, fmt.Errorf("clientType inválido: %s (aceitos: chrome, edge, firefox, ie, opera, safari, electron, uwp, other)", name)
+	}
+
+	return clientType, nil
+}
+
+// RawPairCode remove a formatação do código de vinculação, retornando apenas os caracteres (ABCDEFGH)
+func RawPairCode(code string) string {
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ToUpper(code)
+}
+
+// FormatPairCode agrupa o código de vinculação em blocos de 4 caracteres (ABCD-EFGH), como o WhatsApp exibe
+func FormatPairCode(code string) string {
+	raw := RawPairCode(code)
+
+	groups := make([]string, 0, (len(raw)+3)/4)
+	for len(raw) > 4 {
+		groups = append(groups, raw[:4])
+		raw = raw[4:]
+	}
+	groups = append(groups, raw)
+
+	return strings.Join(groups, "-")
+}
+
 type contextKey string
 
 const (
@@ -0,0 +1,85 @@
+package meow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"zpigo/internal/store"
+	"zpigo/internal/store/models"
+	"zpigo/internal/webhook"
+)
+
+// fakeWebhookRepo implementa apenas List, o único método usado no carregamento
+type fakeWebhookRepo struct {
+	store.WebhookRepositoryInterface
+	webhooks []*models.Webhook
+}
+
+func (r *fakeWebhookRepo) List(ctx context.Context) ([]*models.Webhook, error) {
+	return r.webhooks, nil
+}
+
+func newTestWebhook(t *testing.T, id, sessionID, url string, events ...webhook.EventType) *models.Webhook {
+	t.Helper()
+
+	w := &models.Webhook{
+		ID:         id,
+		SessionID:  sessionID,
+		URL:        url,
+		MaxRetries: 5,
+		RetryDelay: 3,
+	}
+	if err := w.SetEvents(events); err != nil {
+		t.Fatalf("SetEvents: %v", err)
+	}
+	return w
+}
+
+// TestLoadConfigsFromRepositoryAfterRestart simula um reinício: um gerenciador novo, sem nenhuma
+// configuração em memória, precisa recuperar os webhooks persistidos
+func TestLoadConfigsFromRepositoryAfterRestart(t *testing.T) {
+	repo := &fakeWebhookRepo{webhooks: []*models.Webhook{
+		// List devolve do mais recente para o mais antigo
+		newTestWebhook(t, "a-new", "session-a", "https://example.com/novo", webhook.EventMessage),
+		newTestWebhook(t, "a-old", "session-a", "https://example.com/antigo"),
+		newTestWebhook(t, "b", "session-b", "https://example.com/b"),
+		newTestWebhook(t, "c", "session-c", "not-a-url"),
+	}}
+
+	sm := NewSessionManager(nil, nil, nil)
+	defer sm.GetWebhookManager().Stop()
+
+	if _, exists := sm.GetWebhookManager().GetConfig("session-a"); exists {
+		t.Fatal("gerenciador recém-criado não deveria ter configuração")
+	}
+
+	loaded, err := sm.LoadConfigsFromRepository(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("LoadConfigsFromRepository: %v", err)
+	}
+	if loaded != 2 {
+		t.Errorf("carregados = %d, esperado 2", loaded)
+	}
+
+	config, exists := sm.GetWebhookManager().GetConfig("session-a")
+	if !exists {
+		t.Fatal("configuração da session-a não foi recarregada")
+	}
+	if config.URL != "https://example.com/novo" {
+		t.Errorf("URL = %q, esperado o webhook mais recente", config.URL)
+	}
+	if len(config.Events) != 1 || config.Events[0] != webhook.EventMessage {
+		t.Errorf("eventos = %v, esperado [%s]", config.Events, webhook.EventMessage)
+	}
+	if config.MaxRetries != 5 || config.RetryDelay != 3*time.Second {
+		t.Errorf("política de retry = %d/%s, esperado 5/3s", config.MaxRetries, config.RetryDelay)
+	}
+
+	if _, exists := sm.GetWebhookManager().GetConfig("session-b"); !exists {
+		t.Error("configuração da session-b não foi recarregada")
+	}
+	if _, exists := sm.GetWebhookManager().GetConfig("session-c"); exists {
+		t.Error("configuração com URL inválida não deveria ser carregada")
+	}
+}
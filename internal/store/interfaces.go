@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"zpigo/internal/store/models"
 )
@@ -9,17 +10,24 @@ import (
 // SessionRepositoryInterface define as operações para sessões
 type SessionRepositoryInterface interface {
 	Create(ctx context.Context, session *models.Session) error
+	CreateWith(ctx context.Context, session *models.Session, register func(*models.Session) error) error
 	GetByID(ctx context.Context, id string) (*models.Session, error)
+	GetByName(ctx context.Context, name string) ([]*models.Session, error)
 	List(ctx context.Context) ([]*models.Session, error)
 	Update(ctx context.Context, session *models.Session) error
 	Delete(ctx context.Context, id string) error
 	UpdateStatus(ctx context.Context, id string, status models.SessionStatus) error
-	UpdateQRCode(ctx context.Context, id string, qrCode string) error
+	UpdateQRCode(ctx context.Context, id string, qrCode string, timeout time.Duration) error
 	SetConnected(ctx context.Context, id string, phone string, deviceJid string) error
 	SetDisconnected(ctx context.Context, id string) error
 	UpdateProxy(ctx context.Context, id string, proxyHost string, proxyPort int, proxyType models.ProxyType, proxyUser, proxyPass string) error
+	ClearProxy(ctx context.Context, id string) error
+	UpdatePlatform(ctx context.Context, id string, platform, osName string) error
+	UpdateName(ctx context.Context, id string, name string) error
 	UpdateDeviceJid(ctx context.Context, id string, deviceJid string) error
-	GetAll(ctx context.Context) ([]models.Session, error)
+	UpdateStoreMessages(ctx context.Context, id string, enabled bool) error
+	CountByStatus(ctx context.Context) (map[models.SessionStatus]int, error)
+	ClearStaleQRCodes(ctx context.Context, expiredBefore time.Time) ([]string, error)
 }
 
 // WebhookRepositoryInterface define as operações para webhooks
@@ -32,3 +40,58 @@ type WebhookRepositoryInterface interface {
 	Delete(ctx context.Context, id string) error
 	DeleteBySessionID(ctx context.Context, sessionID string) error
 }
+
+// MessageEditRepositoryInterface define as operações para o histórico de edições de mensagens
+type MessageEditRepositoryInterface interface {
+	Create(ctx context.Context, edit *models.MessageEdit) error
+	ListByMessageID(ctx context.Context, sessionID, messageID string) ([]*models.MessageEdit, error)
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+}
+
+// APIKeyRepositoryInterface define as operações para as chaves de API
+type APIKeyRepositoryInterface interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	List(ctx context.Context) ([]*models.APIKey, error)
+	Revoke(ctx context.Context, id string) error
+	TouchLastUsed(ctx context.Context, id string) error
+}
+
+// MessageReceiptRepositoryInterface define as operações para os recibos de leitura por participante
+type MessageReceiptRepositoryInterface interface {
+	Create(ctx context.Context, receipt *models.MessageReceipt) error
+	ListByMessageID(ctx context.Context, sessionID, messageID string) ([]*models.MessageReceipt, error)
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+}
+
+// MessageRepositoryInterface define as operações para as mensagens armazenadas
+type MessageRepositoryInterface interface {
+	Create(ctx context.Context, message *models.Message) error
+	List(ctx context.Context, sessionID, chatJID string, before time.Time, beforeID string, limit int) ([]*models.Message, error)
+	HasAny(ctx context.Context) (bool, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+}
+
+// ChatRepositoryInterface define as operações para os chats conhecidos da sessão
+type ChatRepositoryInterface interface {
+	UpsertMany(ctx context.Context, chats []*models.Chat) error
+	RecordMessage(ctx context.Context, sessionID, jid string, at time.Time, incoming bool) error
+	SetArchived(ctx context.Context, sessionID, jid string, archived bool) error
+	SetPinned(ctx context.Context, sessionID, jid string, pinned bool) error
+	SetMutedUntil(ctx context.Context, sessionID, jid string, mutedUntil int64) error
+	SetRead(ctx context.Context, sessionID, jid string, read bool) error
+	ListBySessionID(ctx context.Context, sessionID string) ([]*models.Chat, error)
+	Delete(ctx context.Context, sessionID, jid string) error
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+}
+
+// BroadcastRepositoryInterface define as operações para os jobs de broadcast
+type BroadcastRepositoryInterface interface {
+	Create(ctx context.Context, job *models.BroadcastJob, recipients []*models.BroadcastRecipient) error
+	GetByID(ctx context.Context, id string) (*models.BroadcastJob, error)
+	ListUnfinished(ctx context.Context) ([]*models.BroadcastJob, error)
+	NextPendingRecipients(ctx context.Context, jobID string, limit int) ([]*models.BroadcastRecipient, error)
+	MarkRecipient(ctx context.Context, recipient *models.BroadcastRecipient) error
+	UpdateStatus(ctx context.Context, id string, status models.BroadcastStatus, lastError string) error
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// APIKey representa uma chave de acesso à API. Apenas o hash da chave é armazenado;
+// a chave em texto puro é exibida uma única vez, na criação.
+type APIKey struct {
+	ID        string `json:"id" db:"id"`
+	KeyHash   string `json:"-" db:"keyhash"`
+	Prefix    string `json:"prefix" db:"prefix"`
+	Label     string `json:"label" db:"label"`
+	SessionID string `json:"sessionId,omitempty" db:"sessionid"` // Vazio quando a chave vale para todas as sessões
+
+	CreatedAt  time.Time  `json:"createdAt" db:"createdat"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" db:"lastusedat"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" db:"revokedat"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// IsGlobal indica se a chave dá acesso a todas as sessões
+func (k *APIKey) IsGlobal() bool {
+	return k.SessionID == ""
+}
+
+// AllowsSession indica se a chave pode acessar a sessão informada
+func (k *APIKey) AllowsSession(sessionID string) bool {
+	return k.IsGlobal() || k.SessionID == sessionID
+}
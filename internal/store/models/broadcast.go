@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+type BroadcastStatus string
+
+const (
+	BroadcastPending   BroadcastStatus = "pending"
+	BroadcastRunning   BroadcastStatus = "running"
+	BroadcastCompleted BroadcastStatus = "completed"
+	BroadcastCancelled BroadcastStatus = "cancelled"
+	BroadcastFailed    BroadcastStatus = "failed"
+)
+
+// IsFinal indica se o job não será mais processado
+func (s BroadcastStatus) IsFinal() bool {
+	return s == BroadcastCompleted || s == BroadcastCancelled || s == BroadcastFailed
+}
+
+type BroadcastRecipientStatus string
+
+const (
+	RecipientPending BroadcastRecipientStatus = "pending"
+	RecipientSent    BroadcastRecipientStatus = "sent"
+	RecipientFailed  BroadcastRecipientStatus = "failed"
+)
+
+// BroadcastJob é um envio em massa processado em segundo plano. Os contadores Sent e
+// Failed são atualizados a cada destinatário processado.
+type BroadcastJob struct {
+	ID        string          `json:"id" db:"id"`
+	SessionID string          `json:"sessionId" db:"sessionid"`
+	Message   string          `json:"message" db:"message"`
+	Status    BroadcastStatus `json:"status" db:"status"`
+	Total     int             `json:"total" db:"total"`
+	Sent      int             `json:"sent" db:"sent"`
+	Failed    int             `json:"failed" db:"failed"`
+	LastError string          `json:"lastError,omitempty" db:"lasterror"`
+
+	CreatedAt  time.Time  `json:"createdAt" db:"createdat"`
+	UpdatedAt  time.Time  `json:"updatedAt" db:"updatedat"`
+	StartedAt  *time.Time `json:"startedAt,omitempty" db:"startedat"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty" db:"finishedat"`
+}
+
+func (BroadcastJob) TableName() string {
+	return "broadcast_jobs"
+}
+
+// Pending retorna quantos destinatários ainda não foram processados
+func (j *BroadcastJob) Pending() int {
+	return j.Total - j.Sent - j.Failed
+}
+
+// BroadcastRecipient guarda o destinatário de um job com o texto já renderizado
+type BroadcastRecipient struct {
+	ID        string                   `json:"id" db:"id"`
+	JobID     string                   `json:"jobId" db:"jobid"`
+	Position  int                      `json:"position" db:"position"`
+	Phone     string                   `json:"phone" db:"phone"`
+	JID       string                   `json:"jid" db:"jid"`
+	Text      string                   `json:"text" db:"text"`
+	Status    BroadcastRecipientStatus `json:"status" db:"status"`
+	MessageID string                   `json:"messageId,omitempty" db:"messageid"`
+	Error     string                   `json:"error,omitempty" db:"error"`
+
+	SentAt *time.Time `json:"sentAt,omitempty" db:"sentat"`
+}
+
+func (BroadcastRecipient) TableName() string {
+	return "broadcast_recipients"
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// ChatMutedForever é o valor de MutedUntil para chats silenciados sem prazo
+const ChatMutedForever int64 = -1
+
+// Chat é o estado conhecido de um chat da sessão, montado a partir da sincronização de histórico, do
+// app state e das mensagens recebidas. MutedUntil é um timestamp Unix em segundos: 0 indica que o chat
+// não está silenciado e ChatMutedForever que está silenciado sem prazo.
+type Chat struct {
+	SessionID     string     `json:"sessionId" db:"sessionid"`
+	JID           string     `json:"jid" db:"jid"`
+	Name          string     `json:"name" db:"name"`
+	LastMessageAt *time.Time `json:"lastMessageAt,omitempty" db:"lastmessageat"`
+	UnreadCount   int        `json:"unreadCount" db:"unreadcount"`
+	MarkedUnread  bool       `json:"markedUnread" db:"markedunread"`
+	Archived      bool       `json:"archived" db:"archived"`
+	Pinned        bool       `json:"pinned" db:"pinned"`
+	MutedUntil    int64      `json:"mutedUntil" db:"muteduntil"`
+
+	UpdatedAt time.Time `json:"updatedAt" db:"updatedat"`
+}
+
+func (Chat) TableName() string {
+	return "chats"
+}
+
+// IsMuted indica se o chat está silenciado no momento
+func (c *Chat) IsMuted(now time.Time) bool {
+	return c.MutedUntil == ChatMutedForever || c.MutedUntil > now.Unix()
+}
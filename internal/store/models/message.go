@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// Message é uma mensagem armazenada de uma sessão que optou pelo armazenamento. Text é o texto ou a
+// legenda da mensagem, vazio em mídias sem legenda.
+type Message struct {
+	SessionID string    `json:"sessionId" db:"sessionid"`
+	ChatJID   string    `json:"chatJid" db:"chatjid"`
+	MessageID string    `json:"messageId" db:"messageid"`
+	SenderJID string    `json:"senderJid,omitempty" db:"senderjid"`
+	IsFromMe  bool      `json:"isFromMe" db:"isfromme"`
+	IsGroup   bool      `json:"isGroup" db:"isgroup"`
+	Type      string    `json:"type" db:"type"`
+	Text      string    `json:"text,omitempty" db:"text"`
+	SentAt    time.Time `json:"sentAt" db:"sentat"`
+	CreatedAt time.Time `json:"createdAt" db:"createdat"`
+}
+
+func (Message) TableName() string {
+	return "messages"
+}
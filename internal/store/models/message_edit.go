@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+type MessageEditSource string
+
+const (
+	EditSourceAPI   MessageEditSource = "api"
+	EditSourceEvent MessageEditSource = "event"
+)
+
+type MessageEdit struct {
+	ID        string            `json:"id" db:"id"`
+	SessionID string            `json:"sessionId" db:"sessionid"`
+	MessageID string            `json:"messageId" db:"messageid"`
+	ChatJID   string            `json:"chatJid" db:"chatjid"`
+	SenderJID string            `json:"senderJid,omitempty" db:"senderjid"`
+	Content   string            `json:"content" db:"content"`
+	IsFromMe  bool              `json:"isFromMe" db:"isfromme"`
+	Source    MessageEditSource `json:"source" db:"source"`
+
+	EditedAt  time.Time `json:"editedAt" db:"editedat"`
+	CreatedAt time.Time `json:"createdAt" db:"createdat"`
+}
+
+func (MessageEdit) TableName() string {
+	return "message_edits"
+}
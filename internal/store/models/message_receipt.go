@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// MessageReceipt registra a confirmação de leitura (ou reprodução) de uma mensagem por um participante de grupo
+type MessageReceipt struct {
+	ID          string `json:"id" db:"id"`
+	SessionID   string `json:"sessionId" db:"sessionid"`
+	MessageID   string `json:"messageId" db:"messageid"`
+	ChatJID     string `json:"chatJid" db:"chatjid"`
+	ReaderJID   string `json:"readerJid" db:"readerjid"`
+	ReceiptType string `json:"receiptType" db:"receipttype"`
+
+	ReceivedAt time.Time `json:"receivedAt" db:"receivedat"`
+	CreatedAt  time.Time `json:"createdAt" db:"createdat"`
+}
+
+func (MessageReceipt) TableName() string {
+	return "message_receipts"
+}
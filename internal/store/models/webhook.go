@@ -1,14 +1,29 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
+
+	"zpigo/internal/webhook"
 )
 
 type Webhook struct {
 	ID        string `json:"id" db:"id"`
 	SessionID string `json:"sessionId" db:"sessionid"`
 	URL       string `json:"url" db:"url"`
-	Events    string `json:"events" db:"events"`
+	Events    string `json:"-" db:"events"` // Array JSON de tipos de evento; use GetEvents e SetEvents
+	Secret    string `json:"-" db:"secret"`
+
+	MaxRetries       int    `json:"maxRetries" db:"maxretries"`
+	RetryDelay       int    `json:"retryDelay" db:"retrydelay"`       // em segundos
+	MaxRetryDelay    int    `json:"maxRetryDelay" db:"maxretrydelay"` // em segundos
+	Backoff          string `json:"backoff" db:"backoff"`
+	DeliveryDeadline int    `json:"deliveryDeadline" db:"deliverydeadline"` // em segundos, contados a partir do enfileiramento
+
+	// Filtros opcionais; nil entrega mensagens de qualquer origem/tipo de chat
+	FilterFromMe  *bool `json:"filterFromMe,omitempty" db:"filterfromme"`
+	FilterIsGroup *bool `json:"filterIsGroup,omitempty" db:"filterisgroup"`
 
 	CreatedAt time.Time `json:"createdAt" db:"createdat"`
 	UpdatedAt time.Time `json:"updatedAt" db:"updatedat"`
@@ -19,3 +34,43 @@ type Webhook struct {
 func (Webhook) TableName() string {
 	return "webhooks"
 }
+
+// GetEvents retorna os eventos inscritos. Valores gravados no formato antigo, separados
+// por vírgula, continuam sendo lidos.
+func (w *Webhook) GetEvents() []webhook.EventType {
+	events := []webhook.EventType{}
+	raw := strings.TrimSpace(w.Events)
+	if raw == "" {
+		return events
+	}
+
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &events); err == nil {
+			return events
+		}
+		return []webhook.EventType{}
+	}
+
+	for _, event := range strings.Split(raw, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, webhook.EventType(event))
+		}
+	}
+	return events
+}
+
+// SetEvents valida os eventos contra os tipos conhecidos e os grava como array JSON
+func (w *Webhook) SetEvents(events []webhook.EventType) error {
+	validated, err := webhook.ParseEventTypes(webhook.EventTypeStrings(events))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(validated)
+	if err != nil {
+		return err
+	}
+
+	w.Events = string(data)
+	return nil
+}
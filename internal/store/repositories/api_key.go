@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpigo/internal/logger"
+	"zpigo/internal/store/models"
+)
+
+type APIKeyRepository struct {
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:     db,
+		logger: logger.NewForComponent("api-key-repo"),
+	}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	if key.ID == "" {
+		key.ID = uuid.New().String()
+	}
+	key.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO api_keys (id, keyhash, prefix, label, sessionid, createdat)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, key.ID, key.KeyHash, key.Prefix, key.Label, key.SessionID, key.CreatedAt)
+	return err
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := `
+		SELECT id, keyhash, prefix, label, sessionid, createdat, lastusedat, revokedat
+		FROM api_keys WHERE keyhash = $1
+	`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("chave de API não encontrada")
+	}
+	return key, err
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]*models.APIKey, error) {
+	query := `
+		SELECT id, keyhash, prefix, label, sessionid, createdat, lastusedat, revokedat
+		FROM api_keys ORDER BY createdat DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Revoke marca a chave como revogada. Revogar uma chave já revogada não altera a data original.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id string) error {
+	query := `UPDATE api_keys SET revokedat = COALESCE(revokedat, $2) WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("chave de API não encontrada")
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id string) error {
+	query := `UPDATE api_keys SET lastusedat = $2 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, time.Now())
+	return err
+}
+
+func scanAPIKey(row interface{ Scan(...any) error }) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	err := row.Scan(
+		&key.ID, &key.KeyHash, &key.Prefix, &key.Label, &key.SessionID,
+		&key.CreatedAt, &key.LastUsedAt, &key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
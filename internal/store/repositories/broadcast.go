@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpigo/internal/logger"
+	"zpigo/internal/store/models"
+)
+
+type BroadcastRepository struct {
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewBroadcastRepository(db *sql.DB) *BroadcastRepository {
+	return &BroadcastRepository{
+		db:     db,
+		logger: logger.NewForComponent("broadcast-repo"),
+	}
+}
+
+// Create grava o job e todos os destinatários em uma única transação
+func (r *BroadcastRepository) Create(ctx context.Context, job *models.BroadcastJob, recipients []*models.BroadcastRecipient) error {
+	if job.ID == "" {
+		job.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	job.Total = len(recipients)
+	if job.Status == "" {
+		job.Status = models.BroadcastPending
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO broadcast_jobs (id, sessionid, message, status, total, sent, failed, lasterror, createdat, updatedat)
+		VALUES ($1, $2, $3, $4, $5, 0, 0, '', $6, $7)
+	`, job.ID, job.SessionID, job.Message, job.Status, job.Total, job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO broadcast_recipients (id, jobid, position, phone, jid, text, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, recipient := range recipients {
+		if recipient.ID == "" {
+			recipient.ID = uuid.New().String()
+		}
+		recipient.JobID = job.ID
+		recipient.Position = i
+		recipient.Status = models.RecipientPending
+
+		if _, err := stmt.ExecContext(ctx,
+			recipient.ID, recipient.JobID, recipient.Position, recipient.Phone,
+			recipient.JID, recipient.Text, recipient.Status,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *BroadcastRepository) GetByID(ctx context.Context, id string) (*models.BroadcastJob, error) {
+	query := `
+		SELECT id, sessionid, message, status, total, sent, failed, lasterror, createdat, updatedat, startedat, finishedat
+		FROM broadcast_jobs WHERE id = $1
+	`
+
+	job, err := scanBroadcastJob(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("broadcast não encontrado")
+	}
+	return job, err
+}
+
+// ListUnfinished retorna os jobs pendentes ou em andamento, usados para retomar o processamento
+func (r *BroadcastRepository) ListUnfinished(ctx context.Context) ([]*models.BroadcastJob, error) {
+	query := `
+		SELECT id, sessionid, message, status, total, sent, failed, lasterror, createdat, updatedat, startedat, finishedat
+		FROM broadcast_jobs WHERE status IN ($1, $2) ORDER BY createdat ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, models.BroadcastPending, models.BroadcastRunning)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []*models.BroadcastJob{}
+	for rows.Next() {
+		job, err := scanBroadcastJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// NextPendingRecipients retorna os próximos destinatários ainda não processados, na ordem de criação
+func (r *BroadcastRepository) NextPendingRecipients(ctx context.Context, jobID string, limit int) ([]*models.BroadcastRecipient, error) {
+	query := `
+		SELECT id, jobid, position, phone, jid, text, status, messageid, error, sentat
+		FROM broadcast_recipients WHERE jobid = $1 AND status = $2
+		ORDER BY position ASC LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, jobID, models.RecipientPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipients := []*models.BroadcastRecipient{}
+	for rows.Next() {
+		recipient := &models.BroadcastRecipient{}
+		var messageID, errMsg sql.NullString
+		if err := rows.Scan(
+			&recipient.ID, &recipient.JobID, &recipient.Position, &recipient.Phone, &recipient.JID,
+			&recipient.Text, &recipient.Status, &messageID, &errMsg, &recipient.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		recipient.MessageID = messageID.String
+		recipient.Error = errMsg.String
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, rows.Err()
+}
+
+// MarkRecipient grava o resultado do envio ao destinatário e atualiza os contadores do job
+func (r *BroadcastRepository) MarkRecipient(ctx context.Context, recipient *models.BroadcastRecipient) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE broadcast_recipients SET status = $2, messageid = $3, error = $4, sentat = $5
+		WHERE id = $1 AND status = $6
+	`, recipient.ID, recipient.Status, recipient.MessageID, recipient.Error, recipient.SentAt, models.RecipientPending)
+	if err != nil {
+		return err
+	}
+
+	// Destinatário já processado: os contadores não são incrementados de novo
+	if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+		return err
+	}
+
+	counter := "sent"
+	lastError := ""
+	if recipient.Status == models.RecipientFailed {
+		counter = "failed"
+		lastError = recipient.Error
+	}
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE broadcast_jobs SET %[1]s = %[1]s + 1, lasterror = CASE WHEN $2 = '' THEN lasterror ELSE $2 END, updatedat = $3
+		WHERE id = $1
+	`, counter), recipient.JobID, lastError, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateStatus altera o status do job, registrando o início na primeira execução e o
+// término quando o status é final. Jobs já finalizados não são alterados.
+func (r *BroadcastRepository) UpdateStatus(ctx context.Context, id string, status models.BroadcastStatus, lastError string) error {
+	now := time.Now()
+
+	var startedAt, finishedAt *time.Time
+	if status == models.BroadcastRunning {
+		startedAt = &now
+	}
+	if status.IsFinal() {
+		finishedAt = &now
+	}
+
+	query := `
+		UPDATE broadcast_jobs
+		SET status = $2,
+		    lasterror = CASE WHEN $3 = '' THEN lasterror ELSE $3 END,
+		    startedat = COALESCE(startedat, $4),
+		    finishedat = $5,
+		    updatedat = $6
+		WHERE id = $1 AND status NOT IN ($7, $8, $9)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, status, lastError, startedAt, finishedAt, now,
+		models.BroadcastCompleted, models.BroadcastCancelled, models.BroadcastFailed)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("broadcast não encontrado ou já finalizado")
+	}
+
+	return nil
+}
+
+func scanBroadcastJob(row interface{ Scan(...any) error }) (*models.BroadcastJob, error) {
+	job := &models.BroadcastJob{}
+	err := row.Scan(
+		&job.ID, &job.SessionID, &job.Message, &job.Status, &job.Total, &job.Sent, &job.Failed,
+		&job.LastError, &job.CreatedAt, &job.UpdatedAt, &job.StartedAt, &job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"zpigo/internal/logger"
+	"zpigo/internal/store/models"
+)
+
+type ChatRepository struct {
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewChatRepository(db *sql.DB) *ChatRepository {
+	return &ChatRepository{
+		db:     db,
+		logger: logger.NewForComponent("chat-repo"),
+	}
+}
+
+// UpsertMany grava os chats recebidos na sincronização de histórico em uma única transação. Um nome
+// vazio não sobrescreve o nome já conhecido e a última mensagem só avança.
+func (r *ChatRepository) UpsertMany(ctx context.Context, chats []*models.Chat) error {
+	if len(chats) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO chats (sessionid, jid, name, lastmessageat, unreadcount, markedunread, archived, pinned, muteduntil, updatedat)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (sessionid, jid) DO UPDATE SET
+			name = COALESCE(NULLIF(EXCLUDED.name, ''), chats.name),
+			lastmessageat = GREATEST(chats.lastmessageat, EXCLUDED.lastmessageat),
+			unreadcount = EXCLUDED.unreadcount,
+			markedunread = EXCLUDED.markedunread,
+			archived = EXCLUDED.archived,
+			pinned = EXCLUDED.pinned,
+			muteduntil = EXCLUDED.muteduntil,
+			updatedat = EXCLUDED.updatedat
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, chat := range chats {
+		chat.UpdatedAt = now
+		if _, err := stmt.ExecContext(ctx,
+			chat.SessionID, chat.JID, chat.Name, chat.LastMessageAt, chat.UnreadCount,
+			chat.MarkedUnread, chat.Archived, chat.Pinned, chat.MutedUntil, chat.UpdatedAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RecordMessage atualiza a última mensagem do chat, criando-o se necessário. Mensagens recebidas
+// incrementam os não lidos; mensagens enviadas pela própria conta zeram, como faz o WhatsApp.
+func (r *ChatRepository) RecordMessage(ctx context.Context, sessionID, jid string, at time.Time, incoming bool) error {
+	unread := 0
+	if incoming {
+		unread = 1
+	}
+
+	query := `
+		INSERT INTO chats (sessionid, jid, lastmessageat, unreadcount, updatedat)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (sessionid, jid) DO UPDATE SET
+			lastmessageat = GREATEST(chats.lastmessageat, EXCLUDED.lastmessageat),
+			unreadcount = CASE WHEN $6 THEN chats.unreadcount + 1 ELSE 0 END,
+			markedunread = chats.markedunread AND $6,
+			updatedat = EXCLUDED.updatedat
+	`
+
+	_, err := r.db.ExecContext(ctx, query, sessionID, jid, at, unread, time.Now(), incoming)
+	return err
+}
+
+// SetArchived atualiza se o chat está arquivado
+func (r *ChatRepository) SetArchived(ctx context.Context, sessionID, jid string, archived bool) error {
+	return r.upsertSetting(ctx, sessionID, jid, "archived", archived)
+}
+
+// SetPinned atualiza se o chat está fixado
+func (r *ChatRepository) SetPinned(ctx context.Context, sessionID, jid string, pinned bool) error {
+	return r.upsertSetting(ctx, sessionID, jid, "pinned", pinned)
+}
+
+// SetMutedUntil atualiza até quando o chat está silenciado (ver models.Chat)
+func (r *ChatRepository) SetMutedUntil(ctx context.Context, sessionID, jid string, mutedUntil int64) error {
+	return r.upsertSetting(ctx, sessionID, jid, "muteduntil", mutedUntil)
+}
+
+// SetRead marca o chat como lido, zerando os não lidos, ou como não lido
+func (r *ChatRepository) SetRead(ctx context.Context, sessionID, jid string, read bool) error {
+	query := `
+		INSERT INTO chats (sessionid, jid, markedunread, updatedat)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (sessionid, jid) DO UPDATE SET
+			unreadcount = CASE WHEN EXCLUDED.markedunread THEN chats.unreadcount ELSE 0 END,
+			markedunread = EXCLUDED.markedunread,
+			updatedat = EXCLUDED.updatedat
+	`
+
+	_, err := r.db.ExecContext(ctx, query, sessionID, jid, !read, time.Now())
+	return err
+}
+
+// upsertSetting grava uma única coluna de configuração do chat. column deve ser uma constante, nunca
+// um valor vindo do usuário.
+func (r *ChatRepository) upsertSetting(ctx context.Context, sessionID, jid, column string, value interface{}) error {
+	query := `
+		INSERT INTO chats (sessionid, jid, ` + column + `, updatedat)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (sessionid, jid) DO UPDATE SET
+			` + column + ` = EXCLUDED.` + column + `,
+			updatedat = EXCLUDED.updatedat
+	`
+
+	_, err := r.db.ExecContext(ctx, query, sessionID, jid, value, time.Now())
+	return err
+}
+
+// ListBySessionID retorna os chats da sessão na ordem do WhatsApp: fixados primeiro e depois pela
+// última mensagem, da mais recente para a mais antiga
+func (r *ChatRepository) ListBySessionID(ctx context.Context, sessionID string) ([]*models.Chat, error) {
+	query := `
+		SELECT sessionid, jid, name, lastmessageat, unreadcount, markedunread, archived, pinned, muteduntil, updatedat
+		FROM chats WHERE sessionid = $1
+		ORDER BY pinned DESC, lastmessageat DESC NULLS LAST, jid ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chats []*models.Chat
+	for rows.Next() {
+		chat := &models.Chat{}
+		err := rows.Scan(
+			&chat.SessionID, &chat.JID, &chat.Name, &chat.LastMessageAt, &chat.UnreadCount,
+			&chat.MarkedUnread, &chat.Archived, &chat.Pinned, &chat.MutedUntil, &chat.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
+
+	return chats, rows.Err()
+}
+
+func (r *ChatRepository) Delete(ctx context.Context, sessionID, jid string) error {
+	query := `DELETE FROM chats WHERE sessionid = $1 AND jid = $2`
+	_, err := r.db.ExecContext(ctx, query, sessionID, jid)
+	return err
+}
+
+func (r *ChatRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	query := `DELETE FROM chats WHERE sessionid = $1`
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	return err
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"zpigo/internal/logger"
+	"zpigo/internal/store/models"
+)
+
+type MessageRepository struct {
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewMessageRepository(db *sql.DB) *MessageRepository {
+	return &MessageRepository{
+		db:     db,
+		logger: logger.NewForComponent("message-repo"),
+	}
+}
+
+// Create armazena a mensagem; reentregas da mesma mensagem são ignoradas
+func (r *MessageRepository) Create(ctx context.Context, message *models.Message) error {
+	message.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO messages (sessionid, chatjid, messageid, senderjid, isfromme, isgroup, type, text, sentat, createdat)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (sessionid, chatjid, messageid) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		message.SessionID, message.ChatJID, message.MessageID, message.SenderJID, message.IsFromMe,
+		message.IsGroup, message.Type, message.Text, message.SentAt, message.CreatedAt,
+	)
+
+	return err
+}
+
+// List retorna as mensagens da sessão da mais recente para a mais antiga. chatJID vazio inclui todos os
+// chats. before e beforeID formam o cursor da paginação (a última mensagem da página anterior): são
+// retornadas as mensagens anteriores a before ou, no mesmo segundo, com ID menor que beforeID.
+func (r *MessageRepository) List(ctx context.Context, sessionID, chatJID string, before time.Time, beforeID string, limit int) ([]*models.Message, error) {
+	query := `
+		SELECT sessionid, chatjid, messageid, senderjid, isfromme, isgroup, type, text, sentat, createdat
+		FROM messages
+		WHERE sessionid = $1 AND ($2 = '' OR chatjid = $2)
+			AND ($3::timestamp IS NULL OR sentat < $3 OR (sentat = $3 AND messageid < $4))
+		ORDER BY sentat DESC, messageid DESC
+		LIMIT $5
+	`
+
+	var beforeArg interface{}
+	if !before.IsZero() {
+		beforeArg = before
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID, chatJID, beforeArg, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*models.Message
+	for rows.Next() {
+		message := &models.Message{}
+		err := rows.Scan(
+			&message.SessionID, &message.ChatJID, &message.MessageID, &message.SenderJID, &message.IsFromMe,
+			&message.IsGroup, &message.Type, &message.Text, &message.SentAt, &message.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// HasAny indica se há alguma mensagem armazenada, de qualquer sessão
+func (r *MessageRepository) HasAny(ctx context.Context) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM messages)`).Scan(&exists)
+	return exists, err
+}
+
+// DeleteOlderThan remove as mensagens enviadas antes de before, de todas as sessões, e retorna quantas
+// foram removidas
+func (r *MessageRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM messages WHERE sentat < $1`
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *MessageRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	query := `DELETE FROM messages WHERE sessionid = $1`
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	return err
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpigo/internal/logger"
+	"zpigo/internal/store/models"
+)
+
+type MessageEditRepository struct {
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewMessageEditRepository(db *sql.DB) *MessageEditRepository {
+	return &MessageEditRepository{
+		db:     db,
+		logger: logger.NewForComponent("message-edit-repo"),
+	}
+}
+
+func (r *MessageEditRepository) Create(ctx context.Context, edit *models.MessageEdit) error {
+	if edit.ID == "" {
+		edit.ID = uuid.New().String()
+	}
+
+	edit.CreatedAt = time.Now()
+	if edit.EditedAt.IsZero() {
+		edit.EditedAt = edit.CreatedAt
+	}
+
+	query := `
+		INSERT INTO message_edits (id, sessionid, messageid, chatjid, senderjid, content, isfromme, source, editedat, createdat)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		edit.ID, edit.SessionID, edit.MessageID, edit.ChatJID, edit.SenderJID,
+		edit.Content, edit.IsFromMe, edit.Source, edit.EditedAt, edit.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *MessageEditRepository) ListByMessageID(ctx context.Context, sessionID, messageID string) ([]*models.MessageEdit, error) {
+	query := `
+		SELECT id, sessionid, messageid, chatjid, senderjid, content, isfromme, source, editedat, createdat
+		FROM message_edits WHERE sessionid = $1 AND messageid = $2 ORDER BY editedat ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edits []*models.MessageEdit
+	for rows.Next() {
+		edit := &models.MessageEdit{}
+		err := rows.Scan(
+			&edit.ID, &edit.SessionID, &edit.MessageID, &edit.ChatJID, &edit.SenderJID,
+			&edit.Content, &edit.IsFromMe, &edit.Source, &edit.EditedAt, &edit.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, edit)
+	}
+
+	return edits, rows.Err()
+}
+
+func (r *MessageEditRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	query := `DELETE FROM message_edits WHERE sessionid = $1`
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	return err
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpigo/internal/logger"
+	"zpigo/internal/store/models"
+)
+
+type MessageReceiptRepository struct {
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewMessageReceiptRepository(db *sql.DB) *MessageReceiptRepository {
+	return &MessageReceiptRepository{
+		db:     db,
+		logger: logger.NewForComponent("message-receipt-repo"),
+	}
+}
+
+// Create registra o recibo, ignorando repetições do mesmo leitor e tipo para a mensagem
+func (r *MessageReceiptRepository) Create(ctx context.Context, receipt *models.MessageReceipt) error {
+	if receipt.ID == "" {
+		receipt.ID = uuid.New().String()
+	}
+
+	receipt.CreatedAt = time.Now()
+	if receipt.ReceivedAt.IsZero() {
+		receipt.ReceivedAt = receipt.CreatedAt
+	}
+
+	query := `
+		INSERT INTO message_receipts (id, sessionid, messageid, chatjid, readerjid, receipttype, receivedat, createdat)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (sessionid, messageid, readerjid, receipttype) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		receipt.ID, receipt.SessionID, receipt.MessageID, receipt.ChatJID,
+		receipt.ReaderJID, receipt.ReceiptType, receipt.ReceivedAt, receipt.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *MessageReceiptRepository) ListByMessageID(ctx context.Context, sessionID, messageID string) ([]*models.MessageReceipt, error) {
+	query := `
+		SELECT id, sessionid, messageid, chatjid, readerjid, receipttype, receivedat, createdat
+		FROM message_receipts WHERE sessionid = $1 AND messageid = $2 ORDER BY receivedat ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []*models.MessageReceipt
+	for rows.Next() {
+		receipt := &models.MessageReceipt{}
+		err := rows.Scan(
+			&receipt.ID, &receipt.SessionID, &receipt.MessageID, &receipt.ChatJID,
+			&receipt.ReaderJID, &receipt.ReceiptType, &receipt.ReceivedAt, &receipt.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, rows.Err()
+}
+
+func (r *MessageReceiptRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	query := `DELETE FROM message_receipts WHERE sessionid = $1`
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	return err
+}
@@ -24,7 +24,36 @@ func NewSessionRepository(db *sql.DB) *SessionRepository {
 	}
 }
 
+// sessionExecer é atendido por *sql.DB e *sql.Tx, permitindo gravar a sessão dentro ou fora de uma transação
+type sessionExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
 func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	return r.insert(ctx, r.db, session)
+}
+
+// CreateWith insere a sessão em uma transação e chama register antes do commit, de modo que a linha só
+// é gravada se register tiver sucesso. Se o commit falhar, cabe ao chamador desfazer o que register fez.
+func (r *SessionRepository) CreateWith(ctx context.Context, session *models.Session, register func(*models.Session) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.insert(ctx, tx, session); err != nil {
+		return err
+	}
+
+	if err := register(session); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SessionRepository) insert(ctx context.Context, db sessionExecer, session *models.Session) error {
 	if session.ID == "" {
 		session.ID = uuid.New().String()
 	}
@@ -39,16 +68,16 @@ func (r *SessionRepository) Create(ctx context.Context, session *models.Session)
 
 	query := `
 		INSERT INTO sessions (id, name, phone, status, qrcode, devicejid, 
-			proxyhost, proxyport, proxytype, proxyuser, proxypass, 
+			proxyhost, proxyport, proxytype, proxyuser, proxypass, platform, osname,
 			createdat, updatedat, connectedat)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := db.ExecContext(ctx, query,
 		session.ID, session.Name, session.Phone, session.Status, session.QRCode,
 		session.DeviceJid, session.ProxyHost, session.ProxyPort, session.ProxyType,
-		session.ProxyUser, session.ProxyPass, session.CreatedAt, session.UpdatedAt,
-		session.ConnectedAt,
+		session.ProxyUser, session.ProxyPass, session.Platform, session.OSName,
+		session.CreatedAt, session.UpdatedAt, session.ConnectedAt,
 	)
 
 	return err
@@ -57,16 +86,18 @@ func (r *SessionRepository) Create(ctx context.Context, session *models.Session)
 func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.Session, error) {
 	session := &models.Session{}
 	query := `
-		SELECT id, name, phone, status, qrcode, devicejid, proxyhost, proxyport,
-			proxytype, proxyuser, proxypass, createdat, updatedat, connectedat
+		SELECT id, name, phone, status, qrcode, qrcodeissuedat, qrcodeexpiresat, devicejid,
+			proxyhost, proxyport, proxytype, proxyuser, proxypass, platform, osname, storemessages,
+			createdat, updatedat, connectedat
 		FROM sessions WHERE id = $1
 	`
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&session.ID, &session.Name, &session.Phone, &session.Status, &session.QRCode,
+		&session.QRCodeIssuedAt, &session.QRCodeExpiresAt,
 		&session.DeviceJid, &session.ProxyHost, &session.ProxyPort, &session.ProxyType,
-		&session.ProxyUser, &session.ProxyPass, &session.CreatedAt, &session.UpdatedAt,
-		&session.ConnectedAt,
+		&session.ProxyUser, &session.ProxyPass, &session.Platform, &session.OSName, &session.StoreMessages,
+		&session.CreatedAt, &session.UpdatedAt, &session.ConnectedAt,
 	)
 
 	if err != nil {
@@ -81,12 +112,30 @@ func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.Ses
 
 func (r *SessionRepository) List(ctx context.Context) ([]*models.Session, error) {
 	query := `
-		SELECT id, name, phone, status, qrcode, devicejid, proxyhost, proxyport,
-			proxytype, proxyuser, proxypass, createdat, updatedat, connectedat
+		SELECT id, name, phone, status, qrcode, qrcodeissuedat, qrcodeexpiresat, devicejid,
+			proxyhost, proxyport, proxytype, proxyuser, proxypass, platform, osname, storemessages,
+			createdat, updatedat, connectedat
 		FROM sessions ORDER BY createdat DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	return r.querySessions(ctx, query)
+}
+
+// GetByName retorna todas as sessões com o nome informado, da mais recente para a mais antiga,
+// já que o nome não é único
+func (r *SessionRepository) GetByName(ctx context.Context, name string) ([]*models.Session, error) {
+	query := `
+		SELECT id, name, phone, status, qrcode, qrcodeissuedat, qrcodeexpiresat, devicejid,
+			proxyhost, proxyport, proxytype, proxyuser, proxypass, platform, osname, storemessages,
+			createdat, updatedat, connectedat
+		FROM sessions WHERE name = $1 ORDER BY createdat DESC
+	`
+
+	return r.querySessions(ctx, query, name)
+}
+
+func (r *SessionRepository) querySessions(ctx context.Context, query string, args ...interface{}) ([]*models.Session, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -97,9 +146,10 @@ func (r *SessionRepository) List(ctx context.Context) ([]*models.Session, error)
 		session := &models.Session{}
 		err := rows.Scan(
 			&session.ID, &session.Name, &session.Phone, &session.Status, &session.QRCode,
+			&session.QRCodeIssuedAt, &session.QRCodeExpiresAt,
 			&session.DeviceJid, &session.ProxyHost, &session.ProxyPort, &session.ProxyType,
-			&session.ProxyUser, &session.ProxyPass, &session.CreatedAt, &session.UpdatedAt,
-			&session.ConnectedAt,
+			&session.ProxyUser, &session.ProxyPass, &session.Platform, &session.OSName, &session.StoreMessages,
+			&session.CreatedAt, &session.UpdatedAt, &session.ConnectedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -117,7 +167,8 @@ func (r *SessionRepository) Update(ctx context.Context, session *models.Session)
 		UPDATE sessions
 		SET name = $2, phone = $3, status = $4, qrcode = $5, devicejid = $6,
 		    proxyhost = $7, proxyport = $8, proxytype = $9, proxyuser = $10, proxypass = $11,
-		    updatedat = $12, connectedat = $13
+		    updatedat = $12, connectedat = $13, qrcodeissuedat = $14, qrcodeexpiresat = $15,
+		    platform = $16, osname = $17
 		WHERE id = $1
 	`
 
@@ -125,6 +176,7 @@ func (r *SessionRepository) Update(ctx context.Context, session *models.Session)
 		session.ID, session.Name, session.Phone, session.Status, session.QRCode,
 		session.DeviceJid, session.ProxyHost, session.ProxyPort, session.ProxyType,
 		session.ProxyUser, session.ProxyPass, session.UpdatedAt, session.ConnectedAt,
+		session.QRCodeIssuedAt, session.QRCodeExpiresAt, session.Platform, session.OSName,
 	)
 
 	if err != nil {
@@ -181,9 +233,20 @@ func (r *SessionRepository) UpdateStatus(ctx context.Context, id string, status
 	return nil
 }
 
-func (r *SessionRepository) UpdateQRCode(ctx context.Context, id string, qrCode string) error {
-	query := `UPDATE sessions SET qrcode = $2, updatedat = $3 WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id, qrCode, time.Now())
+// UpdateQRCode salva o QR code atual com o horário de emissão e a validade informada.
+// Um qrCode vazio limpa o código e os horários associados.
+func (r *SessionRepository) UpdateQRCode(ctx context.Context, id string, qrCode string, timeout time.Duration) error {
+	now := time.Now()
+
+	var issuedAt, expiresAt *time.Time
+	if qrCode != "" {
+		expires := now.Add(timeout)
+		issuedAt = &now
+		expiresAt = &expires
+	}
+
+	query := `UPDATE sessions SET qrcode = $2, qrcodeissuedat = $3, qrcodeexpiresat = $4, updatedat = $5 WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id, qrCode, issuedAt, expiresAt, now)
 	if err != nil {
 		return err
 	}
@@ -268,6 +331,90 @@ func (r *SessionRepository) UpdateProxy(ctx context.Context, id string, proxyHos
 	return nil
 }
 
+// ClearProxy remove o proxy da sessão. As colunas voltam aos valores vazios gravados por Create, que
+// HasProxy trata como sessão sem proxy.
+func (r *SessionRepository) ClearProxy(ctx context.Context, id string) error {
+	query := `
+		UPDATE sessions
+		SET proxyhost = '', proxyport = 0, proxytype = '', proxyuser = '', proxypass = '', updatedat = $2
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("sessão não encontrada")
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) UpdateName(ctx context.Context, id string, name string) error {
+	query := `UPDATE sessions SET name = $2, updatedat = $3 WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id, name, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("sessão não encontrada")
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) UpdatePlatform(ctx context.Context, id string, platform, osName string) error {
+	query := `UPDATE sessions SET platform = $2, osname = $3, updatedat = $4 WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id, platform, osName, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("sessão não encontrada")
+	}
+
+	return nil
+}
+
+// UpdateStoreMessages define se a sessão armazena as mensagens recebidas
+func (r *SessionRepository) UpdateStoreMessages(ctx context.Context, id string, enabled bool) error {
+	query := `UPDATE sessions SET storemessages = $2, updatedat = $3 WHERE id = $1`
+	result, err := r.db.ExecContext(ctx, query, id, enabled, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("sessão não encontrada")
+	}
+
+	return nil
+}
+
 func (r *SessionRepository) UpdateDeviceJid(ctx context.Context, id string, deviceJid string) error {
 	query := `UPDATE sessions SET devicejid = $2, updatedat = $3 WHERE id = $1`
 	result, err := r.db.ExecContext(ctx, query, id, deviceJid, time.Now())
@@ -287,16 +434,52 @@ func (r *SessionRepository) UpdateDeviceJid(ctx context.Context, id string, devi
 	return nil
 }
 
-func (r *SessionRepository) GetAll(ctx context.Context) ([]models.Session, error) {
-	sessions, err := r.List(ctx)
+// ClearStaleQRCodes limpa o QR code das sessões em connecting cujo QR expirou antes do instante
+// informado, voltando-as para disconnected. Retorna os IDs afetados.
+func (r *SessionRepository) ClearStaleQRCodes(ctx context.Context, expiredBefore time.Time) ([]string, error) {
+	query := `
+		UPDATE sessions
+		SET status = $1, qrcode = '', qrcodeissuedat = NULL, qrcodeexpiresat = NULL, updatedat = $2
+		WHERE status = $3 AND qrcodeexpiresat IS NOT NULL AND qrcodeexpiresat < $4
+		RETURNING id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, models.StatusDisconnected, time.Now(), models.StatusConnecting, expiredBefore)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	var result []models.Session
-	for _, session := range sessions {
-		result = append(result, *session)
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// CountByStatus retorna a quantidade de sessões cadastradas agrupadas por status
+func (r *SessionRepository) CountByStatus(ctx context.Context) (map[models.SessionStatus]int, error) {
+	query := `SELECT status, COUNT(*) FROM sessions GROUP BY status`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[models.SessionStatus]int)
+	for rows.Next() {
+		var status models.SessionStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
 	}
 
-	return result, nil
+	return counts, rows.Err()
 }
@@ -32,15 +32,20 @@ func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook)
 	now := time.Now()
 	webhook.CreatedAt = now
 	webhook.UpdatedAt = now
+	if webhook.Events == "" {
+		webhook.Events = "[]"
+	}
 
 	query := `
-		INSERT INTO webhooks (id, sessionid, url, events, createdat, updatedat)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO webhooks (id, sessionid, url, events, secret, maxretries, retrydelay, maxretrydelay,
+		                      backoff, deliverydeadline, filterfromme, filterisgroup, createdat, updatedat)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
 		webhook.ID, webhook.SessionID, webhook.URL, webhook.Events,
-		webhook.CreatedAt, webhook.UpdatedAt,
+		webhook.Secret, webhook.MaxRetries, webhook.RetryDelay, webhook.MaxRetryDelay,
+		webhook.Backoff, webhook.DeliveryDeadline, webhook.FilterFromMe, webhook.FilterIsGroup, webhook.CreatedAt, webhook.UpdatedAt,
 	)
 
 	return err
@@ -49,13 +54,14 @@ func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook)
 func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
 	webhook := &models.Webhook{}
 	query := `
-		SELECT id, sessionid, url, events, createdat, updatedat
+		SELECT id, sessionid, url, events, secret, maxretries, retrydelay, maxretrydelay, backoff, deliverydeadline, filterfromme, filterisgroup, createdat, updatedat
 		FROM webhooks WHERE id = $1
 	`
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&webhook.ID, &webhook.SessionID, &webhook.URL, &webhook.Events,
-		&webhook.CreatedAt, &webhook.UpdatedAt,
+		&webhook.Secret, &webhook.MaxRetries, &webhook.RetryDelay, &webhook.MaxRetryDelay,
+		&webhook.Backoff, &webhook.DeliveryDeadline, &webhook.FilterFromMe, &webhook.FilterIsGroup, &webhook.CreatedAt, &webhook.UpdatedAt,
 	)
 
 	if err != nil {
@@ -70,7 +76,7 @@ func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*models.Web
 
 func (r *WebhookRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*models.Webhook, error) {
 	query := `
-		SELECT id, sessionid, url, events, createdat, updatedat
+		SELECT id, sessionid, url, events, secret, maxretries, retrydelay, maxretrydelay, backoff, deliverydeadline, filterfromme, filterisgroup, createdat, updatedat
 		FROM webhooks WHERE sessionid = $1 ORDER BY createdat DESC
 	`
 
@@ -85,7 +91,8 @@ func (r *WebhookRepository) GetBySessionID(ctx context.Context, sessionID string
 		webhook := &models.Webhook{}
 		err := rows.Scan(
 			&webhook.ID, &webhook.SessionID, &webhook.URL, &webhook.Events,
-			&webhook.CreatedAt, &webhook.UpdatedAt,
+			&webhook.Secret, &webhook.MaxRetries, &webhook.RetryDelay, &webhook.MaxRetryDelay,
+			&webhook.Backoff, &webhook.DeliveryDeadline, &webhook.FilterFromMe, &webhook.FilterIsGroup, &webhook.CreatedAt, &webhook.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -98,7 +105,7 @@ func (r *WebhookRepository) GetBySessionID(ctx context.Context, sessionID string
 
 func (r *WebhookRepository) List(ctx context.Context) ([]*models.Webhook, error) {
 	query := `
-		SELECT id, sessionid, url, events, createdat, updatedat
+		SELECT id, sessionid, url, events, secret, maxretries, retrydelay, maxretrydelay, backoff, deliverydeadline, filterfromme, filterisgroup, createdat, updatedat
 		FROM webhooks ORDER BY createdat DESC
 	`
 
@@ -113,7 +120,8 @@ func (r *WebhookRepository) List(ctx context.Context) ([]*models.Webhook, error)
 		webhook := &models.Webhook{}
 		err := rows.Scan(
 			&webhook.ID, &webhook.SessionID, &webhook.URL, &webhook.Events,
-			&webhook.CreatedAt, &webhook.UpdatedAt,
+			&webhook.Secret, &webhook.MaxRetries, &webhook.RetryDelay, &webhook.MaxRetryDelay,
+			&webhook.Backoff, &webhook.DeliveryDeadline, &webhook.FilterFromMe, &webhook.FilterIsGroup, &webhook.CreatedAt, &webhook.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -126,15 +134,22 @@ func (r *WebhookRepository) List(ctx context.Context) ([]*models.Webhook, error)
 
 func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
 	webhook.UpdatedAt = time.Now()
+	if webhook.Events == "" {
+		webhook.Events = "[]"
+	}
 
 	query := `
 		UPDATE webhooks
-		SET sessionid = $2, url = $3, events = $4, updatedat = $5
+		SET sessionid = $2, url = $3, events = $4, secret = $5, maxretries = $6,
+		    retrydelay = $7, maxretrydelay = $8, backoff = $9, deliverydeadline = $10,
+		    filterfromme = $11, filterisgroup = $12, updatedat = $13
 		WHERE id = $1
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
-		webhook.ID, webhook.SessionID, webhook.URL, webhook.Events, webhook.UpdatedAt,
+		webhook.ID, webhook.SessionID, webhook.URL, webhook.Events,
+		webhook.Secret, webhook.MaxRetries, webhook.RetryDelay, webhook.MaxRetryDelay,
+		webhook.Backoff, webhook.DeliveryDeadline, webhook.FilterFromMe, webhook.FilterIsGroup, webhook.UpdatedAt,
 	)
 
 	if err != nil {
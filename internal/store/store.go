@@ -45,7 +45,7 @@ func NewStore(cfg *config.Config) (*Store, error) {
 	}
 
 	// Criar container WhatsApp
-	waLogger := logger.ForWhatsApp("store")
+	waLogger := logger.NewWhatsAppLogger("store", cfg.App.WALogLevel)
 	container := sqlstore.NewWithDB(db, "postgres", waLogger)
 
 	if err := container.Upgrade(context.Background()); err != nil {
@@ -81,6 +81,11 @@ func (s *Store) GetContainer() *sqlstore.Container {
 	return s.container
 }
 
+// GetConfig retorna a configuração da aplicação
+func (s *Store) GetConfig() *config.Config {
+	return s.config
+}
+
 // GetSessionRepository retorna o repositório de sessões
 func (s *Store) GetSessionRepository() SessionRepositoryInterface {
 	return s.sessionRepo
@@ -91,6 +96,23 @@ func (s *Store) GetWebhookRepository() WebhookRepositoryInterface {
 	return s.webhookRepo
 }
 
+// PingDatabase verifica se o banco da aplicação está acessível
+func (s *Store) PingDatabase(ctx context.Context) error {
+	if s.db == nil {
+		return fmt.Errorf("banco de dados não inicializado")
+	}
+	return s.db.PingContext(ctx)
+}
+
+// PingContainer verifica se o store de dispositivos do WhatsApp responde a consultas
+func (s *Store) PingContainer(ctx context.Context) error {
+	if s.container == nil {
+		return fmt.Errorf("container do WhatsApp não inicializado")
+	}
+	_, err := s.container.GetAllDevices(ctx)
+	return err
+}
+
 // Close fecha as conexões
 func (s *Store) Close() error {
 	if s.db != nil {
@@ -113,6 +135,36 @@ func (s *Store) createAppTables(ctx context.Context) error {
 		return fmt.Errorf("erro ao criar tabela webhooks: %w", err)
 	}
 
+	// Criar tabela de edições de mensagens
+	if err := s.createMessageEditsTable(ctx); err != nil {
+		return fmt.Errorf("erro ao criar tabela message_edits: %w", err)
+	}
+
+	// Criar tabela de recibos de leitura por participante
+	if err := s.createMessageReceiptsTable(ctx); err != nil {
+		return fmt.Errorf("erro ao criar tabela message_receipts: %w", err)
+	}
+
+	// Criar tabela de chats conhecidos da sessão
+	if err := s.createChatsTable(ctx); err != nil {
+		return fmt.Errorf("erro ao criar tabela chats: %w", err)
+	}
+
+	// Criar tabela de mensagens armazenadas
+	if err := s.createMessagesTable(ctx); err != nil {
+		return fmt.Errorf("erro ao criar tabela messages: %w", err)
+	}
+
+	// Criar tabela de chaves de API
+	if err := s.createAPIKeysTable(ctx); err != nil {
+		return fmt.Errorf("erro ao criar tabela api_keys: %w", err)
+	}
+
+	// Criar tabelas de broadcasts
+	if err := s.createBroadcastTables(ctx); err != nil {
+		return fmt.Errorf("erro ao criar tabelas de broadcast: %w", err)
+	}
+
 	// Criar índices
 	if err := s.createIndexes(ctx); err != nil {
 		return fmt.Errorf("erro ao criar índices: %w", err)
@@ -139,11 +191,34 @@ func (s *Store) createSessionsTable(ctx context.Context) error {
 			proxypass VARCHAR(255),
 			createdat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updatedat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			connectedat TIMESTAMP
+			connectedat TIMESTAMP,
+			qrcodeissuedat TIMESTAMP,
+			qrcodeexpiresat TIMESTAMP,
+			platform VARCHAR(50) NOT NULL DEFAULT '',
+			osname VARCHAR(100) NOT NULL DEFAULT '',
+			storemessages BOOLEAN NOT NULL DEFAULT FALSE
 		)`
 
-	_, err := s.db.ExecContext(ctx, query)
-	return err
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	// Colunas adicionadas após a criação inicial da tabela
+	migrations := []string{
+		`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS qrcodeissuedat TIMESTAMP`,
+		`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS qrcodeexpiresat TIMESTAMP`,
+		`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS platform VARCHAR(50) NOT NULL DEFAULT ''`,
+		`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS osname VARCHAR(100) NOT NULL DEFAULT ''`,
+		`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS storemessages BOOLEAN NOT NULL DEFAULT FALSE`,
+	}
+
+	for _, migration := range migrations {
+		if _, err := s.db.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("erro ao migrar tabela sessions: %w", err)
+		}
+	}
+
+	return nil
 }
 
 // createWebhooksTable cria a tabela de webhooks
@@ -153,22 +228,221 @@ func (s *Store) createWebhooksTable(ctx context.Context) error {
 			id VARCHAR(255) PRIMARY KEY,
 			sessionid VARCHAR(255) NOT NULL,
 			url VARCHAR(500) NOT NULL,
-			events TEXT,
+			events JSONB NOT NULL DEFAULT '[]',
+			secret VARCHAR(255) NOT NULL DEFAULT '',
+			maxretries INTEGER NOT NULL DEFAULT 3,
+			retrydelay INTEGER NOT NULL DEFAULT 5,
+			maxretrydelay INTEGER NOT NULL DEFAULT 300,
+			backoff VARCHAR(20) NOT NULL DEFAULT 'linear',
+			deliverydeadline INTEGER NOT NULL DEFAULT 3600,
+			filterfromme BOOLEAN,
+			filterisgroup BOOLEAN,
 			createdat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updatedat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (sessionid) REFERENCES sessions(id) ON DELETE CASCADE
 		)`
 
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	// Colunas adicionadas após a criação inicial da tabela
+	migrations := []string{
+		`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS secret VARCHAR(255) NOT NULL DEFAULT ''`,
+		`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS maxretries INTEGER NOT NULL DEFAULT 3`,
+		`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS retrydelay INTEGER NOT NULL DEFAULT 5`,
+		`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS backoff VARCHAR(20) NOT NULL DEFAULT 'linear'`,
+		`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS maxretrydelay INTEGER NOT NULL DEFAULT 300`,
+		`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS deliverydeadline INTEGER NOT NULL DEFAULT 3600`,
+		`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS filterfromme BOOLEAN`,
+		`ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS filterisgroup BOOLEAN`,
+		// Eventos eram gravados como texto separado por vírgula; converte para array JSON
+		`DO $$
+		BEGIN
+			IF EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name = 'webhooks' AND column_name = 'events' AND data_type = 'text'
+			) THEN
+				ALTER TABLE webhooks ALTER COLUMN events TYPE JSONB USING (
+					CASE
+						WHEN events IS NULL OR btrim(events) = '' THEN '[]'::jsonb
+						WHEN left(btrim(events), 1) = '[' THEN events::jsonb
+						ELSE to_jsonb(regexp_split_to_array(btrim(events), '\s*,\s*'))
+					END
+				);
+				ALTER TABLE webhooks ALTER COLUMN events SET DEFAULT '[]';
+				ALTER TABLE webhooks ALTER COLUMN events SET NOT NULL;
+			END IF;
+		END $$`,
+	}
+
+	for _, migration := range migrations {
+		if _, err := s.db.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("erro ao migrar tabela webhooks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// createMessageEditsTable cria a tabela de histórico de edições de mensagens
+func (s *Store) createMessageEditsTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS message_edits (
+			id VARCHAR(255) PRIMARY KEY,
+			sessionid VARCHAR(255) NOT NULL,
+			messageid VARCHAR(255) NOT NULL,
+			chatjid VARCHAR(255) NOT NULL,
+			senderjid VARCHAR(255),
+			content TEXT,
+			isfromme BOOLEAN NOT NULL DEFAULT FALSE,
+			source VARCHAR(20) NOT NULL,
+			editedat TIMESTAMP NOT NULL,
+			createdat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (sessionid) REFERENCES sessions(id) ON DELETE CASCADE
+		)`
+
 	_, err := s.db.ExecContext(ctx, query)
 	return err
 }
 
+// createMessageReceiptsTable cria a tabela de recibos de leitura por participante de grupo
+func (s *Store) createMessageReceiptsTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS message_receipts (
+			id VARCHAR(255) PRIMARY KEY,
+			sessionid VARCHAR(255) NOT NULL,
+			messageid VARCHAR(255) NOT NULL,
+			chatjid VARCHAR(255) NOT NULL,
+			readerjid VARCHAR(255) NOT NULL,
+			receipttype VARCHAR(20) NOT NULL,
+			receivedat TIMESTAMP NOT NULL,
+			createdat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (sessionid, messageid, readerjid, receipttype),
+			FOREIGN KEY (sessionid) REFERENCES sessions(id) ON DELETE CASCADE
+		)`
+
+	_, err := s.db.ExecContext(ctx, query)
+	return err
+}
+
+// createChatsTable cria a tabela de chats conhecidos, alimentada pela sincronização de histórico, pelo
+// app state e pelas mensagens
+func (s *Store) createChatsTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS chats (
+			sessionid VARCHAR(255) NOT NULL,
+			jid VARCHAR(255) NOT NULL,
+			name VARCHAR(255) NOT NULL DEFAULT '',
+			lastmessageat TIMESTAMP,
+			unreadcount INTEGER NOT NULL DEFAULT 0,
+			markedunread BOOLEAN NOT NULL DEFAULT FALSE,
+			archived BOOLEAN NOT NULL DEFAULT FALSE,
+			pinned BOOLEAN NOT NULL DEFAULT FALSE,
+			muteduntil BIGINT NOT NULL DEFAULT 0,
+			updatedat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (sessionid, jid),
+			FOREIGN KEY (sessionid) REFERENCES sessions(id) ON DELETE CASCADE
+		)`
+
+	_, err := s.db.ExecContext(ctx, query)
+	return err
+}
+
+// createMessagesTable cria a tabela de mensagens armazenadas das sessões que optaram pelo armazenamento
+func (s *Store) createMessagesTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS messages (
+			sessionid VARCHAR(255) NOT NULL,
+			chatjid VARCHAR(255) NOT NULL,
+			messageid VARCHAR(255) NOT NULL,
+			senderjid VARCHAR(255) NOT NULL DEFAULT '',
+			isfromme BOOLEAN NOT NULL DEFAULT FALSE,
+			isgroup BOOLEAN NOT NULL DEFAULT FALSE,
+			type VARCHAR(50) NOT NULL,
+			text TEXT NOT NULL DEFAULT '',
+			sentat TIMESTAMP NOT NULL,
+			createdat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (sessionid, chatjid, messageid),
+			FOREIGN KEY (sessionid) REFERENCES sessions(id) ON DELETE CASCADE
+		)`
+
+	_, err := s.db.ExecContext(ctx, query)
+	return err
+}
+
+// createAPIKeysTable cria a tabela de chaves de API. sessionid vazio indica uma chave sem restrição de sessão
+func (s *Store) createAPIKeysTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS api_keys (
+			id VARCHAR(255) PRIMARY KEY,
+			keyhash VARCHAR(64) NOT NULL UNIQUE,
+			prefix VARCHAR(16) NOT NULL,
+			label VARCHAR(255) NOT NULL DEFAULT '',
+			sessionid VARCHAR(255) NOT NULL DEFAULT '',
+			createdat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			lastusedat TIMESTAMP,
+			revokedat TIMESTAMP
+		)`
+
+	_, err := s.db.ExecContext(ctx, query)
+	return err
+}
+
+// createBroadcastTables cria as tabelas de jobs de broadcast e de seus destinatários
+func (s *Store) createBroadcastTables(ctx context.Context) error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS broadcast_jobs (
+			id VARCHAR(255) PRIMARY KEY,
+			sessionid VARCHAR(255) NOT NULL,
+			message TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			total INTEGER NOT NULL DEFAULT 0,
+			sent INTEGER NOT NULL DEFAULT 0,
+			failed INTEGER NOT NULL DEFAULT 0,
+			lasterror TEXT NOT NULL DEFAULT '',
+			createdat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updatedat TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			startedat TIMESTAMP,
+			finishedat TIMESTAMP,
+			FOREIGN KEY (sessionid) REFERENCES sessions(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS broadcast_recipients (
+			id VARCHAR(255) PRIMARY KEY,
+			jobid VARCHAR(255) NOT NULL,
+			position INTEGER NOT NULL,
+			phone VARCHAR(255) NOT NULL,
+			jid VARCHAR(255) NOT NULL,
+			text TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			messageid VARCHAR(255) NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			sentat TIMESTAMP,
+			FOREIGN KEY (jobid) REFERENCES broadcast_jobs(id) ON DELETE CASCADE
+		)`,
+	}
+
+	for _, query := range queries {
+		if _, err := s.db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // createIndexes cria os índices das tabelas
 func (s *Store) createIndexes(ctx context.Context) error {
 	indexes := []string{
 		`CREATE INDEX IF NOT EXISTS idx_sessions_status ON sessions(status)`,
 		`CREATE INDEX IF NOT EXISTS idx_sessions_devicejid ON sessions(devicejid)`,
 		`CREATE INDEX IF NOT EXISTS idx_webhooks_sessionid ON webhooks(sessionid)`,
+		`CREATE INDEX IF NOT EXISTS idx_message_edits_message ON message_edits(sessionid, messageid)`,
+		`CREATE INDEX IF NOT EXISTS idx_messages_session_sentat ON messages(sessionid, sentat DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_messages_chat_sentat ON messages(sessionid, chatjid, sentat DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_messages_sentat ON messages(sentat)`,
+		`CREATE INDEX IF NOT EXISTS idx_broadcast_jobs_status ON broadcast_jobs(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_broadcast_recipients_pending ON broadcast_recipients(jobid, status, position)`,
 	}
 
 	for _, query := range indexes {
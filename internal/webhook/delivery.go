@@ -1,11 +1,9 @@
 package webhook
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"zpigo/internal/logger"
@@ -13,6 +11,12 @@ import (
 
 func (wm *Manager) processDelivery(delivery *Delivery, workerLogger logger.Logger) {
 	startTime := time.Now()
+	if !delivery.Deadline.IsZero() && startTime.After(delivery.Deadline) {
+		delivery.Error = "Prazo da entrega esgotado antes da tentativa"
+		wm.expireDelivery(delivery, workerLogger)
+		return
+	}
+
 	delivery.Attempts++
 	delivery.LastAttempt = startTime
 
@@ -27,7 +31,8 @@ func (wm *Manager) processDelivery(delivery *Delivery, workerLogger logger.Logge
 		delivery.Status = string(StatusFailed)
 		delivery.Error = fmt.Sprintf("Erro ao serializar payload: %v", err)
 		workerLogger.Error("Erro ao serializar payload", "error", err, "deliveryID", delivery.ID)
-		wm.incrementStat("total_failed")
+		wm.incrementStat(delivery.SessionID, "total_failed")
+		wm.recordDelivery(delivery)
 		return
 	}
 
@@ -36,19 +41,19 @@ func (wm *Manager) processDelivery(delivery *Delivery, workerLogger logger.Logge
 		SetHeader("User-Agent", "ZPigo-Webhook/1.0").
 		SetBody(payloadBytes)
 
-	config, exists := wm.GetConfig(delivery.SessionID)
-	if exists && config.Headers != nil {
+	timestamp := time.Now().Unix()
+
+	if config := delivery.Config; config != nil {
 		for key, value := range config.Headers {
 			req.SetHeader(key, value)
 		}
-	}
 
-	if exists && config.Secret != "" {
-		signature := wm.generateSignature(payloadBytes, config.Secret)
-		req.SetHeader("X-Webhook-Signature", signature)
+		if config.Secret != "" {
+			req.SetHeader(SignatureHeader, SignPayload(payloadBytes, timestamp, config.Secret))
+		}
 	}
 
-	req.SetHeader("X-Webhook-Timestamp", fmt.Sprintf("%d", time.Now().Unix()))
+	req.SetHeader(TimestampHeader, strconv.FormatInt(timestamp, 10))
 
 	resp, err := req.Post(delivery.URL)
 	duration := time.Since(startTime)
@@ -66,7 +71,8 @@ func (wm *Manager) processDelivery(delivery *Delivery, workerLogger logger.Logge
 			"deliveryID", delivery.ID,
 			"statusCode", resp.StatusCode(),
 			"duration", duration)
-		wm.incrementStat("total_success")
+		wm.incrementStat(delivery.SessionID, "total_success")
+		wm.recordDelivery(delivery)
 	} else {
 		delivery.Error = fmt.Sprintf("Status code inválido: %d", resp.StatusCode())
 		wm.handleDeliveryFailure(delivery, workerLogger)
@@ -79,37 +85,71 @@ func (wm *Manager) handleDeliveryFailure(delivery *Delivery, workerLogger logger
 		"attempt", delivery.Attempts,
 		"error", delivery.Error)
 
-	if delivery.Attempts < delivery.MaxRetries {
-		backoffDelay := time.Duration(delivery.Attempts) * 5 * time.Second
-		delivery.NextRetry = time.Now().Add(backoffDelay)
+	if delivery.Attempts >= delivery.MaxRetries {
+		wm.expireDelivery(delivery, workerLogger)
+		return
+	}
+
+	backoffDelay := time.Duration(delivery.Attempts) * 5 * time.Second
+	if config := delivery.Config; config != nil && config.RetryDelay > 0 {
+		backoffDelay = config.NextRetryDelay(delivery.Attempts)
+	}
+	delivery.NextRetry = time.Now().Add(backoffDelay)
 
-		workerLogger.Info("Agendando retry",
+	if !delivery.Deadline.IsZero() && delivery.NextRetry.After(delivery.Deadline) {
+		workerLogger.Warn("Próxima tentativa ultrapassaria o prazo da entrega",
 			"deliveryID", delivery.ID,
 			"nextRetry", delivery.NextRetry,
-			"backoffDelay", backoffDelay)
-
-		go func() {
-			time.Sleep(backoffDelay)
-			select {
-			case wm.deliveryQueue <- delivery:
-				wm.incrementStat("total_retries")
-			default:
-				workerLogger.Warn("Fila cheia, descartando retry", "deliveryID", delivery.ID)
-			}
-		}()
-	} else {
-		delivery.Status = string(StatusExpired)
-		workerLogger.Error("Delivery expirada após máximo de tentativas",
-			"deliveryID", delivery.ID,
-			"attempts", delivery.Attempts)
-		wm.incrementStat("total_failed")
+			"deadline", delivery.Deadline)
+		delivery.NextRetry = time.Time{}
+		wm.expireDelivery(delivery, workerLogger)
+		return
 	}
+
+	delivery.Status = string(StatusPending)
+	wm.recordDelivery(delivery)
+
+	workerLogger.Info("Agendando retry",
+		"deliveryID", delivery.ID,
+		"nextRetry", delivery.NextRetry,
+		"backoffDelay", backoffDelay)
+
+	wm.retryWG.Add(1)
+	go func() {
+		defer wm.retryWG.Done()
+
+		timer := time.NewTimer(backoffDelay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-wm.stopChan:
+			delivery.Error = "Gerenciador de webhooks parado antes do retry"
+			wm.expireDelivery(delivery, workerLogger)
+			return
+		}
+
+		if wm.enqueue(delivery, true) {
+			wm.incrementStat(delivery.SessionID, "total_retries")
+			return
+		}
+
+		workerLogger.Warn("Fila cheia, descartando retry", "deliveryID", delivery.ID)
+		delivery.Error = "Fila de webhooks cheia no momento do retry"
+		wm.incrementStat(delivery.SessionID, "total_dropped")
+		wm.expireDelivery(delivery, workerLogger)
+	}()
 }
 
-func (wm *Manager) generateSignature(payload []byte, secret string) string {
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write(payload)
-	return "sha256=" + hex.EncodeToString(h.Sum(nil))
+// expireDelivery encerra a entrega sem novas tentativas
+func (wm *Manager) expireDelivery(delivery *Delivery, workerLogger logger.Logger) {
+	delivery.Status = string(StatusExpired)
+	workerLogger.Error("Delivery expirada",
+		"deliveryID", delivery.ID,
+		"attempts", delivery.Attempts,
+		"error", delivery.Error)
+	wm.incrementStat(delivery.SessionID, "total_failed")
+	wm.recordDelivery(delivery)
 }
 
 func (wm *Manager) SendTestWebhook(sessionID, url string) error {
@@ -186,10 +226,6 @@ func (wm *Manager) ValidateWebhookEndpoint(url string) (*Response, error) {
 	}, nil
 }
 
-func (wm *Manager) GetDeliveryHistory(sessionID string, limit int) ([]*Delivery, error) {
-	return []*Delivery{}, nil
-}
-
 func (wm *Manager) RetryFailedDeliveries(sessionID string) error {
 	wm.logger.Info("Retry de deliveries falhadas solicitado", "sessionID", sessionID)
 	return nil
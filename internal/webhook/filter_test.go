@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"fmt"
+	"testing"
+)
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func formatBoolPtr(v *bool) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*v)
+}
+
+func TestFilterMatchesFromMeIsGroup(t *testing.T) {
+	options := []*bool{nil, boolPtr(true), boolPtr(false)}
+
+	for _, filterFromMe := range options {
+		for _, filterIsGroup := range options {
+			for _, fromMe := range []bool{true, false} {
+				for _, isGroup := range []bool{true, false} {
+					filter := NewFilter(filterFromMe, filterIsGroup)
+					attrs := EventAttributes{FromMe: boolPtr(fromMe), IsGroup: boolPtr(isGroup)}
+
+					want := (filterFromMe == nil || *filterFromMe == fromMe) &&
+						(filterIsGroup == nil || *filterIsGroup == isGroup)
+
+					name := fmt.Sprintf("filtro fromMe=%s isGroup=%s, evento fromMe=%v isGroup=%v",
+						formatBoolPtr(filterFromMe), formatBoolPtr(filterIsGroup), fromMe, isGroup)
+					t.Run(name, func(t *testing.T) {
+						if got := filter.Matches("session", EventMessage, attrs); got != want {
+							t.Errorf("Matches = %v, esperado %v", got, want)
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestFilterIgnoresEventsWithoutAttributes(t *testing.T) {
+	filter := NewFilter(boolPtr(false), boolPtr(true))
+
+	if !filter.Matches("session", EventConnected, EventAttributes{}) {
+		t.Error("evento sem isFromMe/isGroup deveria passar pelo filtro")
+	}
+}
+
+func TestManagerAcceptsAppliesFilter(t *testing.T) {
+	wm := NewManager(Options{Workers: 1})
+	defer wm.Stop()
+
+	// apenas mensagens recebidas em grupos
+	err := wm.SetConfig("session", &Config{
+		URL:     "https://example.com/webhook",
+		Events:  []EventType{EventMessage},
+		Enabled: true,
+		Filter:  NewFilter(boolPtr(false), boolPtr(true)),
+	})
+	if err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		fromMe  bool
+		isGroup bool
+		want    bool
+	}{
+		{"recebida em grupo", false, true, true},
+		{"recebida em conversa individual", false, false, false},
+		{"enviada em grupo", true, true, false},
+		{"enviada em conversa individual", true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]interface{}{"isFromMe": tt.fromMe, "isGroup": tt.isGroup}
+			if got := wm.Accepts("session", EventMessage, data); got != tt.want {
+				t.Errorf("Accepts = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,28 +16,79 @@ type Manager struct {
 
 	httpClient *resty.Client
 
-	deliveryQueue chan *Delivery
+	deliveryQueue  chan *Delivery
+	enqueueTimeout time.Duration
 
-	workers    int
-	stopChan   chan bool
-	workerWG   sync.WaitGroup
+	// queueMu protege o fechamento da fila: enqueue segura a leitura e Stop a escrita, para que
+	// nenhum envio aconteça em um canal fechado
+	queueMu     sync.RWMutex
+	queueClosed bool
+
+	workers  int
+	stopChan chan struct{}
+	stopOnce sync.Once
+	workerWG sync.WaitGroup
+	retryWG  sync.WaitGroup
 
 	logger logger.Logger
 
 	globalConfig *Config
 
-	stats Stats
-	statsMu sync.RWMutex
+	stats        Stats
+	sessionStats map[string]*Stats
+	statsMu      sync.RWMutex
+
+	history   map[string][]*Delivery
+	historyMu sync.RWMutex
+}
+
+// DeliveryHistorySize é a quantidade de entregas recentes mantidas por sessão
+const DeliveryHistorySize = 100
+
+const (
+	DefaultWorkers        = 10
+	DefaultQueueSize      = 1000
+	DefaultEnqueueTimeout = 500 * time.Millisecond
+)
+
+// Options configura o pool de workers e a fila de entregas
+type Options struct {
+	Workers   int // Workers que processam entregas em paralelo; zero usa DefaultWorkers
+	QueueSize int // Capacidade da fila de entregas; zero usa DefaultQueueSize
+
+	// EnqueueTimeout é quanto um evento prioritário (ver priorityEvents) espera por vaga na
+	// fila cheia antes de ser descartado. Zero descarta imediatamente, como os demais eventos.
+	EnqueueTimeout time.Duration
+}
+
+// priorityEvents são os eventos que aguardam vaga na fila cheia em vez de serem descartados
+// imediatamente, já que perdê-los costuma exigir intervenção manual
+var priorityEvents = map[EventType]bool{
+	EventMessage:     true,
+	EventFBMessage:   true,
+	EventReceipt:     true,
+	EventPairSuccess: true,
+	EventLoggedOut:   true,
 }
 
-func NewManager(workers int) *Manager {
+func NewManager(opts Options) *Manager {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+
 	wm := &Manager{
-		configs:       make(map[string]*Config),
-		httpClient:    newHTTPClient(),
-		deliveryQueue: make(chan *Delivery, 1000),
-		workers:       workers,
-		stopChan:      make(chan bool),
-		logger:        logger.NewForComponent("WebhookManager"),
+		configs:        make(map[string]*Config),
+		httpClient:     newHTTPClient(),
+		deliveryQueue:  make(chan *Delivery, opts.QueueSize),
+		enqueueTimeout: opts.EnqueueTimeout,
+		workers:        opts.Workers,
+		stopChan:       make(chan struct{}),
+		sessionStats:   make(map[string]*Stats),
+		history:        make(map[string][]*Delivery),
+		logger:         logger.NewForComponent("WebhookManager"),
 	}
 
 	wm.startWorkers()
@@ -50,7 +101,7 @@ func newHTTPClient() *resty.Client {
 	client.SetRedirectPolicy(resty.FlexibleRedirectPolicy(15))
 	client.SetTimeout(10 * time.Second)
 	client.SetRetryCount(0)
-	
+
 	return client
 }
 
@@ -63,22 +114,40 @@ func (wm *Manager) SetConfig(sessionID string, config *Config) error {
 		return fmt.Errorf("URL de webhook inválida: %s", config.URL)
 	}
 
-	if config.Timeout == 0 {
-		config.Timeout = 10 * time.Second
-	}
-	if config.MaxRetries == 0 {
-		config.MaxRetries = 3
-	}
-	if config.RetryDelay == 0 {
-		config.RetryDelay = 5 * time.Second
-	}
+	applyConfigDefaults(config)
 
 	wm.configs[sessionID] = config
 	wm.logger.Info("Webhook configurado", "sessionID", sessionID, "url", config.URL, "events", len(config.Events))
-	
+
 	return nil
 }
 
+// UpdateRetryPolicy altera a política de retry do webhook da sessão. A nova política vale
+// para as entregas enfileiradas a partir de agora.
+func (wm *Manager) UpdateRetryPolicy(sessionID string, policy RetryPolicy) (*Config, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, exists := wm.GetConfig(sessionID)
+	if !exists {
+		return nil, fmt.Errorf("webhook não configurado para a sessão %s", sessionID)
+	}
+
+	updated := *current
+	updated.MaxRetries = policy.MaxRetries
+	updated.RetryDelay = policy.RetryDelay
+	updated.MaxRetryDelay = policy.MaxRetryDelay
+	updated.Backoff = policy.Backoff
+	updated.Deadline = policy.Deadline
+
+	if err := wm.SetConfig(sessionID, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
 func (wm *Manager) GetConfig(sessionID string) (*Config, bool) {
 	wm.mu.RLock()
 	defer wm.mu.RUnlock()
@@ -93,43 +162,118 @@ func (wm *Manager) DeleteConfig(sessionID string) {
 	wm.logger.Info("Webhook removido", "sessionID", sessionID)
 }
 
+// GlobalDeliveryKey identifica as entregas do webhook global no histórico e nas estatísticas
+const GlobalDeliveryKey = "global"
+
+// SetGlobalConfig configura o webhook global, que recebe os eventos de todas as sessões, inclusive as
+// criadas depois. Ele não substitui os webhooks das sessões: um evento aceito pelos dois é entregue aos dois.
 func (wm *Manager) SetGlobalConfig(config *Config) error {
 	wm.mu.Lock()
 	defer wm.mu.Unlock()
-	
+
 	if !isValidURL(config.URL) {
 		return fmt.Errorf("URL de webhook global inválida: %s", config.URL)
 	}
-	
+
+	applyConfigDefaults(config)
+
 	wm.globalConfig = config
-	wm.logger.Info("Webhook global configurado", "url", config.URL)
-	
+	wm.logger.Info("Webhook global configurado", "url", config.URL, "events", len(config.Events))
+
 	return nil
 }
 
+// GetGlobalConfig retorna o webhook global, se configurado
+func (wm *Manager) GetGlobalConfig() (*Config, bool) {
+	wm.mu.RLock()
+	defer wm.mu.RUnlock()
+
+	return wm.globalConfig, wm.globalConfig != nil
+}
+
+// ClearGlobalConfig remove o webhook global; os webhooks das sessões continuam ativos
+func (wm *Manager) ClearGlobalConfig() {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	wm.globalConfig = nil
+	wm.logger.Info("Webhook global removido")
+}
+
+// GlobalSubscribes indica se o webhook global está ativo e inscrito no tipo de evento. Os filtros por
+// dados do evento são avaliados depois, em Send.
+func (wm *Manager) GlobalSubscribes(eventType EventType) bool {
+	wm.mu.RLock()
+	globalConfig := wm.globalConfig
+	wm.mu.RUnlock()
+
+	return globalConfig != nil && globalConfig.Enabled && wm.shouldSendEvent(globalConfig.Events, eventType)
+}
+
+// applyConfigDefaults preenche os parâmetros de entrega não informados
+func applyConfigDefaults(config *Config) {
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = 5 * time.Second
+	}
+	if config.Backoff == "" {
+		config.Backoff = BackoffExponential
+	}
+	if config.MaxRetryDelay == 0 {
+		config.MaxRetryDelay = MaxRetryDelay
+	}
+	if config.Deadline == 0 {
+		config.Deadline = DefaultDeliveryDeadline
+	}
+}
+
 func (wm *Manager) Send(sessionID string, eventType EventType, eventData interface{}, additionalData map[string]interface{}) {
-	config, hasSessionConfig := wm.GetConfig(sessionID)
-	
-	if hasSessionConfig && config.Enabled && wm.shouldSendEvent(config.Events, string(eventType)) {
-		wm.queueDelivery(sessionID, config, eventType, eventData, additionalData)
+	attrs := EventAttributesFromData(eventData)
+
+	if config, exists := wm.GetConfig(sessionID); exists && wm.accepts(config, sessionID, eventType, attrs) {
+		wm.queueDelivery(sessionID, sessionID, config, eventType, eventData, additionalData)
 	}
 
 	wm.mu.RLock()
 	globalConfig := wm.globalConfig
 	wm.mu.RUnlock()
 
-	if globalConfig != nil && globalConfig.Enabled && wm.shouldSendEvent(globalConfig.Events, string(eventType)) {
-		wm.queueDelivery("global", globalConfig, eventType, eventData, additionalData)
+	if globalConfig != nil && wm.accepts(globalConfig, sessionID, eventType, attrs) {
+		wm.queueDelivery(GlobalDeliveryKey, sessionID, globalConfig, eventType, eventData, additionalData)
 	}
 }
 
-func (wm *Manager) shouldSendEvent(configuredEvents []string, eventType string) bool {
+// Accepts indica se o evento, com os dados informados, seria entregue ao webhook da sessão ou ao global
+func (wm *Manager) Accepts(sessionID string, eventType EventType, eventData interface{}) bool {
+	attrs := EventAttributesFromData(eventData)
+
+	if config, exists := wm.GetConfig(sessionID); exists && wm.accepts(config, sessionID, eventType, attrs) {
+		return true
+	}
+
+	wm.mu.RLock()
+	globalConfig := wm.globalConfig
+	wm.mu.RUnlock()
+
+	return globalConfig != nil && wm.accepts(globalConfig, sessionID, eventType, attrs)
+}
+
+func (wm *Manager) accepts(config *Config, sessionID string, eventType EventType, attrs EventAttributes) bool {
+	return config.Enabled && wm.shouldSendEvent(config.Events, eventType) && config.Filter.Matches(sessionID, eventType, attrs)
+}
+
+func (wm *Manager) shouldSendEvent(configuredEvents []EventType, eventType EventType) bool {
 	if len(configuredEvents) == 0 {
 		return false
 	}
 
 	for _, event := range configuredEvents {
-		if event == "All" || event == eventType {
+		if event == EventAll || event == eventType {
 			return true
 		}
 	}
@@ -137,7 +281,9 @@ func (wm *Manager) shouldSendEvent(configuredEvents []string, eventType string)
 	return false
 }
 
-func (wm *Manager) queueDelivery(sessionID string, config *Config, eventType EventType, eventData interface{}, additionalData map[string]interface{}) {
+// queueDelivery enfileira a entrega do evento da sessão sessionID. key identifica o webhook no histórico
+// e nas estatísticas: o ID da sessão, ou GlobalDeliveryKey para o webhook global.
+func (wm *Manager) queueDelivery(key, sessionID string, config *Config, eventType EventType, eventData interface{}, additionalData map[string]interface{}) {
 	payload := &Payload{
 		Type:      string(eventType),
 		SessionID: sessionID,
@@ -146,49 +292,178 @@ func (wm *Manager) queueDelivery(sessionID string, config *Config, eventType Eve
 		Data:      additionalData,
 	}
 
+	snapshot := *config
+	now := time.Now()
+
 	delivery := &Delivery{
-		ID:         fmt.Sprintf("%s-%d", sessionID, time.Now().UnixNano()),
-		SessionID:  sessionID,
+		ID:         fmt.Sprintf("%s-%d", key, now.UnixNano()),
+		SessionID:  key,
 		URL:        config.URL,
 		Payload:    payload,
 		Attempts:   0,
 		MaxRetries: config.MaxRetries,
 		Status:     string(StatusPending),
+		Config:     &snapshot,
+	}
+	if snapshot.Deadline > 0 {
+		delivery.Deadline = now.Add(snapshot.Deadline)
+	}
+
+	// Registrado antes de enfileirar: depois disso a entrega pertence ao worker
+	wm.recordDelivery(delivery)
+
+	if !wm.enqueue(delivery, priorityEvents[eventType]) {
+		delivery.Status = string(StatusFailed)
+		delivery.Error = "Fila de webhooks cheia"
+		wm.recordDrop(delivery, eventType)
+		return
+	}
+
+	wm.logger.Debug("Webhook enfileirado", "sessionID", sessionID, "webhook", key, "eventType", eventType, "url", config.URL)
+	wm.incrementStat(key, "total_sent")
+}
+
+// enqueue coloca a entrega na fila. Com a fila cheia, retorna false imediatamente, a menos que
+// wait seja true: nesse caso aguarda uma vaga por até enqueueTimeout. Depois de Stop, sempre
+// retorna false.
+func (wm *Manager) enqueue(delivery *Delivery, wait bool) bool {
+	wm.queueMu.RLock()
+	defer wm.queueMu.RUnlock()
+
+	if wm.queueClosed {
+		return false
 	}
 
 	select {
 	case wm.deliveryQueue <- delivery:
-		wm.logger.Debug("Webhook enfileirado", "sessionID", sessionID, "eventType", eventType, "url", config.URL)
-		wm.incrementStat("total_sent")
+		return true
 	default:
-		wm.logger.Warn("Fila de webhooks cheia, descartando delivery", "sessionID", sessionID, "eventType", eventType)
+	}
+
+	if !wait || wm.enqueueTimeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(wm.enqueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case wm.deliveryQueue <- delivery:
+		return true
+	case <-timer.C:
+		return false
+	case <-wm.stopChan:
+		return false
 	}
 }
 
+// recordDrop contabiliza e registra uma entrega descartada por falta de vaga na fila
+func (wm *Manager) recordDrop(delivery *Delivery, eventType EventType) {
+	wm.incrementStat(delivery.SessionID, "total_dropped")
+	wm.recordDelivery(delivery)
+
+	wm.statsMu.RLock()
+	dropped := wm.stats.TotalDropped
+	wm.statsMu.RUnlock()
+
+	wm.logger.Error("Fila de webhooks cheia, entrega descartada",
+		"sessionID", delivery.SessionID,
+		"deliveryID", delivery.ID,
+		"eventType", eventType,
+		"queueCapacity", cap(wm.deliveryQueue),
+		"totalDropped", dropped)
+}
+
 func (wm *Manager) GetStats() Stats {
 	wm.statsMu.RLock()
 	defer wm.statsMu.RUnlock()
-	
+
 	stats := wm.stats
 	stats.QueueSize = len(wm.deliveryQueue)
-	
+
 	return stats
 }
 
-func (wm *Manager) incrementStat(stat string) {
+// GetSessionStats retorna as estatísticas de entrega de uma sessão. QueueSize não é
+// calculado por sessão e fica zerado.
+func (wm *Manager) GetSessionStats(sessionID string) Stats {
+	wm.statsMu.RLock()
+	defer wm.statsMu.RUnlock()
+
+	if stats, exists := wm.sessionStats[sessionID]; exists {
+		return *stats
+	}
+
+	return Stats{}
+}
+
+func (wm *Manager) incrementStat(sessionID, stat string) {
 	wm.statsMu.Lock()
 	defer wm.statsMu.Unlock()
-	
-	switch stat {
-	case "total_sent":
-		wm.stats.TotalSent++
-	case "total_success":
-		wm.stats.TotalSuccess++
-	case "total_failed":
-		wm.stats.TotalFailed++
-	case "total_retries":
-		wm.stats.TotalRetries++
+
+	sessionStats, exists := wm.sessionStats[sessionID]
+	if !exists {
+		sessionStats = &Stats{}
+		wm.sessionStats[sessionID] = sessionStats
+	}
+
+	for _, stats := range []*Stats{&wm.stats, sessionStats} {
+		switch stat {
+		case "total_sent":
+			stats.TotalSent++
+		case "total_success":
+			stats.TotalSuccess++
+		case "total_failed":
+			stats.TotalFailed++
+		case "total_retries":
+			stats.TotalRetries++
+		case "total_dropped":
+			stats.TotalDropped++
+		}
+	}
+}
+
+// recordDelivery guarda uma cópia do estado atual da entrega no histórico da sessão,
+// substituindo o registro anterior da mesma entrega
+func (wm *Manager) recordDelivery(delivery *Delivery) {
+	snapshot := *delivery
+
+	wm.historyMu.Lock()
+	defer wm.historyMu.Unlock()
+
+	entries := wm.history[delivery.SessionID]
+	for i, entry := range entries {
+		if entry.ID == delivery.ID {
+			entries[i] = &snapshot
+			return
+		}
 	}
+
+	entries = append(entries, &snapshot)
+	if len(entries) > DeliveryHistorySize {
+		entries = entries[len(entries)-DeliveryHistorySize:]
+	}
+	wm.history[delivery.SessionID] = entries
+}
+
+// GetDeliveryHistory retorna as entregas recentes da sessão, da mais nova para a mais antiga.
+// limit menor ou igual a zero retorna todo o histórico mantido.
+func (wm *Manager) GetDeliveryHistory(sessionID string, limit int) []*Delivery {
+	wm.historyMu.RLock()
+	defer wm.historyMu.RUnlock()
+
+	entries := wm.history[sessionID]
+	if limit <= 0 || limit > len(entries) {
+		limit = len(entries)
+	}
+
+	result := make([]*Delivery, 0, limit)
+	for i := len(entries) - 1; i >= 0 && len(result) < limit; i-- {
+		entry := *entries[i]
+		result = append(result, &entry)
+	}
+
+	return result
 }
 
 func (wm *Manager) startWorkers() {
@@ -214,19 +489,23 @@ func (wm *Manager) worker(id int) {
 	}
 }
 
+// Stop encerra os workers e os retries agendados e fecha a fila; entregas enviadas depois disso são
+// descartadas. Chamadas repetidas são seguras.
 func (wm *Manager) Stop() {
-	wm.logger.Info("Parando gerenciador de webhooks")
-	
-	for i := 0; i < wm.workers; i++ {
-		wm.stopChan <- true
-	}
-	
-	wm.workerWG.Wait()
-	
-	close(wm.deliveryQueue)
-	close(wm.stopChan)
-	
-	wm.logger.Info("Gerenciador de webhooks parado")
+	wm.stopOnce.Do(func() {
+		wm.logger.Info("Parando gerenciador de webhooks")
+
+		close(wm.stopChan)
+		wm.workerWG.Wait()
+		wm.retryWG.Wait()
+
+		wm.queueMu.Lock()
+		wm.queueClosed = true
+		close(wm.deliveryQueue)
+		wm.queueMu.Unlock()
+
+		wm.logger.Info("Gerenciador de webhooks parado")
+	})
 }
 
 func isValidURL(url string) bool {
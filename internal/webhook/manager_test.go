@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStopWithScheduledRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	wm := NewManager(Options{Workers: 1})
+
+	err := wm.SetConfig("session", &Config{
+		URL:        server.URL,
+		Events:     []EventType{EventMessage},
+		Enabled:    true,
+		MaxRetries: 3,
+		RetryDelay: time.Minute,
+		Backoff:    BackoffFixed,
+		Deadline:   time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+
+	wm.Send("session", EventMessage, nil, nil)
+
+	// Aguarda a primeira tentativa falhar e o retry ser agendado
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		history := wm.GetDeliveryHistory("session", 1)
+		if len(history) == 1 && history[0].Attempts == 1 && !history[0].NextRetry.IsZero() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("retry não foi agendado")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		wm.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop não retornou com um retry agendado")
+	}
+
+	history := wm.GetDeliveryHistory("session", 1)
+	if len(history) != 1 || history[0].Status != string(StatusExpired) {
+		t.Errorf("entrega com retry pendente deveria expirar no Stop, obtido %+v", history)
+	}
+
+	// Depois do Stop, novas entregas são descartadas em vez de enviadas no canal fechado
+	if wm.enqueue(&Delivery{ID: "depois-do-stop"}, true) {
+		t.Error("enqueue aceitou entrega depois do Stop")
+	}
+	wm.Send("session", EventMessage, nil, nil)
+
+	// Chamadas repetidas são seguras
+	wm.Stop()
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+
+	signaturePrefix = "sha256="
+)
+
+var (
+	ErrInvalidSignature = errors.New("assinatura do webhook inválida")
+	ErrInvalidTimestamp = errors.New("timestamp do webhook inválido")
+	ErrTimestampExpired = errors.New("timestamp do webhook fora da tolerância")
+)
+
+// SignPayload assina "timestamp.body" com HMAC-SHA256 e retorna o valor do cabeçalho
+// X-Webhook-Signature ("sha256=<hex>"). Incluir o timestamp na assinatura impede que um payload
+// capturado seja reenviado com outro X-Webhook-Timestamp.
+func SignPayload(body []byte, timestamp int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature valida os cabeçalhos X-Webhook-Timestamp e X-Webhook-Signature recebidos junto
+// com body. O timestamp precisa estar a no máximo tolerance do horário atual (zero desativa a
+// verificação de tempo) e a comparação da assinatura é feita em tempo constante.
+func VerifySignature(body []byte, timestamp, signature, secret string, tolerance time.Duration) error {
+	unix, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	if tolerance > 0 {
+		age := time.Since(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrTimestampExpired
+		}
+	}
+
+	expected := SignPayload(body, unix, secret)
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
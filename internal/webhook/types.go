@@ -1,18 +1,119 @@
 package webhook
 
 import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
 	"time"
 )
 
 type Config struct {
 	URL        string            `json:"url"`
-	Events     []string          `json:"events"`
+	Events     []EventType       `json:"events"`
 	Headers    map[string]string `json:"headers,omitempty"`
 	Timeout    time.Duration     `json:"timeout"`
-	MaxRetries int               `json:"max_retries"`
-	RetryDelay time.Duration     `json:"retry_delay"`
-	Enabled    bool              `json:"enabled"`
-	Secret     string            `json:"secret,omitempty"`
+	MaxRetries int               `json:"maxRetries"`
+	RetryDelay time.Duration     `json:"retryDelay"`
+	Backoff    BackoffStrategy   `json:"backoff"`
+
+	MaxRetryDelay time.Duration `json:"maxRetryDelay"` // Teto do intervalo entre tentativas
+	Deadline      time.Duration `json:"deadline"`      // Prazo da entrega, contado do enfileiramento; depois dele a entrega expira
+
+	Filter  *Filter `json:"filter,omitempty"` // Restrições adicionais aos eventos inscritos
+	Enabled bool    `json:"enabled"`
+	Secret  string  `json:"secret,omitempty"`
+}
+
+// BackoffStrategy define como o intervalo entre tentativas cresce a cada falha
+type BackoffStrategy string
+
+const (
+	BackoffFixed       BackoffStrategy = "fixed"
+	BackoffLinear      BackoffStrategy = "linear"
+	BackoffExponential BackoffStrategy = "exponential"
+)
+
+// Limites aceitos para a política de retry
+const (
+	MinMaxRetries = 1
+	MaxMaxRetries = 10
+	MinRetryDelay = 1 * time.Second
+	MaxRetryDelay = 5 * time.Minute
+
+	MinDeliveryDeadline     = 1 * time.Minute
+	MaxDeliveryDeadline     = 24 * time.Hour
+	DefaultDeliveryDeadline = 1 * time.Hour
+)
+
+func (b BackoffStrategy) IsValid() bool {
+	switch b {
+	case BackoffFixed, BackoffLinear, BackoffExponential:
+		return true
+	default:
+		return false
+	}
+}
+
+// Delay calcula o intervalo antes da próxima tentativa, dado o número de tentativas já feitas,
+// limitado a maxDelay (ou a MaxRetryDelay quando maxDelay não é informado)
+func (b BackoffStrategy) Delay(base, maxDelay time.Duration, attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if maxDelay <= 0 {
+		maxDelay = MaxRetryDelay
+	}
+
+	switch b {
+	case BackoffFixed:
+		return min(base, maxDelay)
+	case BackoffExponential:
+		delay := base
+		for i := 1; i < attempts && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		return min(delay, maxDelay)
+	default:
+		return min(time.Duration(attempts)*base, maxDelay)
+	}
+}
+
+// RetryPolicy agrupa os parâmetros de retry alteráveis de um webhook
+type RetryPolicy struct {
+	MaxRetries    int
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	Backoff       BackoffStrategy
+	Deadline      time.Duration
+}
+
+// Validate verifica se a política está dentro dos limites aceitos
+func (p RetryPolicy) Validate() error {
+	if p.MaxRetries < MinMaxRetries || p.MaxRetries > MaxMaxRetries {
+		return fmt.Errorf("maxRetries deve estar entre %d e %d", MinMaxRetries, MaxMaxRetries)
+	}
+	if p.RetryDelay < MinRetryDelay || p.RetryDelay > MaxRetryDelay {
+		return fmt.Errorf("retryDelay deve estar entre %s e %s", MinRetryDelay, MaxRetryDelay)
+	}
+	if p.MaxRetryDelay < p.RetryDelay || p.MaxRetryDelay > MaxRetryDelay {
+		return fmt.Errorf("maxRetryDelay deve estar entre retryDelay (%s) e %s", p.RetryDelay, MaxRetryDelay)
+	}
+	if !p.Backoff.IsValid() {
+		return fmt.Errorf("estratégia de backoff inválida: %s", p.Backoff)
+	}
+	if p.Deadline < MinDeliveryDeadline || p.Deadline > MaxDeliveryDeadline {
+		return fmt.Errorf("deadline deve estar entre %s e %s", MinDeliveryDeadline, MaxDeliveryDeadline)
+	}
+	return nil
+}
+
+// NextRetryDelay calcula o intervalo antes da próxima tentativa com jitter: metade do intervalo
+// da estratégia é fixa e a outra metade é sorteada, para que entregas que falharam juntas não
+// sejam repetidas todas ao mesmo tempo quando o endpoint voltar.
+func (c *Config) NextRetryDelay(attempts int) time.Duration {
+	delay := c.Backoff.Delay(c.RetryDelay, c.MaxRetryDelay, attempts)
+	half := delay / 2
+	return half + rand.N(delay-half+1)
 }
 
 type Payload struct {
@@ -29,12 +130,17 @@ type Delivery struct {
 	URL         string        `json:"url"`
 	Payload     interface{}   `json:"payload"`
 	Attempts    int           `json:"attempts"`
-	MaxRetries  int           `json:"max_retries"`
-	LastAttempt time.Time     `json:"last_attempt"`
-	NextRetry   time.Time     `json:"next_retry"`
+	MaxRetries  int           `json:"maxRetries"`
+	LastAttempt time.Time     `json:"lastAttempt"`
+	NextRetry   time.Time     `json:"nextRetry"`
 	Status      string        `json:"status"`
 	Error       string        `json:"error,omitempty"`
 	Duration    time.Duration `json:"duration"`
+	Deadline    time.Time     `json:"deadline"`
+
+	// Config é a cópia da configuração resolvida ao enfileirar, usada em todas as tentativas.
+	// Entregas do webhook global não têm configuração registrada pelo SessionID.
+	Config *Config `json:"-"`
 }
 
 type DeliveryStatus string
@@ -55,6 +161,7 @@ const (
 	EventPairSuccess                 EventType = "PairSuccess"
 	EventPairError                   EventType = "PairError"
 	EventQR                          EventType = "QR"
+	EventQRTimeout                   EventType = "QRTimeout"
 	EventQRScannedWithoutMultidevice EventType = "QRScannedWithoutMultidevice"
 	EventStreamReplaced              EventType = "StreamReplaced"
 	EventStreamError                 EventType = "StreamError"
@@ -132,8 +239,112 @@ const (
 	EventAll EventType = "All"
 )
 
+var supportedEventTypes = map[EventType]bool{
+	EventConnected: true, EventDisconnected: true, EventLoggedOut: true, EventPairSuccess: true,
+	EventPairError: true, EventQR: true, EventQRTimeout: true, EventQRScannedWithoutMultidevice: true, EventStreamReplaced: true,
+	EventStreamError: true, EventConnectFailure: true, EventClientOutdated: true, EventTemporaryBan: true,
+	EventCATRefreshError: true, EventKeepAliveTimeout: true, EventKeepAliveRestored: true, EventManualLoginReconnect: true,
+	EventMessage: true, EventFBMessage: true, EventReceipt: true, EventUndecryptableMessage: true,
+	EventMediaRetry: true, EventMediaRetryError: true, EventPresence: true, EventChatPresence: true,
+	EventGroupInfo: true, EventJoinedGroup: true, EventContact: true, EventPushName: true,
+	EventBusinessName: true, EventPicture: true, EventUserAbout: true, EventArchive: true,
+	EventPin: true, EventMute: true, EventStar: true, EventMarkChatAsRead: true,
+	EventDeleteChat: true, EventClearChat: true, EventDeleteForMe: true, EventLabelEdit: true,
+	EventLabelAssociationChat: true, EventLabelAssociationMessage: true, EventPrivacySettings: true, EventPushNameSetting: true,
+	EventUnarchiveChatsSetting: true, EventHistorySync: true, EventAppState: true, EventAppStateSyncComplete: true,
+	EventOfflineSyncPreview: true, EventOfflineSyncCompleted: true, EventCallOffer: true, EventCallOfferNotice: true,
+	EventCallAccept: true, EventCallPreAccept: true, EventCallReject: true, EventCallTerminate: true,
+	EventCallRelayLatency: true, EventCallTransport: true, EventUnknownCallEvent: true, EventNewsletterJoin: true,
+	EventNewsletterLeave: true, EventNewsletterLiveUpdate: true, EventNewsletterMuteChange: true, EventBlocklist: true,
+	EventIdentityChange: true, EventUserStatusMute: true,
+}
+
+// IsValid indica se o tipo corresponde a um evento concreto emitido pelo ZPigo ("All" não é um evento)
+func (e EventType) IsValid() bool {
+	return supportedEventTypes[e]
+}
+
+// EventGroups agrupa eventos relacionados sob um nome, aceito em qualquer lista de eventos
+// no lugar dos eventos individuais
+var EventGroups = map[string][]EventType{
+	"connection": {
+		EventConnected, EventDisconnected, EventLoggedOut, EventPairSuccess, EventPairError, EventQR,
+		EventQRTimeout, EventQRScannedWithoutMultidevice, EventStreamReplaced, EventStreamError, EventConnectFailure,
+		EventClientOutdated, EventTemporaryBan, EventCATRefreshError, EventKeepAliveTimeout,
+		EventKeepAliveRestored, EventManualLoginReconnect,
+	},
+	"messages": {
+		EventMessage, EventFBMessage, EventReceipt, EventUndecryptableMessage, EventMediaRetry, EventMediaRetryError,
+	},
+	"presence": {EventPresence, EventChatPresence},
+	"groups":   {EventGroupInfo, EventJoinedGroup},
+	"contacts": {EventContact, EventPushName, EventBusinessName, EventPicture, EventUserAbout, EventIdentityChange},
+	"chats": {
+		EventArchive, EventPin, EventMute, EventStar, EventMarkChatAsRead, EventDeleteChat, EventClearChat, EventDeleteForMe,
+	},
+	"labels":   {EventLabelEdit, EventLabelAssociationChat, EventLabelAssociationMessage},
+	"settings": {EventPrivacySettings, EventPushNameSetting, EventUnarchiveChatsSetting, EventBlocklist, EventUserStatusMute},
+	"sync": {
+		EventHistorySync, EventAppState, EventAppStateSyncComplete, EventOfflineSyncPreview, EventOfflineSyncCompleted,
+	},
+	"calls": {
+		EventCallOffer, EventCallOfferNotice, EventCallAccept, EventCallPreAccept, EventCallReject,
+		EventCallTerminate, EventCallRelayLatency, EventCallTransport, EventUnknownCallEvent,
+	},
+	"newsletters": {EventNewsletterJoin, EventNewsletterLeave, EventNewsletterLiveUpdate, EventNewsletterMuteChange},
+}
+
+// ParseEventTypes converte e valida a lista de eventos informada pelo cliente.
+// Além dos eventos concretos, aceita "All" para inscrever todos os eventos e os nomes de
+// EventGroups, que são expandidos nos eventos do grupo. Eventos repetidos são descartados.
+func ParseEventTypes(values []string) ([]EventType, error) {
+	events := make([]EventType, 0, len(values))
+	seen := make(map[EventType]bool, len(values))
+	add := func(eventType EventType) {
+		if !seen[eventType] {
+			seen[eventType] = true
+			events = append(events, eventType)
+		}
+	}
+
+	var invalid []string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if group, ok := EventGroups[strings.ToLower(value)]; ok {
+			for _, eventType := range group {
+				add(eventType)
+			}
+			continue
+		}
+		eventType := EventType(value)
+		if eventType != EventAll && !eventType.IsValid() {
+			invalid = append(invalid, value)
+			continue
+		}
+		add(eventType)
+	}
+
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("tipos de evento desconhecidos: %s", strings.Join(invalid, ", "))
+	}
+
+	return events, nil
+}
+
+// EventTypeStrings converte a lista tipada de eventos em strings
+func EventTypeStrings(events []EventType) []string {
+	values := make([]string, 0, len(events))
+	for _, event := range events {
+		values = append(values, string(event))
+	}
+	return values
+}
+
 type Response struct {
-	StatusCode int               `json:"status_code"`
+	StatusCode int               `json:"statusCode"`
 	Headers    map[string]string `json:"headers"`
 	Body       string            `json:"body"`
 	Duration   time.Duration     `json:"duration"`
@@ -141,17 +352,91 @@ type Response struct {
 }
 
 type Stats struct {
-	TotalSent      int64 `json:"total_sent"`
-	TotalSuccess   int64 `json:"total_success"`
-	TotalFailed    int64 `json:"total_failed"`
-	TotalRetries   int64 `json:"total_retries"`
-	AverageLatency int64 `json:"average_latency_ms"`
-	QueueSize      int   `json:"queue_size"`
+	TotalSent      int64 `json:"totalSent"`
+	TotalSuccess   int64 `json:"totalSuccess"`
+	TotalFailed    int64 `json:"totalFailed"`
+	TotalRetries   int64 `json:"totalRetries"`
+	TotalDropped   int64 `json:"totalDropped"`
+	AverageLatency int64 `json:"averageLatencyMs"`
+	QueueSize      int   `json:"queueSize"`
 }
 
+// Filter restringe quais eventos inscritos são entregues. Campos vazios não filtram. FromMe e
+// IsGroup só se aplicam a eventos que trazem essas informações (mensagens e recibos detalhados);
+// os demais eventos passam pelo filtro.
 type Filter struct {
 	Events    []string `json:"events,omitempty"`
-	SessionID string   `json:"session_id,omitempty"`
-	FromMe    *bool    `json:"from_me,omitempty"`
-	IsGroup   *bool    `json:"is_group,omitempty"`
+	SessionID string   `json:"sessionId,omitempty"`
+	FromMe    *bool    `json:"fromMe,omitempty"`
+	IsGroup   *bool    `json:"isGroup,omitempty"`
+}
+
+// EventAttributes são as características do evento avaliadas pelo Filter
+type EventAttributes struct {
+	FromMe  *bool
+	IsGroup *bool
+}
+
+// EventAttributesFromData extrai isFromMe e isGroup dos dados do evento, quando presentes
+func EventAttributesFromData(eventData interface{}) EventAttributes {
+	var attrs EventAttributes
+
+	data, ok := eventData.(map[string]interface{})
+	if !ok {
+		return attrs
+	}
+	if fromMe, ok := data["isFromMe"].(bool); ok {
+		attrs.FromMe = &fromMe
+	}
+	if isGroup, ok := data["isGroup"].(bool); ok {
+		attrs.IsGroup = &isGroup
+	}
+
+	return attrs
+}
+
+// NewFilter cria um filtro por origem e tipo de chat; retorna nil quando nenhum dos dois é informado
+func NewFilter(fromMe, isGroup *bool) *Filter {
+	if fromMe == nil && isGroup == nil {
+		return nil
+	}
+	return &Filter{FromMe: fromMe, IsGroup: isGroup}
+}
+
+// IsEmpty indica se o filtro não restringe nenhum evento
+func (f *Filter) IsEmpty() bool {
+	return f == nil || (len(f.Events) == 0 && f.SessionID == "" && f.FromMe == nil && f.IsGroup == nil)
+}
+
+// Matches indica se o evento passa pelo filtro
+func (f *Filter) Matches(sessionID string, eventType EventType, attrs EventAttributes) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.SessionID != "" && f.SessionID != sessionID {
+		return false
+	}
+
+	if len(f.Events) > 0 {
+		matched := false
+		for _, event := range f.Events {
+			if event == string(EventAll) || event == string(eventType) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if f.FromMe != nil && attrs.FromMe != nil && *f.FromMe != *attrs.FromMe {
+		return false
+	}
+	if f.IsGroup != nil && attrs.IsGroup != nil && *f.IsGroup != *attrs.IsGroup {
+		return false
+	}
+
+	return true
 }
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTypesJSONKeys(t *testing.T) {
+	fromMe, isGroup := true, false
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		keys    []string
+		removed []string
+	}{
+		{
+			name:    "Config",
+			value:   Config{},
+			keys:    []string{"maxRetries", "retryDelay", "maxRetryDelay"},
+			removed: []string{"max_retries", "retry_delay"},
+		},
+		{
+			name:    "Delivery",
+			value:   Delivery{},
+			keys:    []string{"sessionId", "maxRetries", "lastAttempt", "nextRetry"},
+			removed: []string{"max_retries", "last_attempt", "next_retry"},
+		},
+		{
+			name:    "Response",
+			value:   Response{},
+			keys:    []string{"statusCode"},
+			removed: []string{"status_code"},
+		},
+		{
+			name:    "Stats",
+			value:   Stats{},
+			keys:    []string{"totalSent", "totalSuccess", "totalFailed", "totalRetries", "averageLatencyMs", "queueSize"},
+			removed: []string{"total_sent", "total_success", "total_failed", "total_retries", "average_latency_ms", "queue_size"},
+		},
+		{
+			name:    "Filter",
+			value:   Filter{SessionID: "s", FromMe: &fromMe, IsGroup: &isGroup},
+			keys:    []string{"sessionId", "fromMe", "isGroup"},
+			removed: []string{"session_id", "from_me", "is_group"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("erro ao serializar: %v", err)
+			}
+
+			var keys map[string]interface{}
+			if err := json.Unmarshal(data, &keys); err != nil {
+				t.Fatalf("erro ao decodificar: %v", err)
+			}
+
+			for _, key := range tt.keys {
+				if _, ok := keys[key]; !ok {
+					t.Errorf("chave %q ausente em %s", key, data)
+				}
+			}
+			for _, key := range tt.removed {
+				if _, ok := keys[key]; ok {
+					t.Errorf("chave antiga %q ainda presente", key)
+				}
+			}
+		})
+	}
+}